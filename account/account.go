@@ -106,4 +106,5 @@ type AccountMetadata struct {
 	Key       []byte //PrivateKey in encrypted
 	EncAlg    string //Encrypt alg of private key
 	Hash      string //Hash alg
+	Path      string //Derivation path of HD account
 }
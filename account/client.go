@@ -36,6 +36,8 @@ import (
 type Client interface {
 	//NewAccount create a new account.
 	NewAccount(label string, typeCode keypair.KeyType, curveCode byte, sigScheme s.SignatureScheme, passwd []byte) (*Account, error)
+	//NewDerivedAccount create a new account derived from the HD seed with path
+	NewDerivedAccount(label string, seed []byte, path string, curveCode byte, sigScheme s.SignatureScheme, passwd []byte) (*Account, error)
 	//ImportAccount import a already exist account to wallet
 	ImportAccount(accMeta *AccountMetadata) error
 	//GetAccountByAddress return account object by address
@@ -170,6 +172,43 @@ func (this *ClientImpl) NewAccount(label string, typeCode keypair.KeyType, curve
 	}, nil
 }
 
+func (this *ClientImpl) NewDerivedAccount(label string, seed []byte, path string, curveCode byte, sigScheme s.SignatureScheme, passwd []byte) (*Account, error) {
+	if len(passwd) == 0 {
+		return nil, fmt.Errorf("password cannot empty")
+	}
+	prvkey, err := DerivePrivateKey(seed, path, curveCode)
+	if err != nil {
+		return nil, fmt.Errorf("derivePrivateKey error:%s", err)
+	}
+	pubkey := prvkey.Public()
+	address := types.AddressFromPubKey(pubkey)
+	addressBase58 := address.ToBase58()
+	if this.GetAccountMetadataByAddress(addressBase58) != nil {
+		return nil, fmt.Errorf("account:%s already exists", addressBase58)
+	}
+	prvSecret, err := keypair.EncryptPrivateKey(prvkey, addressBase58, passwd)
+	if err != nil {
+		return nil, fmt.Errorf("encryptPrivateKey error:%s", err)
+	}
+	accData := &AccountData{}
+	accData.Label = label
+	accData.SetKeyPair(prvSecret)
+	accData.SigSch = sigScheme.Name()
+	accData.PubKey = hex.EncodeToString(keypair.SerializePublicKey(pubkey))
+	accData.DerivationPath = path
+
+	err = this.addAccountData(accData)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{
+		PrivateKey: prvkey,
+		PublicKey:  pubkey,
+		Address:    address,
+		SigScheme:  sigScheme,
+	}, nil
+}
+
 func (this *ClientImpl) addAccountData(accData *AccountData) error {
 	if !this.checkSigScheme(accData.Alg, accData.SigSch) {
 		return fmt.Errorf("sigScheme:%s does not match KeyType:%s", accData.SigSch, accData.Alg)
@@ -214,6 +253,7 @@ func (this *ClientImpl) ImportAccount(accMeta *AccountMetadata) error {
 	accData.Hash = accMeta.Hash
 	accData.Salt = accMeta.Salt
 	accData.Param = map[string]string{"curve": accMeta.Curve}
+	accData.DerivationPath = accMeta.Path
 
 	oldAccMeta := this.GetAccountMetadataByLabel(accData.Label)
 	if oldAccMeta != nil {
@@ -341,6 +381,7 @@ func (this *ClientImpl) getAccountMetadata(accData *AccountData) *AccountMetadat
 	accMeta.Hash = accData.Hash
 	accMeta.Curve = accData.Param["curve"]
 	accMeta.Salt = accData.Salt
+	accMeta.Path = accData.DerivationPath
 	return accMeta
 }
 
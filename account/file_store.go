@@ -33,11 +33,12 @@ import (
 type AccountData struct {
 	keypair.ProtectedKey
 
-	Label     string `json:"label"`
-	PubKey    string `json:"publicKey"`
-	SigSch    string `json:"signatureScheme"`
-	IsDefault bool   `json:"isDefault"`
-	Lock      bool   `json:"lock"`
+	Label          string `json:"label"`
+	PubKey         string `json:"publicKey"`
+	SigSch         string `json:"signatureScheme"`
+	IsDefault      bool   `json:"isDefault"`
+	Lock           bool   `json:"lock"`
+	DerivationPath string `json:"derivationPath,omitempty"`
 }
 
 func (this *AccountData) SetKeyPair(keyinfo *keypair.ProtectedKey) {
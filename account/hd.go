@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ontio/ontology-crypto/ec"
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	//DEFAULT_HD_PATH is the BIP-44 account level path, the account index is appended as the last element
+	DEFAULT_HD_PATH = "m/44'/1024'/0'/0"
+	//MNEMONIC_ENTROPY_BITS gives a 12 words mnemonic
+	MNEMONIC_ENTROPY_BITS = 128
+	//HARDENED_KEY_START is the first index of hardened child keys
+	HARDENED_KEY_START = uint32(0x80000000)
+)
+
+//master key hmac keys defined by SLIP-0010
+var hdCurveSeeds = map[byte]string{
+	keypair.P256:      "Nist256p1 seed",
+	keypair.SECP256K1: "Bitcoin seed",
+}
+
+//IsHDCurveSupported return whether accounts of the curve can be derived
+func IsHDCurveSupported(curveCode byte) bool {
+	_, ok := hdCurveSeeds[curveCode]
+	return ok
+}
+
+//NewMnemonic return a new random BIP-39 mnemonic
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(MNEMONIC_ENTROPY_BITS)
+	if err != nil {
+		return "", fmt.Errorf("new entropy error:%s", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+//NewSeedFromMnemonic check the mnemonic and return the BIP-39 seed of it
+func NewSeedFromMnemonic(mnemonic string, passphrase string) ([]byte, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic:%s", err)
+	}
+	return seed, nil
+}
+
+//HDAccountPath return the derivation path of the account with index under the base path
+func HDAccountPath(basePath string, index uint32) string {
+	if basePath == "" {
+		basePath = DEFAULT_HD_PATH
+	}
+	return fmt.Sprintf("%s/%d", strings.TrimSuffix(basePath, "/"), index)
+}
+
+//ParseDerivationPath parse path like m/44'/1024'/0'/0/0 into child indexes
+func ParseDerivationPath(path string) ([]uint32, error) {
+	elems := strings.Split(strings.TrimSpace(path), "/")
+	if len(elems) == 0 || elems[0] != "m" {
+		return nil, fmt.Errorf("derivation path:%s must start with m", path)
+	}
+	indexes := make([]uint32, 0, len(elems)-1)
+	for _, elem := range elems[1:] {
+		hardened := false
+		if strings.HasSuffix(elem, "'") || strings.HasSuffix(elem, "h") {
+			hardened = true
+			elem = elem[:len(elem)-1]
+		}
+		index, err := strconv.ParseUint(elem, 10, 32)
+		if err != nil || uint32(index) >= HARDENED_KEY_START {
+			return nil, fmt.Errorf("invalid derivation path element:%s", elem)
+		}
+		if hardened {
+			index += uint64(HARDENED_KEY_START)
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
+
+//DerivePrivateKey derive the ECDSA key of path from seed, following SLIP-0010
+//which is BIP-32 for secp256k1 and its extension for P-256
+func DerivePrivateKey(seed []byte, path string, curveCode byte) (keypair.PrivateKey, error) {
+	curveSeed, ok := hdCurveSeeds[curveCode]
+	if !ok {
+		return nil, fmt.Errorf("curve:%d does not support key derivation", curveCode)
+	}
+	curve, err := keypair.GetCurve(curveCode)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	key, chainCode := hdMasterKey(curve, []byte(curveSeed), seed)
+	for _, index := range indexes {
+		key, chainCode = hdChildKey(curve, key, chainCode, index)
+	}
+	return &ec.PrivateKey{
+		Algorithm:  ec.ECDSA,
+		PrivateKey: ec.ConstructPrivateKey(padKey(key), curve),
+	}, nil
+}
+
+func hdMasterKey(curve elliptic.Curve, curveSeed []byte, seed []byte) (*big.Int, []byte) {
+	I := hmacSha512(curveSeed, seed)
+	for {
+		key := new(big.Int).SetBytes(I[:32])
+		if key.Sign() != 0 && key.Cmp(curve.Params().N) < 0 {
+			return key, I[32:]
+		}
+		I = hmacSha512(curveSeed, I)
+	}
+}
+
+func hdChildKey(curve elliptic.Curve, key *big.Int, chainCode []byte, index uint32) (*big.Int, []byte) {
+	data := make([]byte, 0, 37)
+	if index >= HARDENED_KEY_START {
+		data = append(data, 0)
+		data = append(data, padKey(key)...)
+	} else {
+		x, y := curve.ScalarBaseMult(padKey(key))
+		data = append(data, ec.EncodePublicKey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y}, true)...)
+	}
+	data = append(data, ser32(index)...)
+	n := curve.Params().N
+	for {
+		I := hmacSha512(chainCode, data)
+		il := new(big.Int).SetBytes(I[:32])
+		child := new(big.Int).Add(il, key)
+		child.Mod(child, n)
+		if il.Cmp(n) < 0 && child.Sign() != 0 {
+			return child, I[32:]
+		}
+		data = append([]byte{1}, I[32:]...)
+		data = append(data, ser32(index)...)
+	}
+}
+
+func padKey(key *big.Int) []byte {
+	buf := make([]byte, 32)
+	k := key.Bytes()
+	copy(buf[32-len(k):], k)
+	return buf
+}
+
+func ser32(index uint32) []byte {
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, index)
+	return buf
+}
+
+func hmacSha512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
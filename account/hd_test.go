@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package account
+
+import (
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/ec"
+	"github.com/ontio/ontology-crypto/keypair"
+	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDerivePrivateKey(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	//test vector 1 of SLIP-0010
+	vectors := map[byte]map[string]string{
+		keypair.P256: {
+			"m":                      "612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2",
+			"m/0'":                   "6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c",
+			"m/0'/1":                 "284e9d38d07d21e4e281b645089a94f4cf5a5a81369acf151a1c3a57f18b2129",
+			"m/0'/1/2'":              "694596e8a54f252c960eb771a3c41e7e32496d03b954aeb90f61635b8e092aa7",
+			"m/0'/1/2'/2":            "5996c37fd3dd2679039b23ed6f70b506c6b56b3cb5e424681fb0fa64caf82aaa",
+			"m/0'/1/2'/2/1000000000": "21c4f269ef0a5fd1badf47eeacebeeaa3de22eb8e5b0adcd0f27dd99d34d0119",
+		},
+		keypair.SECP256K1: {
+			"m":                      "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+			"m/0'":                   "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+			"m/0'/1":                 "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+			"m/0'/1/2'":              "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+			"m/0'/1/2'/2":            "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+			"m/0'/1/2'/2/1000000000": "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+		},
+	}
+	for curve, paths := range vectors {
+		for path, expect := range paths {
+			pri, err := DerivePrivateKey(seed, path, curve)
+			assert.Nil(t, err)
+			assert.Equal(t, expect, hex.EncodeToString(padKey(pri.(*ec.PrivateKey).D)), path)
+		}
+	}
+
+	_, err := DerivePrivateKey(seed, "m/0'", keypair.P384)
+	assert.NotNil(t, err)
+	_, err = ParseDerivationPath("44'/0")
+	assert.NotNil(t, err)
+	_, err = ParseDerivationPath("m/2147483648")
+	assert.NotNil(t, err)
+}
+
+func TestNewDerivedAccount(t *testing.T) {
+	walletFile := "hd_wallet.dat"
+	defer func() {
+		os.Remove(walletFile)
+		os.Remove(walletFile + "~")
+	}()
+	mnemonic, err := NewMnemonic()
+	assert.Nil(t, err)
+	seed, err := NewSeedFromMnemonic(mnemonic, "")
+	assert.Nil(t, err)
+	_, err = NewSeedFromMnemonic(mnemonic+" abandon", "")
+	assert.NotNil(t, err)
+
+	wallet, err := NewClientImpl(walletFile)
+	assert.Nil(t, err)
+	passwd := []byte("passwd")
+	path := HDAccountPath("", 0)
+	acc, err := wallet.NewDerivedAccount("hd", seed, path, keypair.SECP256K1, s.SHA256withECDSA, passwd)
+	assert.Nil(t, err)
+	_, err = wallet.NewDerivedAccount("hd1", seed, path, keypair.SECP256K1, s.SHA256withECDSA, passwd)
+	assert.NotNil(t, err)
+
+	wallet, err = NewClientImpl(walletFile)
+	assert.Nil(t, err)
+	accMeta := wallet.GetAccountMetadataByLabel("hd")
+	assert.NotNil(t, accMeta)
+	assert.Equal(t, acc.Address.ToBase58(), accMeta.Address)
+	assert.Equal(t, "m/44'/1024'/0'/0/0", accMeta.Path)
+	restored, err := wallet.GetAccountByLabel("hd", passwd)
+	assert.Nil(t, err)
+	assert.Equal(t, keypair.SerializePrivateKey(acc.PrivateKey), keypair.SerializePrivateKey(restored.PrivateKey))
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package client provides typed clients of the JSON-RPC and restful api of a poly node,
+// and builds signed invocations of the native contracts
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	DEFAULT_TIMEOUT        = 10 * time.Second
+	DEFAULT_RETRY_INTERVAL = time.Second
+	DEFAULT_MAX_BATCH_SIZE = 100 //same as the default of the node
+)
+
+//options of the rpc and restful clients
+type options struct {
+	httpClient    *http.Client
+	timeout       time.Duration
+	retries       int
+	retryInterval time.Duration
+	maxBatchSize  int
+	apiKey        string
+}
+
+type Option func(*options)
+
+//WithTimeout sets the timeout of each http request
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.timeout = timeout
+	}
+}
+
+//WithRetry retries a request at most retries times when it fails to reach the node or the node
+//answers a http status 5xx, waiting interval between two attempts
+func WithRetry(retries int, interval time.Duration) Option {
+	return func(opts *options) {
+		opts.retries = retries
+		opts.retryInterval = interval
+	}
+}
+
+//WithHttpClient uses the given http client, e.g. with a custom tls config, instead of a new one
+func WithHttpClient(client *http.Client) Option {
+	return func(opts *options) {
+		opts.httpClient = client
+	}
+}
+
+//WithMaxBatchSize sets the max number of requests sent in one batch, larger batches are split,
+//it should not exceed the limit of the node
+func WithMaxBatchSize(size int) Option {
+	return func(opts *options) {
+		opts.maxBatchSize = size
+	}
+}
+
+//WithApiKey sends key, or a json web token, in the X-Api-Key header of each request for nodes requiring authentication
+func WithApiKey(key string) Option {
+	return func(opts *options) {
+		opts.apiKey = key
+	}
+}
+
+//transport sends http requests with the timeout and retry policy of the options
+type transport struct {
+	client        *http.Client
+	retries       int
+	retryInterval time.Duration
+	apiKey        string
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		timeout:       DEFAULT_TIMEOUT,
+		retryInterval: DEFAULT_RETRY_INTERVAL,
+		maxBatchSize:  DEFAULT_MAX_BATCH_SIZE,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.maxBatchSize <= 0 {
+		o.maxBatchSize = DEFAULT_MAX_BATCH_SIZE
+	}
+	return o
+}
+
+func newTransport(opts *options) *transport {
+	client := opts.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: opts.timeout}
+	}
+	return &transport{
+		client:        client,
+		retries:       opts.retries,
+		retryInterval: opts.retryInterval,
+		apiKey:        opts.apiKey,
+	}
+}
+
+//do sends a request with the given method and body, retrying on failures of transport
+func (self *transport) do(method, url string, body []byte) ([]byte, error) {
+	var err error
+	for i := 0; i <= self.retries; i++ {
+		if i > 0 {
+			time.Sleep(self.retryInterval)
+		}
+		var data []byte
+		var retry bool
+		data, retry, err = self.send(method, url, body)
+		if err == nil || !retry {
+			return data, err
+		}
+	}
+	return nil, err
+}
+
+//send sends a request once, the returned bool tells whether the failure is worth a retry
+func (self *transport) send(method, url string, body []byte) ([]byte, bool, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if self.apiKey != "" {
+		req.Header.Set("X-Api-Key", self.apiKey)
+	}
+	resp, err := self.client.Do(req)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("read response body error: %s", err)
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, true, fmt.Errorf("http status %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("http status %s", resp.Status)
+	}
+	return data, false, nil
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/http/base/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	rpc.HandleFunc("getblockcount", func(params []interface{}) map[string]interface{} {
+		return map[string]interface{}{"error": berr.SUCCESS, "result": 11}
+	})
+	rpc.HandleFunc("getblockhash", func(params []interface{}) map[string]interface{} {
+		height, _ := params[0].(float64)
+		hash := common.Uint256{byte(height)}
+		return map[string]interface{}{"error": berr.SUCCESS, "result": hash.ToHexString()}
+	}, "height")
+	rpc.HandleFunc("getrawtransaction", func(params []interface{}) map[string]interface{} {
+		return map[string]interface{}{"error": berr.UNKNOWN_TRANSACTION, "result": "unknown transaction"}
+	}, "hash", "verbose")
+}
+
+func newRpcServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(rpc.Handle))
+}
+
+func TestRpcCall(t *testing.T) {
+	server := newRpcServer()
+	defer server.Close()
+	client := NewRpcClient(server.URL)
+
+	height, err := client.GetCurrentBlockHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+
+	hash, err := client.GetBlockHash(3)
+	assert.Nil(t, err)
+	assert.Equal(t, common.Uint256{3}, hash)
+
+	_, err = client.GetRawTransaction(common.UINT256_EMPTY)
+	assert.True(t, IsRpcError(err, berr.UNKNOWN_TRANSACTION))
+
+	err = client.Call(nil, "nosuchmethod")
+	assert.True(t, IsRpcError(err, berr.JSONRPC_METHOD_NOT_FOUND))
+}
+
+func TestRpcBatchCall(t *testing.T) {
+	server := newRpcServer()
+	defer server.Close()
+	client := NewRpcClient(server.URL, WithMaxBatchSize(2))
+
+	hashes := make([]string, 5)
+	batch := make([]*BatchElem, 0, len(hashes)+1)
+	for i := range hashes {
+		batch = append(batch, &BatchElem{Method: "getblockhash", Params: []interface{}{i}, Result: &hashes[i]})
+	}
+	batch = append(batch, &BatchElem{Method: "getrawtransaction", Params: []interface{}{""}})
+	assert.Nil(t, client.BatchCall(batch))
+	for i, str := range hashes {
+		assert.Nil(t, batch[i].Error)
+		hash := common.Uint256{byte(i)}
+		assert.Equal(t, hash.ToHexString(), str)
+	}
+	assert.True(t, IsRpcError(batch[len(hashes)].Error, berr.UNKNOWN_TRANSACTION))
+}
+
+func TestRpcBatchRejected(t *testing.T) {
+	server := newRpcServer()
+	defer server.Close()
+	rpc.SetMaxBatchSize(1)
+	defer rpc.SetMaxBatchSize(DEFAULT_MAX_BATCH_SIZE)
+
+	client := NewRpcClient(server.URL)
+	batch := []*BatchElem{{Method: "getblockcount"}, {Method: "getblockcount"}}
+	err := client.BatchCall(batch)
+	assert.True(t, IsRpcError(err, berr.JSONRPC_INVALID_REQUEST))
+}
+
+func TestRetry(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rpc.Handle(w, r)
+	}))
+	defer server.Close()
+
+	client := NewRpcClient(server.URL, WithRetry(1, time.Millisecond))
+	_, err := client.GetBlockCount()
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&count))
+
+	client = NewRpcClient(server.URL, WithRetry(2, time.Millisecond))
+	atomic.StoreInt32(&count, 0)
+	blockCount, err := client.GetBlockCount()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(11), blockCount)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&count))
+}
+
+func TestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		rpc.Handle(w, r)
+	}))
+	defer server.Close()
+
+	client := NewRpcClient(server.URL, WithTimeout(20*time.Millisecond))
+	_, err := client.GetBlockCount()
+	assert.NotNil(t, err)
+}
+
+func TestRestClient(t *testing.T) {
+	hash := common.Uint256{1, 2, 3}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/block/height":
+			fmt.Fprint(w, `{"Action":"getblockheight","Result":10,"Error":0,"Desc":"SUCCESS","Version":"1.0.0"}`)
+		case "/api/v1/block/hash/10":
+			fmt.Fprintf(w, `{"Action":"getblockhash","Result":"%s","Error":0,"Desc":"SUCCESS","Version":"1.0.0"}`, hash.ToHexString())
+		case "/api/v1/storage/" + common.ADDRESS_EMPTY.ToHexString() + "/01":
+			fmt.Fprint(w, `{"Action":"getstorage","Result":"","Error":0,"Desc":"SUCCESS","Version":"1.0.0"}`)
+		default:
+			fmt.Fprint(w, `{"Action":"","Result":"","Error":42001,"Desc":"INVALID METHOD","Version":"1.0.0"}`)
+		}
+	}))
+	defer server.Close()
+	client := NewRestClient(server.URL + "/")
+
+	height, err := client.GetCurrentBlockHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+
+	blockHash, err := client.GetBlockHash(height)
+	assert.Nil(t, err)
+	assert.Equal(t, hash, blockHash)
+
+	value, err := client.GetStorage(common.ADDRESS_EMPTY, []byte{1})
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+
+	_, err = client.GetVersion()
+	restErr, ok := err.(*RestError)
+	assert.True(t, ok)
+	assert.Equal(t, berr.INVALID_METHOD, restErr.Code)
+}
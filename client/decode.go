@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/types"
+	ccmcom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+)
+
+type deserializable interface {
+	Deserialization(source *common.ZeroCopySource) error
+}
+
+//DecodedInvoke is a native contract invocation decoded from the payload of a transaction
+type DecodedInvoke struct {
+	Contract string `json:",omitempty"`
+	Address  string
+	Method   string
+	Args     string
+	Params   interface{} `json:",omitempty"`
+	Error    string      `json:",omitempty"`
+}
+
+//DecodeInvoke decodes the native invocation of tx, the args are decoded into the param of the method
+//if the method is known, otherwise only the raw args are returned
+func DecodeInvoke(tx *types.Transaction) (*DecodedInvoke, error) {
+	invokeCode, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return nil, fmt.Errorf("tx is not an invocation")
+	}
+	invoke := new(states.ContractInvokeParam)
+	if err := invoke.Deserialization(common.NewZeroCopySource(invokeCode.Code)); err != nil {
+		return nil, fmt.Errorf("invalid native invocation:%s", err)
+	}
+	decoded := &DecodedInvoke{
+		Contract: nativeContractName(invoke.Address),
+		Address:  invoke.Address.ToHexString(),
+		Method:   invoke.Method,
+		Args:     common.ToHexString(invoke.Args),
+	}
+	method := GetNativeMethod(invoke.Address, invoke.Method)
+	if method == nil {
+		return decoded, nil
+	}
+	param, err := DecodeParam(method, invoke.Args)
+	if err != nil {
+		decoded.Error = err.Error()
+		return decoded, nil
+	}
+	decoded.Params = ParamToJSON(param)
+	return decoded, nil
+}
+
+//DecodeParam deserializes args into the param of method the same way as the contract does
+func DecodeParam(method *NativeMethod, args []byte) (interface{}, error) {
+	param := method.NewParam()
+	if param == nil {
+		return nil, nil
+	}
+	p, ok := param.(deserializable)
+	if !ok {
+		return nil, fmt.Errorf("%s, param %T is not deserializable", method.Name, param)
+	}
+	if err := p.Deserialization(common.NewZeroCopySource(args)); err != nil {
+		return nil, fmt.Errorf("%s, param.Deserialization error: %s", method.Name, err)
+	}
+	return param, nil
+}
+
+//DecodeToMerkleValue decodes the cross chain request stored by makeProof, which is also the value
+//proved against the cross states root
+func DecodeToMerkleValue(data []byte) (*ccmcom.ToMerkleValue, error) {
+	value := new(ccmcom.ToMerkleValue)
+	if err := value.Deserialization(common.NewZeroCopySource(data)); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func nativeContractName(address common.Address) string {
+	for name, addr := range nutils.NativeContracts {
+		if addr == address {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	ccmcom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeInvoke(t *testing.T) {
+	addr := common.Address{1}
+	builder := NewNativeBuilder(0)
+	tx, err := builder.ApproveRegisterSideChain(&side_chain_manager.ChainidParam{Chainid: 3, Address: addr})
+	assert.Nil(t, err)
+	decoded, err := DecodeInvoke(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, "side_chain_manager", decoded.Contract)
+	assert.Equal(t, side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN, decoded.Method)
+	assert.Empty(t, decoded.Error)
+	out, err := json.Marshal(decoded.Params)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Chainid":3,"Address":"`+addr.ToBase58()+`"}`, string(out))
+
+	//unknown method keeps the raw args
+	tx, err = builder.NewInvokeTx(nutils.SideChainManagerContractAddress, "unknown", []byte{1, 2})
+	assert.Nil(t, err)
+	decoded, err = DecodeInvoke(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, "0102", decoded.Args)
+	assert.Nil(t, decoded.Params)
+
+	//malformed args
+	tx, err = builder.NewInvokeTx(nutils.SideChainManagerContractAddress, side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN, []byte{1})
+	assert.Nil(t, err)
+	decoded, err = DecodeInvoke(tx)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, decoded.Error)
+}
+
+func TestDecodeToMerkleValue(t *testing.T) {
+	value := &ccmcom.ToMerkleValue{
+		TxHash:      []byte{1, 2},
+		FromChainID: 2,
+		MakeTxParam: &ccmcom.MakeTxParam{
+			TxHash:              []byte{3},
+			CrossChainID:        []byte{4},
+			FromContractAddress: []byte{5},
+			ToChainID:           7,
+			ToContractAddress:   []byte{6},
+			Method:              "unlock",
+			Args:                []byte{7},
+		},
+	}
+	sink := common.NewZeroCopySink(nil)
+	value.Serialization(sink)
+	decoded, err := DecodeToMerkleValue(sink.Bytes())
+	assert.Nil(t, err)
+	assert.Equal(t, value, decoded)
+
+	_, err = DecodeToMerkleValue(sink.Bytes()[:10])
+	assert.NotNil(t, err)
+}
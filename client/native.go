@@ -0,0 +1,252 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager"
+	ccmcom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+)
+
+//params of native methods, most of them serialize without error
+type serializable interface {
+	Serialization(sink *common.ZeroCopySink)
+}
+
+type serializableWithErr interface {
+	Serialization(sink *common.ZeroCopySink) error
+}
+
+//NativeBuilder builds unsigned transactions invoking the methods of the native contracts,
+//the params are serialized the same way as the contracts deserialize them
+type NativeBuilder struct {
+	chainId uint64
+	nonce   uint32
+}
+
+//NewNativeBuilder returns a builder of transactions for the chain of chainId,
+//the nonces of the transactions start at a random number
+func NewNativeBuilder(chainId uint64) *NativeBuilder {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return &NativeBuilder{chainId: chainId, nonce: r.Uint32()}
+}
+
+//NewNativeBuilder returns a builder of transactions for the network of the node
+func (self *RpcClient) NewNativeBuilder() (*NativeBuilder, error) {
+	networkId, err := self.GetNetworkId()
+	if err != nil {
+		return nil, fmt.Errorf("get network id error: %s", err)
+	}
+	return NewNativeBuilder(config.GetChainIdByNetId(networkId)), nil
+}
+
+//SetNonce sets the nonce of the next built transaction
+func (self *NativeBuilder) SetNonce(nonce uint32) {
+	atomic.StoreUint32(&self.nonce, nonce-1)
+}
+
+//NewInvokeTx builds a transaction invoking method of the native contract at contract with args
+func (self *NativeBuilder) NewInvokeTx(contract common.Address, method string, args []byte) (*types.Transaction, error) {
+	code := common.NewZeroCopySink(nil)
+	param := &states.ContractInvokeParam{Address: contract, Method: method, Args: args}
+	param.Serialization(code)
+	tx := &types.Transaction{
+		Version: types.CURR_TX_VERSION,
+		TxType:  types.Invoke,
+		Nonce:   atomic.AddUint32(&self.nonce, 1),
+		ChainID: self.chainId,
+		Payload: &payload.InvokeCode{Code: code.Bytes()},
+		Sigs:    make([]types.Sig, 0),
+	}
+	//a transaction gets its hash when deserialized
+	sink := common.NewZeroCopySink(nil)
+	if err := tx.Serialization(sink); err != nil {
+		return nil, fmt.Errorf("tx.Serialization error: %s", err)
+	}
+	return types.TransactionFromRawBytes(sink.Bytes())
+}
+
+func (self *NativeBuilder) invoke(contract common.Address, method string, param interface{}) (*types.Transaction, error) {
+	sink := common.NewZeroCopySink(nil)
+	switch p := param.(type) {
+	case nil:
+	case serializable:
+		p.Serialization(sink)
+	case serializableWithErr:
+		if err := p.Serialization(sink); err != nil {
+			return nil, fmt.Errorf("%s, param.Serialization error: %s", method, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s, param %T is not serializable", method, param)
+	}
+	return self.NewInvokeTx(contract, method, sink.Bytes())
+}
+
+//header_sync
+
+func (self *NativeBuilder) SyncGenesisHeader(param *hscommon.SyncGenesisHeaderParam) (*types.Transaction, error) {
+	return self.invoke(nutils.HeaderSyncContractAddress, header_sync.SYNC_GENESIS_HEADER, param)
+}
+
+func (self *NativeBuilder) SyncBlockHeader(param *hscommon.SyncBlockHeaderParam) (*types.Transaction, error) {
+	return self.invoke(nutils.HeaderSyncContractAddress, header_sync.SYNC_BLOCK_HEADER, param)
+}
+
+func (self *NativeBuilder) SyncCrossChainMsg(param *hscommon.SyncCrossChainMsgParam) (*types.Transaction, error) {
+	return self.invoke(nutils.HeaderSyncContractAddress, header_sync.SYNC_CROSS_CHAIN_MSG, param)
+}
+
+//cross_chain_manager
+
+func (self *NativeBuilder) ImportOuterTransfer(param *ccmcom.EntranceParam) (*types.Transaction, error) {
+	return self.invoke(nutils.CrossChainManagerContractAddress, cross_chain_manager.IMPORT_OUTER_TRANSFER_NAME, param)
+}
+
+func (self *NativeBuilder) MultiSign(param *ccmcom.MultiSignParam) (*types.Transaction, error) {
+	return self.invoke(nutils.CrossChainManagerContractAddress, cross_chain_manager.MULTI_SIGN, param)
+}
+
+func (self *NativeBuilder) BlackChain(param *cross_chain_manager.BlackChainParam) (*types.Transaction, error) {
+	return self.invoke(nutils.CrossChainManagerContractAddress, cross_chain_manager.BLACK_CHAIN, param)
+}
+
+func (self *NativeBuilder) WhiteChain(param *cross_chain_manager.BlackChainParam) (*types.Transaction, error) {
+	return self.invoke(nutils.CrossChainManagerContractAddress, cross_chain_manager.WHITE_CHAIN, param)
+}
+
+//side_chain_manager
+
+func (self *NativeBuilder) RegisterSideChain(param *side_chain_manager.RegisterSideChainParam) (*types.Transaction, error) {
+	return self.invoke(nutils.SideChainManagerContractAddress, side_chain_manager.REGISTER_SIDE_CHAIN, param)
+}
+
+func (self *NativeBuilder) ApproveRegisterSideChain(param *side_chain_manager.ChainidParam) (*types.Transaction, error) {
+	return self.invoke(nutils.SideChainManagerContractAddress, side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN, param)
+}
+
+func (self *NativeBuilder) UpdateSideChain(param *side_chain_manager.RegisterSideChainParam) (*types.Transaction, error) {
+	return self.invoke(nutils.SideChainManagerContractAddress, side_chain_manager.UPDATE_SIDE_CHAIN, param)
+}
+
+func (self *NativeBuilder) ApproveUpdateSideChain(param *side_chain_manager.ChainidParam) (*types.Transaction, error) {
+	return self.invoke(nutils.SideChainManagerContractAddress, side_chain_manager.APPROVE_UPDATE_SIDE_CHAIN, param)
+}
+
+func (self *NativeBuilder) QuitSideChain(param *side_chain_manager.ChainidParam) (*types.Transaction, error) {
+	return self.invoke(nutils.SideChainManagerContractAddress, side_chain_manager.QUIT_SIDE_CHAIN, param)
+}
+
+func (self *NativeBuilder) ApproveQuitSideChain(param *side_chain_manager.ChainidParam) (*types.Transaction, error) {
+	return self.invoke(nutils.SideChainManagerContractAddress, side_chain_manager.APPROVE_QUIT_SIDE_CHAIN, param)
+}
+
+func (self *NativeBuilder) RegisterRedeem(param *side_chain_manager.RegisterRedeemParam) (*types.Transaction, error) {
+	return self.invoke(nutils.SideChainManagerContractAddress, side_chain_manager.REGISTER_REDEEM, param)
+}
+
+func (self *NativeBuilder) SetBtcTxParam(param *side_chain_manager.BtcTxParam) (*types.Transaction, error) {
+	return self.invoke(nutils.SideChainManagerContractAddress, side_chain_manager.SET_BTC_TX_PARAM, param)
+}
+
+//node_manager, initConfig is left out as it is only called in the genesis block
+
+func (self *NativeBuilder) RegisterCandidate(param *node_manager.RegisterPeerParam) (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.REGISTER_CANDIDATE, param)
+}
+
+func (self *NativeBuilder) UnRegisterCandidate(param *node_manager.PeerParam) (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.UNREGISTER_CANDIDATE, param)
+}
+
+func (self *NativeBuilder) ApproveCandidate(param *node_manager.PeerParam) (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.APPROVE_CANDIDATE, param)
+}
+
+func (self *NativeBuilder) BlackNode(param *node_manager.PeerListParam) (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.BLACK_NODE, param)
+}
+
+func (self *NativeBuilder) WhiteNode(param *node_manager.PeerParam) (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.WHITE_NODE, param)
+}
+
+func (self *NativeBuilder) QuitNode(param *node_manager.PeerParam) (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.QUIT_NODE, param)
+}
+
+func (self *NativeBuilder) UpdateConfig(param *node_manager.UpdateConfigParam) (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.UPDATE_CONFIG, param)
+}
+
+func (self *NativeBuilder) CommitDpos() (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.COMMIT_DPOS, nil)
+}
+
+func (self *NativeBuilder) ReportEquivocation(param *node_manager.ReportEquivocationParam) (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.REPORT_EQUIVOCATION, param)
+}
+
+func (self *NativeBuilder) RejectProposal(param *node_manager.ProposalParam) (*types.Transaction, error) {
+	return self.invoke(nutils.NodeManagerContractAddress, node_manager.REJECT_PROPOSAL, param)
+}
+
+//relayer_manager
+
+func (self *NativeBuilder) RegisterRelayer(param *relayer_manager.RelayerListParam) (*types.Transaction, error) {
+	return self.invoke(nutils.RelayerManagerContractAddress, relayer_manager.REGISTER_RELAYER, param)
+}
+
+func (self *NativeBuilder) ApproveRegisterRelayer(param *relayer_manager.ApproveRelayerParam) (*types.Transaction, error) {
+	return self.invoke(nutils.RelayerManagerContractAddress, relayer_manager.APPROVE_REGISTER_RELAYER, param)
+}
+
+func (self *NativeBuilder) RemoveRelayer(param *relayer_manager.RelayerListParam) (*types.Transaction, error) {
+	return self.invoke(nutils.RelayerManagerContractAddress, relayer_manager.REMOVE_RELAYER, param)
+}
+
+func (self *NativeBuilder) ApproveRemoveRelayer(param *relayer_manager.ApproveRelayerParam) (*types.Transaction, error) {
+	return self.invoke(nutils.RelayerManagerContractAddress, relayer_manager.APPROVE_REMOVE_RELAYER, param)
+}
+
+//SignTransaction adds the signature of signer to tx
+func SignTransaction(tx *types.Transaction, signer *account.Account) error {
+	return utils.SignTransaction(signer, tx)
+}
+
+//MultiSignTransaction adds the signature of signer to the m of pubKeys multi-signature of tx
+func MultiSignTransaction(tx *types.Transaction, m uint16, pubKeys []keypair.PublicKey, signer *account.Account) error {
+	return utils.MultiSigTransaction(tx, m, pubKeys, signer)
+}
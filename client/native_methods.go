@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager"
+	ccmcom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+)
+
+//NativeMethod is a method of a native contract which can be invoked by transaction
+type NativeMethod struct {
+	Contract string
+	Address  common.Address
+	Name     string
+	newParam func() interface{}
+}
+
+//NewParam returns a new param of the method, nil if the method takes no param
+func (self *NativeMethod) NewParam() interface{} {
+	if self.newParam == nil {
+		return nil
+	}
+	return self.newParam()
+}
+
+//NativeMethods are the invokable methods of the native contracts by contract name and method name,
+//the same as the NativeBuilder methods
+var NativeMethods = map[string]map[string]*NativeMethod{
+	"header_sync": nativeMethods("header_sync", map[string]func() interface{}{
+		header_sync.SYNC_GENESIS_HEADER:  func() interface{} { return new(hscommon.SyncGenesisHeaderParam) },
+		header_sync.SYNC_BLOCK_HEADER:    func() interface{} { return new(hscommon.SyncBlockHeaderParam) },
+		header_sync.SYNC_CROSS_CHAIN_MSG: func() interface{} { return new(hscommon.SyncCrossChainMsgParam) },
+	}),
+	"cross_chain_manager": nativeMethods("cross_chain_manager", map[string]func() interface{}{
+		cross_chain_manager.IMPORT_OUTER_TRANSFER_NAME: func() interface{} { return new(ccmcom.EntranceParam) },
+		cross_chain_manager.MULTI_SIGN:                 func() interface{} { return new(ccmcom.MultiSignParam) },
+		cross_chain_manager.BLACK_CHAIN:                func() interface{} { return new(cross_chain_manager.BlackChainParam) },
+		cross_chain_manager.WHITE_CHAIN:                func() interface{} { return new(cross_chain_manager.BlackChainParam) },
+	}),
+	"side_chain_manager": nativeMethods("side_chain_manager", map[string]func() interface{}{
+		side_chain_manager.REGISTER_SIDE_CHAIN:         func() interface{} { return new(side_chain_manager.RegisterSideChainParam) },
+		side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN: func() interface{} { return new(side_chain_manager.ChainidParam) },
+		side_chain_manager.UPDATE_SIDE_CHAIN:           func() interface{} { return new(side_chain_manager.RegisterSideChainParam) },
+		side_chain_manager.APPROVE_UPDATE_SIDE_CHAIN:   func() interface{} { return new(side_chain_manager.ChainidParam) },
+		side_chain_manager.QUIT_SIDE_CHAIN:             func() interface{} { return new(side_chain_manager.ChainidParam) },
+		side_chain_manager.APPROVE_QUIT_SIDE_CHAIN:     func() interface{} { return new(side_chain_manager.ChainidParam) },
+		side_chain_manager.REGISTER_REDEEM:             func() interface{} { return new(side_chain_manager.RegisterRedeemParam) },
+		side_chain_manager.SET_BTC_TX_PARAM:            func() interface{} { return new(side_chain_manager.BtcTxParam) },
+	}),
+	"node_manager": nativeMethods("node_manager", map[string]func() interface{}{
+		node_manager.REGISTER_CANDIDATE:   func() interface{} { return new(node_manager.RegisterPeerParam) },
+		node_manager.UNREGISTER_CANDIDATE: func() interface{} { return new(node_manager.PeerParam) },
+		node_manager.APPROVE_CANDIDATE:    func() interface{} { return new(node_manager.PeerParam) },
+		node_manager.BLACK_NODE:           func() interface{} { return new(node_manager.PeerListParam) },
+		node_manager.WHITE_NODE:           func() interface{} { return new(node_manager.PeerParam) },
+		node_manager.QUIT_NODE:            func() interface{} { return new(node_manager.PeerParam) },
+		node_manager.UPDATE_CONFIG:        func() interface{} { return new(node_manager.UpdateConfigParam) },
+		node_manager.COMMIT_DPOS:          nil,
+		node_manager.REPORT_EQUIVOCATION:  func() interface{} { return new(node_manager.ReportEquivocationParam) },
+		node_manager.REJECT_PROPOSAL:      func() interface{} { return new(node_manager.ProposalParam) },
+	}),
+	"relayer_manager": nativeMethods("relayer_manager", map[string]func() interface{}{
+		relayer_manager.REGISTER_RELAYER:         func() interface{} { return new(relayer_manager.RelayerListParam) },
+		relayer_manager.APPROVE_REGISTER_RELAYER: func() interface{} { return new(relayer_manager.ApproveRelayerParam) },
+		relayer_manager.REMOVE_RELAYER:           func() interface{} { return new(relayer_manager.RelayerListParam) },
+		relayer_manager.APPROVE_REMOVE_RELAYER:   func() interface{} { return new(relayer_manager.ApproveRelayerParam) },
+	}),
+}
+
+func nativeMethods(contract string, params map[string]func() interface{}) map[string]*NativeMethod {
+	methods := make(map[string]*NativeMethod, len(params))
+	for name, newParam := range params {
+		methods[name] = &NativeMethod{
+			Contract: contract,
+			Address:  nutils.NativeContracts[contract],
+			Name:     name,
+			newParam: newParam,
+		}
+	}
+	return methods
+}
+
+//GetNativeMethod returns the native method of the contract at address, nil if not found
+func GetNativeMethod(address common.Address, method string) *NativeMethod {
+	for _, methods := range NativeMethods {
+		if m, ok := methods[method]; ok && m.Address == address {
+			return m
+		}
+	}
+	return nil
+}
+
+//SortedNativeMethods returns the methods of the native contract sorted by name
+func SortedNativeMethods(contract string) []*NativeMethod {
+	methods := make([]*NativeMethod, 0, len(NativeMethods[contract]))
+	for _, m := range NativeMethods[contract] {
+		methods = append(methods, m)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name < methods[j].Name
+	})
+	return methods
+}
+
+//Invoke builds a transaction invoking the native method with param
+func (self *NativeBuilder) Invoke(method *NativeMethod, param interface{}) (*types.Transaction, error) {
+	if method.newParam == nil && param != nil {
+		return nil, fmt.Errorf("%s takes no param", method.Name)
+	}
+	return self.invoke(method.Address, method.Name, param)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	"github.com/stretchr/testify/assert"
+)
+
+func invokeParam(t *testing.T, tx *types.Transaction) *states.ContractInvokeParam {
+	invoke, ok := tx.Payload.(*payload.InvokeCode)
+	assert.True(t, ok)
+	param := &states.ContractInvokeParam{}
+	assert.Nil(t, param.Deserialization(common.NewZeroCopySource(invoke.Code)))
+	return param
+}
+
+func TestNativeBuilder(t *testing.T) {
+	builder := NewNativeBuilder(2)
+	builder.SetNonce(7)
+
+	param := &relayer_manager.RelayerListParam{
+		AddressList: []common.Address{{1}, {2}},
+		Address:     common.Address{3},
+	}
+	tx, err := builder.RegisterRelayer(param)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(7), tx.Nonce)
+	assert.Equal(t, uint64(2), tx.ChainID)
+	assert.NotEqual(t, common.UINT256_EMPTY, tx.Hash())
+
+	invoke := invokeParam(t, tx)
+	assert.Equal(t, nutils.RelayerManagerContractAddress, invoke.Address)
+	assert.Equal(t, relayer_manager.REGISTER_RELAYER, invoke.Method)
+	decoded := &relayer_manager.RelayerListParam{}
+	assert.Nil(t, decoded.Deserialization(common.NewZeroCopySource(invoke.Args)))
+	assert.Equal(t, param, decoded)
+
+	tx, err = builder.CommitDpos()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(8), tx.Nonce)
+	invoke = invokeParam(t, tx)
+	assert.Equal(t, node_manager.COMMIT_DPOS, invoke.Method)
+	assert.Empty(t, invoke.Args)
+}
+
+func TestSignTransaction(t *testing.T) {
+	acc := account.NewAccount("")
+	tx, err := NewNativeBuilder(0).ApproveCandidate(&node_manager.PeerParam{PeerPubkey: "01", Address: acc.Address})
+	assert.Nil(t, err)
+	assert.Nil(t, SignTransaction(tx, acc))
+
+	signed, err := types.TransactionFromRawBytes(tx.ToArray())
+	assert.Nil(t, err)
+	assert.Equal(t, tx.Hash(), signed.Hash())
+	assert.Equal(t, 1, len(signed.Sigs))
+	hash := signed.Hash()
+	assert.Nil(t, signature.Verify(acc.PublicKey, hash.ToArray(), signed.Sigs[0].SigData[0]))
+
+	accs := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	pubKeys := make([]keypair.PublicKey, 0, len(accs))
+	for _, acc := range accs {
+		pubKeys = append(pubKeys, acc.PublicKey)
+	}
+	tx, err = NewNativeBuilder(0).CommitDpos()
+	assert.Nil(t, err)
+	for _, acc := range accs[:2] {
+		assert.Nil(t, MultiSignTransaction(tx, 2, pubKeys, acc))
+	}
+	assert.Equal(t, 1, len(tx.Sigs))
+	hash = tx.Hash()
+	assert.Nil(t, signature.VerifyMultiSignature(hash.ToArray(), pubKeys, 2, tx.Sigs[0].SigData))
+}
@@ -0,0 +1,303 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/polynetwork/poly/common"
+)
+
+//the params of the native methods are plain structs of integers, strings, bytes and addresses,
+//they are converted from and to json with bytes in hex and addresses in base58 or hex
+
+var (
+	addressType = reflect.TypeOf(common.Address{})
+	uint256Type = reflect.TypeOf(common.Uint256{})
+)
+
+//ParamFromJSON fills param, a pointer to the param of a native method, by the json object.
+//Bytes are in hex, and addresses are in base58 or hex
+func ParamFromJSON(data []byte, param interface{}) error {
+	v := reflect.ValueOf(param)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("param %T is not a pointer", param)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("invalid json: %s", err)
+	}
+	return setParamValue(v.Elem(), value, "")
+}
+
+//ParamToJSON returns the json value of param, the reverse of ParamFromJSON
+func ParamToJSON(param interface{}) interface{} {
+	return paramValue(reflect.ValueOf(param))
+}
+
+//ParamTemplate returns the json value of param with the nil pointers allocated,
+//to show the fields of param
+func ParamTemplate(param interface{}) interface{} {
+	v := reflect.ValueOf(param)
+	allocParam(v)
+	return paramValue(v)
+}
+
+func setParamValue(v reflect.Value, value interface{}, path string) error {
+	if value == nil {
+		return nil
+	}
+	switch v.Type() {
+	case addressType:
+		s, ok := jsonString(value)
+		if !ok {
+			return fmt.Errorf("%s: address string expected", path)
+		}
+		addr, err := common.AddressFromBase58(s)
+		if err != nil {
+			addr, err = common.AddressFromHexString(strings.TrimPrefix(s, "0x"))
+		}
+		if err != nil {
+			return fmt.Errorf("%s: invalid address %s", path, s)
+		}
+		v.Set(reflect.ValueOf(addr))
+		return nil
+	case uint256Type:
+		s, ok := jsonString(value)
+		if !ok {
+			return fmt.Errorf("%s: hash string expected", path)
+		}
+		hash, err := common.Uint256FromHexString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return fmt.Errorf("%s: invalid hash %s", path, s)
+		}
+		v.Set(reflect.ValueOf(hash))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setParamValue(v.Elem(), value, path)
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: object expected", path)
+		}
+		for key, fieldValue := range obj {
+			field, ok := paramField(v, key)
+			if !ok {
+				return fmt.Errorf("unknown field %s", joinPath(path, key))
+			}
+			if err := setParamValue(field, fieldValue, joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := jsonString(value)
+			if !ok {
+				return fmt.Errorf("%s: hex string expected", path)
+			}
+			data, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+			if err != nil {
+				return fmt.Errorf("%s: invalid hex %s", path, s)
+			}
+			v.SetBytes(data)
+			return nil
+		}
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: array expected", path)
+		}
+		slice := reflect.MakeSlice(v.Type(), len(arr), len(arr))
+		for i, elem := range arr {
+			if err := setParamValue(slice.Index(i), elem, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.String:
+		s, ok := jsonString(value)
+		if !ok {
+			return fmt.Errorf("%s: string expected", path)
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("%s: bool expected", path)
+		}
+		v.SetBool(b)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s, _ := jsonString(value)
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: invalid unsigned integer %v", path, value)
+		}
+		v.SetUint(n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s, _ := jsonString(value)
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s: invalid integer %v", path, value)
+		}
+		v.SetInt(n)
+	default:
+		return fmt.Errorf("%s: unsupported type %s", path, v.Type())
+	}
+	return nil
+}
+
+//jsonString accepts both string and number, as the values set in command line are not typed
+func jsonString(value interface{}) (string, bool) {
+	switch s := value.(type) {
+	case string:
+		return s, true
+	case json.Number:
+		return s.String(), true
+	}
+	return "", false
+}
+
+func paramField(v reflect.Value, key string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if strings.EqualFold(key, field.Name) || strings.EqualFold(key, jsonName(field)) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+//orderedObject keeps the json fields in the order of struct fields
+type orderedObject struct {
+	keys   []string
+	values []interface{}
+}
+
+func (self *orderedObject) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, key := range self.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		value, err := json.Marshal(self.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func paramValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Type() {
+	case addressType:
+		addr := v.Interface().(common.Address)
+		return addr.ToBase58()
+	case uint256Type:
+		hash := v.Interface().(common.Uint256)
+		return hash.ToHexString()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return paramValue(v.Elem())
+	case reflect.Struct:
+		obj := &orderedObject{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			obj.keys = append(obj.keys, jsonName(field))
+			obj.values = append(obj.values, paramValue(v.Field(i)))
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			return hex.EncodeToString(data)
+		}
+		arr := make([]interface{}, v.Len())
+		for i := range arr {
+			arr[i] = paramValue(v.Index(i))
+		}
+		return arr
+	}
+	return v.Interface()
+}
+
+func allocParam(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() && v.CanSet() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if !v.IsNil() {
+			allocParam(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				allocParam(v.Field(i))
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	ccmcom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamFromJSON(t *testing.T) {
+	addr := common.Address{1, 2, 3}
+	data := `{"address":"` + addr.ToBase58() + `","chainid":"8","Router":2,"Name":"eth","CCMCAddress":"0x0102","ExtraInfo":""}`
+	param := new(side_chain_manager.RegisterSideChainParam)
+	assert.Nil(t, ParamFromJSON([]byte(data), param))
+	assert.Equal(t, &side_chain_manager.RegisterSideChainParam{
+		Address:     addr,
+		ChainId:     8,
+		Router:      2,
+		Name:        "eth",
+		CCMCAddress: []byte{1, 2},
+		ExtraInfo:   []byte{},
+	}, param)
+
+	//round trip
+	out, err := json.Marshal(ParamToJSON(param))
+	assert.Nil(t, err)
+	decoded := new(side_chain_manager.RegisterSideChainParam)
+	assert.Nil(t, ParamFromJSON(out, decoded))
+	assert.Equal(t, param, decoded)
+
+	//nested pointer, hex address and json tags
+	config := new(node_manager.UpdateConfigParam)
+	assert.Nil(t, ParamFromJSON([]byte(`{"Configuration":{"BlockMsgDelay":10000}}`), config))
+	assert.Equal(t, uint32(10000), config.Configuration.BlockMsgDelay)
+	entrance := new(ccmcom.EntranceParam)
+	assert.Nil(t, ParamFromJSON([]byte(`{"sourceChainId":2,"relayerAddress":"aabb"}`), entrance))
+	assert.Equal(t, uint64(2), entrance.SourceChainID)
+	assert.Equal(t, []byte{0xaa, 0xbb}, entrance.RelayerAddress)
+	peer := new(node_manager.PeerParam)
+	assert.Nil(t, ParamFromJSON([]byte(`{"Address":"`+addr.ToHexString()+`"}`), peer))
+	assert.Equal(t, addr, peer.Address)
+
+	assert.NotNil(t, ParamFromJSON([]byte(`{"Unknown":1}`), new(node_manager.PeerParam)))
+	assert.NotNil(t, ParamFromJSON([]byte(`{"ChainId":-1}`), new(side_chain_manager.RegisterSideChainParam)))
+	assert.NotNil(t, ParamFromJSON([]byte(`{"CCMCAddress":"xyz"}`), new(side_chain_manager.RegisterSideChainParam)))
+}
+
+func TestParamTemplate(t *testing.T) {
+	out, err := json.Marshal(ParamTemplate(new(node_manager.UpdateConfigParam)))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Configuration":{"BlockMsgDelay":0,"HashMsgDelay":0,"PeerHandshakeTimeout":0,"MaxBlockChangeView":0}}`, string(out))
+}
+
+func TestNativeMethods(t *testing.T) {
+	for contract, methods := range NativeMethods {
+		assert.NotEqual(t, common.ADDRESS_EMPTY, nutils.NativeContracts[contract])
+		for name, method := range methods {
+			assert.Equal(t, method, GetNativeMethod(method.Address, name))
+		}
+	}
+	method := GetNativeMethod(nutils.SideChainManagerContractAddress, side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN)
+	assert.NotNil(t, method)
+	assert.IsType(t, new(side_chain_manager.ChainidParam), method.NewParam())
+	assert.Nil(t, GetNativeMethod(nutils.NodeManagerContractAddress, side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN))
+
+	tx, err := NewNativeBuilder(0).Invoke(method, &side_chain_manager.ChainidParam{Chainid: 3})
+	assert.Nil(t, err)
+	invoke := invokeParam(t, tx)
+	assert.Equal(t, side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN, invoke.Method)
+	_, err = NewNativeBuilder(0).Invoke(NativeMethods["node_manager"][node_manager.COMMIT_DPOS], method.NewParam())
+	assert.NotNil(t, err)
+}
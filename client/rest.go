@@ -0,0 +1,375 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	berr "github.com/polynetwork/poly/http/base/error"
+)
+
+const REST_VERSION = "1.0.0"
+
+//RestError is the error of a failed restful request
+type RestError struct {
+	Action string
+	Code   int64
+	Desc   string
+	Result json.RawMessage
+}
+
+func (self *RestError) Error() string {
+	return fmt.Sprintf("rest %s error %d: %s, %s", self.Action, self.Code, self.Desc, string(self.Result))
+}
+
+type restResponse struct {
+	Action  string          `json:"Action"`
+	Result  json.RawMessage `json:"Result"`
+	Error   int64           `json:"Error"`
+	Desc    string          `json:"Desc"`
+	Version string          `json:"Version"`
+}
+
+type restRequest struct {
+	Action  string `json:"Action"`
+	Version string `json:"Version"`
+	Data    string `json:"Data"`
+}
+
+//BlockTransactions is the hashes of the transactions in a block
+type BlockTransactions struct {
+	Hash         string
+	Height       uint32
+	Transactions []string
+}
+
+//RestClient is a client of the restful api of a poly node
+type RestClient struct {
+	addr      string
+	transport *transport
+}
+
+//NewRestClient returns a client of the restful api at addr, e.g. http://localhost:20334
+func NewRestClient(addr string, opts ...Option) *RestClient {
+	return &RestClient{
+		addr:      strings.TrimRight(addr, "/"),
+		transport: newTransport(newOptions(opts)),
+	}
+}
+
+func (self *RestClient) get(result interface{}, path string) error {
+	data, err := self.transport.do("GET", self.addr+path, nil)
+	if err != nil {
+		return err
+	}
+	return decodeRest(data, result)
+}
+
+func (self *RestClient) post(result interface{}, path, action, data string) error {
+	body, err := json.Marshal(&restRequest{Action: action, Version: REST_VERSION, Data: data})
+	if err != nil {
+		return fmt.Errorf("json.Marshal request error: %s", err)
+	}
+	rsp, err := self.transport.do("POST", self.addr+path, body)
+	if err != nil {
+		return err
+	}
+	return decodeRest(rsp, result)
+}
+
+//decodeRest decodes the result of a restful response, an empty string result is left as not found
+func decodeRest(data []byte, result interface{}) error {
+	resp := &restResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("json.Unmarshal response: %s error: %s", data, err)
+	}
+	if resp.Error != berr.SUCCESS {
+		return &RestError{Action: resp.Action, Code: resp.Error, Desc: resp.Desc, Result: resp.Result}
+	}
+	if result == nil || len(resp.Result) == 0 || string(resp.Result) == `""` {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("json.Unmarshal result: %s error: %s", resp.Result, err)
+	}
+	return nil
+}
+
+func (self *RestClient) getHash(path string) (common.Uint256, error) {
+	var str string
+	if err := self.get(&str, path); err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	return common.Uint256FromHexString(str)
+}
+
+func (self *RestClient) getBytes(path string) ([]byte, error) {
+	var str string
+	if err := self.get(&str, path); err != nil {
+		return nil, err
+	}
+	if str == "" {
+		return nil, nil
+	}
+	return common.HexToBytes(str)
+}
+
+//GetConnectionCount returns the number of peers of the node
+func (self *RestClient) GetConnectionCount() (uint32, error) {
+	var count uint32
+	err := self.get(&count, "/api/v1/node/connectioncount")
+	return count, err
+}
+
+//GetCurrentBlockHeight returns the current height
+func (self *RestClient) GetCurrentBlockHeight() (uint32, error) {
+	var height uint32
+	err := self.get(&height, "/api/v1/block/height")
+	return height, err
+}
+
+//GetBlockHash returns the hash of the block at height
+func (self *RestClient) GetBlockHash(height uint32) (common.Uint256, error) {
+	return self.getHash(fmt.Sprintf("/api/v1/block/hash/%d", height))
+}
+
+//GetBlockByHeight returns the block at height
+func (self *RestClient) GetBlockByHeight(height uint32) (*types.Block, error) {
+	raw, err := self.getBytes(fmt.Sprintf("/api/v1/block/details/height/%d?raw=1", height))
+	if err != nil {
+		return nil, err
+	}
+	return types.BlockFromRawBytes(raw)
+}
+
+//GetBlockByHash returns the block of hash
+func (self *RestClient) GetBlockByHash(hash common.Uint256) (*types.Block, error) {
+	raw, err := self.getBytes(fmt.Sprintf("/api/v1/block/details/hash/%s?raw=1", hash.ToHexString()))
+	if err != nil {
+		return nil, err
+	}
+	return types.BlockFromRawBytes(raw)
+}
+
+//GetBlockInfoByHeight returns the json form of the block at height
+func (self *RestClient) GetBlockInfoByHeight(height uint32) (*bcomn.BlockInfo, error) {
+	info := &bcomn.BlockInfo{}
+	if err := self.get(info, fmt.Sprintf("/api/v1/block/details/height/%d", height)); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//GetBlockInfoByHash returns the json form of the block of hash
+func (self *RestClient) GetBlockInfoByHash(hash common.Uint256) (*bcomn.BlockInfo, error) {
+	info := &bcomn.BlockInfo{}
+	if err := self.get(info, "/api/v1/block/details/hash/"+hash.ToHexString()); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//GetBlockTxsByHeight returns the hashes of the transactions in the block at height
+func (self *RestClient) GetBlockTxsByHeight(height uint32) (*BlockTransactions, error) {
+	txs := &BlockTransactions{}
+	if err := self.get(txs, fmt.Sprintf("/api/v1/block/transactions/height/%d", height)); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+//GetBlockHeightByTxHash returns the height of the block including the transaction
+func (self *RestClient) GetBlockHeightByTxHash(hash common.Uint256) (uint32, error) {
+	var height uint32
+	err := self.get(&height, "/api/v1/block/height/txhash/"+hash.ToHexString())
+	return height, err
+}
+
+//GetRawTransaction returns the transaction of hash
+func (self *RestClient) GetRawTransaction(hash common.Uint256) (*types.Transaction, error) {
+	raw, err := self.getBytes(fmt.Sprintf("/api/v1/transaction/%s?raw=1", hash.ToHexString()))
+	if err != nil {
+		return nil, err
+	}
+	return types.TransactionFromRawBytes(raw)
+}
+
+//GetTransactionInfo returns the json form of the transaction of hash
+func (self *RestClient) GetTransactionInfo(hash common.Uint256) (*bcomn.Transactions, error) {
+	info := &bcomn.Transactions{}
+	if err := self.get(info, "/api/v1/transaction/"+hash.ToHexString()); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//SendRawTransaction sends a signed transaction to the node and returns its hash
+func (self *RestClient) SendRawTransaction(tx *types.Transaction) (common.Uint256, error) {
+	var str string
+	if err := self.post(&str, "/api/v1/transaction", "sendrawtransaction", common.ToHexString(tx.ToArray())); err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	return common.Uint256FromHexString(str)
+}
+
+//PreExecTransaction executes the transaction on the current state of the node without sending it
+func (self *RestClient) PreExecTransaction(tx *types.Transaction) (*bcomn.PreExecuteResult, error) {
+	result := &bcomn.PreExecuteResult{}
+	err := self.post(result, "/api/v1/transaction?preExec=1", "sendrawtransaction", common.ToHexString(tx.ToArray()))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetStorage returns the value of key in the storage of contract, nil if not found
+func (self *RestClient) GetStorage(contract common.Address, key []byte) ([]byte, error) {
+	return self.getBytes(fmt.Sprintf("/api/v1/storage/%s/%s", contract.ToHexString(), common.ToHexString(key)))
+}
+
+//GetSmartContractEvent returns the events of the transaction of hash, nil if not found
+func (self *RestClient) GetSmartContractEvent(hash common.Uint256) (*bcomn.ExecuteNotify, error) {
+	var notify *bcomn.ExecuteNotify
+	err := self.get(&notify, "/api/v1/smartcode/event/txhash/"+hash.ToHexString())
+	return notify, err
+}
+
+//GetSmartContractEventsByHeight returns the events of the transactions at height
+func (self *RestClient) GetSmartContractEventsByHeight(height uint32) ([]*bcomn.ExecuteNotify, error) {
+	var notifies []*bcomn.ExecuteNotify
+	err := self.get(&notifies, fmt.Sprintf("/api/v1/smartcode/event/transactions/%d", height))
+	return notifies, err
+}
+
+//GetMerkleProof returns the proof of the block hash at height in the block root at rootHeight
+func (self *RestClient) GetMerkleProof(height, rootHeight uint32) ([]byte, error) {
+	proof := &bcomn.MerkleProof{}
+	if err := self.get(proof, fmt.Sprintf("/api/v1/merkleproof/%d/%d", height, rootHeight)); err != nil {
+		return nil, err
+	}
+	return common.HexToBytes(proof.AuditPath)
+}
+
+//GetMemPoolTxCount returns the number of verified and unverified transactions in the mempool
+func (self *RestClient) GetMemPoolTxCount() ([]uint32, error) {
+	count := make([]uint32, 0, 2)
+	err := self.get(&count, "/api/v1/mempool/txcount")
+	return count, err
+}
+
+//GetMemPoolTxState returns the verification state of a transaction in the mempool
+func (self *RestClient) GetMemPoolTxState(hash common.Uint256) (*bcomn.TXNEntryInfo, error) {
+	info := &bcomn.TXNEntryInfo{}
+	if err := self.get(info, "/api/v1/mempool/txstate/"+hash.ToHexString()); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//GetVersion returns the version of the node
+func (self *RestClient) GetVersion() (string, error) {
+	var version string
+	err := self.get(&version, "/api/v1/version")
+	return version, err
+}
+
+//GetNetworkId returns the network id of the node
+func (self *RestClient) GetNetworkId() (uint32, error) {
+	var id uint32
+	err := self.get(&id, "/api/v1/networkid")
+	return id, err
+}
+
+//GetSideChains returns the registered side chains
+func (self *RestClient) GetSideChains() ([]*bcomn.SideChainInfo, error) {
+	var sideChains []*bcomn.SideChainInfo
+	err := self.get(&sideChains, "/api/v1/sidechains")
+	return sideChains, err
+}
+
+//GetSideChain returns the registered side chain of chainId, nil if not registered
+func (self *RestClient) GetSideChain(chainId uint64) (*bcomn.SideChainInfo, error) {
+	var sideChain *bcomn.SideChainInfo
+	err := self.get(&sideChain, fmt.Sprintf("/api/v1/sidechain/%d", chainId))
+	return sideChain, err
+}
+
+//GetProposals returns the open governance proposals
+func (self *RestClient) GetProposals() ([]*bcomn.ProposalInfo, error) {
+	var proposals []*bcomn.ProposalInfo
+	err := self.get(&proposals, "/api/v1/proposals")
+	return proposals, err
+}
+
+//GetProposal returns the open governance proposal of id, nil if not found
+func (self *RestClient) GetProposal(id uint64) (*bcomn.ProposalInfo, error) {
+	var proposal *bcomn.ProposalInfo
+	err := self.get(&proposal, fmt.Sprintf("/api/v1/proposal/%d", id))
+	return proposal, err
+}
+
+//GetRelayers returns the addresses of the registered relayers
+func (self *RestClient) GetRelayers() ([]string, error) {
+	var relayers []string
+	err := self.get(&relayers, "/api/v1/relayers")
+	return relayers, err
+}
+
+//GetPeerPool returns the consensus peer pool of the current governance view
+func (self *RestClient) GetPeerPool() ([]*bcomn.PeerPoolItemInfo, error) {
+	var peers []*bcomn.PeerPoolItemInfo
+	err := self.get(&peers, "/api/v1/peerpool")
+	return peers, err
+}
+
+//GetGovernanceView returns the current governance view
+func (self *RestClient) GetGovernanceView() (*bcomn.GovernanceViewInfo, error) {
+	view := &bcomn.GovernanceViewInfo{}
+	if err := self.get(view, "/api/v1/governanceview"); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+//GetBlackedChains returns the ids of the blacked chains
+func (self *RestClient) GetBlackedChains() ([]uint64, error) {
+	var chainIds []uint64
+	err := self.get(&chainIds, "/api/v1/blackedchains")
+	return chainIds, err
+}
+
+//GetHeaderSyncTips returns the height of the latest synced header of each side chain
+func (self *RestClient) GetHeaderSyncTips() ([]*bcomn.HeaderSyncTip, error) {
+	var tips []*bcomn.HeaderSyncTip
+	err := self.get(&tips, "/api/v1/headersync/tips")
+	return tips, err
+}
+
+//GetHeaderSyncTip returns the height of the latest synced header of the side chain of chainId
+func (self *RestClient) GetHeaderSyncTip(chainId uint64) (uint64, error) {
+	tip := &bcomn.HeaderSyncTip{}
+	if err := self.get(tip, fmt.Sprintf("/api/v1/headersync/tip/%d", chainId)); err != nil {
+		return 0, err
+	}
+	return tip.Height, nil
+}
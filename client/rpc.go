@@ -0,0 +1,498 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/consensus/vbft"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/http/base/api"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+)
+
+const JSON_RPC_VERSION = "2.0"
+
+//RpcError is the error object of a failed JSON-RPC request
+type RpcError struct {
+	Code    int64           `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (self *RpcError) Error() string {
+	if len(self.Data) == 0 {
+		return fmt.Sprintf("rpc error %d: %s", self.Code, self.Message)
+	}
+	return fmt.Sprintf("rpc error %d: %s, %s", self.Code, self.Message, string(self.Data))
+}
+
+//BatchElem is one request of a batch, Result should be a pointer to decode the result into,
+//Error is set when the request fails
+type BatchElem struct {
+	Method string
+	Params []interface{}
+	Result interface{}
+	Error  error
+}
+
+type rpcRequest struct {
+	Version string        `json:"jsonrpc"`
+	Id      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Version string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *RpcError       `json:"error"`
+}
+
+//RpcClient is a client of the JSON-RPC api of a poly node
+type RpcClient struct {
+	addr         string
+	transport    *transport
+	maxBatchSize int
+	id           uint64
+}
+
+//NewRpcClient returns a client of the JSON-RPC api at addr, e.g. http://localhost:20336
+func NewRpcClient(addr string, opts ...Option) *RpcClient {
+	o := newOptions(opts)
+	return &RpcClient{
+		addr:         addr,
+		transport:    newTransport(o),
+		maxBatchSize: o.maxBatchSize,
+	}
+}
+
+func (self *RpcClient) nextId() uint64 {
+	return atomic.AddUint64(&self.id, 1)
+}
+
+//Call invokes method with by-position params and decodes its result into result if not nil
+func (self *RpcClient) Call(result interface{}, method string, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	req := &rpcRequest{Version: JSON_RPC_VERSION, Id: self.nextId(), Method: method, Params: params}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("json.Marshal request error: %s", err)
+	}
+	data, err := self.transport.do("POST", self.addr, body)
+	if err != nil {
+		return err
+	}
+	resp := &rpcResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return fmt.Errorf("json.Unmarshal response: %s error: %s", data, err)
+	}
+	return resp.decode(result)
+}
+
+//BatchCall sends the requests in batches of at most the max batch size, the error of each
+//request is set to its element, the returned error is only about the batches themselves
+func (self *RpcClient) BatchCall(batch []*BatchElem) error {
+	for start := 0; start < len(batch); start += self.maxBatchSize {
+		end := start + self.maxBatchSize
+		if end > len(batch) {
+			end = len(batch)
+		}
+		if err := self.batchCall(batch[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *RpcClient) batchCall(batch []*BatchElem) error {
+	reqs := make([]*rpcRequest, 0, len(batch))
+	elems := make(map[uint64]*BatchElem, len(batch))
+	for _, elem := range batch {
+		params := elem.Params
+		if params == nil {
+			params = []interface{}{}
+		}
+		req := &rpcRequest{Version: JSON_RPC_VERSION, Id: self.nextId(), Method: elem.Method, Params: params}
+		reqs = append(reqs, req)
+		elems[req.Id] = elem
+	}
+	body, err := json.Marshal(reqs)
+	if err != nil {
+		return fmt.Errorf("json.Marshal batch error: %s", err)
+	}
+	data, err := self.transport.do("POST", self.addr, body)
+	if err != nil {
+		return err
+	}
+	data = bytes.TrimSpace(data)
+	//a rejected batch is answered with a single error
+	if len(data) > 0 && data[0] != '[' {
+		resp := &rpcResponse{}
+		if err := json.Unmarshal(data, resp); err != nil {
+			return fmt.Errorf("json.Unmarshal response: %s error: %s", data, err)
+		}
+		return resp.decode(nil)
+	}
+	resps := make([]*rpcResponse, 0, len(batch))
+	if err := json.Unmarshal(data, &resps); err != nil {
+		return fmt.Errorf("json.Unmarshal batch response: %s error: %s", data, err)
+	}
+	for _, resp := range resps {
+		id, err := strconv.ParseUint(string(resp.Id), 10, 64)
+		if err != nil {
+			continue
+		}
+		elem, ok := elems[id]
+		if !ok {
+			continue
+		}
+		elem.Error = resp.decode(elem.Result)
+		delete(elems, id)
+	}
+	for _, elem := range elems {
+		elem.Error = fmt.Errorf("no response to %s in batch", elem.Method)
+	}
+	return nil
+}
+
+func (self *rpcResponse) decode(result interface{}) error {
+	if self.Error != nil {
+		return self.Error
+	}
+	if result == nil || len(self.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(self.Result, result); err != nil {
+		return fmt.Errorf("json.Unmarshal result: %s error: %s", self.Result, err)
+	}
+	return nil
+}
+
+func (self *RpcClient) callHash(method string, params ...interface{}) (common.Uint256, error) {
+	var str string
+	if err := self.Call(&str, method, params...); err != nil {
+		return common.UINT256_EMPTY, err
+	}
+	return common.Uint256FromHexString(str)
+}
+
+func (self *RpcClient) callBytes(method string, params ...interface{}) ([]byte, error) {
+	var str string
+	if err := self.Call(&str, method, params...); err != nil {
+		return nil, err
+	}
+	if str == "" {
+		return nil, nil
+	}
+	return common.HexToBytes(str)
+}
+
+//GetBestBlockHash returns the hash of the current block
+func (self *RpcClient) GetBestBlockHash() (common.Uint256, error) {
+	return self.callHash("getbestblockhash")
+}
+
+//GetBlockCount returns the number of blocks, i.e. the current height plus one
+func (self *RpcClient) GetBlockCount() (uint32, error) {
+	var count uint32
+	err := self.Call(&count, "getblockcount")
+	return count, err
+}
+
+//GetCurrentBlockHeight returns the current height
+func (self *RpcClient) GetCurrentBlockHeight() (uint32, error) {
+	count, err := self.GetBlockCount()
+	if err != nil {
+		return 0, err
+	}
+	return count - 1, nil
+}
+
+//GetBlockHash returns the hash of the block at height
+func (self *RpcClient) GetBlockHash(height uint32) (common.Uint256, error) {
+	return self.callHash("getblockhash", height)
+}
+
+func (self *RpcClient) getBlock(heightOrHash interface{}) (*types.Block, error) {
+	raw, err := self.callBytes("getblock", heightOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return types.BlockFromRawBytes(raw)
+}
+
+//GetBlockByHeight returns the block at height
+func (self *RpcClient) GetBlockByHeight(height uint32) (*types.Block, error) {
+	return self.getBlock(height)
+}
+
+//GetBlockByHash returns the block of hash
+func (self *RpcClient) GetBlockByHash(hash common.Uint256) (*types.Block, error) {
+	return self.getBlock(hash.ToHexString())
+}
+
+//GetBlockInfoByHeight returns the json form of the block at height
+func (self *RpcClient) GetBlockInfoByHeight(height uint32) (*bcomn.BlockInfo, error) {
+	info := &bcomn.BlockInfo{}
+	if err := self.Call(info, "getblock", height, 1); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//GetBlockInfoByHash returns the json form of the block of hash
+func (self *RpcClient) GetBlockInfoByHash(hash common.Uint256) (*bcomn.BlockInfo, error) {
+	info := &bcomn.BlockInfo{}
+	if err := self.Call(info, "getblock", hash.ToHexString(), 1); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//GetHeaderByHeight returns the header at height
+func (self *RpcClient) GetHeaderByHeight(height uint32) (*types.Header, error) {
+	raw, err := self.callBytes("getheaderbyheight", height)
+	if err != nil {
+		return nil, err
+	}
+	return types.HeaderFromRawBytes(raw)
+}
+
+//GetBlockTxsByHeight returns the hashes of the transactions in the block at height
+func (self *RpcClient) GetBlockTxsByHeight(height uint32) (*BlockTransactions, error) {
+	txs := &BlockTransactions{}
+	if err := self.Call(txs, "getblocktxsbyheight", height); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+//GetBlockHeightByTxHash returns the height of the block including the transaction
+func (self *RpcClient) GetBlockHeightByTxHash(hash common.Uint256) (uint32, error) {
+	var height uint32
+	err := self.Call(&height, "getblockheightbytxhash", hash.ToHexString())
+	return height, err
+}
+
+//GetLatestBlockMsgsSnap returns the consensus messages of the latest block
+func (self *RpcClient) GetLatestBlockMsgsSnap() (*vbft.LatestBlockMsgsSnap, error) {
+	snap := &vbft.LatestBlockMsgsSnap{}
+	if err := self.Call(snap, "getlatestblockmsgssnap"); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+//GetConsensusTrace returns the trace of the consensus round of height
+func (self *RpcClient) GetConsensusTrace(height uint32) (*vbft.RoundTrace, error) {
+	trace := &vbft.RoundTrace{}
+	if err := self.Call(trace, "getconsensustrace", height); err != nil {
+		return nil, err
+	}
+	return trace, nil
+}
+
+//GetConnectionCount returns the number of peers of the node
+func (self *RpcClient) GetConnectionCount() (uint32, error) {
+	var count uint32
+	err := self.Call(&count, "getconnectioncount")
+	return count, err
+}
+
+//GetRawTransaction returns the transaction of hash
+func (self *RpcClient) GetRawTransaction(hash common.Uint256) (*types.Transaction, error) {
+	raw, err := self.callBytes("getrawtransaction", hash.ToHexString())
+	if err != nil {
+		return nil, err
+	}
+	return types.TransactionFromRawBytes(raw)
+}
+
+//GetTransactionInfo returns the json form of the transaction of hash
+func (self *RpcClient) GetTransactionInfo(hash common.Uint256) (*bcomn.Transactions, error) {
+	info := &bcomn.Transactions{}
+	if err := self.Call(info, "getrawtransaction", hash.ToHexString(), 1); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//SendRawTransaction sends a signed transaction to the node and returns its hash
+func (self *RpcClient) SendRawTransaction(tx *types.Transaction) (common.Uint256, error) {
+	return self.callHash("sendrawtransaction", common.ToHexString(tx.ToArray()))
+}
+
+//PreExecTransaction executes the transaction on the current state of the node without sending it
+func (self *RpcClient) PreExecTransaction(tx *types.Transaction) (*bcomn.PreExecuteResult, error) {
+	result := &bcomn.PreExecuteResult{}
+	if err := self.Call(result, "sendrawtransaction", common.ToHexString(tx.ToArray()), 1); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//CallNative runs method of the native contract at contract read-only against current state,
+//a failed method is reported in the Error of the result rather than as an error
+func (self *RpcClient) CallNative(contract common.Address, method string, args []byte) (*bcomn.CallNativeResult, error) {
+	result := &bcomn.CallNativeResult{}
+	if err := self.Call(result, "callnative", contract.ToHexString(), method, common.ToHexString(args)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetStorage returns the value of key in the storage of contract, nil if not found
+func (self *RpcClient) GetStorage(contract common.Address, key []byte) ([]byte, error) {
+	return self.callBytes("getstorage", contract.ToHexString(), common.ToHexString(key))
+}
+
+//GetVersion returns the version of the node
+func (self *RpcClient) GetVersion() (string, error) {
+	var version string
+	err := self.Call(&version, "getversion")
+	return version, err
+}
+
+//GetNetworkId returns the network id of the node
+func (self *RpcClient) GetNetworkId() (uint32, error) {
+	var id uint32
+	err := self.Call(&id, "getnetworkid")
+	return id, err
+}
+
+//GetMemPoolTxCount returns the number of verified and unverified transactions in the mempool
+func (self *RpcClient) GetMemPoolTxCount() ([]uint32, error) {
+	count := make([]uint32, 0, 2)
+	err := self.Call(&count, "getmempooltxcount")
+	return count, err
+}
+
+//GetMemPoolTxState returns the verification state of a transaction in the mempool
+func (self *RpcClient) GetMemPoolTxState(hash common.Uint256) (*bcomn.TXNEntryInfo, error) {
+	info := &bcomn.TXNEntryInfo{}
+	if err := self.Call(info, "getmempooltxstate", hash.ToHexString()); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//GetSmartContractEvent returns the events of the transaction of hash, nil if not found
+func (self *RpcClient) GetSmartContractEvent(hash common.Uint256) (*bcomn.ExecuteNotify, error) {
+	var notify *bcomn.ExecuteNotify
+	err := self.Call(&notify, "getsmartcodeevent", hash.ToHexString())
+	return notify, err
+}
+
+//GetSmartContractEventsByHeight returns the events of the transactions at height
+func (self *RpcClient) GetSmartContractEventsByHeight(height uint32) ([]*bcomn.ExecuteNotify, error) {
+	var notifies []*bcomn.ExecuteNotify
+	err := self.Call(&notifies, "getsmartcodeevent", height)
+	return notifies, err
+}
+
+//GetMerkleProof returns the proof of the block hash at height in the block root at rootHeight
+func (self *RpcClient) GetMerkleProof(height, rootHeight uint32) ([]byte, error) {
+	return self.getProof("getmerkleproof", height, rootHeight)
+}
+
+//GetCrossStatesProof returns the proof of key in the cross states root at height
+func (self *RpcClient) GetCrossStatesProof(height uint32, key []byte) ([]byte, error) {
+	return self.getProof("getcrossstatesproof", height, common.ToHexString(key))
+}
+
+func (self *RpcClient) getProof(method string, params ...interface{}) ([]byte, error) {
+	proof := &bcomn.MerkleProof{}
+	if err := self.Call(proof, method, params...); err != nil {
+		return nil, err
+	}
+	return common.HexToBytes(proof.AuditPath)
+}
+
+//GetStateMerkleRoot returns the state merkle root at height
+func (self *RpcClient) GetStateMerkleRoot(height uint32) (common.Uint256, error) {
+	return self.callHash("getstatemerkleroot", height)
+}
+
+//GetSideChain returns the registered side chain of chainId, nil if it is not registered
+func (self *RpcClient) GetSideChain(chainId uint64) (*bcomn.SideChainInfo, error) {
+	var sideChain *bcomn.SideChainInfo
+	err := self.Call(&sideChain, "getsidechain", chainId)
+	return sideChain, err
+}
+
+//ListSideChains returns all registered side chains
+func (self *RpcClient) ListSideChains() ([]*bcomn.SideChainInfo, error) {
+	var sideChains []*bcomn.SideChainInfo
+	err := self.Call(&sideChains, "listsidechains")
+	return sideChains, err
+}
+
+//GetProposal returns the open governance proposal of id, nil if it is not found
+func (self *RpcClient) GetProposal(id uint64) (*bcomn.ProposalInfo, error) {
+	var proposal *bcomn.ProposalInfo
+	err := self.Call(&proposal, "getproposal", id)
+	return proposal, err
+}
+
+//ListProposals returns all open governance proposals
+func (self *RpcClient) ListProposals() ([]*bcomn.ProposalInfo, error) {
+	var proposals []*bcomn.ProposalInfo
+	err := self.Call(&proposals, "listproposals")
+	return proposals, err
+}
+
+//GetHeaderSyncTip returns the height of the latest header synced from the side chain of chainId
+func (self *RpcClient) GetHeaderSyncTip(chainId uint64) (uint64, error) {
+	tip := &bcomn.HeaderSyncTip{}
+	if err := self.Call(tip, "getheadersynctip", chainId); err != nil {
+		return 0, err
+	}
+	return tip.Height, nil
+}
+
+//Discover returns the methods served by the node with their params and result schemas
+func (self *RpcClient) Discover() (*api.Discovery, error) {
+	discovery := &api.Discovery{}
+	if err := self.Call(discovery, "rpc_discover"); err != nil {
+		return nil, err
+	}
+	return discovery, nil
+}
+
+//GetSideChainHeaderByHeight returns the json of the header synced from the side chain of chainId at height,
+//its layout depends on the chain
+func (self *RpcClient) GetSideChainHeaderByHeight(chainId, height uint64) (json.RawMessage, error) {
+	var header json.RawMessage
+	err := self.Call(&header, "getheaderbyheight", chainId, height)
+	return header, err
+}
+
+//IsRpcError reports whether err is a rpc error with code
+func IsRpcError(err error, code int64) bool {
+	rpcErr, ok := err.(*RpcError)
+	return ok && rpcErr.Code == code
+}
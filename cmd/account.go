@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	s "github.com/ontio/ontology-crypto/signature"
+	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/cmd/common"
 	"github.com/polynetwork/poly/cmd/utils"
 	"github.com/polynetwork/poly/common/config"
@@ -179,6 +180,10 @@ func checkNumber(ctx *cli.Context) int {
 		return 1
 	}
 }
+func checkHDPath(ctx *cli.Context, offset int) string {
+	start := ctx.Uint(utils.GetFlagName(utils.AccountHDIndexFlag))
+	return account.HDAccountPath(ctx.String(utils.GetFlagName(utils.AccountHDPathFlag)), uint32(start)+uint32(offset))
+}
 func checkLabel(ctx *cli.Context) string {
 	if ctx.IsSet(utils.GetFlagName(utils.AccountLabelFlag)) {
 		return ctx.String(utils.GetFlagName(utils.AccountLabelFlag))
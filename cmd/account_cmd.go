@@ -55,9 +55,14 @@ You can use ./Ontology account --help command to view help information of wallet
 					utils.AccountDefaultFlag,
 					utils.AccountLabelFlag,
 					utils.IdentityFlag,
+					utils.AccountDeriveFlag,
+					utils.AccountHDPathFlag,
+					utils.AccountHDIndexFlag,
 					utils.WalletFileFlag,
 				},
 				Description: ` Add a new account to wallet.
+   With --derive, a new BIP-39 mnemonic is generated and the accounts are derived from it by the path
+   --hd-path/<index>, starting from --hd-index. Only ecdsa with P-256 or secp256k1 support derivation.
    Ontology support three type of key: ecdsa, sm2 and ed25519, and support 224、256、384、521 bits length of key in ecdsa, but only support 256 bits length of key in sm2 and ed25519.
    Ontology support multiple signature scheme.
    For ECDSA support SHA224withECDSA、SHA256withECDSA、SHA384withECDSA、SHA512withEdDSA、SHA3-224withECDSA、SHA3-256withECDSA、SHA3-384withECDSA、SHA3-512withECDSA、RIPEMD160withECDSA;
@@ -132,8 +137,15 @@ You can use ./Ontology account --help command to view help information of wallet
 					utils.WalletFileFlag,
 					utils.AccountSourceFileFlag,
 					utils.AccountWIFFlag,
+					utils.AccountMnemonicFlag,
+					utils.AccountKeylenFlag,
+					utils.AccountHDPathFlag,
+					utils.AccountHDIndexFlag,
+					utils.AccountQuantityFlag,
+					utils.AccountLabelFlag,
 				},
-				Description: "Import accounts of wallet to another. If not specific accounts in args, all account in source will be import",
+				Description: "Import accounts of wallet to another. If not specific accounts in args, all account in source will be import.\n" +
+					"   With --mnemonic, the accounts derived from the input BIP-39 mnemonic are imported.",
 			},
 			{
 				Action:    accountExport,
@@ -169,10 +181,30 @@ func accountCreate(ctx *cli.Context) error {
 	optionFile := checkFileName(ctx)
 	optionNumber := checkNumber(ctx)
 	optionLabel := checkLabel(ctx)
-	pass, _ := password.GetConfirmedPassword()
+	optionDerive := ctx.Bool(utils.GetFlagName(utils.AccountDeriveFlag))
 	keyType := keyTypeMap[optionType].code
 	curve := curveMap[optionCurve].code
 	scheme := schemeMap[optionScheme].code
+	var seed []byte
+	if optionDerive {
+		if ctx.Bool(utils.IdentityFlag.Name) {
+			return fmt.Errorf("cannot derive ONT ID")
+		}
+		if keyType != keypair.PK_ECDSA || !account.IsHDCurveSupported(curve) {
+			return fmt.Errorf("key type %s with curve %s does not support derivation", optionType, optionCurve)
+		}
+		mnemonic, err := account.NewMnemonic()
+		if err != nil {
+			return err
+		}
+		seed, err = account.NewSeedFromMnemonic(mnemonic, "")
+		if err != nil {
+			return err
+		}
+		PrintWarnMsg("Please write down the mnemonic and keep it safe, it is the only way to recover the derived accounts:")
+		PrintInfoMsg("%s", mnemonic)
+	}
+	pass, _ := password.GetConfirmedPassword()
 	wallet, err := account.Open(optionFile)
 	if err != nil {
 		return fmt.Errorf("open wallet error:%s", err)
@@ -199,7 +231,14 @@ func accountCreate(ctx *cli.Context) error {
 		if label != "" && optionNumber > 1 {
 			label = fmt.Sprintf("%s%d", label, i+1)
 		}
-		acc, err := wallet.NewAccount(label, keyType, curve, scheme, pass)
+		var acc *account.Account
+		path := ""
+		if optionDerive {
+			path = checkHDPath(ctx, i)
+			acc, err = wallet.NewDerivedAccount(label, seed, path, curve, scheme, pass)
+		} else {
+			acc, err = wallet.NewAccount(label, keyType, curve, scheme, pass)
+		}
 		if err != nil {
 			return fmt.Errorf("new account error:%s", err)
 		}
@@ -208,6 +247,9 @@ func accountCreate(ctx *cli.Context) error {
 		PrintInfoMsg("Address:%s", acc.Address.ToBase58())
 		PrintInfoMsg("Public key:%s", hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)))
 		PrintInfoMsg("Signature scheme:%s", acc.SigScheme.Name())
+		if path != "" {
+			PrintInfoMsg("Derivation path:%s", path)
+		}
 	}
 
 	PrintInfoMsg("Create account successfully.")
@@ -264,6 +306,9 @@ func accountList(ctx *cli.Context) error {
 		PrintInfoMsg("	Curve: %v", accMeta.Curve)
 		PrintInfoMsg("	Key length: %v bits", len(accMeta.Key)*8)
 		PrintInfoMsg("	Public key: %v", accMeta.PubKey)
+		if accMeta.Path != "" {
+			PrintInfoMsg("	Derivation path: %v", accMeta.Path)
+		}
 		PrintInfoMsg("	Signature scheme: %v\n", accMeta.SigSch)
 	}
 	return nil
@@ -387,6 +432,9 @@ func accountDelete(ctx *cli.Context) error {
 }
 
 func accountImport(ctx *cli.Context) error {
+	if ctx.Bool(utils.GetFlagName(utils.AccountMnemonicFlag)) {
+		return accountImportMnemonic(ctx)
+	}
 	source := ctx.String(utils.GetFlagName(utils.AccountSourceFileFlag))
 	if source == "" {
 		PrintErrorMsg("Missing source wallet path argument to import.")
@@ -526,6 +574,74 @@ func accountImport(ctx *cli.Context) error {
 	return nil
 }
 
+func accountImportMnemonic(ctx *cli.Context) error {
+	optionCurve := ctx.String(utils.GetFlagName(utils.AccountKeylenFlag))
+	curve, ok := curveMap[optionCurve]
+	if !ok || !account.IsHDCurveSupported(curve.code) {
+		return fmt.Errorf("curve %s does not support derivation", optionCurve)
+	}
+	optionFile := checkFileName(ctx)
+	optionNumber := checkNumber(ctx)
+	optionLabel := checkLabel(ctx)
+	wallet, err := account.Open(optionFile)
+	if err != nil {
+		return fmt.Errorf("open wallet error:%s", err)
+	}
+
+	mnemonic, err := password.GetMnemonic()
+	if err != nil {
+		return err
+	}
+	seed, err := account.NewSeedFromMnemonic(string(mnemonic), "")
+	common.ClearPasswd(mnemonic)
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Please input a password to encrypt the imported key(s)")
+	pwd, err := password.GetConfirmedPassword()
+	if err != nil {
+		return err
+	}
+	defer common.ClearPasswd(pwd)
+
+	succ := 0
+	fail := 0
+	skip := 0
+	for i := 0; i < optionNumber; i++ {
+		label := optionLabel
+		if label != "" && optionNumber > 1 {
+			label = fmt.Sprintf("%s%d", label, i+1)
+		}
+		path := checkHDPath(ctx, i)
+		pri, err := account.DerivePrivateKey(seed, path, curve.code)
+		if err != nil {
+			return err
+		}
+		addr := types.AddressFromPubKey(pri.Public())
+		b58addr := addr.ToBase58()
+		if wallet.GetAccountMetadataByAddress(b58addr) != nil {
+			PrintWarnMsg("Account %s (path: %s) already exists.", b58addr, path)
+			skip++
+			continue
+		}
+		_, err = wallet.NewDerivedAccount(label, seed, path, curve.code, signature.SHA256withECDSA, pwd)
+		if err != nil {
+			PrintWarnMsg("Import account: %s (path: %s) failed, %s", b58addr, path, err)
+			fail++
+			continue
+		}
+		succ++
+		PrintInfoMsg("Import account: %s (path: %s) successfully.", b58addr, path)
+	}
+
+	PrintInfoMsg("Import from mnemonic to %s complete.", optionFile)
+	PrintInfoMsg("Total:\t%d", optionNumber)
+	PrintInfoMsg("Success:%d", succ)
+	PrintInfoMsg("Failed:\t%d", fail)
+	PrintInfoMsg("Skip:\t%d", skip)
+	return nil
+}
+
 func accountExport(ctx *cli.Context) error {
 	if ctx.NArg() <= 0 {
 		PrintErrorMsg("Missing target file argument to export.")
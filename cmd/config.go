@@ -20,10 +20,14 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/polynetwork/poly/cmd/utils"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	tc "github.com/polynetwork/poly/txnpool/common"
 	"github.com/urfave/cli"
 )
 
@@ -39,6 +43,16 @@ func SetOntologyConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
 	setRpcConfig(ctx, cfg.Rpc)
 	setRestfulConfig(ctx, cfg.Restful)
 	setWebSocketConfig(ctx, cfg.Ws)
+	err = setApiAccessConfig(ctx, cfg.Access)
+	if err != nil {
+		return nil, fmt.Errorf("setApiAccessConfig error:%s", err)
+	}
+	err = setTxPoolConfig(ctx, cfg.TxPool)
+	if err != nil {
+		return nil, fmt.Errorf("setTxPoolConfig error:%s", err)
+	}
+	setMetricsConfig(ctx, cfg.Metrics)
+	setGrpcConfig(ctx, cfg.Grpc)
 	if cfg.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
 		cfg.Ws.EnableHttpWs = true
 		cfg.Restful.EnableHttpRestful = true
@@ -110,6 +124,10 @@ func setGenesis(ctx *cli.Context, cfg *config.OntologyConfig) error {
 		if len(cfg.Genesis.VBFT.Peers) < config.VBFT_MIN_NODE_NUM {
 			return fmt.Errorf("VBFT consensus at least need %d peers in config", config.VBFT_MIN_NODE_NUM)
 		}
+	case config.CONSENSUS_TYPE_HOTSTUFF:
+		if cfg.Genesis.HotStuff == nil || len(cfg.Genesis.HotStuff.Peers) < config.HOTSTUFF_MIN_NODE_NUM {
+			return fmt.Errorf("HotStuff consensus at least need %d peers in config", config.HOTSTUFF_MIN_NODE_NUM)
+		}
 	default:
 		return fmt.Errorf("Unknow consensus:%s", cfg.Genesis.ConsensusType)
 	}
@@ -166,6 +184,7 @@ func setRpcConfig(ctx *cli.Context, cfg *config.RpcConfig) {
 	cfg.EnableHttpJsonRpc = !ctx.Bool(utils.GetFlagName(utils.RPCDisabledFlag))
 	cfg.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
 	cfg.HttpLocalPort = ctx.Uint(utils.GetFlagName(utils.RPCLocalProtFlag))
+	cfg.HttpMaxBatchSize = ctx.Uint(utils.GetFlagName(utils.RPCMaxBatchSizeFlag))
 }
 
 func setRestfulConfig(ctx *cli.Context, cfg *config.RestfulConfig) {
@@ -179,6 +198,68 @@ func setWebSocketConfig(ctx *cli.Context, cfg *config.WebSocketConfig) {
 	cfg.HttpWsPort = ctx.Uint(utils.GetFlagName(utils.WsPortFlag))
 }
 
+func setApiAccessConfig(ctx *cli.Context, cfg *config.ApiAccessConfig) error {
+	file := ctx.String(utils.GetFlagName(utils.ApiAccessFileFlag))
+	if file == "" {
+		return nil
+	}
+	if !common.FileExisted(file) {
+		return fmt.Errorf("file %s not exist", file)
+	}
+	return utils.GetJsonObjectFromFile(file, cfg)
+}
+
+func setTxPoolConfig(ctx *cli.Context, cfg *config.TxPoolConfig) error {
+	cfg.MaxTxsPerSigner = ctx.Uint(utils.GetFlagName(utils.TxpoolMaxTxsPerSignerFlag))
+	cfg.MaxSize = ctx.Uint64(utils.GetFlagName(utils.TxpoolMaxSizeFlag)) * 1024 * 1024
+	cfg.EnableJournal = ctx.Bool(utils.GetFlagName(utils.TxpoolJournalFlag))
+	cfg.JournalMaxSize = ctx.Uint64(utils.GetFlagName(utils.TxpoolJournalSizeFlag)) * 1024 * 1024
+	if ctx.IsSet(utils.GetFlagName(utils.TxpoolPrioritiesFlag)) {
+		priorities, err := parseTxPoolPriorities(ctx.String(utils.GetFlagName(utils.TxpoolPrioritiesFlag)))
+		if err != nil {
+			return err
+		}
+		cfg.Priorities = priorities
+	}
+	_, err := tc.NewPrioritizer(cfg.Priorities)
+	return err
+}
+
+//parseTxPoolPriorities parses priorities like "node_manager:3,cross_chain_manager.ImportOuterTransfer:1"
+func parseTxPoolPriorities(str string) ([]*config.TxPoolPriority, error) {
+	priorities := make([]*config.TxPoolPriority, 0)
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		idx := strings.LastIndex(item, ":")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid tx pool priority %s", item)
+		}
+		prio, err := strconv.ParseUint(item[idx+1:], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tx pool priority %s", item)
+		}
+		priority := &config.TxPoolPriority{Contract: item[:idx], Priority: uint8(prio)}
+		if dot := strings.Index(priority.Contract, "."); dot >= 0 {
+			priority.Contract, priority.Method = priority.Contract[:dot], priority.Contract[dot+1:]
+		}
+		priorities = append(priorities, priority)
+	}
+	return priorities, nil
+}
+
+func setMetricsConfig(ctx *cli.Context, cfg *config.MetricsConfig) {
+	cfg.EnableHttpMetrics = ctx.Bool(utils.GetFlagName(utils.MetricsEnabledFlag))
+	cfg.HttpMetricsPort = ctx.Uint(utils.GetFlagName(utils.MetricsPortFlag))
+}
+
+func setGrpcConfig(ctx *cli.Context, cfg *config.GrpcConfig) {
+	cfg.EnableGrpc = ctx.Bool(utils.GetFlagName(utils.GrpcEnabledFlag))
+	cfg.GrpcPort = ctx.Uint(utils.GetFlagName(utils.GrpcPortFlag))
+}
+
 func SetRpcPort(ctx *cli.Context) {
 	if ctx.IsSet(utils.GetFlagName(utils.RPCPortFlag)) {
 		config.DefConfig.Rpc.HttpJsonPort = ctx.Uint(utils.GetFlagName(utils.RPCPortFlag))
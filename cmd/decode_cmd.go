@@ -0,0 +1,280 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/polynetwork/poly/client"
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	httpcom "github.com/polynetwork/poly/http/base/common"
+	ccmcom "github.com/polynetwork/poly/native/service/cross_chain_manager/common"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/urfave/cli"
+)
+
+var DecodeCommand = cli.Command{
+	Name:      "decode",
+	Action:    cli.ShowSubcommandHelp,
+	Usage:     "Decode transactions and cross chain payloads",
+	ArgsUsage: "[arguments...]",
+	Description: `Decode the native invocations of transactions into the params of the native methods,
+   and the cross chain requests made by makeProof into ToMerkleValue.`,
+	Subcommands: []cli.Command{
+		{
+			Action:    decodeTx,
+			Name:      "tx",
+			Usage:     "Decode transaction by raw transaction or hash",
+			ArgsUsage: "<rawtx|txhash>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+			Description: `Decode the transaction and its native invocation. If the transaction is on chain,
+   the cross chain requests of its makeProof events are decoded as well.`,
+		},
+		{
+			Action:    decodeParam,
+			Name:      "param",
+			Usage:     "Decode the args of native method",
+			ArgsUsage: "<contract> <method> <args>",
+			Description: `Decode the serialized args in hex of native method. The contract is the name
+   of the native contract, e.g. cross_chain_manager, or its address in hex.`,
+		},
+		{
+			Action:      decodeMerkleValue,
+			Name:        "merkle",
+			Usage:       "Decode ToMerkleValue",
+			ArgsUsage:   "<value>",
+			Description: "Decode ToMerkleValue in hex, which is the cross chain request proved against the cross states root.",
+		},
+		{
+			Action:    decodeRequest,
+			Name:      "request",
+			Usage:     "Decode the cross chain request stored by makeProof",
+			ArgsUsage: "<key>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+			},
+			Description: "Read the cross chain request by the storage key in hex of makeProof event and decode it.",
+		},
+	},
+}
+
+type decodedTx struct {
+	Hash     string
+	Height   uint32
+	Version  byte
+	TxType   types.TransactionType
+	Nonce    uint32
+	ChainID  uint64
+	Payer    string
+	Sigs     []httpcom.Sig
+	Invoke   *client.DecodedInvoke `json:",omitempty"`
+	Requests []*crossChainRequest  `json:",omitempty"`
+}
+
+type crossChainRequest struct {
+	Key           string
+	ToMerkleValue interface{}
+}
+
+func decodeTx(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing <rawtx|txhash> argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	arg := ctx.Args().First()
+	var txData []byte
+	var err error
+	if len(arg) == common.UINT256_SIZE*2 {
+		txData, err = utils.GetRawTransactionData(arg)
+		if err != nil {
+			return fmt.Errorf("GetRawTransactionData error:%s", err)
+		}
+	} else {
+		txData, err = hex.DecodeString(arg)
+		if err != nil {
+			return fmt.Errorf("RawTx hex decode error:%s", err)
+		}
+	}
+	tx, err := types.TransactionFromRawBytes(txData)
+	if err != nil {
+		return fmt.Errorf("TransactionFromRawBytes error:%s", err)
+	}
+	txHash := tx.Hash()
+	decoded := &decodedTx{
+		Hash:    txHash.ToHexString(),
+		Version: tx.Version,
+		TxType:  tx.TxType,
+		Nonce:   tx.Nonce,
+		ChainID: tx.ChainID,
+		Payer:   tx.Payer.ToBase58(),
+		Sigs:    httpcom.TransArryByteToHexString(tx).Sigs,
+	}
+	if tx.TxType == types.Invoke {
+		decoded.Invoke, err = client.DecodeInvoke(tx)
+		if err != nil {
+			return fmt.Errorf("DecodeInvoke error:%s", err)
+		}
+	}
+	//the tx may be not on chain yet
+	height, err := utils.GetTxHeight(decoded.Hash)
+	if err == nil {
+		decoded.Height = height
+		decoded.Requests, err = makeProofRequests(decoded.Hash)
+		if err != nil {
+			PrintWarnMsg("Cannot decode cross chain requests:%s", err)
+		}
+	}
+	PrintJsonObject(decoded)
+	return nil
+}
+
+func decodeParam(ctx *cli.Context) error {
+	if ctx.NArg() < 3 {
+		PrintErrorMsg("Missing <contract> <method> <args> argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	contract := ctx.Args().Get(0)
+	address, ok := nutils.NativeContracts[contract]
+	if !ok {
+		var err error
+		address, err = common.AddressFromHexString(contract)
+		if err != nil {
+			return fmt.Errorf("invalid contract:%s", contract)
+		}
+	}
+	method := client.GetNativeMethod(address, ctx.Args().Get(1))
+	if method == nil {
+		return fmt.Errorf("unknown method %s of contract %s", ctx.Args().Get(1), contract)
+	}
+	args, err := hex.DecodeString(ctx.Args().Get(2))
+	if err != nil {
+		return fmt.Errorf("args hex decode error:%s", err)
+	}
+	param, err := client.DecodeParam(method, args)
+	if err != nil {
+		return err
+	}
+	PrintJsonObject(client.ParamToJSON(param))
+	return nil
+}
+
+func decodeMerkleValue(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing <value> argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	data, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("value hex decode error:%s", err)
+	}
+	value, err := client.DecodeToMerkleValue(data)
+	if err != nil {
+		return fmt.Errorf("DecodeToMerkleValue error:%s", err)
+	}
+	PrintJsonObject(client.ParamToJSON(value))
+	return nil
+}
+
+func decodeRequest(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing <key> argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	request, err := getCrossChainRequest(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	PrintJsonObject(request)
+	return nil
+}
+
+//makeProofRequests returns the cross chain requests made by the tx according to its makeProof events
+func makeProofRequests(txHash string) ([]*crossChainRequest, error) {
+	data, err := utils.GetSmartContractEventInfo(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("GetSmartContractEvent error:%s", err)
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+	notify := &httpcom.ExecuteNotify{}
+	if err := json.Unmarshal(data, notify); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal ExecuteNotify error:%s", err)
+	}
+	requests := make([]*crossChainRequest, 0)
+	for _, info := range notify.Notify {
+		if info.ContractAddress != nutils.CrossChainManagerContractAddress.ToHexString() {
+			continue
+		}
+		states, ok := info.States.([]interface{})
+		if !ok || len(states) < 6 || states[0] != ccmcom.NOTIFY_MAKE_PROOF {
+			continue
+		}
+		key, ok := states[5].(string)
+		if !ok {
+			continue
+		}
+		request, err := getCrossChainRequest(key)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+//getCrossChainRequest reads the cross chain request by the storage key of makeProof event, the key
+//is prefixed with the address of cross chain manager
+func getCrossChainRequest(key string) (*crossChainRequest, error) {
+	data, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("key hex decode error:%s", err)
+	}
+	contract := nutils.CrossChainManagerContractAddress
+	if len(data) <= common.ADDR_LEN || !bytes.Equal(data[:common.ADDR_LEN], contract[:]) {
+		return nil, fmt.Errorf("key:%s is not a cross chain request", key)
+	}
+	value, err := utils.GetStorage(contract.ToHexString(), hex.EncodeToString(data[common.ADDR_LEN:]))
+	if err != nil {
+		return nil, fmt.Errorf("GetStorage error:%s", err)
+	}
+	if value == nil {
+		return nil, fmt.Errorf("cross chain request of key:%s not found", key)
+	}
+	merkleValue, err := client.DecodeToMerkleValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("DecodeToMerkleValue error:%s", err)
+	}
+	return &crossChainRequest{
+		Key:           key,
+		ToMerkleValue: client.ParamToJSON(merkleValue),
+	}, nil
+}
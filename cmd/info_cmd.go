@@ -85,7 +85,7 @@ var ShowTxCommand = cli.Command{
 	Flags: []cli.Flag{
 		utils.RPCPortFlag,
 	},
-	Description: "Show info of raw transaction.",
+	Description: "Show info of raw transaction. Use the decode tx command to decode the native invocation of the transaction.",
 }
 
 func blockInfo(ctx *cli.Context) error {
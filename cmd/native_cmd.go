@@ -0,0 +1,253 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/client"
+	cmdcom "github.com/polynetwork/poly/cmd/common"
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/urfave/cli"
+)
+
+var NativeCommand = cli.Command{
+	Name:      "native",
+	Action:    cli.ShowSubcommandHelp,
+	Usage:     "Build, sign and send invocations of the native contracts",
+	ArgsUsage: "<contract> <method>",
+	Description: `Build, sign and send invocations of the native contract methods.
+   The params of a method are given in json by --params, or field by field by --param, see the help of the method
+   for its fields. Bytes are in hex, and addresses are in base58 or hex. The Address field of the params is
+   the signer if not specified. With --dry-run the transaction is pre-executed, with --send it is sent to the node.`,
+	Subcommands: nativeContractCommands(),
+}
+
+func nativeContractCommands() []cli.Command {
+	contracts := make([]string, 0, len(client.NativeMethods))
+	for contract := range client.NativeMethods {
+		contracts = append(contracts, contract)
+	}
+	sort.Strings(contracts)
+	commands := make([]cli.Command, 0, len(contracts))
+	for _, contract := range contracts {
+		methods := client.SortedNativeMethods(contract)
+		subcommands := make([]cli.Command, 0, len(methods))
+		for _, method := range methods {
+			subcommands = append(subcommands, nativeMethodCommand(method))
+		}
+		commands = append(commands, cli.Command{
+			Name:        contract,
+			Action:      cli.ShowSubcommandHelp,
+			Usage:       fmt.Sprintf("Invoke the methods of %s", contract),
+			ArgsUsage:   "<method>",
+			Subcommands: subcommands,
+		})
+	}
+	return commands
+}
+
+func nativeMethodCommand(method *client.NativeMethod) cli.Command {
+	description := fmt.Sprintf("Invoke %s of %s, which takes no params.", method.Name, method.Contract)
+	if param := method.NewParam(); param != nil {
+		template, _ := json.Marshal(client.ParamTemplate(param))
+		var out bytes.Buffer
+		json.Indent(&out, template, "   ", "   ")
+		description = fmt.Sprintf("Invoke %s of %s, the params are:\n   %s", method.Name, method.Contract, out.String())
+	}
+	return cli.Command{
+		Name:        method.Name,
+		Usage:       fmt.Sprintf("Invoke %s of %s", method.Name, method.Contract),
+		Description: description,
+		Action: func(ctx *cli.Context) error {
+			return nativeInvoke(ctx, method)
+		},
+		Flags: []cli.Flag{
+			utils.RPCPortFlag,
+			utils.WalletFileFlag,
+			utils.AccountAddressFlag,
+			utils.NativeParamsFlag,
+			utils.NativeParamFlag,
+			utils.NativeArgsFlag,
+			utils.NativeRawFlag,
+			utils.DryRunFlag,
+			utils.SendTxFlag,
+		},
+	}
+}
+
+func nativeInvoke(ctx *cli.Context, method *client.NativeMethod) error {
+	SetRpcPort(ctx)
+	var param interface{}
+	var args []byte
+	var err error
+	if ctx.IsSet(utils.GetFlagName(utils.NativeArgsFlag)) {
+		args, err = hex.DecodeString(strings.TrimPrefix(ctx.String(utils.GetFlagName(utils.NativeArgsFlag)), "0x"))
+		if err != nil {
+			return fmt.Errorf("args hex decode error:%s", err)
+		}
+	} else if param = method.NewParam(); param != nil {
+		if err = parseNativeParam(ctx, param); err != nil {
+			return fmt.Errorf("invalid params of %s: %s", method.Name, err)
+		}
+	}
+
+	raw := ctx.Bool(utils.GetFlagName(utils.NativeRawFlag))
+	var signer *account.Account
+	if !raw {
+		signer, err = cmdcom.GetAccount(ctx)
+		if err != nil {
+			return fmt.Errorf("GetAccount error:%s", err)
+		}
+		if param != nil && setSignerAddress(param, signer.Address) {
+			PrintInfoMsg("Address of params is set to the signer %s", signer.Address.ToBase58())
+		}
+	}
+	if param != nil {
+		PrintInfoMsg("Params of %s:", method.Name)
+		PrintJsonObject(client.ParamToJSON(param))
+	}
+
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return fmt.Errorf("GetNetworkId error:%s", err)
+	}
+	builder := client.NewNativeBuilder(config.GetChainIdByNetId(networkId))
+	var tx *types.Transaction
+	if param == nil && args != nil {
+		tx, err = builder.NewInvokeTx(method.Address, method.Name, args)
+	} else {
+		tx, err = builder.Invoke(method, param)
+	}
+	if err != nil {
+		return err
+	}
+	if raw {
+		PrintInfoMsg("Unsigned RawTx:")
+		PrintInfoMsg(hex.EncodeToString(tx.ToArray()))
+		return nil
+	}
+
+	if err = utils.SignTransaction(signer, tx); err != nil {
+		return fmt.Errorf("SignTransaction error:%s", err)
+	}
+	rawTx := hex.EncodeToString(tx.ToArray())
+	PrintInfoMsg("RawTx:")
+	PrintInfoMsg(rawTx)
+	PrintInfoMsg("")
+
+	if ctx.Bool(utils.GetFlagName(utils.DryRunFlag)) {
+		preResult, err := utils.PrepareSendRawTransaction(rawTx)
+		if err != nil {
+			return err
+		}
+		if preResult.State == 0 {
+			return fmt.Errorf("pre-execute transaction failed. %v", preResult)
+		}
+		PrintInfoMsg("Pre-execute transaction success.")
+		PrintJsonObject(preResult)
+		return nil
+	}
+	if ctx.IsSet(utils.GetFlagName(utils.SendTxFlag)) {
+		txHash, err := utils.SendRawTransactionData(rawTx)
+		if err != nil {
+			return err
+		}
+		PrintInfoMsg("Send transaction success.")
+		PrintInfoMsg("  TxHash:%s", txHash)
+		PrintInfoMsg("\nTip:")
+		PrintInfoMsg("  Using './poly info status %s' to query transaction status.", txHash)
+	}
+	return nil
+}
+
+//parseNativeParam fills param by the json of --params, overridden by the fields of --param
+func parseNativeParam(ctx *cli.Context, param interface{}) error {
+	fields := make(map[string]interface{})
+	if data := ctx.String(utils.GetFlagName(utils.NativeParamsFlag)); data != "" {
+		if strings.HasPrefix(data, "@") {
+			content, err := ioutil.ReadFile(data[1:])
+			if err != nil {
+				return err
+			}
+			data = string(content)
+		}
+		decoder := json.NewDecoder(strings.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&fields); err != nil {
+			return fmt.Errorf("invalid json:%s", err)
+		}
+	}
+	for _, field := range ctx.StringSlice(utils.GetFlagName(utils.NativeParamFlag)) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid param %s, <name=value> expected", field)
+		}
+		//the value is json, or a string if not
+		var value interface{}
+		decoder := json.NewDecoder(strings.NewReader(kv[1]))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil || decoder.More() {
+			value = kv[1]
+		}
+		obj := fields
+		names := strings.Split(kv[0], ".")
+		for _, name := range names[:len(names)-1] {
+			sub, ok := obj[name].(map[string]interface{})
+			if !ok {
+				sub = make(map[string]interface{})
+				obj[name] = sub
+			}
+			obj = sub
+		}
+		obj[names[len(names)-1]] = value
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return client.ParamFromJSON(data, param)
+}
+
+//setSignerAddress sets the Address field of param to signer if it is not specified
+func setSignerAddress(param interface{}, signer common.Address) bool {
+	v := reflect.ValueOf(param)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	field := v.Elem().FieldByName("Address")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(common.Address{}) {
+		return false
+	}
+	if field.Interface().(common.Address) != common.ADDRESS_EMPTY {
+		return false
+	}
+	field.Set(reflect.ValueOf(signer))
+	return true
+}
@@ -0,0 +1,298 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	cmdcom "github.com/polynetwork/poly/cmd/common"
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/urfave/cli"
+)
+
+var PSTCommand = cli.Command{
+	Name:      "pst",
+	Action:    cli.ShowSubcommandHelp,
+	Usage:     "Partially signed transaction workflow for multi-signature and consensus approvals",
+	ArgsUsage: "[arguments...]",
+	Description: `A partially signed transaction (PST) file records the transaction, the required pub keys, m of n,
+   and the collected signatures. It is passed among the signers to sign, merged and finalized to send.
+   With --consensus, the PST collects one approval transaction from each consensus operator for the governance
+   methods checked by consensus signs, such as approveRegisterSideChain, at the address of the operator.`,
+	Subcommands: []cli.Command{
+		{
+			Action:    pstCreate,
+			Name:      "create",
+			Usage:     "Create a PST from raw transaction",
+			ArgsUsage: "<rawtx>",
+			Flags: []cli.Flag{
+				utils.AccountMultiMFlag,
+				utils.AccountMultiPubKeyFlag,
+				utils.PSTConsensusFlag,
+				utils.PSTOutputFlag,
+			},
+		},
+		{
+			Action:    pstShow,
+			Name:      "show",
+			Usage:     "Show the transaction and signatures of PST",
+			ArgsUsage: "<file>",
+		},
+		{
+			Action:    pstSign,
+			Name:      "sign",
+			Usage:     "Add the signature of account to PST",
+			ArgsUsage: "<file>",
+			Flags: []cli.Flag{
+				utils.WalletFileFlag,
+				utils.AccountAddressFlag,
+				utils.PSTOutputFlag,
+			},
+			Description: "Add the signature of account to PST. The PST file is overwritten if --out is not specified.",
+		},
+		{
+			Action:    pstMerge,
+			Name:      "merge",
+			Usage:     "Merge the signatures of PSTs of the same transaction",
+			ArgsUsage: "<file> <file>...",
+			Flags: []cli.Flag{
+				utils.PSTOutputFlag,
+			},
+			Description: "Merge the signatures of PSTs to the first one. The first PST file is overwritten if --out is not specified.",
+		},
+		{
+			Action:    pstFinalize,
+			Name:      "finalize",
+			Usage:     "Output the signed transaction(s) of PST",
+			ArgsUsage: "<file>",
+			Flags: []cli.Flag{
+				utils.RPCPortFlag,
+				utils.SendTxFlag,
+				utils.PrepareExecTransactionFlag,
+			},
+		},
+	},
+}
+
+func pstCreate(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing <rawtx> argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	out := ctx.String(utils.GetFlagName(utils.PSTOutputFlag))
+	if out == "" {
+		PrintErrorMsg("Missing %s argument.", utils.GetFlagName(utils.PSTOutputFlag))
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	pkstr := strings.TrimSpace(strings.Trim(ctx.String(utils.GetFlagName(utils.AccountMultiPubKeyFlag)), ","))
+	if pkstr == "" {
+		PrintErrorMsg("Missing %s argument.", utils.GetFlagName(utils.AccountMultiPubKeyFlag))
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	pubKeys := make([]keypair.PublicKey, 0)
+	for _, pk := range strings.Split(pkstr, ",") {
+		pk := strings.TrimSpace(pk)
+		if pk == "" {
+			continue
+		}
+		data, err := hex.DecodeString(pk)
+		if err != nil {
+			return fmt.Errorf("invalid pub key:%s", pk)
+		}
+		pubKey, err := keypair.DeserializePublicKey(data)
+		if err != nil {
+			return fmt.Errorf("invalid pub key:%s", pk)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	txData, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("RawTx hex decode error:%s", err)
+	}
+	tx, err := types.TransactionFromRawBytes(txData)
+	if err != nil {
+		return fmt.Errorf("TransactionFromRawBytes error:%s", err)
+	}
+
+	var pst *utils.PSTransaction
+	if ctx.Bool(utils.GetFlagName(utils.PSTConsensusFlag)) {
+		pst, err = utils.NewConsensusPSTransaction(tx, pubKeys)
+	} else {
+		m := ctx.Uint(utils.GetFlagName(utils.AccountMultiMFlag))
+		pst, err = utils.NewPSTransaction(tx, uint16(m), pubKeys)
+	}
+	if err != nil {
+		return err
+	}
+	if err = pst.Save(out); err != nil {
+		return fmt.Errorf("save PST to %s error:%s", out, err)
+	}
+	PrintInfoMsg("PST of tx %s created, %d of %d signatures required.", pst.TxHash, pst.M, len(pst.PubKeys))
+	PrintInfoMsg("  File:%s", out)
+	return nil
+}
+
+func pstShow(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing <file> argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	pst, err := utils.LoadPSTransaction(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	PrintInfoMsg("Kind:%s", pst.Kind)
+	PrintInfoMsg("TxHash:%s", pst.TxHash)
+	if pst.Payload != nil {
+		PrintInfoMsg("Contract:%s %s", pst.Payload.Contract, pst.Payload.ContractName)
+		PrintInfoMsg("Method:%s", pst.Payload.Method)
+		PrintInfoMsg("Args:%s", pst.Payload.Args)
+	}
+	PrintInfoMsg("Signatures:%d of %d collected, %d of %d required", pst.Signed(), len(pst.PubKeys), pst.M, len(pst.PubKeys))
+	for i, key := range pst.PubKeys {
+		data, _ := hex.DecodeString(key)
+		pubKey, _ := keypair.DeserializePublicKey(data)
+		addr := types.AddressFromPubKey(pubKey)
+		state := "unsigned"
+		if pst.IsSignedBy(key) {
+			state = "signed"
+		}
+		PrintInfoMsg("Index %d Address:%s PubKey:%s %s", i+1, addr.ToBase58(), key, state)
+	}
+	return nil
+}
+
+func pstSign(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing <file> argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	file := ctx.Args().First()
+	pst, err := utils.LoadPSTransaction(file)
+	if err != nil {
+		return err
+	}
+	acc, err := cmdcom.GetAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("GetAccount error:%s", err)
+	}
+	if err = pst.Sign(acc); err != nil {
+		return err
+	}
+	out := ctx.String(utils.GetFlagName(utils.PSTOutputFlag))
+	if out == "" {
+		out = file
+	}
+	if err = pst.Save(out); err != nil {
+		return fmt.Errorf("save PST to %s error:%s", out, err)
+	}
+	PrintInfoMsg("Signed by %s, %d of %d signatures collected.", acc.Address.ToBase58(), pst.Signed(), pst.M)
+	PrintInfoMsg("  File:%s", out)
+	return nil
+}
+
+func pstMerge(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		PrintErrorMsg("Missing <file> arguments, at least 2 PST files expected.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	files := ctx.Args()
+	pst, err := utils.LoadPSTransaction(files[0])
+	if err != nil {
+		return err
+	}
+	for _, file := range files[1:] {
+		other, err := utils.LoadPSTransaction(file)
+		if err != nil {
+			return err
+		}
+		if err = pst.Merge(other); err != nil {
+			return fmt.Errorf("merge %s error:%s", file, err)
+		}
+	}
+	out := ctx.String(utils.GetFlagName(utils.PSTOutputFlag))
+	if out == "" {
+		out = files[0]
+	}
+	if err = pst.Save(out); err != nil {
+		return fmt.Errorf("save PST to %s error:%s", out, err)
+	}
+	PrintInfoMsg("Merged %d PST files, %d of %d signatures collected.", len(files), pst.Signed(), pst.M)
+	PrintInfoMsg("  File:%s", out)
+	return nil
+}
+
+func pstFinalize(ctx *cli.Context) error {
+	SetRpcPort(ctx)
+	if ctx.NArg() < 1 {
+		PrintErrorMsg("Missing <file> argument.")
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	pst, err := utils.LoadPSTransaction(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	txs, err := pst.Finalize()
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		sink := common.NewZeroCopySink(nil)
+		if err := tx.Serialization(sink); err != nil {
+			return fmt.Errorf("tx serialization error:%s", err)
+		}
+		rawTx := hex.EncodeToString(sink.Bytes())
+		PrintInfoMsg("RawTx after signed:")
+		PrintInfoMsg(rawTx)
+		PrintInfoMsg("")
+
+		if ctx.IsSet(utils.GetFlagName(utils.PrepareExecTransactionFlag)) {
+			preResult, err := utils.PrepareSendRawTransaction(rawTx)
+			if err != nil {
+				return err
+			}
+			if preResult.State == 0 {
+				return fmt.Errorf("prepare execute transaction failed. %v", preResult)
+			}
+			PrintInfoMsg("Prepare execute transaction success.")
+			PrintInfoMsg("Result:%v", preResult.Result)
+			continue
+		}
+		if ctx.IsSet(utils.GetFlagName(utils.SendTxFlag)) {
+			txHash, err := utils.SendRawTransactionData(rawTx)
+			if err != nil {
+				return err
+			}
+			PrintInfoMsg("Send transaction success.")
+			PrintInfoMsg("  TxHash:%s", txHash)
+		}
+	}
+	return nil
+}
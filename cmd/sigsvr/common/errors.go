@@ -29,6 +29,8 @@ const (
 	CLIERR_ABI_NOT_FOUND       = 1007
 	CLIERR_ABI_UNMATCH         = 1008
 	CLIERR_DUPLICATE_SIG       = 1009
+	CLIERR_POLICY_DENIED       = 1010
+	CLIERR_RATE_LIMITED        = 1011
 	CLIERR_INTERNAL_ERR        = 900
 )
 
@@ -42,6 +44,8 @@ var RPCErrorDesc = map[int]string{
 	CLIERR_ABI_NOT_FOUND:       "abi not found",
 	CLIERR_ABI_UNMATCH:         "abi unmatch",
 	CLIERR_DUPLICATE_SIG:       "Duplicate sig",
+	CLIERR_POLICY_DENIED:       "denied by signing policy",
+	CLIERR_RATE_LIMITED:        "signing rate limit exceeded",
 	CLIERR_INTERNAL_ERR:        "internal error",
 }
 
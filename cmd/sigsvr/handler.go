@@ -24,4 +24,5 @@ func init() {
 	DefCliRpcSvr.RegHandler("createaccount", handlers.CreateAccount)
 	DefCliRpcSvr.RegHandler("exportaccount", handlers.ExportAccount)
 	DefCliRpcSvr.RegHandler("sigdata", handlers.SigData)
+	DefCliRpcSvr.RegHandler("sigrawtx", handlers.SigRawTx)
 }
@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	clisvrcom "github.com/polynetwork/poly/cmd/sigsvr/common"
+	"github.com/polynetwork/poly/cmd/sigsvr/policy"
 	cliutil "github.com/polynetwork/poly/cmd/utils"
 	"github.com/polynetwork/poly/common/log"
 )
@@ -52,12 +53,22 @@ func SigData(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
 		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
 		return
 	}
+	entry := &policy.AuditEntry{Qid: req.Qid, Method: req.Method, Account: signer.Address.ToBase58()}
+	if err := policy.DefEngine.CheckBlind(signer.Address); err != nil {
+		entry.Reason = err.Error()
+		policy.DefEngine.Audit(entry)
+		resp.ErrorCode = policyErrorCode(err)
+		resp.ErrorInfo = err.Error()
+		return
+	}
 	sigData, err := cliutil.Sign(rawData, signer)
 	if err != nil {
 		log.Infof("Cli Qid:%s SigData Sign error:%s", req.Qid, err)
 		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
 		return
 	}
+	entry.Allowed = true
+	policy.DefEngine.Audit(entry)
 	resp.Result = &SigDataRsp{
 		SignedData: hex.EncodeToString(sigData),
 	}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	clisvrcom "github.com/polynetwork/poly/cmd/sigsvr/common"
+	"github.com/polynetwork/poly/cmd/sigsvr/policy"
+	cliutil "github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/types"
+)
+
+type SigRawTxReq struct {
+	RawTx string `json:"raw_tx"`
+}
+
+type SigRawTxRsp struct {
+	SignedTx string         `json:"signed_tx"`
+	Tx       *policy.TxInfo `json:"tx"`
+}
+
+//SigRawTx decodes the transaction and signs it if the signing policy of the account allows
+func SigRawTx(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	rawReq := &SigRawTxReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	rawTx, err := hex.DecodeString(rawReq.RawTx)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigRawTx hex.DecodeString error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	tx, err := types.TransactionFromRawBytes(rawTx)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigRawTx TransactionFromRawBytes error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_TX
+		return
+	}
+	info, err := policy.DecodeTx(tx)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigRawTx DecodeTx error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_TX
+		resp.ErrorInfo = err.Error()
+		return
+	}
+	signer, err := req.GetAccount()
+	if err != nil {
+		log.Infof("Cli Qid:%s SigRawTx GetAccount:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_ACCOUNT_UNLOCK
+		return
+	}
+	entry := &policy.AuditEntry{Qid: req.Qid, Method: req.Method, Account: signer.Address.ToBase58(), Tx: info}
+	if err := policy.DefEngine.CheckTx(signer.Address, info); err != nil {
+		entry.Reason = err.Error()
+		policy.DefEngine.Audit(entry)
+		resp.ErrorCode = policyErrorCode(err)
+		resp.ErrorInfo = err.Error()
+		return
+	}
+	err = cliutil.SignTransaction(signer, tx)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigRawTx SignTransaction error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	sink := common.NewZeroCopySink(nil)
+	err = tx.Serialization(sink)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigRawTx tx Serialization error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	entry.Allowed = true
+	policy.DefEngine.Audit(entry)
+	resp.Result = &SigRawTxRsp{
+		SignedTx: hex.EncodeToString(sink.Bytes()),
+		Tx:       info,
+	}
+}
+
+func policyErrorCode(err error) int {
+	if errors.Is(err, policy.ErrRateLimited) {
+		return clisvrcom.CLIERR_RATE_LIMITED
+	}
+	return clisvrcom.CLIERR_POLICY_DENIED
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/polynetwork/poly/common/log"
+)
+
+//AuditEntry records a signing request and the decision on it
+type AuditEntry struct {
+	Time    string
+	Qid     string
+	Method  string
+	Account string
+	Tx      *TxInfo `json:",omitempty"`
+	Allowed bool
+	Reason  string `json:",omitempty"`
+}
+
+//appends entries to the audit log as json lines
+type auditor struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+func newAuditor(path string) (*auditor, error) {
+	if path == "" {
+		return &auditor{}, nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log %s error:%s", path, err)
+	}
+	return &auditor{file: file}, nil
+}
+
+//Audit records the entry, entries are always logged and also appended to the audit log if configured
+func (self *Engine) Audit(entry *AuditEntry) {
+	entry.Time = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Errorf("Audit json.Marshal error:%s", err)
+		return
+	}
+	log.Infof("[SigAudit]%s", data)
+	if self == nil || self.audit.file == nil {
+		return
+	}
+	self.audit.lock.Lock()
+	defer self.audit.lock.Unlock()
+	if _, err := self.audit.file.Write(append(data, '\n')); err != nil {
+		log.Errorf("Audit write error:%s", err)
+	}
+}
+
+//Close closes the audit log
+func (self *Engine) Close() error {
+	if self == nil || self.audit.file == nil {
+		return nil
+	}
+	return self.audit.file.Close()
+}
@@ -0,0 +1,263 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package policy decides what the keys of the sig server may sign, poly transactions
+// are decoded and checked against per key allow-lists of native contracts and methods
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/types"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+)
+
+//ANY_METHOD allows every method of a contract
+const ANY_METHOD = "*"
+
+var (
+	ErrDenied      = errors.New("denied by signing policy")
+	ErrRateLimited = errors.New("signing rate limit exceeded")
+)
+
+//KeyPolicy restricts what a key signs
+type KeyPolicy struct {
+	AllowBlindSign bool                //allow sigdata to sign raw bytes
+	Contracts      map[string][]string //contract name or address in hex to allowed methods
+	RateLimit      float64             //signatures per second, 0 for no limit
+	RateBurst      int
+}
+
+//PolicyConfig is the content of the policy file of the sig server
+type PolicyConfig struct {
+	Default  *KeyPolicy            //policy of keys without their own, keys are denied if nil
+	Keys     map[string]*KeyPolicy //base58 address of the key to its policy
+	AuditLog string                //file the audit entries are appended to, only logged if empty
+}
+
+//DefEngine is the policy engine of the sig server, everything is allowed if nil
+var DefEngine *Engine
+
+type keyRule struct {
+	blind     bool
+	contracts map[common.Address]map[string]bool
+	limiter   *limiter
+}
+
+//Engine enforces the signing policies and keeps the audit log
+type Engine struct {
+	lock     sync.Mutex
+	keys     map[common.Address]*keyRule
+	defRule  *KeyPolicy
+	defRules map[common.Address]*keyRule //rules created from the default policy
+	audit    *auditor
+}
+
+//LoadEngine creates the engine from the json policy file
+func LoadEngine(path string) (*Engine, error) {
+	cfg := &PolicyConfig{}
+	if err := utils.GetJsonObjectFromFile(path, cfg); err != nil {
+		return nil, fmt.Errorf("load policy file %s error:%s", path, err)
+	}
+	return NewEngine(cfg)
+}
+
+func NewEngine(cfg *PolicyConfig) (*Engine, error) {
+	engine := &Engine{
+		keys:     make(map[common.Address]*keyRule),
+		defRule:  cfg.Default,
+		defRules: make(map[common.Address]*keyRule),
+	}
+	if cfg.Default != nil {
+		if _, err := newKeyRule(cfg.Default); err != nil {
+			return nil, fmt.Errorf("default policy: %s", err)
+		}
+	}
+	for key, policy := range cfg.Keys {
+		addr, err := common.AddressFromBase58(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key address %s: %s", key, err)
+		}
+		if policy == nil {
+			return nil, fmt.Errorf("empty policy of key %s", key)
+		}
+		rule, err := newKeyRule(policy)
+		if err != nil {
+			return nil, fmt.Errorf("policy of key %s: %s", key, err)
+		}
+		engine.keys[addr] = rule
+	}
+	audit, err := newAuditor(cfg.AuditLog)
+	if err != nil {
+		return nil, err
+	}
+	engine.audit = audit
+	return engine, nil
+}
+
+func newKeyRule(policy *KeyPolicy) (*keyRule, error) {
+	rule := &keyRule{
+		blind:     policy.AllowBlindSign,
+		contracts: make(map[common.Address]map[string]bool),
+	}
+	for contract, methods := range policy.Contracts {
+		addr, err := parseContract(contract)
+		if err != nil {
+			return nil, err
+		}
+		if len(methods) == 0 {
+			return nil, fmt.Errorf("no methods allowed of contract %s", contract)
+		}
+		allowed := make(map[string]bool)
+		for _, method := range methods {
+			allowed[method] = true
+		}
+		rule.contracts[addr] = allowed
+	}
+	if policy.RateLimit < 0 || policy.RateBurst < 0 {
+		return nil, fmt.Errorf("invalid rate limit")
+	}
+	if policy.RateLimit > 0 {
+		rule.limiter = newLimiter(policy.RateLimit, policy.RateBurst)
+	}
+	return rule, nil
+}
+
+func parseContract(contract string) (common.Address, error) {
+	if addr, ok := nutils.NativeContracts[strings.ToLower(contract)]; ok {
+		return addr, nil
+	}
+	addr, err := common.AddressFromHexString(contract)
+	if err != nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("invalid contract %s", contract)
+	}
+	return addr, nil
+}
+
+//rule returns the rule of the key, keys without their own policy share the default
+//policy but are limited separately
+func (self *Engine) rule(key common.Address) *keyRule {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if rule, ok := self.keys[key]; ok {
+		return rule
+	}
+	if self.defRule == nil {
+		return nil
+	}
+	rule, ok := self.defRules[key]
+	if !ok {
+		rule, _ = newKeyRule(self.defRule)
+		self.defRules[key] = rule
+	}
+	return rule
+}
+
+//CheckBlind checks whether the key may sign raw bytes
+func (self *Engine) CheckBlind(key common.Address) error {
+	if self == nil {
+		return nil
+	}
+	rule := self.rule(key)
+	if rule == nil {
+		return fmt.Errorf("%w: no policy of key %s", ErrDenied, key.ToBase58())
+	}
+	if !rule.blind {
+		return fmt.Errorf("%w: blind signing not allowed", ErrDenied)
+	}
+	return rule.take()
+}
+
+//CheckTx checks whether the key may sign the transaction
+func (self *Engine) CheckTx(key common.Address, info *TxInfo) error {
+	if self == nil {
+		return nil
+	}
+	rule := self.rule(key)
+	if rule == nil {
+		return fmt.Errorf("%w: no policy of key %s", ErrDenied, key.ToBase58())
+	}
+	if info.invoke == nil {
+		return fmt.Errorf("%w: transaction is not a native invocation", ErrDenied)
+	}
+	methods, ok := rule.contracts[info.invoke.Address]
+	if !ok {
+		return fmt.Errorf("%w: contract %s not allowed", ErrDenied, info.Contract)
+	}
+	if !methods[ANY_METHOD] && !methods[info.Method] {
+		return fmt.Errorf("%w: method %s of contract %s not allowed", ErrDenied, info.Method, info.Contract)
+	}
+	return rule.take()
+}
+
+func (self *keyRule) take() error {
+	if self.limiter != nil && !self.limiter.allow() {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+//TxInfo is the decoded content of a transaction to sign
+type TxInfo struct {
+	Hash     string
+	TxType   byte
+	ChainID  uint64
+	Nonce    uint32
+	Payer    string `json:",omitempty"`
+	Contract string `json:",omitempty"`
+	Method   string `json:",omitempty"`
+	Args     string `json:",omitempty"`
+
+	invoke *states.ContractInvokeParam
+}
+
+//DecodeTx decodes the transaction, the contract invocation is decoded for invoke transactions
+func DecodeTx(tx *types.Transaction) (*TxInfo, error) {
+	hash := tx.Hash()
+	info := &TxInfo{
+		Hash:    hash.ToHexString(),
+		TxType:  byte(tx.TxType),
+		ChainID: tx.ChainID,
+		Nonce:   tx.Nonce,
+	}
+	if tx.Payer != common.ADDRESS_EMPTY {
+		info.Payer = tx.Payer.ToBase58()
+	}
+	if tx.TxType != types.Invoke {
+		return info, nil
+	}
+	invokeCode, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return nil, fmt.Errorf("invalid payload of invoke transaction")
+	}
+	param := &states.ContractInvokeParam{}
+	if err := param.Deserialization(common.NewZeroCopySource(invokeCode.Code)); err != nil {
+		return nil, fmt.Errorf("decode invocation error:%s", err)
+	}
+	info.invoke = param
+	info.Contract = param.Address.ToHexString()
+	info.Method = param.Method
+	info.Args = common.ToHexString(param.Args)
+	return info, nil
+}
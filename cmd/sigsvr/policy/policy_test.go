@@ -0,0 +1,162 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly/client"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	relayer = common.Address{1}
+	other   = common.Address{2}
+)
+
+func newTxInfo(t *testing.T, contract common.Address, method string) *TxInfo {
+	tx, err := client.NewNativeBuilder(0).NewInvokeTx(contract, method, []byte{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := DecodeTx(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestDecodeTx(t *testing.T) {
+	info := newTxInfo(t, nutils.HeaderSyncContractAddress, header_sync.SYNC_BLOCK_HEADER)
+	assert.Equal(t, nutils.HeaderSyncContractAddress.ToHexString(), info.Contract)
+	assert.Equal(t, header_sync.SYNC_BLOCK_HEADER, info.Method)
+	assert.Equal(t, "0102", info.Args)
+	assert.NotEqual(t, common.UINT256_EMPTY.ToHexString(), info.Hash)
+}
+
+func TestCheckTx(t *testing.T) {
+	engine, err := NewEngine(&PolicyConfig{
+		Keys: map[string]*KeyPolicy{
+			relayer.ToBase58(): {Contracts: map[string][]string{
+				"header_sync": {header_sync.SYNC_BLOCK_HEADER},
+				nutils.CrossChainManagerContractAddress.ToHexString(): {cross_chain_manager.IMPORT_OUTER_TRANSFER_NAME},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, engine.CheckTx(relayer, newTxInfo(t, nutils.HeaderSyncContractAddress, header_sync.SYNC_BLOCK_HEADER)))
+	assert.Nil(t, engine.CheckTx(relayer, newTxInfo(t, nutils.CrossChainManagerContractAddress, cross_chain_manager.IMPORT_OUTER_TRANSFER_NAME)))
+
+	err = engine.CheckTx(relayer, newTxInfo(t, nutils.HeaderSyncContractAddress, header_sync.SYNC_GENESIS_HEADER))
+	assert.True(t, errors.Is(err, ErrDenied), "method not allowed")
+	err = engine.CheckTx(relayer, newTxInfo(t, nutils.NodeManagerContractAddress, header_sync.SYNC_BLOCK_HEADER))
+	assert.True(t, errors.Is(err, ErrDenied), "contract not allowed")
+	err = engine.CheckTx(other, newTxInfo(t, nutils.HeaderSyncContractAddress, header_sync.SYNC_BLOCK_HEADER))
+	assert.True(t, errors.Is(err, ErrDenied), "key without policy")
+	assert.True(t, errors.Is(engine.CheckBlind(relayer), ErrDenied), "blind signing")
+
+	var nilEngine *Engine
+	assert.Nil(t, nilEngine.CheckTx(other, newTxInfo(t, nutils.NodeManagerContractAddress, "any")))
+	assert.Nil(t, nilEngine.CheckBlind(other))
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	engine, err := NewEngine(&PolicyConfig{
+		Default: &KeyPolicy{AllowBlindSign: true, Contracts: map[string][]string{"node_manager": {ANY_METHOD}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, engine.CheckBlind(other))
+	assert.Nil(t, engine.CheckTx(other, newTxInfo(t, nutils.NodeManagerContractAddress, "anyMethod")))
+	err = engine.CheckTx(other, newTxInfo(t, nutils.RelayerManagerContractAddress, "anyMethod"))
+	assert.True(t, errors.Is(err, ErrDenied))
+}
+
+func TestRateLimit(t *testing.T) {
+	engine, err := NewEngine(&PolicyConfig{
+		Default: &KeyPolicy{AllowBlindSign: true, RateLimit: 1, RateBurst: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	limiter := engine.rule(relayer).limiter
+	limiter.now, limiter.last = func() time.Time { return now }, now
+	assert.Nil(t, engine.CheckBlind(relayer))
+	assert.Nil(t, engine.CheckBlind(relayer))
+	assert.Equal(t, ErrRateLimited, engine.CheckBlind(relayer))
+	assert.Nil(t, engine.CheckBlind(other), "keys are limited separately")
+
+	now = now.Add(time.Second)
+	assert.Nil(t, engine.CheckBlind(relayer))
+	assert.Equal(t, ErrRateLimited, engine.CheckBlind(relayer))
+}
+
+func TestInvalidConfig(t *testing.T) {
+	_, err := NewEngine(&PolicyConfig{Keys: map[string]*KeyPolicy{"invalid": {}}})
+	assert.NotNil(t, err)
+	_, err = NewEngine(&PolicyConfig{Default: &KeyPolicy{Contracts: map[string][]string{"unknown": {ANY_METHOD}}}})
+	assert.NotNil(t, err)
+	_, err = NewEngine(&PolicyConfig{Default: &KeyPolicy{Contracts: map[string][]string{"header_sync": {}}}})
+	assert.NotNil(t, err)
+	_, err = NewEngine(&PolicyConfig{Default: &KeyPolicy{RateLimit: -1}})
+	assert.NotNil(t, err)
+}
+
+func TestAudit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sigsvr_audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	engine, err := NewEngine(&PolicyConfig{AuditLog: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := newTxInfo(t, nutils.HeaderSyncContractAddress, header_sync.SYNC_BLOCK_HEADER)
+	engine.Audit(&AuditEntry{Qid: "1", Method: "sigrawtx", Account: relayer.ToBase58(), Tx: info, Allowed: true})
+	engine.Audit(&AuditEntry{Qid: "2", Method: "sigdata", Account: relayer.ToBase58(), Reason: "denied"})
+	assert.Nil(t, engine.Close())
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, 2, len(lines))
+	entry := &AuditEntry{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), entry))
+	assert.True(t, entry.Allowed)
+	assert.Equal(t, header_sync.SYNC_BLOCK_HEADER, entry.Tx.Method)
+	assert.NotEmpty(t, entry.Time)
+}
@@ -121,9 +121,15 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.AccountSourceFileFlag,
 			utils.AccountWIFFlag,
 			utils.AccountLowSecurityFlag,
+			utils.AccountDeriveFlag,
+			utils.AccountMnemonicFlag,
+			utils.AccountHDPathFlag,
+			utils.AccountHDIndexFlag,
 			utils.AccountMultiMFlag,
 			utils.AccountMultiPubKeyFlag,
 			utils.IdentityFlag,
+			utils.PSTOutputFlag,
+			utils.PSTConsensusFlag,
 		},
 	},
 	{
@@ -139,6 +145,11 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxpoolPreExecDisableFlag,
 			utils.DisableSyncVerifyTxFlag,
 			utils.DisableBroadcastNetTxFlag,
+			utils.TxpoolMaxTxsPerSignerFlag,
+			utils.TxpoolMaxSizeFlag,
+			utils.TxpoolPrioritiesFlag,
+			utils.TxpoolJournalFlag,
+			utils.TxpoolJournalSizeFlag,
 		},
 	},
 	{
@@ -163,6 +174,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.RPCPortFlag,
 			utils.RPCLocalEnableFlag,
 			utils.RPCLocalProtFlag,
+			utils.RPCMaxBatchSizeFlag,
 		},
 	},
 	{
@@ -180,6 +192,26 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WsPortFlag,
 		},
 	},
+	{
+		Name: "API ACCESS",
+		Flags: []cli.Flag{
+			utils.ApiAccessFileFlag,
+		},
+	},
+	{
+		Name: "METRICS",
+		Flags: []cli.Flag{
+			utils.MetricsEnabledFlag,
+			utils.MetricsPortFlag,
+		},
+	},
+	{
+		Name: "GRPC",
+		Flags: []cli.Flag{
+			utils.GrpcEnabledFlag,
+			utils.GrpcPortFlag,
+		},
+	},
 	{
 		Name: "TEST MODE",
 		Flags: []cli.Flag{
@@ -217,6 +249,11 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ForceSendTxFlag,
 			utils.TransactionPayerFlag,
 			utils.PrepareExecTransactionFlag,
+			utils.NativeParamsFlag,
+			utils.NativeParamFlag,
+			utils.NativeArgsFlag,
+			utils.NativeRawFlag,
+			utils.DryRunFlag,
 			utils.TransferFromAmountFlag,
 			utils.WithdrawONGReceiveAccountFlag,
 			utils.WithdrawONGAmountFlag,
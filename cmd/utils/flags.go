@@ -21,6 +21,7 @@ package utils
 import (
 	"strings"
 
+	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/urfave/cli"
 )
@@ -158,6 +159,11 @@ var (
 		Usage: "Json rpc local server listening port `<number>`",
 		Value: config.DEFAULT_RPC_LOCAL_PORT,
 	}
+	RPCMaxBatchSizeFlag = cli.UintFlag{
+		Name:  "rpcmaxbatch",
+		Usage: "Json rpc max number of requests in a batch `<number>`",
+		Value: config.DEFAULT_RPC_MAX_BATCH_SIZE,
+	}
 
 	//Websocket setting
 	WsEnabledFlag = cli.BoolFlag{
@@ -170,6 +176,34 @@ var (
 		Value: config.DEFAULT_WS_PORT,
 	}
 
+	//Api access setting
+	ApiAccessFileFlag = cli.StringFlag{
+		Name:  "accessfile",
+		Usage: "Json `<file>` of the api keys, ip allow-lists, cors origins and rate limits of the rpc, restful and ws servers",
+	}
+
+	//Metrics setting
+	MetricsEnabledFlag = cli.BoolFlag{
+		Name:  "metrics",
+		Usage: "Enable prometheus metrics server",
+	}
+	MetricsPortFlag = cli.UintFlag{
+		Name:  "metricsport",
+		Usage: "Metrics server listening port `<number>`",
+		Value: config.DEFAULT_METRICS_PORT,
+	}
+
+	//gRPC setting
+	GrpcEnabledFlag = cli.BoolFlag{
+		Name:  "grpc",
+		Usage: "Enable gRPC server",
+	}
+	GrpcPortFlag = cli.UintFlag{
+		Name:  "grpcport",
+		Usage: "gRPC server listening port `<number>`",
+		Value: config.DEFAULT_GRPC_PORT,
+	}
+
 	//Restful setting
 	RestfulEnableFlag = cli.BoolFlag{
 		Name:  "rest",
@@ -249,6 +283,23 @@ var (
 		Name:  "wif",
 		Usage: "Import WIF keys from the source file specified by --source option",
 	}
+	AccountDeriveFlag = cli.BoolFlag{
+		Name:  "derive",
+		Usage: "Derive the account(s) from a new BIP-39 mnemonic, only ecdsa with P-256 or secp256k1 is supported",
+	}
+	AccountMnemonicFlag = cli.BoolFlag{
+		Name:  "mnemonic",
+		Usage: "Import account(s) derived from a BIP-39 mnemonic",
+	}
+	AccountHDPathFlag = cli.StringFlag{
+		Name:  "hd-path",
+		Usage: "BIP-44 derivation `<path>` of HD accounts, the account index is appended to it",
+		Value: account.DEFAULT_HD_PATH,
+	}
+	AccountHDIndexFlag = cli.UintFlag{
+		Name:  "hd-index",
+		Usage: "Derivation `<index>` of the first HD account",
+	}
 	AccountMultiMFlag = cli.UintFlag{
 		Name:  "m",
 		Usage: "Min signature `<number>` of multi signature address",
@@ -258,6 +309,14 @@ var (
 		Name:  "pubkey",
 		Usage: "Pub key list of multi `<addresses>`, separate addreses with comma `,`",
 	}
+	PSTOutputFlag = cli.StringFlag{
+		Name:  "out,o",
+		Usage: "Output partially signed transaction `<file>`",
+	}
+	PSTConsensusFlag = cli.BoolFlag{
+		Name:  "consensus",
+		Usage: "Collect an approval transaction from each consensus operator of --pubkey, instead of multi-signature",
+	}
 	IdentityFlag = cli.BoolFlag{
 		Name:  "ontid",
 		Usage: "create an ONT ID instead of account",
@@ -377,6 +436,26 @@ var (
 		Name:  "prepare,p",
 		Usage: "Prepare execute transaction, without commit to ledger",
 	}
+	NativeParamsFlag = cli.StringFlag{
+		Name:  "params",
+		Usage: "Params of the native method in json `<object>`, or @<file> to read the json from file",
+	}
+	NativeParamFlag = cli.StringSliceFlag{
+		Name:  "param",
+		Usage: "Set a param field of the native method by `<name=value>`, nested field names are joined by dot",
+	}
+	NativeArgsFlag = cli.StringFlag{
+		Name:  "args",
+		Usage: "Serialized args of the native method in `<hex>`, instead of the params",
+	}
+	NativeRawFlag = cli.BoolFlag{
+		Name:  "raw",
+		Usage: "Print the unsigned raw transaction only, e.g. to create a PST",
+	}
+	DryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Pre-execute the signed transaction without commit to ledger",
+	}
 	WithdrawONGReceiveAccountFlag = cli.StringFlag{
 		Name:  "receive",
 		Usage: "ONG receive `<address>`，Default the same with owner account",
@@ -411,6 +490,10 @@ var (
 		Usage: "Wallet data `<path>`",
 		Value: DEFAULT_WALLET_PATH,
 	}
+	CliPolicyFileFlag = cli.StringFlag{
+		Name:  "policyfile",
+		Usage: "Signing policy `<file>`, keys may sign anything if not set",
+	}
 
 	//Export setting
 	ExportFileFlag = cli.StringFlag{
@@ -451,6 +534,34 @@ var (
 		Usage: "Disable broadcast tx from network in tx pool",
 	}
 
+	TxpoolMaxTxsPerSignerFlag = cli.UintFlag{
+		Name:  "txpool-max-per-signer",
+		Usage: "Max submitted transaction `<number>` of a signer in tx pool, 0 for no limit",
+		Value: config.DEFAULT_TXPOOL_MAX_TXS_PER_SIGNER,
+	}
+
+	TxpoolMaxSizeFlag = cli.Uint64Flag{
+		Name:  "txpool-max-size",
+		Usage: "Max total `<MB>` of the transactions in tx pool, lower priority ones are evicted when full, 0 for no limit",
+		Value: config.DEFAULT_TXPOOL_MAX_SIZE / (1024 * 1024),
+	}
+
+	TxpoolPrioritiesFlag = cli.StringFlag{
+		Name:  "txpool-priorities",
+		Usage: "Tx pool priorities `<contract[.method]:priority,...>` in place of the default, contract is a native contract name or address in hex",
+	}
+
+	TxpoolJournalFlag = cli.BoolFlag{
+		Name:  "txpool-journal",
+		Usage: "Keep the submitted transactions of tx pool on disk and reload them at startup",
+	}
+
+	TxpoolJournalSizeFlag = cli.Uint64Flag{
+		Name:  "txpool-journal-size",
+		Usage: "Max `<MB>` of the tx pool journal",
+		Value: config.DEFAULT_TXPOOL_JOURNAL_SIZE / (1024 * 1024),
+	}
+
 	NonOptionFlag = cli.StringFlag{
 		Name:  "option",
 		Usage: "this command does not need option, please run directly",
@@ -0,0 +1,514 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/constants"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+)
+
+const (
+	PST_VERSION = byte(1)
+
+	//PST_KIND_MULTISIG collects the signatures of a multi-signature address to one transaction
+	PST_KIND_MULTISIG = "multisig"
+	//PST_KIND_CONSENSUS collects one approval transaction from each consensus operator,
+	//for the governance methods checked by node_manager.CheckConsensusSigns
+	PST_KIND_CONSENSUS = "consensus"
+)
+
+type approvalParam interface {
+	Serialization(sink *common.ZeroCopySink)
+	Deserialization(source *common.ZeroCopySource) error
+}
+
+//consensusApprovals returns the param of the governance method, and the address in it
+//which must be the operator who approves
+var consensusApprovals = map[common.Address]map[string]func() (approvalParam, *common.Address){
+	nutils.SideChainManagerContractAddress: {
+		side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN: func() (approvalParam, *common.Address) {
+			param := new(side_chain_manager.ChainidParam)
+			return param, &param.Address
+		},
+		side_chain_manager.APPROVE_UPDATE_SIDE_CHAIN: func() (approvalParam, *common.Address) {
+			param := new(side_chain_manager.ChainidParam)
+			return param, &param.Address
+		},
+		side_chain_manager.APPROVE_QUIT_SIDE_CHAIN: func() (approvalParam, *common.Address) {
+			param := new(side_chain_manager.ChainidParam)
+			return param, &param.Address
+		},
+	},
+	nutils.RelayerManagerContractAddress: {
+		relayer_manager.APPROVE_REGISTER_RELAYER: func() (approvalParam, *common.Address) {
+			param := new(relayer_manager.ApproveRelayerParam)
+			return param, &param.Address
+		},
+		relayer_manager.APPROVE_REMOVE_RELAYER: func() (approvalParam, *common.Address) {
+			param := new(relayer_manager.ApproveRelayerParam)
+			return param, &param.Address
+		},
+	},
+	nutils.NodeManagerContractAddress: {
+		node_manager.APPROVE_CANDIDATE: func() (approvalParam, *common.Address) {
+			param := new(node_manager.PeerParam)
+			return param, &param.Address
+		},
+		node_manager.BLACK_NODE: func() (approvalParam, *common.Address) {
+			param := new(node_manager.PeerListParam)
+			return param, &param.Address
+		},
+		node_manager.WHITE_NODE: func() (approvalParam, *common.Address) {
+			param := new(node_manager.PeerParam)
+			return param, &param.Address
+		},
+		node_manager.REPORT_EQUIVOCATION: func() (approvalParam, *common.Address) {
+			param := new(node_manager.ReportEquivocationParam)
+			return param, &param.Address
+		},
+		node_manager.REJECT_PROPOSAL: func() (approvalParam, *common.Address) {
+			param := new(node_manager.ProposalParam)
+			return param, &param.Address
+		},
+	},
+}
+
+//PSTPayload is the decoded native invocation of the transaction
+type PSTPayload struct {
+	Contract     string `json:"contract"`
+	ContractName string `json:"contractName,omitempty"`
+	Method       string `json:"method"`
+	Args         string `json:"args"`
+}
+
+//PSTransaction is a partially signed transaction file passed among the signers,
+//the collected signatures are keyed by the hex public key of the signer
+type PSTransaction struct {
+	Version   byte              `json:"version"`
+	Kind      string            `json:"kind"`
+	M         uint16            `json:"m"`
+	PubKeys   []string          `json:"pubKeys"`
+	Tx        string            `json:"tx"`
+	TxHash    string            `json:"txHash"`
+	Payload   *PSTPayload       `json:"payload,omitempty"`
+	Sigs      map[string]string `json:"sigs,omitempty"`
+	Approvals map[string]string `json:"approvals,omitempty"`
+}
+
+//NewPSTransaction create a PST collecting m of the pubKeys signatures to tx
+func NewPSTransaction(tx *types.Transaction, m uint16, pubKeys []keypair.PublicKey) (*PSTransaction, error) {
+	pkSize := len(pubKeys)
+	if m == 0 || int(m) > pkSize || pkSize > constants.MULTI_SIG_MAX_PUBKEY_SIZE {
+		return nil, fmt.Errorf("invalid m:%d of %d pub keys", m, pkSize)
+	}
+	return newPSTransaction(PST_KIND_MULTISIG, tx, m, pubKeys)
+}
+
+//NewConsensusPSTransaction create a PST collecting the approvals of the consensus operators
+//of pubKeys to the governance invocation of tx. The address in the invocation args is
+//replaced by the address of each operator when approving
+func NewConsensusPSTransaction(tx *types.Transaction, pubKeys []keypair.PublicKey) (*PSTransaction, error) {
+	if len(pubKeys) == 0 {
+		return nil, fmt.Errorf("no consensus pub key")
+	}
+	invoke, err := decodeNativeInvoke(tx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := consensusApprovals[invoke.Address][invoke.Method]; !ok {
+		return nil, fmt.Errorf("method %s of contract %s is not approved by consensus signs", invoke.Method, invoke.Address.ToHexString())
+	}
+	//same as node_manager.CheckConsensusSigns, a proposal is rejected once it can not be approved any more
+	m := (2*len(pubKeys) + 2) / 3
+	if invoke.Address == nutils.NodeManagerContractAddress && invoke.Method == node_manager.REJECT_PROPOSAL {
+		m = len(pubKeys) - m + 1
+	}
+	return newPSTransaction(PST_KIND_CONSENSUS, tx, uint16(m), pubKeys)
+}
+
+func newPSTransaction(kind string, tx *types.Transaction, m uint16, pubKeys []keypair.PublicKey) (*PSTransaction, error) {
+	pst := &PSTransaction{
+		Version: PST_VERSION,
+		Kind:    kind,
+		M:       m,
+		PubKeys: make([]string, 0, len(pubKeys)),
+	}
+	for _, pk := range pubKeys {
+		key := hex.EncodeToString(keypair.SerializePublicKey(pk))
+		if pst.indexOf(key) >= 0 {
+			return nil, fmt.Errorf("duplicate pub key:%s", key)
+		}
+		pst.PubKeys = append(pst.PubKeys, key)
+	}
+	unsigned := *tx
+	unsigned.Sigs = nil
+	sink := common.NewZeroCopySink(nil)
+	if err := unsigned.Serialization(sink); err != nil {
+		return nil, fmt.Errorf("tx serialization error:%s", err)
+	}
+	pst.Tx = hex.EncodeToString(sink.Bytes())
+	tx, err := pst.transaction()
+	if err != nil {
+		return nil, err
+	}
+	txHash := tx.Hash()
+	pst.TxHash = txHash.ToHexString()
+	if invoke, err := decodeNativeInvoke(tx); err == nil {
+		pst.Payload = &PSTPayload{
+			Contract: invoke.Address.ToHexString(),
+			Method:   invoke.Method,
+			Args:     hex.EncodeToString(invoke.Args),
+		}
+		for name, addr := range nutils.NativeContracts {
+			if addr == invoke.Address {
+				pst.Payload.ContractName = name
+			}
+		}
+	}
+	return pst, nil
+}
+
+//LoadPSTransaction read the PST file and check the collected signatures
+func LoadPSTransaction(path string) (*PSTransaction, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pst := &PSTransaction{}
+	if err := json.Unmarshal(data, pst); err != nil {
+		return nil, fmt.Errorf("invalid PST file %s:%s", path, err)
+	}
+	if pst.Version != PST_VERSION {
+		return nil, fmt.Errorf("unsupported PST version:%d", pst.Version)
+	}
+	if pst.Kind != PST_KIND_MULTISIG && pst.Kind != PST_KIND_CONSENSUS {
+		return nil, fmt.Errorf("unknown PST kind:%s", pst.Kind)
+	}
+	if err := pst.verify(); err != nil {
+		return nil, fmt.Errorf("invalid PST file %s:%s", path, err)
+	}
+	return pst, nil
+}
+
+//Save write the PST to path
+func (this *PSTransaction) Save(path string) error {
+	data, err := json.MarshalIndent(this, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+//Signed return the number of collected signatures
+func (this *PSTransaction) Signed() int {
+	if this.Kind == PST_KIND_CONSENSUS {
+		return len(this.Approvals)
+	}
+	return len(this.Sigs)
+}
+
+//IsSignedBy return whether the signature of pubKey is collected
+func (this *PSTransaction) IsSignedBy(pubKey string) bool {
+	if this.Kind == PST_KIND_CONSENSUS {
+		_, ok := this.Approvals[pubKey]
+		return ok
+	}
+	_, ok := this.Sigs[pubKey]
+	return ok
+}
+
+//Sign add the signature of signer, which must be one of the required pub keys
+func (this *PSTransaction) Sign(signer *account.Account) error {
+	key := hex.EncodeToString(keypair.SerializePublicKey(signer.PublicKey))
+	if this.indexOf(key) < 0 {
+		return fmt.Errorf("signer %s is not required by the PST", signer.Address.ToBase58())
+	}
+	if this.Kind == PST_KIND_CONSENSUS {
+		tx, err := this.approvalTransaction(signer.PublicKey)
+		if err != nil {
+			return err
+		}
+		if err := SignTransaction(signer, tx); err != nil {
+			return err
+		}
+		sink := common.NewZeroCopySink(nil)
+		if err := tx.Serialization(sink); err != nil {
+			return fmt.Errorf("tx serialization error:%s", err)
+		}
+		if this.Approvals == nil {
+			this.Approvals = make(map[string]string)
+		}
+		this.Approvals[key] = hex.EncodeToString(sink.Bytes())
+		return nil
+	}
+	tx, err := this.transaction()
+	if err != nil {
+		return err
+	}
+	txHash := tx.Hash()
+	sigData, err := Sign(txHash.ToArray(), signer)
+	if err != nil {
+		return fmt.Errorf("sign error:%s", err)
+	}
+	if this.Sigs == nil {
+		this.Sigs = make(map[string]string)
+	}
+	this.Sigs[key] = hex.EncodeToString(sigData)
+	return nil
+}
+
+//Merge add the signatures collected in other, which must be of the same transaction
+func (this *PSTransaction) Merge(other *PSTransaction) error {
+	if this.Kind != other.Kind || this.Tx != other.Tx || this.M != other.M || len(this.PubKeys) != len(other.PubKeys) {
+		return fmt.Errorf("PST of tx %s does not match tx %s", other.TxHash, this.TxHash)
+	}
+	for i, key := range this.PubKeys {
+		if other.PubKeys[i] != key {
+			return fmt.Errorf("PST of tx %s requires different pub keys", other.TxHash)
+		}
+	}
+	if err := other.verify(); err != nil {
+		return err
+	}
+	for key, sig := range other.Sigs {
+		if this.Sigs == nil {
+			this.Sigs = make(map[string]string)
+		}
+		this.Sigs[key] = sig
+	}
+	for key, raw := range other.Approvals {
+		if this.Approvals == nil {
+			this.Approvals = make(map[string]string)
+		}
+		this.Approvals[key] = raw
+	}
+	return nil
+}
+
+//Finalize return the transactions ready to send. It is the multi-signed transaction
+//for multisig PST, or the approval transactions of every operator for consensus PST
+func (this *PSTransaction) Finalize() ([]*types.Transaction, error) {
+	if this.Signed() < int(this.M) {
+		return nil, fmt.Errorf("not enough signatures, %d of %d collected", this.Signed(), this.M)
+	}
+	if this.Kind == PST_KIND_CONSENSUS {
+		txs := make([]*types.Transaction, 0, len(this.Approvals))
+		for _, key := range this.PubKeys {
+			raw, ok := this.Approvals[key]
+			if !ok {
+				continue
+			}
+			tx, err := decodeTransaction(raw)
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, tx)
+		}
+		return txs, nil
+	}
+	tx, err := this.transaction()
+	if err != nil {
+		return nil, err
+	}
+	pubKeys, err := this.pubKeys()
+	if err != nil {
+		return nil, err
+	}
+	sig := types.Sig{
+		PubKeys: pubKeys,
+		M:       this.M,
+		SigData: make([][]byte, 0, this.M),
+	}
+	//signatures in the order of pub keys, only m of them are checked
+	for _, key := range this.PubKeys {
+		sigData, ok := this.Sigs[key]
+		if !ok {
+			continue
+		}
+		data, err := hex.DecodeString(sigData)
+		if err != nil {
+			return nil, err
+		}
+		sig.SigData = append(sig.SigData, data)
+		if len(sig.SigData) == int(this.M) {
+			break
+		}
+	}
+	tx.Sigs = []types.Sig{sig}
+	sink := common.NewZeroCopySink(nil)
+	if err := tx.Serialization(sink); err != nil {
+		return nil, fmt.Errorf("tx serialization error:%s", err)
+	}
+	tx, err = types.TransactionFromRawBytes(sink.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return []*types.Transaction{tx}, nil
+}
+
+//verify check every collected signature is of a required pub key and valid
+func (this *PSTransaction) verify() error {
+	tx, err := this.transaction()
+	if err != nil {
+		return err
+	}
+	pubKeys, err := this.pubKeys()
+	if err != nil {
+		return err
+	}
+	txHash := tx.Hash()
+	for key, sigData := range this.Sigs {
+		index := this.indexOf(key)
+		if index < 0 {
+			return fmt.Errorf("signature of pub key %s is not required", key)
+		}
+		data, err := hex.DecodeString(sigData)
+		if err != nil {
+			return fmt.Errorf("invalid signature of pub key %s:%s", key, err)
+		}
+		if err := signature.Verify(pubKeys[index], txHash.ToArray(), data); err != nil {
+			return fmt.Errorf("invalid signature of pub key %s:%s", key, err)
+		}
+	}
+	for key, raw := range this.Approvals {
+		index := this.indexOf(key)
+		if index < 0 {
+			return fmt.Errorf("approval of pub key %s is not required", key)
+		}
+		approval, err := decodeTransaction(raw)
+		if err != nil {
+			return fmt.Errorf("invalid approval of pub key %s:%s", key, err)
+		}
+		expect, err := this.approvalTransaction(pubKeys[index])
+		if err != nil {
+			return err
+		}
+		if approval.Hash() != expect.Hash() {
+			return fmt.Errorf("approval of pub key %s is not the PST transaction", key)
+		}
+		if len(approval.Sigs) != 1 || len(approval.Sigs[0].SigData) != 1 {
+			return fmt.Errorf("approval of pub key %s is not single signed", key)
+		}
+		approvalHash := approval.Hash()
+		if err := signature.Verify(pubKeys[index], approvalHash.ToArray(), approval.Sigs[0].SigData[0]); err != nil {
+			return fmt.Errorf("invalid approval of pub key %s:%s", key, err)
+		}
+	}
+	return nil
+}
+
+//approvalTransaction return the unsigned approval of the operator with pubKey,
+//which is the PST transaction invoked with the address and paid by the operator
+func (this *PSTransaction) approvalTransaction(pubKey keypair.PublicKey) (*types.Transaction, error) {
+	tx, err := this.transaction()
+	if err != nil {
+		return nil, err
+	}
+	invoke, err := decodeNativeInvoke(tx)
+	if err != nil {
+		return nil, err
+	}
+	newParam, ok := consensusApprovals[invoke.Address][invoke.Method]
+	if !ok {
+		return nil, fmt.Errorf("method %s is not approved by consensus signs", invoke.Method)
+	}
+	param, addr := newParam()
+	if err := param.Deserialization(common.NewZeroCopySource(invoke.Args)); err != nil {
+		return nil, fmt.Errorf("deserialize args of %s error:%s", invoke.Method, err)
+	}
+	operator := types.AddressFromPubKey(pubKey)
+	*addr = operator
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	invoke.Args = sink.Bytes()
+
+	sink = common.NewZeroCopySink(nil)
+	invoke.Serialization(sink)
+	tx.Payload = &payload.InvokeCode{Code: sink.Bytes()}
+	tx.Payer = operator
+	tx.Sigs = nil
+	sink = common.NewZeroCopySink(nil)
+	if err := tx.Serialization(sink); err != nil {
+		return nil, fmt.Errorf("tx serialization error:%s", err)
+	}
+	return types.TransactionFromRawBytes(sink.Bytes())
+}
+
+func (this *PSTransaction) transaction() (*types.Transaction, error) {
+	return decodeTransaction(this.Tx)
+}
+
+func (this *PSTransaction) pubKeys() ([]keypair.PublicKey, error) {
+	pubKeys := make([]keypair.PublicKey, 0, len(this.PubKeys))
+	for _, key := range this.PubKeys {
+		data, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pub key:%s", key)
+		}
+		pk, err := keypair.DeserializePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pub key:%s", key)
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+	return pubKeys, nil
+}
+
+func (this *PSTransaction) indexOf(pubKey string) int {
+	for i, key := range this.PubKeys {
+		if key == pubKey {
+			return i
+		}
+	}
+	return -1
+}
+
+func decodeTransaction(raw string) (*types.Transaction, error) {
+	data, err := hex.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("tx hex decode error:%s", err)
+	}
+	return types.TransactionFromRawBytes(data)
+}
+
+func decodeNativeInvoke(tx *types.Transaction) (*states.ContractInvokeParam, error) {
+	invokeCode, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return nil, fmt.Errorf("tx is not an invocation")
+	}
+	param := &states.ContractInvokeParam{}
+	if err := param.Deserialization(common.NewZeroCopySource(invokeCode.Code)); err != nil {
+		return nil, fmt.Errorf("invalid native invocation:%s", err)
+	}
+	return param, nil
+}
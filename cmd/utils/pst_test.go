@@ -0,0 +1,121 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package utils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	"github.com/stretchr/testify/assert"
+)
+
+func newApproveTx(chainid uint64) *types.Transaction {
+	param := &side_chain_manager.ChainidParam{Chainid: chainid}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	invoke := &states.ContractInvokeParam{
+		Address: nutils.SideChainManagerContractAddress,
+		Method:  side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN,
+		Args:    sink.Bytes(),
+	}
+	sink = common.NewZeroCopySink(nil)
+	invoke.Serialization(sink)
+	return &types.Transaction{
+		TxType:  types.Invoke,
+		Nonce:   1,
+		Payload: &payload.InvokeCode{Code: sink.Bytes()},
+	}
+}
+
+func TestMultiSigPSTransaction(t *testing.T) {
+	accs := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	pubKeys := []keypair.PublicKey{accs[0].PublicKey, accs[1].PublicKey, accs[2].PublicKey}
+	pst, err := NewPSTransaction(newApproveTx(1), 2, pubKeys)
+	assert.Nil(t, err)
+	assert.Equal(t, "side_chain_manager", pst.Payload.ContractName)
+	assert.Equal(t, side_chain_manager.APPROVE_REGISTER_SIDE_CHAIN, pst.Payload.Method)
+
+	other := *pst
+	assert.Nil(t, pst.Sign(accs[2]))
+	assert.NotNil(t, pst.Sign(account.NewAccount("")))
+	_, err = pst.Finalize()
+	assert.NotNil(t, err)
+
+	//signed separately and merged in another order
+	assert.Nil(t, other.Sign(accs[0]))
+	assert.Nil(t, pst.Merge(&other))
+	assert.Equal(t, 2, pst.Signed())
+
+	path := "test.pst"
+	defer os.Remove(path)
+	assert.Nil(t, pst.Save(path))
+	loaded, err := LoadPSTransaction(path)
+	assert.Nil(t, err)
+	txs, err := loaded.Finalize()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(txs))
+	txHash := txs[0].Hash()
+	assert.Equal(t, pst.TxHash, txHash.ToHexString())
+	assert.Nil(t, signature.VerifyMultiSignature(txHash.ToArray(), txs[0].Sigs[0].PubKeys, 2, txs[0].Sigs[0].SigData))
+
+	//tampered signature is rejected
+	loaded.Sigs[loaded.PubKeys[1]] = loaded.Sigs[loaded.PubKeys[0]]
+	assert.Nil(t, loaded.Save(path))
+	_, err = LoadPSTransaction(path)
+	assert.NotNil(t, err)
+}
+
+func TestConsensusPSTransaction(t *testing.T) {
+	accs := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	pubKeys := make([]keypair.PublicKey, 0, len(accs))
+	for _, acc := range accs {
+		pubKeys = append(pubKeys, acc.PublicKey)
+	}
+	pst, err := NewConsensusPSTransaction(newApproveTx(8), pubKeys)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(3), pst.M)
+
+	for _, acc := range accs[1:] {
+		assert.Nil(t, pst.Sign(acc))
+	}
+	assert.Nil(t, pst.verify())
+	txs, err := pst.Finalize()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(txs))
+	for i, tx := range txs {
+		invoke, err := decodeNativeInvoke(tx)
+		assert.Nil(t, err)
+		param := new(side_chain_manager.ChainidParam)
+		assert.Nil(t, param.Deserialization(common.NewZeroCopySource(invoke.Args)))
+		assert.Equal(t, uint64(8), param.Chainid)
+		assert.Equal(t, accs[i+1].Address, param.Address)
+		assert.Equal(t, accs[i+1].Address, tx.Payer)
+	}
+
+	_, err = NewConsensusPSTransaction(&types.Transaction{TxType: types.Invoke, Payload: &payload.InvokeCode{}}, pubKeys)
+	assert.NotNil(t, err)
+}
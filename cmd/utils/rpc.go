@@ -25,12 +25,16 @@ import (
 	rpcerr "github.com/polynetwork/poly/http/base/error"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"strings"
 )
 
 //JsonRpc version
 const JSON_RPC_VERSION = "2.0"
 
+//API_KEY_ENV names the environment variable of the api key sent to nodes requiring authentication
+const API_KEY_ENV = "POLY_API_KEY"
+
 const (
 	ERROR_INVALID_PARAMS   = rpcerr.INVALID_PARAMS
 	ERROR_ONTOLOGY_COMMON  = 10000
@@ -63,11 +67,19 @@ type JsonRpcRequest struct {
 	Params  []interface{} `json:"params"`
 }
 
+//JsonRpcError object of a failed JsonRpcRequest
+type JsonRpcError struct {
+	Code    int64           `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
 //JsonRpcResponse object response for JsonRpcRequest
 type JsonRpcResponse struct {
-	Error  int64           `json:"error"`
-	Desc   string          `json:"desc"`
-	Result json.RawMessage `json:"result"`
+	Version string          `json:"jsonrpc"`
+	Id      string          `json:"id"`
+	Error   *JsonRpcError   `json:"error"`
+	Result  json.RawMessage `json:"result"`
 }
 
 func sendRpcRequest(method string, params []interface{}) ([]byte, *OntologyError) {
@@ -83,7 +95,15 @@ func sendRpcRequest(method string, params []interface{}) ([]byte, *OntologyError
 	}
 
 	addr := fmt.Sprintf("http://localhost:%d", config.DefConfig.Rpc.HttpJsonPort)
-	resp, err := http.Post(addr, "application/json", strings.NewReader(string(data)))
+	req, err := http.NewRequest("POST", addr, strings.NewReader(string(data)))
+	if err != nil {
+		return nil, NewOntologyError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key := os.Getenv(API_KEY_ENV); key != "" {
+		req.Header.Set("X-Api-Key", key)
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, NewOntologyError(err)
 	}
@@ -98,8 +118,8 @@ func sendRpcRequest(method string, params []interface{}) ([]byte, *OntologyError
 	if err != nil {
 		return nil, NewOntologyError(fmt.Errorf("json.Unmarshal JsonRpcResponse:%s error:%s", body, err))
 	}
-	if rpcRsp.Error != 0 {
-		return nil, NewOntologyError(fmt.Errorf("\n %s ", string(body)), rpcRsp.Error)
+	if rpcRsp.Error != nil {
+		return nil, NewOntologyError(fmt.Errorf("\n %s ", string(body)), rpcRsp.Error.Code)
 	}
 	return rpcRsp.Result, nil
 }
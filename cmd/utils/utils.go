@@ -113,6 +113,27 @@ func GetRawTransaction(txHash string) ([]byte, error) {
 	return nil, ontErr.Error
 }
 
+func GetRawTransactionData(txHash string) ([]byte, error) {
+	data, ontErr := sendRpcRequest("getrawtransaction", []interface{}{txHash})
+	if ontErr != nil {
+		switch ontErr.ErrorCode {
+		case ERROR_INVALID_PARAMS:
+			return nil, fmt.Errorf("invalid TxHash:%s", txHash)
+		}
+		return nil, ontErr.Error
+	}
+	hexStr := ""
+	err := json.Unmarshal(data, &hexStr)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal error:%s", err)
+	}
+	txData, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString error:%s", err)
+	}
+	return txData, nil
+}
+
 func GetBlock(hashOrHeight interface{}) ([]byte, error) {
 	data, ontErr := sendRpcRequest("getblock", []interface{}{hashOrHeight, 1})
 	if ontErr == nil {
@@ -278,6 +299,31 @@ func GetSmartContractEventInfo(txHash string) ([]byte, error) {
 	return nil, ontErr.Error
 }
 
+//GetStorage returns the storage value of contract by key, nil if not found
+func GetStorage(contract, key string) ([]byte, error) {
+	data, ontErr := sendRpcRequest("getstorage", []interface{}{contract, key})
+	if ontErr != nil {
+		switch ontErr.ErrorCode {
+		case ERROR_INVALID_PARAMS:
+			return nil, fmt.Errorf("invalid contract:%s or key:%s", contract, key)
+		}
+		return nil, ontErr.Error
+	}
+	hexStr := ""
+	err := json.Unmarshal(data, &hexStr)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal error:%s", err)
+	}
+	if hexStr == "" {
+		return nil, nil
+	}
+	value, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString error:%s", err)
+	}
+	return value, nil
+}
+
 func hasAlreadySig(data []byte, pk keypair.PublicKey, sigDatas [][]byte) bool {
 	for _, sigData := range sigDatas {
 		err := signature.Verify(pk, data, sigData)
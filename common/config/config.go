@@ -41,10 +41,12 @@ const (
 	DBFT_MIN_NODE_NUM        = 4 //min node number of dbft consensus
 	SOLO_MIN_NODE_NUM        = 1 //min node number of solo consensus
 	VBFT_MIN_NODE_NUM        = 4 //min node number of vbft consensus
+	HOTSTUFF_MIN_NODE_NUM    = 4 //min node number of hotstuff consensus
 
-	CONSENSUS_TYPE_DBFT = "dbft"
-	CONSENSUS_TYPE_SOLO = "solo"
-	CONSENSUS_TYPE_VBFT = "vbft"
+	CONSENSUS_TYPE_DBFT     = "dbft"
+	CONSENSUS_TYPE_SOLO     = "solo"
+	CONSENSUS_TYPE_VBFT     = "vbft"
+	CONSENSUS_TYPE_HOTSTUFF = "hotstuff"
 
 	DEFAULT_LOG_LEVEL                       = log.InfoLog
 	DEFAULT_MAX_LOG_SIZE                    = 100 //MByte
@@ -52,9 +54,13 @@ const (
 	DEFAULT_CONSENSUS_PORT                  = uint(20339)
 	DEFAULT_RPC_PORT                        = uint(20336)
 	DEFAULT_RPC_LOCAL_PORT                  = uint(20337)
+	DEFAULT_RPC_MAX_BATCH_SIZE              = uint(100)
 	DEFAULT_REST_PORT                       = uint(20334)
 	DEFAULT_WS_PORT                         = uint(20335)
+	DEFAULT_METRICS_PORT                    = uint(20340)
+	DEFAULT_GRPC_PORT                       = uint(20341)
 	DEFAULT_REST_MAX_CONN                   = uint(1024)
+	DEFAULT_API_MAX_REQUEST_SIZE            = uint(4 * 1024 * 1024)
 	DEFAULT_MAX_CONN_IN_BOUND               = uint(1024)
 	DEFAULT_MAX_CONN_OUT_BOUND              = uint(1024)
 	DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP = uint(16)
@@ -67,6 +73,10 @@ const (
 	DEFUALT_CLI_RPC_ADDRESS                 = "127.0.0.1"
 	DEFAULT_GAS_LIMIT                       = 20000
 	DEFAULT_GAS_PRICE                       = 500
+	DEFAULT_TXPOOL_MAX_TXS_PER_SIGNER       = uint(10000)
+	DEFAULT_TXPOOL_MAX_SIZE                 = uint64(512 * 1024 * 1024)
+	DEFAULT_TXPOOL_JOURNAL_SIZE             = uint64(64 * 1024 * 1024)
+	DEFAULT_TXPOOL_JOURNAL_FILE             = "txpool.journal"
 
 	DEFAULT_DATA_DIR      = "./Chain"
 	DEFAULT_RESERVED_FILE = "./peers.rsv"
@@ -106,6 +116,11 @@ var EXTRA_INFO_HEIGHT = map[uint32]uint32{
 
 var EXTRA_INFO_HEIGHT_FORK_CHECK bool
 
+var GOVERNANCE_PROPOSAL_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET: constants.GOVERNANCE_PROPOSAL_HEIGHT_MAINNET,
+	NETWORK_ID_TEST_NET: constants.GOVERNANCE_PROPOSAL_HEIGHT_TESTNET,
+}
+
 func GetNetworkMagic(id uint32) uint32 {
 	nid, ok := NETWORK_MAGIC[id]
 	if ok {
@@ -118,6 +133,10 @@ func GetExtraInfoHeight(id uint32) uint32 {
 	return EXTRA_INFO_HEIGHT[id]
 }
 
+func GetGovernanceProposalHeight(id uint32) uint32 {
+	return GOVERNANCE_PROPOSAL_HEIGHT[id]
+}
+
 func GetNetworkName(id uint32) string {
 	name, ok := NETWORK_NAME[id]
 	if ok {
@@ -240,6 +259,7 @@ type GenesisConfig struct {
 	SeedList      []string
 	ConsensusType string
 	VBFT          *VBFTConfig
+	HotStuff      *HotStuffConfig
 	DBFT          *DBFTConfig
 	SOLO          *SOLOConfig
 }
@@ -249,6 +269,7 @@ func NewGenesisConfig() *GenesisConfig {
 		SeedList:      make([]string, 0),
 		ConsensusType: CONSENSUS_TYPE_DBFT,
 		VBFT:          &VBFTConfig{},
+		HotStuff:      &HotStuffConfig{},
 		DBFT:          &DBFTConfig{},
 		SOLO:          &SOLOConfig{},
 	}
@@ -267,7 +288,73 @@ type VBFTConfig struct {
 	Peers                []*VBFTPeerInfo `json:"peers"`
 }
 
+func (self *VBFTConfig) InitConfigParam() *InitConfigParam {
+	return &InitConfigParam{
+		BlockMsgDelay:        self.BlockMsgDelay,
+		HashMsgDelay:         self.HashMsgDelay,
+		PeerHandshakeTimeout: self.PeerHandshakeTimeout,
+		MaxBlockChangeView:   self.MaxBlockChangeView,
+		VrfValue:             self.VrfValue,
+		VrfProof:             self.VrfProof,
+		Peers:                self.Peers,
+	}
+}
+
 func (self *VBFTConfig) Serialization(sink *common.ZeroCopySink) error {
+	return self.InitConfigParam().Serialization(sink)
+}
+
+func (this *VBFTConfig) Deserialization(source *common.ZeroCopySource) error {
+	param := new(InitConfigParam)
+	if err := param.Deserialization(source); err != nil {
+		return err
+	}
+	this.BlockMsgDelay = param.BlockMsgDelay
+	this.HashMsgDelay = param.HashMsgDelay
+	this.PeerHandshakeTimeout = param.PeerHandshakeTimeout
+	this.MaxBlockChangeView = param.MaxBlockChangeView
+	this.VrfValue = param.VrfValue
+	this.VrfProof = param.VrfProof
+	this.Peers = param.Peers
+	return nil
+}
+
+//
+// HotStuff genesis config, from local config file
+//
+type HotStuffConfig struct {
+	BlockInterval      uint32          `json:"block_interval"` // milliseconds
+	RoundTimeout       uint32          `json:"round_timeout"`  // milliseconds a round lasts beyond the block interval
+	MaxBlockChangeView uint32          `json:"max_block_change_view"`
+	Peers              []*VBFTPeerInfo `json:"peers"`
+}
+
+func (self *HotStuffConfig) InitConfigParam() *InitConfigParam {
+	return &InitConfigParam{
+		BlockMsgDelay:      self.BlockInterval,
+		HashMsgDelay:       self.RoundTimeout,
+		MaxBlockChangeView: self.MaxBlockChangeView,
+		Peers:              self.Peers,
+		ConsensusType:      CONSENSUS_TYPE_HOTSTUFF,
+	}
+}
+
+// InitConfigParam is the input of initConfig of node_manager in the genesis block, built from the
+// genesis config of the consensus engine. It is serialized the same as VBFTConfig, so that the
+// genesis blocks of vbft chains are unchanged. ConsensusType is only serialized for the engines
+// other than vbft, and is stored on chain by initConfig.
+type InitConfigParam struct {
+	BlockMsgDelay        uint32
+	HashMsgDelay         uint32
+	PeerHandshakeTimeout uint32
+	MaxBlockChangeView   uint32
+	VrfValue             string
+	VrfProof             string
+	Peers                []*VBFTPeerInfo
+	ConsensusType        string //empty for vbft
+}
+
+func (self *InitConfigParam) Serialization(sink *common.ZeroCopySink) error {
 	sink.WriteUint32(self.BlockMsgDelay)
 	sink.WriteUint32(self.HashMsgDelay)
 	sink.WriteUint32(self.PeerHandshakeTimeout)
@@ -280,10 +367,13 @@ func (self *VBFTConfig) Serialization(sink *common.ZeroCopySink) error {
 			return err
 		}
 	}
+	if self.ConsensusType != "" {
+		sink.WriteString(self.ConsensusType)
+	}
 	return nil
 }
 
-func (this *VBFTConfig) Deserialization(source *common.ZeroCopySource) error {
+func (this *InitConfigParam) Deserialization(source *common.ZeroCopySource) error {
 	blockMsgDelay, eof := source.NextUint32()
 	if eof {
 		return fmt.Errorf("serialization.ReadUint32, deserialize blockMsgDelay error!")
@@ -321,6 +411,13 @@ func (this *VBFTConfig) Deserialization(source *common.ZeroCopySource) error {
 		}
 		peers = append(peers, peer)
 	}
+	var consensusType string
+	if source.Len() > 0 {
+		consensusType, eof = source.NextString()
+		if eof {
+			return fmt.Errorf("serialization.ReadString, deserialize consensusType error!")
+		}
+	}
 	this.BlockMsgDelay = blockMsgDelay
 	this.HashMsgDelay = hashMsgDelay
 	this.PeerHandshakeTimeout = peerHandshakeTimeout
@@ -328,6 +425,7 @@ func (this *VBFTConfig) Deserialization(source *common.ZeroCopySource) error {
 	this.VrfValue = vrfValue
 	this.VrfProof = vrfProof
 	this.Peers = peers
+	this.ConsensusType = consensusType
 	return nil
 }
 
@@ -423,6 +521,7 @@ type RpcConfig struct {
 	EnableHttpJsonRpc bool
 	HttpJsonPort      uint
 	HttpLocalPort     uint
+	HttpMaxBatchSize  uint
 }
 
 type RestfulConfig struct {
@@ -440,6 +539,59 @@ type WebSocketConfig struct {
 	HttpKeyPath  string
 }
 
+//ApiAccessConfig restricts who may use the json rpc, restful and websocket apis and how often, it is shared by the
+//three servers. Without ApiKeys and JwtSecret no authentication is required
+type ApiAccessConfig struct {
+	ApiKeys          []string           //keys accepted in the X-Api-Key header or as bearer token
+	ReadOnlyApiKeys  []string           //keys that may not call write methods
+	JwtSecret        string             //secret of the HS256 json web tokens accepted as bearer token, scope "read" makes one read only
+	AuthWriteOnly    bool               //only write methods need authentication
+	AllowIPs         []string           //ips or CIDRs that may use the apis, empty allows all
+	WriteAllowIPs    []string           //ips or CIDRs that may call write methods, empty allows all
+	CorsOrigins      []string           //origins allowed by cors and websocket, "*" allows all
+	MaxRequestSize   uint               //max bytes of a request body or websocket message
+	RateLimit        float64            //calls per second of an ip, 0 is unlimited
+	RateBurst        uint               //calls of an ip allowed at once, defaults to RateLimit
+	MethodRateLimits map[string]float64 //calls per second of an ip to a method, on top of RateLimit
+}
+
+//TxPoolPriority gives the transactions invoking Method of Contract a priority, transactions
+//of higher priority are packed into blocks first and evicted from a full pool last
+type TxPoolPriority struct {
+	Contract string //native contract name or address in hex
+	Method   string //empty for every method of the contract
+	Priority uint8
+}
+
+//DefTxPoolPriorities puts governance first, then header sync before cross chain transfers
+var DefTxPoolPriorities = []*TxPoolPriority{
+	{Contract: "node_manager", Priority: 3},
+	{Contract: "side_chain_manager", Priority: 3},
+	{Contract: "relayer_manager", Priority: 3},
+	{Contract: "header_sync", Priority: 2},
+	{Contract: "cross_chain_manager", Method: "ImportOuterTransfer", Priority: 1},
+}
+
+//TxPoolConfig bounds the verified transactions in the pool, the bounds apply to
+//submitted transactions, not to those of blocks under verification
+type TxPoolConfig struct {
+	MaxTxsPerSigner uint              //max transactions of a signer in the pool, 0 for no limit
+	MaxSize         uint64            //max total size in bytes of the transactions in the pool, 0 for no limit
+	Priorities      []*TxPoolPriority //transactions not matched have priority 0
+	EnableJournal   bool              //keep the submitted transactions on disk across restarts
+	JournalMaxSize  uint64            //max size in bytes of the journal
+}
+
+type MetricsConfig struct {
+	EnableHttpMetrics bool
+	HttpMetricsPort   uint
+}
+
+type GrpcConfig struct {
+	EnableGrpc bool
+	GrpcPort   uint
+}
+
 type OntologyConfig struct {
 	Genesis   *GenesisConfig
 	Common    *CommonConfig
@@ -448,6 +600,10 @@ type OntologyConfig struct {
 	Rpc       *RpcConfig
 	Restful   *RestfulConfig
 	Ws        *WebSocketConfig
+	Access    *ApiAccessConfig
+	TxPool    *TxPoolConfig
+	Metrics   *MetricsConfig
+	Grpc      *GrpcConfig
 }
 
 func NewOntologyConfig() *OntologyConfig {
@@ -487,6 +643,7 @@ func NewOntologyConfig() *OntologyConfig {
 			EnableHttpJsonRpc: true,
 			HttpJsonPort:      DEFAULT_RPC_PORT,
 			HttpLocalPort:     DEFAULT_RPC_LOCAL_PORT,
+			HttpMaxBatchSize:  DEFAULT_RPC_MAX_BATCH_SIZE,
 		},
 		Restful: &RestfulConfig{
 			EnableHttpRestful: true,
@@ -496,6 +653,24 @@ func NewOntologyConfig() *OntologyConfig {
 			EnableHttpWs: true,
 			HttpWsPort:   DEFAULT_WS_PORT,
 		},
+		Access: &ApiAccessConfig{
+			CorsOrigins:    []string{"*"},
+			MaxRequestSize: DEFAULT_API_MAX_REQUEST_SIZE,
+		},
+		TxPool: &TxPoolConfig{
+			MaxTxsPerSigner: DEFAULT_TXPOOL_MAX_TXS_PER_SIGNER,
+			MaxSize:         DEFAULT_TXPOOL_MAX_SIZE,
+			Priorities:      DefTxPoolPriorities,
+			JournalMaxSize:  DEFAULT_TXPOOL_JOURNAL_SIZE,
+		},
+		Metrics: &MetricsConfig{
+			EnableHttpMetrics: false,
+			HttpMetricsPort:   DEFAULT_METRICS_PORT,
+		},
+		Grpc: &GrpcConfig{
+			EnableGrpc: false,
+			GrpcPort:   DEFAULT_GRPC_PORT,
+		},
 	}
 }
 
@@ -506,6 +681,10 @@ func (this *OntologyConfig) GetBookkeepers() ([]keypair.PublicKey, error) {
 		for _, peer := range this.Genesis.VBFT.Peers {
 			bookKeepers = append(bookKeepers, peer.PeerPubkey)
 		}
+	case CONSENSUS_TYPE_HOTSTUFF:
+		for _, peer := range this.Genesis.HotStuff.Peers {
+			bookKeepers = append(bookKeepers, peer.PeerPubkey)
+		}
 	case CONSENSUS_TYPE_DBFT:
 		bookKeepers = this.Genesis.DBFT.Bookkeepers
 	case CONSENSUS_TYPE_SOLO:
@@ -555,6 +734,8 @@ func (this *OntologyConfig) getDefNetworkIDFromGenesisConfig(genCfg *GenesisConf
 	switch this.Genesis.ConsensusType {
 	case CONSENSUS_TYPE_VBFT:
 		configData, err = json.Marshal(genCfg.VBFT)
+	case CONSENSUS_TYPE_HOTSTUFF:
+		configData, err = json.Marshal(genCfg.HotStuff)
 	case CONSENSUS_TYPE_DBFT:
 		configData, err = json.Marshal(genCfg.DBFT)
 	case CONSENSUS_TYPE_SOLO:
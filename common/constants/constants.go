@@ -19,6 +19,7 @@
 package constants
 
 import (
+	"math"
 	"time"
 )
 
@@ -43,3 +44,7 @@ const (
 // extra info change height
 const EXTRA_INFO_HEIGHT_MAINNET = 2917744
 const EXTRA_INFO_HEIGHT_TESTNET = 1664798
+
+// governance proposal change height, not scheduled yet
+const GOVERNANCE_PROPOSAL_HEIGHT_MAINNET = math.MaxUint32
+const GOVERNANCE_PROPOSAL_HEIGHT_TESTNET = math.MaxUint32
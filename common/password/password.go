@@ -36,6 +36,16 @@ func GetPassword() ([]byte, error) {
 	return passwd, nil
 }
 
+// GetMnemonic gets the HD wallet mnemonic from the command line input without echo
+func GetMnemonic() ([]byte, error) {
+	fmt.Printf("Mnemonic:")
+	mnemonic, err := gopass.GetPasswd()
+	if err != nil {
+		return nil, err
+	}
+	return mnemonic, nil
+}
+
 // GetConfirmedPassword gets double confirmed password from user input
 func GetConfirmedPassword() ([]byte, error) {
 	fmt.Printf("Password:")
@@ -64,6 +64,10 @@ func (self *TxPoolActor) VerifyBlock(txs []*types.Transaction, height uint32) er
 	return nil
 }
 
+func (self *TxPoolActor) SubmitTx(tx *types.Transaction) {
+	self.Pool.Tell(&txpool.TxReq{Tx: tx, Sender: txpool.HttpSender})
+}
+
 type P2PActor struct {
 	P2P *actor.PID
 }
@@ -23,6 +23,12 @@ import "github.com/polynetwork/poly/core/types"
 type StartConsensus struct{}
 type StopConsensus struct{}
 
+//Unsupported answers the requests a consensus engine does not serve, so that the callers fail at once
+//instead of waiting for the request timeout
+type Unsupported struct {
+	Request string
+}
+
 //internal Message
 type TimeOut struct{}
 type BlockCompleted struct {
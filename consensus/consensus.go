@@ -19,9 +19,12 @@
 package consensus
 
 import (
+	"fmt"
+
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/consensus/hotstuff"
 	"github.com/polynetwork/poly/consensus/solo"
 	"github.com/polynetwork/poly/consensus/vbft"
 )
@@ -33,22 +36,41 @@ type ConsensusService interface {
 }
 
 const (
-	CONSENSUS_SOLO = "solo"
-	CONSENSUS_VBFT = "vbft"
+	CONSENSUS_SOLO     = "solo"
+	CONSENSUS_VBFT     = "vbft"
+	CONSENSUS_HOTSTUFF = "hotstuff"
 )
 
+// ConsensusConstructor creates the consensus service of an engine
+type ConsensusConstructor func(account *account.Account, txpool *actor.PID, p2p *actor.PID) (ConsensusService, error)
+
+var engines = make(map[string]ConsensusConstructor)
+
+// RegisterConsensus makes a consensus engine selectable by the consensus type of genesis config
+func RegisterConsensus(consensusType string, constructor ConsensusConstructor) {
+	engines[consensusType] = constructor
+}
+
+func init() {
+	RegisterConsensus(CONSENSUS_SOLO, func(account *account.Account, txpool *actor.PID, p2p *actor.PID) (ConsensusService, error) {
+		return solo.NewSoloService(account, txpool)
+	})
+	RegisterConsensus(CONSENSUS_VBFT, func(account *account.Account, txpool *actor.PID, p2p *actor.PID) (ConsensusService, error) {
+		return vbft.NewVbftServer(account, txpool, p2p)
+	})
+	RegisterConsensus(CONSENSUS_HOTSTUFF, func(account *account.Account, txpool *actor.PID, p2p *actor.PID) (ConsensusService, error) {
+		return hotstuff.NewHotStuffServer(account, txpool, p2p)
+	})
+}
+
 func NewConsensusService(consensusType string, account *account.Account, txpool *actor.PID, ledger *actor.PID, p2p *actor.PID) (ConsensusService, error) {
 	if consensusType == "" {
 		consensusType = CONSENSUS_SOLO
 	}
-	var consensus ConsensusService
-	var err error
-	switch consensusType {
-	case CONSENSUS_SOLO:
-		consensus, err = solo.NewSoloService(account, txpool)
-	case CONSENSUS_VBFT:
-		consensus, err = vbft.NewVbftServer(account, txpool, p2p)
+	constructor, present := engines[consensusType]
+	if !present {
+		return nil, fmt.Errorf("unsupported consensus type %s", consensusType)
 	}
 	log.Infof("ConsensusType:%s", consensusType)
-	return consensus, err
+	return constructor(account, txpool, p2p)
 }
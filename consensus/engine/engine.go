@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/store"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+)
+
+// State is the world state after executing a block. WriteSet only holds the changes of the block
+// at Height, the changes of uncommitted ancestors are found through Parent.
+type State struct {
+	Height          uint32
+	WriteSet        *overlaydb.MemDB
+	CrossStatesRoot common.Uint256
+	Parent          *State
+
+	result *store.ExecuteResult // set when the block is executed right on top of the ledger
+}
+
+// Uncommitted returns the states above the committed height, from the oldest
+func (self *State) Uncommitted(committed uint32) []*State {
+	var states []*State
+	for s := self; s != nil && s.Height > committed; s = s.Parent {
+		if s.WriteSet != nil {
+			states = append(states, s)
+		}
+	}
+	for i, j := 0, len(states)-1; i < j; i, j = i+1, j-1 {
+		states[i], states[j] = states[j], states[i]
+	}
+	return states
+}
+
+// Backend is everything a consensus engine needs from the node to propose, validate and commit
+// blocks. Uncommitted ancestors are always ordered from the first block above the ledger to the
+// parent of the block concerned.
+type Backend interface {
+	// CommittedHeight returns the height of the latest block in ledger
+	CommittedHeight() uint32
+	// CommittedState returns the latest block in ledger and the state after it
+	CommittedState() (*types.Block, *State, error)
+	// CommittedBlock returns the block at height in ledger and the state after it
+	CommittedBlock(height uint32) (*types.Block, *State, error)
+	// ProposeTransactions collects transactions for a new block at height on top of parent,
+	// including the governance system transaction when the governance view expires
+	ProposeTransactions(height uint32, parent *State, ancestors []*types.Block) ([]*types.Transaction, error)
+	// VerifyTransactions checks the transactions of a block proposed by another validator
+	VerifyTransactions(block *types.Block, ancestors []*types.Block) error
+	// BlockRoot returns the block root of a block whose previous blocks starting at startHeight
+	// have the given hashes
+	BlockRoot(startHeight uint32, prevHashes []common.Uint256) common.Uint256
+	// Execute executes block on top of the parent state without persisting anything
+	Execute(block *types.Block, parent *State) (*State, error)
+	// Commit persists a block carrying a quorum of validator signatures to ledger, state is the
+	// result of Execute on the block if any, so that the block is not executed twice
+	Commit(block *types.Block, state *State) error
+	// GovernanceView returns the governance view of node_manager in state
+	GovernanceView(state *State) (*node_manager.GovernanceView, error)
+	// PeerPool returns the peer pool of node_manager for view in state
+	PeerPool(state *State, view uint32) (*node_manager.PeerPoolMap, error)
+	// Validators returns the validators of the governance view in state
+	Validators(state *State) (*ValidatorSet, error)
+	// Configuration returns the consensus configuration of node_manager in state
+	Configuration(state *State) (*node_manager.Configuration, error)
+}
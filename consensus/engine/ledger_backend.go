@@ -0,0 +1,322 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	actorTypes "github.com/polynetwork/poly/consensus/actor"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store"
+	scommon "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	nstates "github.com/polynetwork/poly/native/states"
+	"github.com/polynetwork/poly/validator/increment"
+)
+
+// LedgerBackend is the Backend of a consensus node, backed by the default ledger and the tx pool
+type LedgerBackend struct {
+	ledger        *ledger.Ledger
+	poolActor     *actorTypes.TxPoolActor
+	incrValidator *increment.IncrementValidator
+}
+
+func NewLedgerBackend(ledger *ledger.Ledger, poolActor *actorTypes.TxPoolActor) *LedgerBackend {
+	return &LedgerBackend{
+		ledger:        ledger,
+		poolActor:     poolActor,
+		incrValidator: increment.NewIncrementValidator(20),
+	}
+}
+
+// OnBlockCommitted must be called for every block saved to ledger
+func (self *LedgerBackend) OnBlockCommitted(block *types.Block) {
+	self.incrValidator.AddBlock(block)
+}
+
+func (self *LedgerBackend) CommittedHeight() uint32 {
+	return self.ledger.GetCurrentBlockHeight()
+}
+
+func (self *LedgerBackend) CommittedState() (*types.Block, *State, error) {
+	return self.CommittedBlock(self.ledger.GetCurrentBlockHeight())
+}
+
+func (self *LedgerBackend) CommittedBlock(height uint32) (*types.Block, *State, error) {
+	block, err := self.ledger.GetBlockByHeight(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetBlockByHeight %d: %s", height, err)
+	}
+	crossStatesRoot, err := self.ledger.GetCrossStateRoot(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetCrossStateRoot %d: %s", height, err)
+	}
+	return block, &State{Height: height, CrossStatesRoot: crossStatesRoot}, nil
+}
+
+func (self *LedgerBackend) validHeight() uint32 {
+	height := self.ledger.GetCurrentBlockHeight()
+	start, end := self.incrValidator.BlockRange()
+	if height+1 == end {
+		return start
+	}
+	self.incrValidator.Clean()
+	log.Infof("increment validator block height %v != ledger block height %v", int(end)-1, height)
+	return height
+}
+
+func ancestorTxs(ancestors []*types.Block) map[common.Uint256]bool {
+	txs := make(map[common.Uint256]bool)
+	for _, blk := range ancestors {
+		for _, tx := range blk.Transactions {
+			txs[tx.Hash()] = true
+		}
+	}
+	return txs
+}
+
+func (self *LedgerBackend) ProposeTransactions(height uint32, parent *State, ancestors []*types.Block) ([]*types.Transaction, error) {
+	sysTx, err := self.governanceTransaction(height, parent)
+	if err != nil {
+		return nil, err
+	}
+	transactions := make([]*types.Transaction, 0)
+	if sysTx != nil {
+		transactions = append(transactions, sysTx)
+	}
+
+	validHeight := self.validHeight()
+	pending := ancestorTxs(ancestors)
+	for _, entry := range self.poolActor.GetTxnPool(true, validHeight) {
+		if pending[entry.Tx.Hash()] {
+			continue
+		}
+		if err := self.incrValidator.Verify(entry.Tx, validHeight); err == nil {
+			transactions = append(transactions, entry.Tx)
+		}
+	}
+	return transactions, nil
+}
+
+func (self *LedgerBackend) VerifyTransactions(block *types.Block, ancestors []*types.Block) error {
+	parentTxs := ancestorTxs(ancestors)
+	txs := block.Transactions
+	if len(txs) > 0 && isGovernanceTransaction(txs[0], block.Header.Height) {
+		txs = txs[1:]
+	}
+	seen := make(map[common.Uint256]bool, len(txs))
+	for _, tx := range txs {
+		hash := tx.Hash()
+		if parentTxs[hash] || seen[hash] {
+			return fmt.Errorf("tx %s duplicated", hash.ToHexString())
+		}
+		seen[hash] = true
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+	validHeight := self.validHeight()
+	if err := self.poolActor.VerifyBlock(txs, validHeight); err != nil {
+		return fmt.Errorf("verify block txs: %s", err)
+	}
+	for _, tx := range txs {
+		if err := self.incrValidator.Verify(tx, validHeight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *LedgerBackend) BlockRoot(startHeight uint32, prevHashes []common.Uint256) common.Uint256 {
+	return self.ledger.GetBlockRootWithPreBlockHashes(startHeight, prevHashes)
+}
+
+func (self *LedgerBackend) Execute(block *types.Block, parent *State) (*State, error) {
+	committed := self.ledger.GetCurrentBlockHeight()
+	states := parent.Uncommitted(committed)
+	if len(states) == 0 && block.Header.Height == committed+1 {
+		result, err := self.ledger.ExecuteBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		if result.WriteSet == nil {
+			return nil, fmt.Errorf("block %d saved during execution", block.Header.Height)
+		}
+		return &State{
+			Height:          block.Header.Height,
+			WriteSet:        result.WriteSet,
+			CrossStatesRoot: result.CrossStatesRoot,
+			Parent:          parent,
+			result:          &result,
+		}, nil
+	}
+	var writeSet *overlaydb.MemDB
+	if len(states) > 0 {
+		writeSet = overlaydb.NewMemDB(0, 0)
+		for _, s := range states {
+			s.WriteSet.ForEach(func(key, val []byte) {
+				writeSet.Put(key, val)
+			})
+		}
+	}
+	result, err := self.ledger.ExecuteBlockWithWriteSet(block, writeSet)
+	if err != nil {
+		return nil, err
+	}
+	return &State{
+		Height:          block.Header.Height,
+		WriteSet:        result.WriteSet,
+		CrossStatesRoot: result.CrossStatesRoot,
+		Parent:          parent,
+	}, nil
+}
+
+func (self *LedgerBackend) Commit(block *types.Block, state *State) error {
+	height := self.ledger.GetCurrentBlockHeight()
+	if block.Header.Height <= height {
+		// saved by block sync already
+		hash, saved := block.Hash(), self.ledger.GetBlockHash(block.Header.Height)
+		if hash != saved {
+			return fmt.Errorf("block %d in ledger is %s, not %s", block.Header.Height, saved.ToHexString(), hash.ToHexString())
+		}
+		return nil
+	}
+	var result store.ExecuteResult
+	if state != nil && state.result != nil && block.Header.Height == height+1 {
+		// executed on top of the same ledger
+		result = *state.result
+	} else {
+		var err error
+		if result, err = self.ledger.ExecuteBlock(block); err != nil {
+			return fmt.Errorf("execute block %d: %s", block.Header.Height, err)
+		}
+	}
+	if err := self.ledger.SubmitBlock(block, result); err != nil {
+		return fmt.Errorf("submit block %d: %s", block.Header.Height, err)
+	}
+	return nil
+}
+
+func (self *LedgerBackend) GovernanceView(state *State) (*node_manager.GovernanceView, error) {
+	data, err := self.getStorage(state, []byte(node_manager.GOVERNANCE_VIEW))
+	if err != nil {
+		return nil, fmt.Errorf("get governance view: %s", err)
+	}
+	view := new(node_manager.GovernanceView)
+	if err := view.Deserialization(common.NewZeroCopySource(data)); err != nil {
+		return nil, fmt.Errorf("deserialize governance view: %s", err)
+	}
+	return view, nil
+}
+
+func (self *LedgerBackend) PeerPool(state *State, view uint32) (*node_manager.PeerPoolMap, error) {
+	key := append([]byte(node_manager.PEER_POOL), nutils.GetUint32Bytes(view)...)
+	data, err := self.getStorage(state, key)
+	if err != nil {
+		return nil, fmt.Errorf("get peer pool of view %d: %s", view, err)
+	}
+	peerPoolMap := &node_manager.PeerPoolMap{
+		PeerPoolMap: make(map[string]*node_manager.PeerPoolItem),
+	}
+	if err := peerPoolMap.Deserialization(common.NewZeroCopySource(data)); err != nil {
+		return nil, fmt.Errorf("deserialize peer pool: %s", err)
+	}
+	return peerPoolMap, nil
+}
+
+func (self *LedgerBackend) Validators(state *State) (*ValidatorSet, error) {
+	view, err := self.GovernanceView(state)
+	if err != nil {
+		return nil, err
+	}
+	peerPoolMap, err := self.PeerPool(state, view.View)
+	if err != nil {
+		return nil, err
+	}
+	return ValidatorSetFromPeerPool(view.View, peerPoolMap)
+}
+
+func (self *LedgerBackend) Configuration(state *State) (*node_manager.Configuration, error) {
+	data, err := self.getStorage(state, []byte(node_manager.VBFT_CONFIG))
+	if err != nil {
+		return nil, fmt.Errorf("get consensus config: %s", err)
+	}
+	cfg := new(node_manager.Configuration)
+	if err := cfg.Deserialization(common.NewZeroCopySource(data)); err != nil {
+		return nil, fmt.Errorf("deserialize consensus config: %s", err)
+	}
+	return cfg, nil
+}
+
+// governanceTransaction returns the commitDpos transaction once the governance view of parent
+// has lasted MaxBlockChangeView blocks
+func (self *LedgerBackend) governanceTransaction(height uint32, parent *State) (*types.Transaction, error) {
+	view, err := self.GovernanceView(parent)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := self.Configuration(parent)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxBlockChangeView == 0 || height-view.Height < cfg.MaxBlockChangeView {
+		return nil, nil
+	}
+	return newGovernanceTransaction(height), nil
+}
+
+func (self *LedgerBackend) getStorage(state *State, key []byte) ([]byte, error) {
+	rawKey := make([]byte, 0, 1+common.ADDR_LEN+len(key))
+	rawKey = append(rawKey, byte(scommon.ST_STORAGE))
+	rawKey = append(rawKey, nutils.NodeManagerContractAddress[:]...)
+	rawKey = append(rawKey, key...)
+	committed := self.ledger.GetCurrentBlockHeight()
+	for s := state; s != nil && s.Height > committed; s = s.Parent {
+		if s.WriteSet == nil {
+			continue
+		}
+		rawValue, unknown := s.WriteSet.Get(rawKey)
+		if unknown {
+			continue
+		}
+		if len(rawValue) == 0 {
+			return nil, scommon.ErrNotFound
+		}
+		return states.GetValueFromRawStorageItem(rawValue)
+	}
+	return self.ledger.GetStorageItem(nutils.NodeManagerContractAddress, key)
+}
+
+func newGovernanceTransaction(height uint32) *types.Transaction {
+	param := &nstates.ContractInvokeParam{Address: nutils.NodeManagerContractAddress,
+		Method: node_manager.COMMIT_DPOS, Args: []byte{}}
+	sink := new(common.ZeroCopySink)
+	param.Serialization(sink)
+	return genesis.NewInvokeTransaction(sink.Bytes(), height)
+}
+
+func isGovernanceTransaction(tx *types.Transaction, height uint32) bool {
+	return tx.Hash() == newGovernanceTransaction(height).Hash()
+}
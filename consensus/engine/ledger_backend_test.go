@@ -0,0 +1,138 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package engine
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/ledger"
+	_ "github.com/polynetwork/poly/native/service" // register the native contracts for the genesis block
+)
+
+// newTestBackend opens a ledger in a temp dir initialized with a vbft genesis block of four peers.
+// The genesis of config.DefConfig is taken over while the test runs
+func newTestBackend(t *testing.T) *LedgerBackend {
+	log.InitLog(log.InfoLog, log.Stdout)
+	dir, err := ioutil.TempDir("", "engine_backend")
+	if err != nil {
+		t.Fatalf("TempDir error %s", err)
+	}
+	db, err := ledger.NewLedger(dir)
+	if err != nil {
+		t.Fatalf("NewLedger error %s", err)
+	}
+	oldGenesis := config.DefConfig.Genesis
+	t.Cleanup(func() {
+		config.DefConfig.Genesis = oldGenesis
+		db.Close()
+		os.RemoveAll(dir)
+	})
+	vbftConfig := &config.VBFTConfig{
+		BlockMsgDelay:        5000,
+		HashMsgDelay:         5000,
+		PeerHandshakeTimeout: 10,
+		MaxBlockChangeView:   10000,
+		VrfValue:             config.MainNetConfig.VBFT.VrfValue,
+		VrfProof:             config.MainNetConfig.VBFT.VrfProof,
+	}
+	var bookkeepers []keypair.PublicKey
+	for i := 0; i < 4; i++ {
+		acc := account.NewAccount("SHA256withECDSA")
+		bookkeepers = append(bookkeepers, acc.PublicKey)
+		vbftConfig.Peers = append(vbftConfig.Peers, &config.VBFTPeerInfo{
+			Index:      uint32(i + 1),
+			PeerPubkey: PubKeyID(acc.PublicKey),
+			Address:    acc.Address.ToBase58(),
+		})
+	}
+	config.DefConfig.Genesis = &config.GenesisConfig{
+		SeedList:      []string{},
+		ConsensusType: config.CONSENSUS_TYPE_VBFT,
+		VBFT:          vbftConfig,
+		DBFT:          &config.DBFTConfig{},
+		SOLO:          &config.SOLOConfig{},
+	}
+	block, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	if err != nil {
+		t.Fatalf("BuildGenesisBlock error %s", err)
+	}
+	if err := db.Init(bookkeepers, block); err != nil {
+		t.Fatalf("Init ledger error %s", err)
+	}
+	return NewLedgerBackend(db, nil)
+}
+
+func TestLedgerBackendCommitted(t *testing.T) {
+	backend := newTestBackend(t)
+	if height := backend.CommittedHeight(); height != 0 {
+		t.Fatalf("committed height %d", height)
+	}
+	block, state, err := backend.CommittedBlock(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Hash() != backend.ledger.GetBlockHash(0) || state.Height != 0 {
+		t.Fatalf("committed block at %d is not genesis", state.Height)
+	}
+	latest, _, err := backend.CommittedState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Hash() != block.Hash() {
+		t.Fatalf("committed state at block %d", latest.Header.Height)
+	}
+}
+
+func TestLedgerBackendGovernance(t *testing.T) {
+	backend := newTestBackend(t)
+	state := &State{}
+	view, err := backend.GovernanceView(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peerPool, err := backend.PeerPool(state, view.View)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peers := config.DefConfig.Genesis.VBFT.Peers
+	if len(peerPool.PeerPoolMap) != len(peers) {
+		t.Fatalf("%d peers in pool of view %d, %d in genesis", len(peerPool.PeerPoolMap), view.View, len(peers))
+	}
+	for _, peer := range peers {
+		if _, present := peerPool.PeerPoolMap[peer.PeerPubkey]; !present {
+			t.Fatalf("genesis peer %s not in pool", peer.PeerPubkey)
+		}
+	}
+	if _, err := backend.PeerPool(state, view.View+1); err == nil {
+		t.Fatalf("peer pool of view %d not saved yet", view.View+1)
+	}
+
+	validators, err := backend.Validators(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validators.View != view.View || len(validators.Validators) != len(peers) {
+		t.Fatalf("%d validators of view %d", len(validators.Validators), validators.View)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/polynetwork/poly/core/types"
+)
+
+// PeerConfig is a validator listed in the consensus payload of a block header
+type PeerConfig struct {
+	Index uint32 `json:"index"`
+	ID    string `json:"id"`
+}
+
+// ChainConfig announces the validators taking over after a block
+type ChainConfig struct {
+	View  uint32        `json:"view"`
+	N     uint32        `json:"n"`
+	C     uint32        `json:"c"`
+	Peers []*PeerConfig `json:"peers"`
+}
+
+// BlockInfo is the consensus payload of a block header. Ledger sync and the header sync of other
+// chains read the validator changes of every engine from it, so its json fields are named the
+// same as the vbft payload.
+type BlockInfo struct {
+	Proposer           uint32       `json:"leader"`
+	LastConfigBlockNum uint32       `json:"last_config_block_num"`
+	NewChainConfig     *ChainConfig `json:"new_chain_config"`
+}
+
+// NewChainConfig returns the chain config announcing validators
+func NewChainConfig(validators *ValidatorSet) *ChainConfig {
+	peers := make([]*PeerConfig, 0, validators.Size())
+	for _, v := range validators.Validators {
+		peers = append(peers, &PeerConfig{Index: v.Index, ID: v.ID})
+	}
+	return &ChainConfig{
+		View:  validators.View,
+		N:     uint32(validators.Size()),
+		C:     uint32(validators.Faulty()),
+		Peers: peers,
+	}
+}
+
+func BlockInfoOf(header *types.Header) (*BlockInfo, error) {
+	info := &BlockInfo{}
+	if err := json.Unmarshal(header.ConsensusPayload, info); err != nil {
+		return nil, fmt.Errorf("unmarshal block info: %s", err)
+	}
+	return info, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/poly/account"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/types"
+)
+
+func TestBlockInfo(t *testing.T) {
+	var validators []*Validator
+	for i := 0; i < 4; i++ {
+		acc := account.NewAccount("SHA256withECDSA")
+		validators = append(validators, &Validator{Index: uint32(i + 1), ID: PubKeyID(acc.PublicKey), PubKey: acc.PublicKey})
+	}
+	info := &BlockInfo{Proposer: 3, LastConfigBlockNum: 7, NewChainConfig: NewChainConfig(NewValidatorSet(2, validators))}
+	payload, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &types.Header{ConsensusPayload: payload}
+	decoded, err := BlockInfoOf(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := json.Marshal(decoded); string(again) != string(payload) {
+		t.Fatalf("block info changed after decoding: %s", again)
+	}
+
+	// ledger sync reads the payload as vbft
+	vbftInfo, err := vconfig.VbftBlock(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := vbftInfo.NewChainConfig
+	if vbftInfo.Proposer != 3 || vbftInfo.LastConfigBlockNum != 7 || cfg == nil ||
+		cfg.View != 2 || cfg.N != 4 || cfg.C != 1 || len(cfg.Peers) != 4 {
+		t.Fatalf("unexpected vbft block info %+v", vbftInfo)
+	}
+	for i, peer := range cfg.Peers {
+		if peer.Index != validators[i].Index || peer.ID != validators[i].ID {
+			t.Fatalf("peer %d is %+v", i, peer)
+		}
+	}
+
+	if _, err := BlockInfoOf(&types.Header{ConsensusPayload: []byte("{")}); err == nil {
+		t.Fatal("invalid payload accepted")
+	}
+}
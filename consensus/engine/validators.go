@@ -0,0 +1,173 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+)
+
+type Validator struct {
+	Index  uint32
+	ID     string
+	PubKey keypair.PublicKey
+}
+
+// ValidatorSet is the consensus peers of a governance view ordered by peer index, the leader
+// of a round is chosen round-robin over the set.
+type ValidatorSet struct {
+	View       uint32
+	Validators []*Validator
+	indexes    map[string]int
+}
+
+func NewValidatorSet(view uint32, validators []*Validator) *ValidatorSet {
+	sort.SliceStable(validators, func(i, j int) bool {
+		return validators[i].Index < validators[j].Index
+	})
+	set := &ValidatorSet{
+		View:       view,
+		Validators: validators,
+		indexes:    make(map[string]int, len(validators)),
+	}
+	for i, v := range validators {
+		set.indexes[v.ID] = i
+	}
+	return set
+}
+
+// ValidatorSetFromPeerPool builds the validator set from the consensus peers of node_manager
+func ValidatorSetFromPeerPool(view uint32, peerPoolMap *node_manager.PeerPoolMap) (*ValidatorSet, error) {
+	validators := make([]*Validator, 0, len(peerPoolMap.PeerPoolMap))
+	for _, item := range peerPoolMap.PeerPoolMap {
+		if item.Status != node_manager.ConsensusStatus {
+			continue
+		}
+		raw, err := hex.DecodeString(item.PeerPubkey)
+		if err != nil {
+			return nil, fmt.Errorf("decode peer pubkey %s: %s", item.PeerPubkey, err)
+		}
+		pk, err := keypair.DeserializePublicKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("deserialize peer pubkey %s: %s", item.PeerPubkey, err)
+		}
+		validators = append(validators, &Validator{Index: item.Index, ID: item.PeerPubkey, PubKey: pk})
+	}
+	if len(validators) == 0 {
+		return nil, fmt.Errorf("no consensus peer in view %d", view)
+	}
+	return NewValidatorSet(view, validators), nil
+}
+
+func PubKeyID(pk keypair.PublicKey) string {
+	return hex.EncodeToString(keypair.SerializePublicKey(pk))
+}
+
+func (self *ValidatorSet) Size() int {
+	return len(self.Validators)
+}
+
+// Quorum is the number of signatures required to certify a block, same as the multi-signature
+// threshold of the bookkeeper address
+func (self *ValidatorSet) Quorum() int {
+	n := len(self.Validators)
+	return n - (n-1)/3
+}
+
+// Faulty is the number of byzantine validators tolerated
+func (self *ValidatorSet) Faulty() int {
+	return (len(self.Validators) - 1) / 3
+}
+
+func (self *ValidatorSet) Leader(round uint64) *Validator {
+	return self.Validators[round%uint64(len(self.Validators))]
+}
+
+func (self *ValidatorSet) Contains(pk keypair.PublicKey) bool {
+	_, present := self.indexes[PubKeyID(pk)]
+	return present
+}
+
+func (self *ValidatorSet) GetByID(id string) *Validator {
+	if i, present := self.indexes[id]; present {
+		return self.Validators[i]
+	}
+	return nil
+}
+
+func (self *ValidatorSet) PubKeys() []keypair.PublicKey {
+	keys := make([]keypair.PublicKey, 0, len(self.Validators))
+	for _, v := range self.Validators {
+		keys = append(keys, v.PubKey)
+	}
+	return keys
+}
+
+// Address is the NextBookkeeper of the block after which this set takes over
+func (self *ValidatorSet) Address() (common.Address, error) {
+	return types.AddressFromBookkeepers(self.PubKeys())
+}
+
+func (self *ValidatorSet) Equal(other *ValidatorSet) bool {
+	if other == nil || self.View != other.View || len(self.Validators) != len(other.Validators) {
+		return false
+	}
+	for i, v := range self.Validators {
+		o := other.Validators[i]
+		if v.Index != o.Index || v.ID != o.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// Signer returns the validator whose signature over data is sig
+func (self *ValidatorSet) Signer(data []byte, sig []byte) (*Validator, error) {
+	for _, v := range self.Validators {
+		if signature.Verify(v.PubKey, data, sig) == nil {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("signature not from validator set")
+}
+
+// VerifyQuorum checks that sigs holds a quorum of distinct validator signatures over data
+func (self *ValidatorSet) VerifyQuorum(data []byte, sigs [][]byte) error {
+	if len(sigs) < self.Quorum() {
+		return fmt.Errorf("%d signatures less than quorum %d", len(sigs), self.Quorum())
+	}
+	signers := make(map[string]bool, len(sigs))
+	for i, sig := range sigs {
+		v, err := self.Signer(data, sig)
+		if err != nil {
+			return fmt.Errorf("signature %d: %s", i, err)
+		}
+		if signers[v.ID] {
+			return fmt.Errorf("validator %s signed twice", v.ID)
+		}
+		signers[v.ID] = true
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+)
+
+func TestValidatorSet(t *testing.T) {
+	var accounts []*account.Account
+	var validators []*Validator
+	for i := 0; i < 4; i++ {
+		acc := account.NewAccount("SHA256withECDSA")
+		accounts = append(accounts, acc)
+		validators = append(validators, &Validator{Index: uint32(4 - i), ID: PubKeyID(acc.PublicKey), PubKey: acc.PublicKey})
+	}
+	set := NewValidatorSet(1, validators)
+	if set.Quorum() != 3 || set.Faulty() != 1 {
+		t.Fatalf("quorum %d faulty %d", set.Quorum(), set.Faulty())
+	}
+	for round := uint64(0); round < 8; round++ {
+		if leader := set.Leader(round); leader.Index != uint32(round%4)+1 {
+			t.Fatalf("leader of round %d is %d", round, leader.Index)
+		}
+	}
+
+	data := []byte("block hash")
+	var sigs [][]byte
+	for _, acc := range accounts[:3] {
+		sig, err := signature.Sign(acc, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+	}
+	if err := set.VerifyQuorum(data, sigs); err != nil {
+		t.Fatal(err)
+	}
+	if err := set.VerifyQuorum(data, sigs[:2]); err == nil {
+		t.Fatal("two signatures accepted as quorum")
+	}
+	again, _ := signature.Sign(accounts[0], data)
+	if err := set.VerifyQuorum(data, [][]byte{sigs[0], sigs[1], again}); err == nil {
+		t.Fatal("duplicated signer accepted")
+	}
+	outsider := account.NewAccount("SHA256withECDSA")
+	other, _ := signature.Sign(outsider, data)
+	if err := set.VerifyQuorum(data, [][]byte{sigs[0], sigs[1], other}); err == nil {
+		t.Fatal("outsider signature accepted")
+	}
+	if set.Contains(outsider.PublicKey) || !set.Contains(accounts[2].PublicKey) {
+		t.Fatal("wrong membership")
+	}
+}
+
+func TestStateUncommitted(t *testing.T) {
+	root := &State{Height: 10}
+	s11 := &State{Height: 11, WriteSet: overlaydb.NewMemDB(0, 0), Parent: root}
+	s12 := &State{Height: 12, WriteSet: overlaydb.NewMemDB(0, 0), Parent: s11}
+	states := s12.Uncommitted(10)
+	if len(states) != 2 || states[0] != s11 || states[1] != s12 {
+		t.Fatalf("unexpected uncommitted states %v", states)
+	}
+	if states := s12.Uncommitted(11); len(states) != 1 || states[0] != s12 {
+		t.Fatalf("unexpected uncommitted states after commit %v", states)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package hotstuff
+
+import (
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/consensus/engine"
+	"github.com/polynetwork/poly/core/types"
+)
+
+type blockNode struct {
+	proposal   *Proposal
+	block      *types.Block
+	hash       common.Uint256
+	round      uint64
+	parent     *blockNode
+	qc         *QuorumCert          // certificate of this block, known once a child is seen or votes are collected
+	state      *engine.State        // state after executing this block
+	validators *engine.ValidatorSet // validators of state, which vote on the children of this block
+	info       *engine.BlockInfo
+	receivedAt time.Time
+}
+
+func (self *blockNode) height() uint32 {
+	return self.block.Header.Height
+}
+
+// childLastConfigBlockNum follows the vbft semantic of LastConfigBlockNum
+func (self *blockNode) childLastConfigBlockNum() uint32 {
+	if self.info.NewChainConfig != nil {
+		return self.height()
+	}
+	return self.info.LastConfigBlockNum
+}
+
+// blockTree holds the uncommitted blocks above the last committed block
+type blockTree struct {
+	root  *blockNode
+	nodes map[common.Uint256]*blockNode
+}
+
+func newBlockTree(root *blockNode) *blockTree {
+	return &blockTree{
+		root:  root,
+		nodes: map[common.Uint256]*blockNode{root.hash: root},
+	}
+}
+
+func (self *blockTree) get(hash common.Uint256) *blockNode {
+	return self.nodes[hash]
+}
+
+func (self *blockTree) insert(node *blockNode) {
+	self.nodes[node.hash] = node
+}
+
+// path returns the blocks from the child of root to node
+func (self *blockTree) path(node *blockNode) []*blockNode {
+	var nodes []*blockNode
+	for n := node; n != nil && n != self.root; n = n.parent {
+		nodes = append(nodes, n)
+	}
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	return nodes
+}
+
+func (self *blockTree) descends(node, ancestor *blockNode) bool {
+	for n := node; n != nil; n = n.parent {
+		if n == ancestor {
+			return true
+		}
+		if n.height() <= ancestor.height() {
+			return false
+		}
+	}
+	return false
+}
+
+// setRoot makes a committed node the root and drops the blocks conflicting with it
+func (self *blockTree) setRoot(root *blockNode) {
+	for hash, n := range self.nodes {
+		if !self.descends(n, root) {
+			delete(self.nodes, hash)
+		}
+	}
+	root.parent = nil
+	root.state.Parent = nil
+	root.state.WriteSet = nil
+	self.root = root
+}
@@ -0,0 +1,918 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package hotstuff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/consensus/engine"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+)
+
+const (
+	// MAX_TIMESTAMP_DRIFT is the seconds a proposed block timestamp may run ahead of the local clock
+	MAX_TIMESTAMP_DRIFT = 10
+	// MAX_TIMEOUT_BACKOFF caps the exponential backoff of round timeouts
+	MAX_TIMEOUT_BACKOFF = 6
+	// MAX_PENDING_PROPOSALS bounds the proposals waiting for their parent block
+	MAX_PENDING_PROPOSALS = 256
+	// MAX_ROUND_WINDOW bounds how far from the current round unverifiable proposals, votes and
+	// timeouts are kept
+	MAX_ROUND_WINDOW = 64
+)
+
+// Network delivers consensus messages between validators. Broadcast does not deliver to the
+// local node.
+type Network interface {
+	Broadcast(msg ConsensusMsg)
+	SendTo(peer keypair.PublicKey, msg ConsensusMsg)
+}
+
+type msgEvent struct {
+	from keypair.PublicKey
+	msg  ConsensusMsg
+}
+
+type voteEntry struct {
+	from     keypair.PublicKey
+	vote     *Vote
+	verified bool
+}
+
+// Core is the state machine of a 2-chain pipelined HotStuff: a block is voted on in the round
+// following the QC it extends, and a block is committed as soon as its direct child in the next
+// round is certified. Leaders rotate round-robin over the validators of the block being extended,
+// rounds without progress are skipped with timeout certificates.
+type Core struct {
+	account *account.Account
+	id      string
+	backend engine.Backend
+	network Network
+
+	msgC       chan *msgEvent
+	timeoutC   chan uint64
+	proposeC   chan uint64
+	committedC chan *types.Block
+	quitC      chan struct{}
+	wg         sync.WaitGroup
+
+	tree                *blockTree
+	round               uint64
+	lastVotedRound      uint64
+	timedOutRound       uint64
+	proposedRound       uint64
+	highQC              *QuorumCert
+	lastTC              *TimeoutCert
+	votes               map[common.Uint256]map[string]*voteEntry
+	voted               map[uint64]map[string]common.Uint256
+	timeouts            map[uint64]map[string]*Timeout
+	pending             map[common.Uint256][]*msgEvent
+	pendingRounds       map[uint64]bool
+	pendingCount        int
+	consecutiveTimeouts uint
+	roundTimer          *time.Timer
+	blockInterval       time.Duration
+	baseTimeout         time.Duration
+	voteRecord          string
+}
+
+func NewCore(account *account.Account, backend engine.Backend, network Network) *Core {
+	return &Core{
+		account:       account,
+		id:            engine.PubKeyID(account.PublicKey),
+		backend:       backend,
+		network:       network,
+		msgC:          make(chan *msgEvent, 1024),
+		timeoutC:      make(chan uint64, 16),
+		proposeC:      make(chan uint64, 16),
+		committedC:    make(chan *types.Block, 64),
+		quitC:         make(chan struct{}),
+		votes:         make(map[common.Uint256]map[string]*voteEntry),
+		voted:         make(map[uint64]map[string]common.Uint256),
+		timeouts:      make(map[uint64]map[string]*Timeout),
+		pending:       make(map[common.Uint256][]*msgEvent),
+		pendingRounds: make(map[uint64]bool),
+		// overwritten by the configuration of node_manager
+		blockInterval: 10 * time.Second,
+		baseTimeout:   20 * time.Second,
+	}
+}
+
+// PersistVotes keeps the last voted round in file path, so that a restarted validator never
+// votes twice in a round. It must be called before Start.
+func (self *Core) PersistVotes(path string) error {
+	self.voteRecord = path
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if len(data) != 8 {
+		return fmt.Errorf("invalid vote record %s", path)
+	}
+	self.lastVotedRound = binary.LittleEndian.Uint64(data)
+	self.timedOutRound = self.lastVotedRound
+	return nil
+}
+
+func (self *Core) recordVote(round uint64) error {
+	self.lastVotedRound = round
+	if self.voteRecord == "" {
+		return nil
+	}
+	var data [8]byte
+	binary.LittleEndian.PutUint64(data[:], round)
+	return ioutil.WriteFile(self.voteRecord, data[:], 0600)
+}
+
+func (self *Core) Start() error {
+	root, err := self.loadRoot()
+	if err != nil {
+		return err
+	}
+	self.tree = newBlockTree(root)
+	self.highQC = root.qc
+	self.updateTiming()
+	self.enterRound(root.round+1, nil)
+	self.wg.Add(1)
+	go self.loop()
+	return nil
+}
+
+func (self *Core) Stop() {
+	close(self.quitC)
+	self.wg.Wait()
+}
+
+// HandleMessage queues a consensus message from an authenticated validator
+func (self *Core) HandleMessage(from keypair.PublicKey, msg ConsensusMsg) {
+	select {
+	case self.msgC <- &msgEvent{from: from, msg: msg}:
+	case <-self.quitC:
+	}
+}
+
+// OnBlockCommitted must be called for every block saved to ledger, including the ones saved by
+// block sync
+func (self *Core) OnBlockCommitted(block *types.Block) {
+	select {
+	case self.committedC <- block:
+	case <-self.quitC:
+	}
+}
+
+func (self *Core) loop() {
+	defer self.wg.Done()
+	for {
+		select {
+		case ev := <-self.msgC:
+			if err := self.processMsg(ev); err != nil {
+				log.Warnf("hotstuff: process msg type %d: %s", ev.msg.Type(), err)
+			}
+		case round := <-self.timeoutC:
+			self.onLocalTimeout(round)
+		case round := <-self.proposeC:
+			if err := self.propose(round); err != nil {
+				log.Errorf("hotstuff: propose round %d: %s", round, err)
+			}
+		case block := <-self.committedC:
+			if err := self.onBlockCommitted(block); err != nil {
+				log.Errorf("hotstuff: block %d committed: %s", block.Header.Height, err)
+			}
+		case <-self.quitC:
+			if self.roundTimer != nil {
+				self.roundTimer.Stop()
+			}
+			return
+		}
+	}
+}
+
+func (self *Core) loadRoot() (*blockNode, error) {
+	block, state, err := self.backend.CommittedState()
+	if err != nil {
+		return nil, err
+	}
+	validators, err := self.backend.Validators(state)
+	if err != nil {
+		return nil, err
+	}
+	info, err := engine.BlockInfoOf(block.Header)
+	if err != nil {
+		info = &engine.BlockInfo{}
+	}
+	var round uint64
+	if block.Header.Height > 0 {
+		round = block.Header.ConsensusData
+	}
+	hash := block.Hash()
+	return &blockNode{
+		block: block,
+		hash:  hash,
+		round: round,
+		qc: &QuorumCert{
+			Round:     round,
+			Height:    block.Header.Height,
+			BlockHash: hash,
+			Sigs:      block.Header.SigData,
+		},
+		state:      state,
+		validators: validators,
+		info:       info,
+		receivedAt: time.Now(),
+	}, nil
+}
+
+func (self *Core) updateTiming() {
+	cfg, err := self.backend.Configuration(self.tree.root.state)
+	if err != nil {
+		log.Errorf("hotstuff: get consensus configuration: %s", err)
+		return
+	}
+	self.blockInterval = time.Duration(cfg.BlockMsgDelay) * time.Millisecond
+	self.baseTimeout = self.blockInterval + time.Duration(cfg.HashMsgDelay)*time.Millisecond
+}
+
+func (self *Core) processMsg(ev *msgEvent) error {
+	switch msg := ev.msg.(type) {
+	case *Proposal:
+		return self.processProposal(ev.from, msg)
+	case *Vote:
+		return self.processVote(ev.from, msg)
+	case *Timeout:
+		return self.processTimeout(ev.from, msg)
+	case *BlockFetch:
+		if node := self.tree.get(msg.BlockHash); node != nil && node.proposal != nil {
+			self.network.SendTo(ev.from, node.proposal)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown msg type %d", ev.msg.Type())
+}
+
+// highNode is the block certified by the highest QC, which the next proposal extends
+func (self *Core) highNode() *blockNode {
+	if node := self.tree.get(self.highQC.BlockHash); node != nil {
+		return node
+	}
+	return self.tree.root
+}
+
+func (self *Core) leader(round uint64) *engine.Validator {
+	return self.highNode().validators.Leader(round)
+}
+
+// isValidator checks pk against the validators of the committed and the highest certified block
+func (self *Core) isValidator(pk keypair.PublicKey) bool {
+	return self.tree.root.validators.Contains(pk) || self.highNode().validators.Contains(pk)
+}
+
+func (self *Core) inRoundWindow(round uint64) bool {
+	return round+MAX_ROUND_WINDOW >= self.round && round <= self.round+MAX_ROUND_WINDOW
+}
+
+func (self *Core) enterRound(round uint64, tc *TimeoutCert) {
+	if round <= self.round {
+		return
+	}
+	self.round = round
+	self.lastTC = tc
+	self.resetRoundTimer()
+	log.Debugf("hotstuff: enter round %d, high qc round %d", round, self.highQC.Round)
+
+	if self.leader(round).ID != self.id {
+		return
+	}
+	delay := time.Duration(0)
+	if parent := self.highNode(); parent.round+1 == round {
+		delay = self.blockInterval - time.Since(parent.receivedAt)
+	}
+	time.AfterFunc(delay, func() {
+		select {
+		case self.proposeC <- round:
+		case <-self.quitC:
+		}
+	})
+}
+
+func (self *Core) resetRoundTimer() {
+	if self.roundTimer != nil {
+		self.roundTimer.Stop()
+	}
+	backoff := self.consecutiveTimeouts
+	if backoff > MAX_TIMEOUT_BACKOFF {
+		backoff = MAX_TIMEOUT_BACKOFF
+	}
+	round := self.round
+	self.roundTimer = time.AfterFunc(self.baseTimeout<<backoff, func() {
+		select {
+		case self.timeoutC <- round:
+		case <-self.quitC:
+		}
+	})
+}
+
+func (self *Core) propose(round uint64) error {
+	if round != self.round || round <= self.proposedRound {
+		return nil
+	}
+	parent := self.highNode()
+	ancestors := self.tree.path(parent)
+	height := parent.height() + 1
+	txs, err := self.backend.ProposeTransactions(height, parent.state, blocksOf(ancestors))
+	if err != nil {
+		return err
+	}
+	txHashes := make([]common.Uint256, 0, len(txs))
+	for _, tx := range txs {
+		txHashes = append(txHashes, tx.Hash())
+	}
+	timestamp := uint32(time.Now().Unix())
+	if timestamp <= parent.block.Header.Timestamp {
+		timestamp = parent.block.Header.Timestamp + 1
+	}
+	blockRoot := self.blockRoot(parent)
+	makeBlock := func(nextBookkeeper common.Address, payload []byte) *types.Block {
+		return &types.Block{
+			Header: &types.Header{
+				Version:          types.CURR_HEADER_VERSION,
+				ChainID:          parent.block.Header.ChainID,
+				PrevBlockHash:    parent.hash,
+				TransactionsRoot: common.ComputeMerkleRoot(txHashes),
+				CrossStateRoot:   parent.state.CrossStatesRoot,
+				BlockRoot:        blockRoot,
+				Timestamp:        timestamp,
+				Height:           height,
+				ConsensusData:    round,
+				NextBookkeeper:   nextBookkeeper,
+				ConsensusPayload: payload,
+			},
+			Transactions: txs,
+		}
+	}
+	// the validators after this block are only known after executing it, the header hash is
+	// cached during execution so the final block is built again
+	state, err := self.backend.Execute(makeBlock(common.ADDRESS_EMPTY, nil), parent.state)
+	if err != nil {
+		return err
+	}
+	validators, err := self.backend.Validators(state)
+	if err != nil {
+		return err
+	}
+	nextBookkeeper, err := validators.Address()
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(self.blockInfo(parent, round, validators))
+	if err != nil {
+		return err
+	}
+	block := makeBlock(nextBookkeeper, payload)
+	hash := block.Hash()
+	sig, err := signature.Sign(self.account, hash[:])
+	if err != nil {
+		return err
+	}
+	proposal := &Proposal{
+		Block:   block,
+		Justify: self.highQC,
+		Sig:     sig,
+	}
+	if self.highQC.Round+1 != round {
+		proposal.TC = self.lastTC
+	}
+	self.proposedRound = round
+	log.Infof("hotstuff: propose block %d round %d with %d txs", height, round, len(txs))
+	self.network.Broadcast(proposal)
+	return self.processProposal(self.account.PublicKey, proposal)
+}
+
+func blocksOf(nodes []*blockNode) []*types.Block {
+	blocks := make([]*types.Block, 0, len(nodes))
+	for _, n := range nodes {
+		blocks = append(blocks, n.block)
+	}
+	return blocks
+}
+
+// blockRoot is the block root of a child of parent
+func (self *Core) blockRoot(parent *blockNode) common.Uint256 {
+	root := self.tree.root
+	hashes := []common.Uint256{root.hash}
+	for _, n := range self.tree.path(parent) {
+		hashes = append(hashes, n.hash)
+	}
+	return self.backend.BlockRoot(root.height()+1, hashes)
+}
+
+// blockInfo keeps the vbft payload format so that the header sync of other chains follows the
+// validator changes the same way
+func (self *Core) blockInfo(parent *blockNode, round uint64, validators *engine.ValidatorSet) *engine.BlockInfo {
+	info := &engine.BlockInfo{
+		Proposer:           parent.validators.Leader(round).Index,
+		LastConfigBlockNum: parent.childLastConfigBlockNum(),
+	}
+	if !validators.Equal(parent.validators) {
+		info.NewChainConfig = engine.NewChainConfig(validators)
+	}
+	return info
+}
+
+func (self *Core) processProposal(from keypair.PublicKey, p *Proposal) error {
+	header := p.Block.Header
+	hash := p.Block.Hash()
+	if self.tree.get(hash) != nil || header.Height <= self.tree.root.height() {
+		return nil
+	}
+	if !self.isValidator(from) {
+		return fmt.Errorf("proposal of block %d from non validator", header.Height)
+	}
+	parent := self.tree.get(header.PrevBlockHash)
+	if parent == nil {
+		return self.pendProposal(from, p, hash)
+	}
+	node, err := self.validateProposal(parent, p, hash)
+	if err != nil {
+		return fmt.Errorf("invalid proposal of block %d round %d: %s", header.Height, header.ConsensusData, err)
+	}
+	self.tree.insert(node)
+	self.processQC(p.Justify)
+	if p.TC != nil {
+		self.enterRound(p.TC.Round+1, p.TC)
+	}
+	self.tryVote(node, p)
+	self.checkVotes(node)
+
+	waiting := self.pending[hash]
+	delete(self.pending, hash)
+	self.pendingCount -= len(waiting)
+	for _, ev := range waiting {
+		delete(self.pendingRounds, ev.msg.(*Proposal).Block.Header.ConsensusData)
+	}
+	for _, ev := range waiting {
+		if err := self.processProposal(ev.from, ev.msg.(*Proposal)); err != nil {
+			log.Warnf("hotstuff: %s", err)
+		}
+	}
+	return nil
+}
+
+// pendProposal keeps a proposal until its parent is fetched from the sender. The proposal can not
+// be validated yet, so it must be near the current round and signed by the round leader of the
+// highest certified block, and only one proposal is kept per round.
+func (self *Core) pendProposal(from keypair.PublicKey, p *Proposal, hash common.Uint256) error {
+	header := p.Block.Header
+	round := header.ConsensusData
+	if !self.inRoundWindow(round) || header.Height > self.tree.root.height()+MAX_PENDING_PROPOSALS {
+		return fmt.Errorf("drop block %d of round %d out of window", header.Height, round)
+	}
+	leader := self.leader(round)
+	if err := signature.Verify(leader.PubKey, hash[:], p.Sig); err != nil {
+		return fmt.Errorf("block %d not signed by leader %d of round %d: %s", header.Height, leader.Index, round, err)
+	}
+	if self.pendingRounds[round] {
+		return nil
+	}
+	if self.pendingCount >= MAX_PENDING_PROPOSALS {
+		return fmt.Errorf("too many pending proposals, drop block %d", header.Height)
+	}
+	if len(self.pending[header.PrevBlockHash]) == 0 {
+		self.network.SendTo(from, &BlockFetch{BlockHash: header.PrevBlockHash})
+	}
+	self.pending[header.PrevBlockHash] = append(self.pending[header.PrevBlockHash], &msgEvent{from: from, msg: p})
+	self.pendingRounds[round] = true
+	self.pendingCount++
+	return nil
+}
+
+func (self *Core) validateProposal(parent *blockNode, p *Proposal, hash common.Uint256) (*blockNode, error) {
+	header := p.Block.Header
+	round := header.ConsensusData
+	if header.Height != parent.height()+1 {
+		return nil, fmt.Errorf("height %d does not follow parent %d", header.Height, parent.height())
+	}
+	qc := p.Justify
+	if qc == nil || qc.BlockHash != parent.hash || qc.Round != parent.round || qc.Height != parent.height() {
+		return nil, fmt.Errorf("justify does not certify parent")
+	}
+	if err := self.verifyQC(qc); err != nil {
+		return nil, fmt.Errorf("justify: %s", err)
+	}
+	if round <= parent.round {
+		return nil, fmt.Errorf("round %d not above parent round %d", round, parent.round)
+	}
+	if round != qc.Round+1 {
+		if p.TC == nil || p.TC.Round+1 != round {
+			return nil, fmt.Errorf("no timeout certificate for round %d", round-1)
+		}
+		if err := verifyTC(p.TC, parent.validators); err != nil {
+			return nil, fmt.Errorf("timeout certificate: %s", err)
+		}
+		if qc.Round < p.TC.HighQCRound() {
+			return nil, fmt.Errorf("justify round %d below timeout certificate high qc %d", qc.Round, p.TC.HighQCRound())
+		}
+	}
+	leader := parent.validators.Leader(round)
+	if err := signature.Verify(leader.PubKey, hash[:], p.Sig); err != nil {
+		return nil, fmt.Errorf("not signed by leader %d: %s", leader.Index, err)
+	}
+
+	parentTimestamp := parent.block.Header.Timestamp
+	if header.Timestamp <= parentTimestamp {
+		return nil, fmt.Errorf("timestamp %d not after parent %d", header.Timestamp, parentTimestamp)
+	}
+	latest := uint32(time.Now().Unix())
+	if latest <= parentTimestamp {
+		latest = parentTimestamp + 1
+	}
+	if header.Timestamp > latest+MAX_TIMESTAMP_DRIFT {
+		return nil, fmt.Errorf("timestamp %d too far in the future", header.Timestamp)
+	}
+	txHashes := make([]common.Uint256, 0, len(p.Block.Transactions))
+	for _, tx := range p.Block.Transactions {
+		txHashes = append(txHashes, tx.Hash())
+	}
+	if header.TransactionsRoot != common.ComputeMerkleRoot(txHashes) {
+		return nil, fmt.Errorf("transactions root mismatch")
+	}
+	if header.BlockRoot != self.blockRoot(parent) {
+		return nil, fmt.Errorf("block root mismatch")
+	}
+	if header.CrossStateRoot != parent.state.CrossStatesRoot {
+		return nil, fmt.Errorf("cross state root mismatch")
+	}
+	if err := self.backend.VerifyTransactions(p.Block, blocksOf(self.tree.path(parent))); err != nil {
+		return nil, err
+	}
+	state, err := self.backend.Execute(p.Block, parent.state)
+	if err != nil {
+		return nil, fmt.Errorf("execute: %s", err)
+	}
+	validators, err := self.backend.Validators(state)
+	if err != nil {
+		return nil, err
+	}
+	nextBookkeeper, err := validators.Address()
+	if err != nil {
+		return nil, err
+	}
+	if header.NextBookkeeper != nextBookkeeper {
+		return nil, fmt.Errorf("next bookkeeper mismatch")
+	}
+	info := self.blockInfo(parent, round, validators)
+	payload, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header.ConsensusPayload, payload) {
+		return nil, fmt.Errorf("consensus payload mismatch")
+	}
+	return &blockNode{
+		proposal:   p,
+		block:      p.Block,
+		hash:       hash,
+		round:      round,
+		parent:     parent,
+		state:      state,
+		validators: validators,
+		info:       info,
+		receivedAt: time.Now(),
+	}, nil
+}
+
+// verifyQC checks the signatures of a QC on a known block
+func (self *Core) verifyQC(qc *QuorumCert) error {
+	node := self.tree.get(qc.BlockHash)
+	if node == nil {
+		return fmt.Errorf("unknown block %s", qc.BlockHash.ToHexString())
+	}
+	if qc.Round != node.round || qc.Height != node.height() {
+		return fmt.Errorf("round or height mismatch")
+	}
+	if node.qc != nil || node == self.tree.root {
+		// verified before, or committed already
+		return nil
+	}
+	return node.parent.validators.VerifyQuorum(qc.BlockHash[:], qc.Sigs)
+}
+
+func verifyTC(tc *TimeoutCert, validators *engine.ValidatorSet) error {
+	if len(tc.Votes) < validators.Quorum() {
+		return fmt.Errorf("%d timeouts less than quorum %d", len(tc.Votes), validators.Quorum())
+	}
+	signers := make(map[string]bool, len(tc.Votes))
+	for _, v := range tc.Votes {
+		signer, err := validators.Signer(timeoutDigest(tc.Round, v.HighQCRound), v.Sig)
+		if err != nil {
+			return err
+		}
+		if signers[signer.ID] {
+			return fmt.Errorf("validator %s timed out twice", signer.ID)
+		}
+		signers[signer.ID] = true
+	}
+	return nil
+}
+
+func (self *Core) tryVote(node *blockNode, p *Proposal) {
+	round := node.round
+	if round != self.round || round <= self.lastVotedRound {
+		return
+	}
+	if !node.parent.validators.Contains(self.account.PublicKey) {
+		return
+	}
+	safe := round == p.Justify.Round+1 ||
+		(p.TC != nil && round == p.TC.Round+1 && p.Justify.Round >= p.TC.HighQCRound())
+	if !safe {
+		return
+	}
+	sig, err := signature.Sign(self.account, node.hash[:])
+	if err != nil {
+		log.Errorf("hotstuff: sign vote: %s", err)
+		return
+	}
+	if err := self.recordVote(round); err != nil {
+		log.Errorf("hotstuff: record vote: %s", err)
+		return
+	}
+	vote := &Vote{Round: round, Height: node.height(), BlockHash: node.hash, Sig: sig}
+	self.network.Broadcast(vote)
+	if err := self.processVote(self.account.PublicKey, vote); err != nil {
+		log.Errorf("hotstuff: process own vote: %s", err)
+	}
+}
+
+// processVote keeps the votes of validators, one per round, until the voted block is known
+func (self *Core) processVote(from keypair.PublicKey, v *Vote) error {
+	if v.Height <= self.tree.root.height() || !self.inRoundWindow(v.Round) {
+		return nil
+	}
+	if !self.isValidator(from) {
+		return fmt.Errorf("vote from non validator")
+	}
+	if err := signature.Verify(from, v.BlockHash[:], v.Sig); err != nil {
+		return fmt.Errorf("vote signature: %s", err)
+	}
+	id := engine.PubKeyID(from)
+	voted := self.voted[v.Round]
+	if voted == nil {
+		voted = make(map[string]common.Uint256)
+		self.voted[v.Round] = voted
+	}
+	if hash, present := voted[id]; present {
+		if hash != v.BlockHash {
+			return fmt.Errorf("validator %s voted twice in round %d", id, v.Round)
+		}
+		return nil
+	}
+	voted[id] = v.BlockHash
+	entries := self.votes[v.BlockHash]
+	if entries == nil {
+		entries = make(map[string]*voteEntry)
+		self.votes[v.BlockHash] = entries
+	}
+	entries[id] = &voteEntry{from: from, vote: v}
+	if node := self.tree.get(v.BlockHash); node != nil {
+		self.checkVotes(node)
+	}
+	return nil
+}
+
+// checkVotes forms the QC of node once a quorum of votes of its voters is collected
+func (self *Core) checkVotes(node *blockNode) {
+	entries := self.votes[node.hash]
+	if node.qc != nil || node.parent == nil || len(entries) == 0 {
+		return
+	}
+	voters := node.parent.validators
+	sigs := make([][]byte, 0, len(entries))
+	for id, e := range entries {
+		if !e.verified {
+			if e.vote.Round != node.round || e.vote.Height != node.height() || !voters.Contains(e.from) {
+				delete(entries, id)
+				continue
+			}
+			e.verified = true
+		}
+		sigs = append(sigs, e.vote.Sig)
+	}
+	if len(sigs) < voters.Quorum() {
+		return
+	}
+	self.processQC(&QuorumCert{Round: node.round, Height: node.height(), BlockHash: node.hash, Sigs: sigs})
+}
+
+// processQC handles a verified QC
+func (self *Core) processQC(qc *QuorumCert) {
+	node := self.tree.get(qc.BlockHash)
+	if node == nil {
+		return
+	}
+	if node.qc == nil {
+		node.qc = qc
+	}
+	if qc.Round > self.highQC.Round {
+		self.highQC = qc
+	}
+	if parent := node.parent; parent != nil && parent != self.tree.root && node.round == parent.round+1 {
+		if err := self.commit(parent); err != nil {
+			log.Errorf("hotstuff: commit block %d: %s", parent.height(), err)
+		}
+	}
+	if qc.Round >= self.round {
+		self.consecutiveTimeouts = 0
+		self.enterRound(qc.Round+1, nil)
+	}
+}
+
+func (self *Core) commit(node *blockNode) error {
+	for _, n := range self.tree.path(node) {
+		// every committed block is certified by the justify of its child, the QC signatures
+		// become the header signatures checked by the ledger
+		if n.qc == nil {
+			return fmt.Errorf("block %d round %d has no quorum certificate", n.height(), n.round)
+		}
+		blk := n.block
+		blk.Header.Bookkeepers = n.parent.validators.PubKeys()
+		blk.Header.SigData = n.qc.Sigs
+		if err := self.backend.Commit(blk, n.state); err != nil {
+			return err
+		}
+		log.Infof("hotstuff: block %d round %d committed", n.height(), n.round)
+		self.tree.setRoot(n)
+	}
+	self.cleanup()
+	self.updateTiming()
+	return nil
+}
+
+// cleanup drops the votes, timeouts and pending proposals made useless by the new root
+func (self *Core) cleanup() {
+	root := self.tree.root
+	for hash, entries := range self.votes {
+		for _, e := range entries {
+			if e.vote.Height <= root.height() {
+				delete(self.votes, hash)
+			}
+			break
+		}
+	}
+	for round := range self.voted {
+		if round <= root.round {
+			delete(self.voted, round)
+		}
+	}
+	for round := range self.timeouts {
+		if round <= root.round {
+			delete(self.timeouts, round)
+		}
+	}
+	for hash, events := range self.pending {
+		if events[0].msg.(*Proposal).Block.Header.Height <= root.height()+1 && self.tree.get(hash) == nil {
+			self.pendingCount -= len(events)
+			delete(self.pending, hash)
+			for _, ev := range events {
+				delete(self.pendingRounds, ev.msg.(*Proposal).Block.Header.ConsensusData)
+			}
+		}
+	}
+}
+
+func (self *Core) onLocalTimeout(round uint64) {
+	if round != self.round {
+		return
+	}
+	self.consecutiveTimeouts++
+	log.Infof("hotstuff: round %d timed out, high qc round %d", round, self.highQC.Round)
+	self.sendTimeout(round)
+	self.resetRoundTimer()
+}
+
+func (self *Core) sendTimeout(round uint64) {
+	if round > self.lastVotedRound {
+		// never vote in a round given up
+		if err := self.recordVote(round); err != nil {
+			log.Errorf("hotstuff: record vote: %s", err)
+			return
+		}
+	}
+	sig, err := signature.Sign(self.account, timeoutDigest(round, self.highQC.Round))
+	if err != nil {
+		log.Errorf("hotstuff: sign timeout: %s", err)
+		return
+	}
+	self.timedOutRound = round
+	timeout := &Timeout{Round: round, HighQC: self.highQC, Sig: sig}
+	self.network.Broadcast(timeout)
+	if err := self.processTimeout(self.account.PublicKey, timeout); err != nil {
+		log.Errorf("hotstuff: process own timeout: %s", err)
+	}
+}
+
+func (self *Core) processTimeout(from keypair.PublicKey, t *Timeout) error {
+	if t.Round < self.round {
+		return nil
+	}
+	if t.Round > self.round+MAX_ROUND_WINDOW {
+		return fmt.Errorf("timeout of round %d out of window", t.Round)
+	}
+	id := engine.PubKeyID(from)
+	if !self.isValidator(from) {
+		return fmt.Errorf("timeout from non validator")
+	}
+	if err := signature.Verify(from, timeoutDigest(t.Round, t.HighQC.Round), t.Sig); err != nil {
+		return fmt.Errorf("timeout signature: %s", err)
+	}
+	if node := self.tree.get(t.HighQC.BlockHash); node == nil {
+		if t.HighQC.Height > self.tree.root.height() {
+			self.network.SendTo(from, &BlockFetch{BlockHash: t.HighQC.BlockHash})
+		}
+	} else if err := self.verifyQC(t.HighQC); err != nil {
+		return fmt.Errorf("timeout high qc: %s", err)
+	} else {
+		self.processQC(t.HighQC)
+	}
+	if t.Round < self.round {
+		return nil
+	}
+	validators := self.highNode().validators
+	if validators.GetByID(id) == nil {
+		return nil
+	}
+	timeouts := self.timeouts[t.Round]
+	if timeouts == nil {
+		timeouts = make(map[string]*Timeout)
+		self.timeouts[t.Round] = timeouts
+	}
+	timeouts[id] = t
+
+	// join a round given up by at least one honest validator
+	if t.Round == self.round && self.timedOutRound < t.Round && len(timeouts) > validators.Faulty() {
+		self.sendTimeout(t.Round)
+		return nil
+	}
+	if len(timeouts) >= validators.Quorum() && t.Round == self.round {
+		tc := &TimeoutCert{Round: t.Round}
+		for _, timeout := range timeouts {
+			tc.Votes = append(tc.Votes, &TimeoutVote{HighQCRound: timeout.HighQC.Round, Sig: timeout.Sig})
+		}
+		self.enterRound(t.Round+1, tc)
+	}
+	return nil
+}
+
+func (self *Core) onBlockCommitted(block *types.Block) error {
+	root := self.tree.root
+	if block.Header.Height <= root.height() {
+		return nil
+	}
+	// the ledger moved on by block sync
+	if node := self.tree.get(block.Hash()); node != nil {
+		return self.commit(node)
+	}
+	newRoot, err := self.loadRoot()
+	if err != nil {
+		return err
+	}
+	if newRoot.height() <= root.height() {
+		return nil
+	}
+	log.Infof("hotstuff: ledger synced to block %d round %d", newRoot.height(), newRoot.round)
+	self.tree = newBlockTree(newRoot)
+	self.highQC = newRoot.qc
+	self.lastTC = nil
+	self.cleanup()
+	self.updateTiming()
+	self.enterRound(newRoot.round+1, nil)
+	return nil
+}
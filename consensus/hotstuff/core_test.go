@@ -0,0 +1,589 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package hotstuff
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/consensus/engine"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+)
+
+// testBackend is a ledger of one node, the validator set switches from validators to
+// nextValidators after block changeHeight
+type testBackend struct {
+	lock           sync.Mutex
+	blocks         []*types.Block
+	validators     *engine.ValidatorSet
+	nextValidators *engine.ValidatorSet
+	changeHeight   uint32
+}
+
+func newTestBackend(genesis *types.Block, validators, nextValidators *engine.ValidatorSet, changeHeight uint32) *testBackend {
+	return &testBackend{
+		blocks:         []*types.Block{genesis},
+		validators:     validators,
+		nextValidators: nextValidators,
+		changeHeight:   changeHeight,
+	}
+}
+
+func (self *testBackend) height() uint32 {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return uint32(len(self.blocks) - 1)
+}
+
+func (self *testBackend) block(height uint32) *types.Block {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.blocks[height]
+}
+
+func crossStatesRoot(hash common.Uint256) common.Uint256 {
+	return sha256.Sum256(hash[:])
+}
+
+func (self *testBackend) CommittedHeight() uint32 {
+	return self.height()
+}
+
+func (self *testBackend) CommittedState() (*types.Block, *engine.State, error) {
+	return self.CommittedBlock(self.height())
+}
+
+func (self *testBackend) CommittedBlock(height uint32) (*types.Block, *engine.State, error) {
+	block := self.block(height)
+	return block, &engine.State{Height: block.Header.Height, CrossStatesRoot: crossStatesRoot(block.Hash())}, nil
+}
+
+func (self *testBackend) ProposeTransactions(height uint32, parent *engine.State, ancestors []*types.Block) ([]*types.Transaction, error) {
+	return nil, nil
+}
+
+func (self *testBackend) VerifyTransactions(block *types.Block, ancestors []*types.Block) error {
+	return nil
+}
+
+func (self *testBackend) BlockRoot(startHeight uint32, prevHashes []common.Uint256) common.Uint256 {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	sink := common.NewZeroCopySink(nil)
+	for _, blk := range self.blocks[:startHeight-1] {
+		sink.WriteHash(blk.Hash())
+	}
+	for _, hash := range prevHashes {
+		sink.WriteHash(hash)
+	}
+	return sha256.Sum256(sink.Bytes())
+}
+
+func (self *testBackend) Execute(block *types.Block, parent *engine.State) (*engine.State, error) {
+	return &engine.State{Height: block.Header.Height, CrossStatesRoot: crossStatesRoot(block.Hash()), Parent: parent}, nil
+}
+
+// Commit checks the header the same way as the ledger before appending the block
+func (self *testBackend) Commit(block *types.Block, state *engine.State) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	prev := self.blocks[len(self.blocks)-1]
+	header := block.Header
+	if header.Height != prev.Header.Height+1 || header.PrevBlockHash != prev.Hash() {
+		return fmt.Errorf("block %d does not follow %d", header.Height, prev.Header.Height)
+	}
+	if header.Timestamp <= prev.Header.Timestamp {
+		return fmt.Errorf("block %d timestamp", header.Height)
+	}
+	address, err := types.AddressFromBookkeepers(header.Bookkeepers)
+	if err != nil {
+		return err
+	}
+	if address != prev.Header.NextBookkeeper {
+		return fmt.Errorf("block %d bookkeeper address error", header.Height)
+	}
+	hash := block.Hash()
+	m := len(header.Bookkeepers) - (len(header.Bookkeepers)-1)/3
+	if err := signature.VerifyMultiSignature(hash[:], header.Bookkeepers, m, header.SigData); err != nil {
+		return fmt.Errorf("block %d: %s", header.Height, err)
+	}
+	self.blocks = append(self.blocks, block)
+	return nil
+}
+
+func (self *testBackend) GovernanceView(state *engine.State) (*node_manager.GovernanceView, error) {
+	validators, _ := self.Validators(state)
+	return &node_manager.GovernanceView{View: validators.View}, nil
+}
+
+func (self *testBackend) PeerPool(state *engine.State, view uint32) (*node_manager.PeerPoolMap, error) {
+	return nil, fmt.Errorf("peer pool not supported")
+}
+
+func (self *testBackend) Validators(state *engine.State) (*engine.ValidatorSet, error) {
+	if state.Height >= self.changeHeight {
+		return self.nextValidators, nil
+	}
+	return self.validators, nil
+}
+
+func (self *testBackend) Configuration(state *engine.State) (*node_manager.Configuration, error) {
+	return &node_manager.Configuration{BlockMsgDelay: 20, HashMsgDelay: 200}, nil
+}
+
+// testNetwork delivers messages between cores in memory, through the wire format
+type testNetwork struct {
+	lock  sync.RWMutex
+	cores map[string]*Core
+	down  map[string]bool
+}
+
+func newTestNetwork() *testNetwork {
+	return &testNetwork{
+		cores: make(map[string]*Core),
+		down:  make(map[string]bool),
+	}
+}
+
+func (self *testNetwork) setDown(id string, down bool) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.down[id] = down
+}
+
+func (self *testNetwork) deliver(from keypair.PublicKey, to string, msg ConsensusMsg) {
+	self.lock.RLock()
+	core := self.cores[to]
+	down := self.down[to] || self.down[engine.PubKeyID(from)]
+	self.lock.RUnlock()
+	if core == nil || down {
+		return
+	}
+	data := SerializeMsg(msg)
+	go func() {
+		msg, err := DeserializeMsg(data)
+		if err != nil {
+			panic(err)
+		}
+		core.HandleMessage(from, msg)
+	}()
+}
+
+type testEndpoint struct {
+	net *testNetwork
+	pk  keypair.PublicKey
+}
+
+func (self *testEndpoint) Broadcast(msg ConsensusMsg) {
+	self.net.lock.RLock()
+	ids := make([]string, 0, len(self.net.cores))
+	for id := range self.net.cores {
+		ids = append(ids, id)
+	}
+	self.net.lock.RUnlock()
+	for _, id := range ids {
+		if id != engine.PubKeyID(self.pk) {
+			self.net.deliver(self.pk, id, msg)
+		}
+	}
+}
+
+func (self *testEndpoint) SendTo(peer keypair.PublicKey, msg ConsensusMsg) {
+	self.net.deliver(self.pk, engine.PubKeyID(peer), msg)
+}
+
+type testCluster struct {
+	t        *testing.T
+	accounts []*account.Account
+	backends []*testBackend
+	cores    []*Core
+	net      *testNetwork
+}
+
+func newValidatorSet(view uint32, accounts []*account.Account) *engine.ValidatorSet {
+	validators := make([]*engine.Validator, 0, len(accounts))
+	for i, acc := range accounts {
+		validators = append(validators, &engine.Validator{
+			Index:  uint32(i + 1),
+			ID:     engine.PubKeyID(acc.PublicKey),
+			PubKey: acc.PublicKey,
+		})
+	}
+	return engine.NewValidatorSet(view, validators)
+}
+
+// newTestCluster starts a core for every account, the first n accounts being the validators
+// until changeHeight and the last n accounts afterwards
+func newTestCluster(t *testing.T, accounts []*account.Account, n int, changeHeight uint32) *testCluster {
+	validators := newValidatorSet(1, accounts[:n])
+	nextValidators := validators
+	if changeHeight > 0 {
+		nextValidators = newValidatorSet(2, accounts[len(accounts)-n:])
+	} else {
+		changeHeight = ^uint32(0)
+	}
+	nextBookkeeper, err := validators.Address()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := &types.Block{
+		Header: &types.Header{
+			Timestamp:      uint32(time.Now().Unix()) - 3600,
+			NextBookkeeper: nextBookkeeper,
+		},
+	}
+	cluster := &testCluster{t: t, accounts: accounts, net: newTestNetwork()}
+	for _, acc := range accounts {
+		backend := newTestBackend(genesis, validators, nextValidators, changeHeight)
+		core := NewCore(acc, backend, &testEndpoint{net: cluster.net, pk: acc.PublicKey})
+		cluster.backends = append(cluster.backends, backend)
+		cluster.cores = append(cluster.cores, core)
+		cluster.net.cores[engine.PubKeyID(acc.PublicKey)] = core
+	}
+	return cluster
+}
+
+func (self *testCluster) start(skip ...int) {
+	skipped := make(map[int]bool)
+	for _, i := range skip {
+		skipped[i] = true
+		self.net.setDown(engine.PubKeyID(self.accounts[i].PublicKey), true)
+	}
+	for i, core := range self.cores {
+		if skipped[i] {
+			continue
+		}
+		if err := core.Start(); err != nil {
+			self.t.Fatal(err)
+		}
+	}
+}
+
+func (self *testCluster) stop() {
+	for i, core := range self.cores {
+		if !self.net.down[engine.PubKeyID(self.accounts[i].PublicKey)] {
+			core.Stop()
+		}
+	}
+}
+
+// waitHeight waits until every listed node committed height
+func (self *testCluster) waitHeight(height uint32, timeout time.Duration, nodes ...int) {
+	deadline := time.Now().Add(timeout)
+	for _, i := range nodes {
+		for self.backends[i].height() < height {
+			if time.Now().After(deadline) {
+				self.t.Fatalf("node %d stuck at height %d, waiting for %d", i, self.backends[i].height(), height)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+// checkSafety checks that the listed nodes committed the same blocks
+func (self *testCluster) checkSafety(height uint32, nodes ...int) {
+	for h := uint32(1); h <= height; h++ {
+		hash := self.backends[nodes[0]].block(h).Hash()
+		for _, i := range nodes[1:] {
+			if other := self.backends[i].block(h).Hash(); other != hash {
+				self.t.Fatalf("node %d and %d committed different blocks at height %d", nodes[0], i, h)
+			}
+		}
+	}
+}
+
+func newTestAccounts(n int) []*account.Account {
+	accounts := make([]*account.Account, 0, n)
+	for i := 0; i < n; i++ {
+		accounts = append(accounts, account.NewAccount("SHA256withECDSA"))
+	}
+	return accounts
+}
+
+func TestCommit(t *testing.T) {
+	cluster := newTestCluster(t, newTestAccounts(4), 4, 0)
+	cluster.start()
+	defer cluster.stop()
+
+	cluster.waitHeight(10, 20*time.Second, 0, 1, 2, 3)
+	cluster.checkSafety(10, 0, 1, 2, 3)
+
+	// every validator leads in turn
+	proposers := make(map[uint32]bool)
+	for h := uint32(1); h <= 10; h++ {
+		info, err := engine.BlockInfoOf(cluster.backends[0].block(h).Header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proposers[info.Proposer] = true
+		if info.NewChainConfig != nil {
+			t.Fatalf("unexpected chain config at height %d", h)
+		}
+	}
+	if len(proposers) != 4 {
+		t.Fatalf("%d proposers in 10 blocks", len(proposers))
+	}
+}
+
+func TestCommitWithCrashedValidator(t *testing.T) {
+	cluster := newTestCluster(t, newTestAccounts(4), 4, 0)
+	cluster.start(2)
+	defer cluster.stop()
+
+	cluster.waitHeight(8, 30*time.Second, 0, 1, 3)
+	cluster.checkSafety(8, 0, 1, 3)
+}
+
+func TestValidatorChange(t *testing.T) {
+	// validators 0-3 hand over to 1-4 after block 5
+	cluster := newTestCluster(t, newTestAccounts(5), 4, 5)
+	cluster.start()
+	defer cluster.stop()
+
+	cluster.waitHeight(12, 30*time.Second, 0, 1, 2, 3, 4)
+	cluster.checkSafety(12, 0, 1, 2, 3, 4)
+
+	for h := uint32(1); h <= 12; h++ {
+		header := cluster.backends[4].block(h).Header
+		info, err := engine.BlockInfoOf(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (info.NewChainConfig != nil) != (h == 5) {
+			t.Fatalf("chain config at height %d: %v", h, info.NewChainConfig != nil)
+		}
+		if h == 5 && (info.NewChainConfig.View != 2 || len(info.NewChainConfig.Peers) != 4) {
+			t.Fatalf("unexpected chain config %+v", info.NewChainConfig)
+		}
+		if h > 5 && info.LastConfigBlockNum != 5 {
+			t.Fatalf("last config block num at height %d: %d", h, info.LastConfigBlockNum)
+		}
+		signer := cluster.accounts[0].PublicKey
+		for _, pk := range header.Bookkeepers {
+			if h > 5 && keypair.ComparePublicKey(pk, signer) {
+				t.Fatalf("retired validator signed block %d", h)
+			}
+		}
+	}
+}
+
+func TestMsgSerialization(t *testing.T) {
+	acc := account.NewAccount("SHA256withECDSA")
+	block := &types.Block{Header: &types.Header{Height: 3, ConsensusData: 7}}
+	hash := block.Hash()
+	sig, err := signature.Sign(acc, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	qc := &QuorumCert{Round: 6, Height: 2, BlockHash: common.Uint256{1}, Sigs: [][]byte{sig, sig}}
+	msgs := []ConsensusMsg{
+		&Proposal{Block: block, Justify: qc, TC: &TimeoutCert{Round: 5, Votes: []*TimeoutVote{{HighQCRound: 4, Sig: sig}}}, Sig: sig},
+		&Proposal{Block: block, Justify: qc, Sig: sig},
+		&Vote{Round: 7, Height: 3, BlockHash: hash, Sig: sig},
+		&Timeout{Round: 7, HighQC: qc, Sig: sig},
+		&BlockFetch{BlockHash: hash},
+	}
+	for _, msg := range msgs {
+		data := SerializeMsg(msg)
+		decoded, err := DeserializeMsg(data)
+		if err != nil {
+			t.Fatalf("msg type %d: %s", msg.Type(), err)
+		}
+		if string(SerializeMsg(decoded)) != string(data) {
+			t.Fatalf("msg type %d changed after serialization", msg.Type())
+		}
+		if _, err := DeserializeMsg(data[:len(data)-1]); err == nil {
+			t.Fatalf("truncated msg type %d accepted", msg.Type())
+		}
+	}
+}
+
+// fetchCounter records the block fetches sent by a core
+type fetchCounter struct {
+	lock    sync.Mutex
+	fetches int
+}
+
+func (self *fetchCounter) Broadcast(msg ConsensusMsg) {}
+
+func (self *fetchCounter) SendTo(peer keypair.PublicKey, msg ConsensusMsg) {
+	if _, ok := msg.(*BlockFetch); ok {
+		self.lock.Lock()
+		self.fetches++
+		self.lock.Unlock()
+	}
+}
+
+// newIdleCore loads the state of a core without running its loop, so that messages can be
+// processed directly
+func newIdleCore(t *testing.T, accounts []*account.Account) (*Core, *fetchCounter) {
+	cluster := newTestCluster(t, accounts, 4, 0)
+	core := cluster.cores[0]
+	if err := core.Start(); err != nil {
+		t.Fatal(err)
+	}
+	core.Stop()
+	network := new(fetchCounter)
+	core.network = network
+	return core, network
+}
+
+func accountOf(accounts []*account.Account, validator *engine.Validator) *account.Account {
+	for _, acc := range accounts {
+		if engine.PubKeyID(acc.PublicKey) == validator.ID {
+			return acc
+		}
+	}
+	return nil
+}
+
+func newOrphanProposal(t *testing.T, signer *account.Account, round uint64, parent common.Uint256) *Proposal {
+	block := &types.Block{Header: &types.Header{Height: 3, PrevBlockHash: parent, ConsensusData: round}}
+	hash := block.Hash()
+	sig, err := signature.Sign(signer, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Proposal{Block: block, Justify: &QuorumCert{}, Sig: sig}
+}
+
+func TestProcessProposalPending(t *testing.T) {
+	accounts := newTestAccounts(5)
+	core, network := newIdleCore(t, accounts)
+	round := core.round + 1
+	leader := accountOf(accounts, core.leader(round))
+	var other *account.Account
+	for _, acc := range accounts[:4] {
+		if acc != leader {
+			other = acc
+			break
+		}
+	}
+	outsider := accounts[4]
+	parent := common.Uint256{9}
+
+	if err := core.processProposal(outsider.PublicKey, newOrphanProposal(t, leader, round, parent)); err == nil {
+		t.Fatal("proposal from non validator accepted")
+	}
+	if err := core.processProposal(other.PublicKey, newOrphanProposal(t, other, round, parent)); err == nil {
+		t.Fatal("proposal not signed by leader accepted")
+	}
+	far := core.round + MAX_ROUND_WINDOW + 1
+	if err := core.processProposal(other.PublicKey, newOrphanProposal(t, accountOf(accounts, core.leader(far)), far, parent)); err == nil {
+		t.Fatal("proposal out of round window accepted")
+	}
+	if core.pendingCount != 0 || network.fetches != 0 {
+		t.Fatalf("%d pending proposals, %d fetches after rejected proposals", core.pendingCount, network.fetches)
+	}
+
+	// relayed by another validator, one proposal per round is kept and the parent fetched once
+	if err := core.processProposal(other.PublicKey, newOrphanProposal(t, leader, round, parent)); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.processProposal(other.PublicKey, newOrphanProposal(t, leader, round, common.Uint256{8})); err != nil {
+		t.Fatal(err)
+	}
+	next := round + 1
+	if err := core.processProposal(other.PublicKey, newOrphanProposal(t, accountOf(accounts, core.leader(next)), next, parent)); err != nil {
+		t.Fatal(err)
+	}
+	if core.pendingCount != 2 || network.fetches != 1 {
+		t.Fatalf("%d pending proposals, %d fetches", core.pendingCount, network.fetches)
+	}
+}
+
+func TestProcessVote(t *testing.T) {
+	accounts := newTestAccounts(5)
+	core, _ := newIdleCore(t, accounts)
+	voter := accounts[1]
+	newVote := func(signer *account.Account, round uint64, hash common.Uint256) *Vote {
+		sig, err := signature.Sign(signer, hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Vote{Round: round, Height: 1, BlockHash: hash, Sig: sig}
+	}
+	round := core.round
+
+	if err := core.processVote(accounts[4].PublicKey, newVote(accounts[4], round, common.Uint256{1})); err == nil {
+		t.Fatal("vote from non validator accepted")
+	}
+	if err := core.processVote(voter.PublicKey, newVote(accounts[2], round, common.Uint256{1})); err == nil {
+		t.Fatal("vote signed by another validator accepted")
+	}
+	if err := core.processVote(voter.PublicKey, newVote(voter, round+MAX_ROUND_WINDOW+1, common.Uint256{1})); err != nil {
+		t.Fatal(err)
+	}
+	if len(core.votes) != 0 {
+		t.Fatalf("%d blocks with votes after rejected votes", len(core.votes))
+	}
+
+	if err := core.processVote(voter.PublicKey, newVote(voter, round, common.Uint256{1})); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.processVote(voter.PublicKey, newVote(voter, round, common.Uint256{1})); err != nil {
+		t.Fatal(err)
+	}
+	if err := core.processVote(voter.PublicKey, newVote(voter, round, common.Uint256{2})); err == nil {
+		t.Fatal("second vote in a round accepted")
+	}
+	if len(core.votes) != 1 || len(core.votes[common.Uint256{1}]) != 1 {
+		t.Fatalf("unexpected votes %v", core.votes)
+	}
+}
+
+func TestProcessTimeout(t *testing.T) {
+	accounts := newTestAccounts(5)
+	core, network := newIdleCore(t, accounts)
+	newTimeout := func(signer *account.Account, round uint64) *Timeout {
+		qc := &QuorumCert{Round: round - 1, Height: 5, BlockHash: common.Uint256{1}}
+		sig, err := signature.Sign(signer, timeoutDigest(round, qc.Round))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &Timeout{Round: round, HighQC: qc, Sig: sig}
+	}
+	round := core.round
+
+	if err := core.processTimeout(accounts[4].PublicKey, newTimeout(accounts[4], round)); err == nil {
+		t.Fatal("timeout from non validator accepted")
+	}
+	if err := core.processTimeout(accounts[1].PublicKey, newTimeout(accounts[2], round)); err == nil {
+		t.Fatal("timeout signed by another validator accepted")
+	}
+	if err := core.processTimeout(accounts[1].PublicKey, newTimeout(accounts[1], round+MAX_ROUND_WINDOW+1)); err == nil {
+		t.Fatal("timeout out of round window accepted")
+	}
+	if network.fetches != 0 || len(core.timeouts) != 0 {
+		t.Fatalf("%d fetches, %d rounds of timeouts after rejected timeouts", network.fetches, len(core.timeouts))
+	}
+	if err := core.processTimeout(accounts[1].PublicKey, newTimeout(accounts[1], round)); err != nil {
+		t.Fatal(err)
+	}
+	if network.fetches != 1 || len(core.timeouts[round]) != 1 {
+		t.Fatalf("%d fetches, %d timeouts", network.fetches, len(core.timeouts[round]))
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package hotstuff
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology-eventbus/eventhub"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	actorTypes "github.com/polynetwork/poly/consensus/actor"
+	"github.com/polynetwork/poly/consensus/engine"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/events"
+	"github.com/polynetwork/poly/events/message"
+	msgpack "github.com/polynetwork/poly/p2pserver/message/msg_pack"
+	p2pmsg "github.com/polynetwork/poly/p2pserver/message/types"
+)
+
+const VOTE_RECORD_FILE = "hotstuff_vote"
+
+// Server runs the HotStuff core of a validator on top of the ledger, the tx pool and p2p
+type Server struct {
+	account *account.Account
+	p2p     *actorTypes.P2PActor
+	backend *engine.LedgerBackend
+	core    *Core
+	pid     *actor.PID
+	sub     *events.ActorSubscriber
+
+	dataDir string
+
+	lock    sync.RWMutex
+	peerIds map[string]uint64
+}
+
+func NewHotStuffServer(account *account.Account, txpool, p2p *actor.PID) (*Server, error) {
+	dataDir := filepath.Join(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName)
+	return NewHotStuffServerWithLedger(account, txpool, p2p, ledger.DefLedger, "consensus_hotstuff", dataDir, events.DefEvtHub)
+}
+
+// NewHotStuffServerWithLedger creates a hotstuff server on top of db, spawned as actor name,
+// keeping its vote record in dataDir and listening for saved blocks on evtHub, so that several
+// servers can live in one process.
+func NewHotStuffServerWithLedger(account *account.Account, txpool, p2p *actor.PID, db *ledger.Ledger,
+	name, dataDir string, evtHub *eventhub.EventHub) (*Server, error) {
+	server := &Server{
+		account: account,
+		p2p:     &actorTypes.P2PActor{P2P: p2p},
+		backend: engine.NewLedgerBackend(db, &actorTypes.TxPoolActor{Pool: txpool}),
+		dataDir: dataDir,
+		peerIds: make(map[string]uint64),
+	}
+	props := actor.FromProducer(func() actor.Actor {
+		return server
+	})
+	pid, err := actor.SpawnNamed(props, name)
+	if err != nil {
+		return nil, err
+	}
+	server.pid = pid
+	server.sub = events.NewActorSubscriber(pid, evtHub)
+	return server, nil
+}
+
+func (self *Server) Receive(context actor.Context) {
+	switch msg := context.Message().(type) {
+	case *actor.Restarting:
+		log.Info("hotstuff actor restarting")
+	case *actor.Stopping:
+		log.Info("hotstuff actor stopping")
+	case *actor.Stopped:
+		log.Info("hotstuff actor stopped")
+	case *actor.Started:
+		log.Info("hotstuff actor started")
+	case *actor.Restart:
+		log.Info("hotstuff actor restart")
+	case *actorTypes.StartConsensus:
+		if err := self.start(); err != nil {
+			log.Errorf("hotstuff start consensus: %s", err)
+		}
+	case *actorTypes.StopConsensus:
+		self.stop()
+		// acknowledge callers waiting for the server to be fully stopped
+		if context.Sender() != nil {
+			context.Respond(msg)
+		}
+	case *message.SaveBlockCompleteMsg:
+		log.Infof("hotstuff actor receives block complete event. block height=%d, numtx=%d",
+			msg.Block.Header.Height, len(msg.Block.Transactions))
+		self.backend.OnBlockCommitted(msg.Block)
+		if self.core != nil {
+			self.core.OnBlockCommitted(msg.Block)
+		}
+	case *p2pmsg.ConsensusPayload:
+		self.handlePayload(msg)
+	default:
+		log.Info("hotstuff actor: Unknown msg ", msg, "type", reflect.TypeOf(msg))
+		if context.Sender() != nil {
+			context.Respond(&actorTypes.Unsupported{Request: reflect.TypeOf(msg).String()})
+		}
+	}
+}
+
+func (self *Server) GetPID() *actor.PID {
+	return self.pid
+}
+
+func (self *Server) Start() error {
+	self.pid.Tell(&actorTypes.StartConsensus{})
+	return nil
+}
+
+func (self *Server) Halt() error {
+	self.pid.Tell(&actorTypes.StopConsensus{})
+	return nil
+}
+
+func (self *Server) start() error {
+	if self.core != nil {
+		log.Info("consensus have started")
+		return nil
+	}
+	if err := os.MkdirAll(self.dataDir, 0700); err != nil {
+		return err
+	}
+	core := NewCore(self.account, self.backend, self)
+	if err := core.PersistVotes(filepath.Join(self.dataDir, VOTE_RECORD_FILE)); err != nil {
+		return err
+	}
+	self.sub.Subscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+	if err := core.Start(); err != nil {
+		self.sub.Unsubscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+		return err
+	}
+	self.core = core
+	return nil
+}
+
+func (self *Server) stop() {
+	if self.core == nil {
+		return
+	}
+	self.core.Stop()
+	self.core = nil
+	self.sub.Unsubscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+}
+
+// handlePayload passes a payload whose signature is verified by p2p to the core
+func (self *Server) handlePayload(payload *p2pmsg.ConsensusPayload) {
+	if self.core == nil {
+		return
+	}
+	if keypair.ComparePublicKey(payload.Owner, self.account.PublicKey) {
+		return
+	}
+	msg, err := DeserializeMsg(payload.Data)
+	if err != nil {
+		log.Warnf("hotstuff: consensus payload from %d: %s", payload.PeerId, err)
+		return
+	}
+	self.lock.Lock()
+	self.peerIds[engine.PubKeyID(payload.Owner)] = payload.PeerId
+	self.lock.Unlock()
+	self.core.HandleMessage(payload.Owner, msg)
+}
+
+func (self *Server) newPayload(msg ConsensusMsg) (*p2pmsg.ConsensusPayload, error) {
+	payload := &p2pmsg.ConsensusPayload{
+		Data:  SerializeMsg(msg),
+		Owner: self.account.PublicKey,
+	}
+	buf := new(bytes.Buffer)
+	if err := payload.SerializeUnsigned(buf); err != nil {
+		return nil, fmt.Errorf("failed to serialize consensus msg: %s", err)
+	}
+	sig, err := signature.Sign(self.account, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	payload.Signature = sig
+	return payload, nil
+}
+
+func (self *Server) Broadcast(msg ConsensusMsg) {
+	payload, err := self.newPayload(msg)
+	if err != nil {
+		log.Errorf("hotstuff: broadcast msg type %d: %s", msg.Type(), err)
+		return
+	}
+	self.p2p.Broadcast(payload)
+}
+
+func (self *Server) SendTo(peer keypair.PublicKey, msg ConsensusMsg) {
+	payload, err := self.newPayload(msg)
+	if err != nil {
+		log.Errorf("hotstuff: send msg type %d: %s", msg.Type(), err)
+		return
+	}
+	self.lock.RLock()
+	peerId, present := self.peerIds[engine.PubKeyID(peer)]
+	self.lock.RUnlock()
+	if !present {
+		self.p2p.Broadcast(payload)
+		return
+	}
+	self.p2p.Transmit(peerId, msgpack.NewConsensus(payload))
+}
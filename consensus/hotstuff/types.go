@@ -0,0 +1,358 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package hotstuff
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+)
+
+type MsgType uint8
+
+const (
+	ProposalMessage MsgType = iota + 1
+	VoteMessage
+	TimeoutMessage
+	BlockFetchMessage
+)
+
+type ConsensusMsg interface {
+	Type() MsgType
+	Serialization(sink *common.ZeroCopySink)
+	Deserialization(source *common.ZeroCopySource) error
+}
+
+func SerializeMsg(msg ConsensusMsg) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint8(uint8(msg.Type()))
+	msg.Serialization(sink)
+	return sink.Bytes()
+}
+
+func DeserializeMsg(data []byte) (ConsensusMsg, error) {
+	source := common.NewZeroCopySource(data)
+	t, eof := source.NextUint8()
+	if eof {
+		return nil, io.ErrUnexpectedEOF
+	}
+	var msg ConsensusMsg
+	switch MsgType(t) {
+	case ProposalMessage:
+		msg = new(Proposal)
+	case VoteMessage:
+		msg = new(Vote)
+	case TimeoutMessage:
+		msg = new(Timeout)
+	case BlockFetchMessage:
+		msg = new(BlockFetch)
+	default:
+		return nil, fmt.Errorf("unknown msg type %d", t)
+	}
+	if err := msg.Deserialization(source); err != nil {
+		return nil, fmt.Errorf("deserialize msg type %d: %s", t, err)
+	}
+	return msg, nil
+}
+
+func writeSigs(sink *common.ZeroCopySink, sigs [][]byte) {
+	sink.WriteVarUint(uint64(len(sigs)))
+	for _, sig := range sigs {
+		sink.WriteVarBytes(sig)
+	}
+}
+
+func readSigs(source *common.ZeroCopySource) ([][]byte, error) {
+	n, eof := source.NextVarUint()
+	if eof {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if n > source.Len() {
+		return nil, fmt.Errorf("invalid signature count %d", n)
+	}
+	sigs := make([][]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		sig, eof := source.NextVarBytes()
+		if eof {
+			return nil, io.ErrUnexpectedEOF
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
+// QuorumCert certifies a block with a quorum of validator signatures over the block hash, the
+// signatures become the SigData of the block header once it is committed.
+type QuorumCert struct {
+	Round     uint64
+	Height    uint32
+	BlockHash common.Uint256
+	Sigs      [][]byte
+}
+
+func (this *QuorumCert) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Round)
+	sink.WriteUint32(this.Height)
+	sink.WriteHash(this.BlockHash)
+	writeSigs(sink, this.Sigs)
+}
+
+func (this *QuorumCert) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Round, eof = source.NextUint64()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Height, eof = source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.BlockHash, eof = source.NextHash()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	sigs, err := readSigs(source)
+	if err != nil {
+		return err
+	}
+	this.Sigs = sigs
+	return nil
+}
+
+// TimeoutVote is the signature of a validator giving up a round together with the round of the
+// highest QC it knows
+type TimeoutVote struct {
+	HighQCRound uint64
+	Sig         []byte
+}
+
+// TimeoutCert proves that a quorum of validators gave up Round
+type TimeoutCert struct {
+	Round uint64
+	Votes []*TimeoutVote
+}
+
+func (this *TimeoutCert) HighQCRound() uint64 {
+	var round uint64
+	for _, v := range this.Votes {
+		if v.HighQCRound > round {
+			round = v.HighQCRound
+		}
+	}
+	return round
+}
+
+func (this *TimeoutCert) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Round)
+	sink.WriteVarUint(uint64(len(this.Votes)))
+	for _, v := range this.Votes {
+		sink.WriteUint64(v.HighQCRound)
+		sink.WriteVarBytes(v.Sig)
+	}
+}
+
+func (this *TimeoutCert) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Round, eof = source.NextUint64()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	n, eof := source.NextVarUint()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if n > source.Len() {
+		return fmt.Errorf("invalid timeout vote count %d", n)
+	}
+	this.Votes = make([]*TimeoutVote, 0, n)
+	for i := uint64(0); i < n; i++ {
+		v := new(TimeoutVote)
+		v.HighQCRound, eof = source.NextUint64()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		v.Sig, eof = source.NextVarBytes()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		this.Votes = append(this.Votes, v)
+	}
+	return nil
+}
+
+// timeoutDigest is the data signed by a validator timing out round
+func timeoutDigest(round, highQCRound uint64) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteString("hotstuff-timeout")
+	sink.WriteUint64(round)
+	sink.WriteUint64(highQCRound)
+	digest := sha256.Sum256(sink.Bytes())
+	return digest[:]
+}
+
+// Proposal carries a block of the leader of a round, extending the block certified by Justify.
+// TC must be present when the round does not directly follow Justify.
+type Proposal struct {
+	Block   *types.Block
+	Justify *QuorumCert
+	TC      *TimeoutCert
+	Sig     []byte
+}
+
+func (this *Proposal) Type() MsgType {
+	return ProposalMessage
+}
+
+func (this *Proposal) Round() uint64 {
+	return this.Block.Header.ConsensusData
+}
+
+func (this *Proposal) Serialization(sink *common.ZeroCopySink) {
+	this.Block.Serialization(sink)
+	this.Justify.Serialization(sink)
+	sink.WriteBool(this.TC != nil)
+	if this.TC != nil {
+		this.TC.Serialization(sink)
+	}
+	sink.WriteVarBytes(this.Sig)
+}
+
+func (this *Proposal) Deserialization(source *common.ZeroCopySource) error {
+	this.Block = new(types.Block)
+	if err := this.Block.Deserialization(source); err != nil {
+		return fmt.Errorf("deserialize block: %s", err)
+	}
+	this.Justify = new(QuorumCert)
+	if err := this.Justify.Deserialization(source); err != nil {
+		return fmt.Errorf("deserialize justify: %s", err)
+	}
+	hasTC, eof := source.NextBool()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if hasTC {
+		this.TC = new(TimeoutCert)
+		if err := this.TC.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize tc: %s", err)
+		}
+	}
+	this.Sig, eof = source.NextVarBytes()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// Vote is the signature of a validator over the hash of a block it accepts
+type Vote struct {
+	Round     uint64
+	Height    uint32
+	BlockHash common.Uint256
+	Sig       []byte
+}
+
+func (this *Vote) Type() MsgType {
+	return VoteMessage
+}
+
+func (this *Vote) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Round)
+	sink.WriteUint32(this.Height)
+	sink.WriteHash(this.BlockHash)
+	sink.WriteVarBytes(this.Sig)
+}
+
+func (this *Vote) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Round, eof = source.NextUint64()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Height, eof = source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.BlockHash, eof = source.NextHash()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Sig, eof = source.NextVarBytes()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// Timeout is sent by a validator giving up Round, carrying its highest QC
+type Timeout struct {
+	Round  uint64
+	HighQC *QuorumCert
+	Sig    []byte
+}
+
+func (this *Timeout) Type() MsgType {
+	return TimeoutMessage
+}
+
+func (this *Timeout) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.Round)
+	this.HighQC.Serialization(sink)
+	sink.WriteVarBytes(this.Sig)
+}
+
+func (this *Timeout) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.Round, eof = source.NextUint64()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.HighQC = new(QuorumCert)
+	if err := this.HighQC.Deserialization(source); err != nil {
+		return err
+	}
+	this.Sig, eof = source.NextVarBytes()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// BlockFetch asks a peer for the proposal of an uncommitted block
+type BlockFetch struct {
+	BlockHash common.Uint256
+}
+
+func (this *BlockFetch) Type() MsgType {
+	return BlockFetchMessage
+}
+
+func (this *BlockFetch) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteHash(this.BlockHash)
+}
+
+func (this *BlockFetch) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.BlockHash, eof = source.NextHash()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
@@ -124,6 +124,9 @@ func (self *SoloService) Receive(context actor.Context) {
 		}
 	default:
 		log.Info("solo actor: Unknown msg ", msg, "type", reflect.TypeOf(msg))
+		if context.Sender() != nil {
+			context.Respond(&actorTypes.Unsupported{Request: reflect.TypeOf(msg).String()})
+		}
 	}
 }
 
@@ -22,13 +22,13 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"github.com/polynetwork/poly/core/store/overlaydb"
 	"math"
 	"sync"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/consensus/engine"
 )
 
 type BlockList []*Block
@@ -754,16 +754,10 @@ func (pool *BlockPool) onBlockSealed(blockNum uint32) {
 	}
 }
 
-func (pool *BlockPool) getExecMerkleRoot(blkNum uint32) (common.Uint256, error) {
+func (pool *BlockPool) getExecState(blkNum uint32) *engine.State {
 	pool.lock.RLock()
 	defer pool.lock.RUnlock()
-	return pool.chainStore.getExecMerkleRoot(blkNum)
-}
-
-func (pool *BlockPool) getExecWriteSet(blkNum uint32) *overlaydb.MemDB {
-	pool.lock.RLock()
-	defer pool.lock.RUnlock()
-	return pool.chainStore.getExecWriteSet(blkNum)
+	return pool.chainStore.getExecState(blkNum)
 }
 
 func (pool *BlockPool) submitBlock(blkNum uint32) error {
@@ -24,48 +24,40 @@ import (
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
-	"github.com/polynetwork/poly/core/ledger"
-	"github.com/polynetwork/poly/core/store"
-	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/consensus/engine"
 	"github.com/polynetwork/poly/events/message"
 )
 
 type PendingBlock struct {
 	block        *Block
-	execResult   *store.ExecuteResult
+	state        *engine.State
 	hasSubmitted bool
 }
 type ChainStore struct {
-	db              *ledger.Ledger
+	backend         engine.Backend
 	chainedBlockNum uint32
 	pendingBlocks   map[uint32]*PendingBlock
 	pid             *actor.PID
 	needSubmitBlock bool
 }
 
-func OpenBlockStore(db *ledger.Ledger, serverPid *actor.PID) (*ChainStore, error) {
-	chainstore := &ChainStore{
-		db:              db,
-		chainedBlockNum: db.GetCurrentBlockHeight(),
-		pendingBlocks:   make(map[uint32]*PendingBlock),
-		pid:             serverPid,
-		needSubmitBlock: false,
-	}
-	merkleRoot, err := db.GetStateMerkleRoot(chainstore.chainedBlockNum)
+func OpenBlockStore(backend engine.Backend, serverPid *actor.PID) (*ChainStore, error) {
+	blk, state, err := backend.CommittedState()
 	if err != nil {
-		log.Errorf("GetStateMerkleRoot blockNum:%d, error :%s", chainstore.chainedBlockNum, err)
-		return nil, fmt.Errorf("GetStateMerkleRoot blockNum:%d, error :%s", chainstore.chainedBlockNum, err)
-	}
-	crossStatesRoot, err := db.GetCrossStateRoot(chainstore.chainedBlockNum)
-	if err != nil {
-		return nil, fmt.Errorf("GetCrossStatesRoot blockNum:%d, error :%s", chainstore.chainedBlockNum, err)
+		return nil, err
 	}
-	writeSet := overlaydb.NewMemDB(1, 1)
-	block, err := chainstore.getBlock(chainstore.chainedBlockNum)
+	block, err := initVbftBlock(blk)
 	if err != nil {
 		return nil, err
 	}
-	chainstore.pendingBlocks[chainstore.chainedBlockNum] = &PendingBlock{block: block, execResult: &store.ExecuteResult{WriteSet: writeSet, MerkleRoot: merkleRoot, CrossStatesRoot: crossStatesRoot}}
+	chainstore := &ChainStore{
+		backend:         backend,
+		chainedBlockNum: state.Height,
+		pendingBlocks:   make(map[uint32]*PendingBlock),
+		pid:             serverPid,
+		needSubmitBlock: false,
+	}
+	chainstore.pendingBlocks[chainstore.chainedBlockNum] = &PendingBlock{block: block, state: state}
 	return chainstore, nil
 }
 
@@ -77,41 +69,27 @@ func (self *ChainStore) GetChainedBlockNum() uint32 {
 	return self.chainedBlockNum
 }
 
-func (self *ChainStore) getExecMerkleRoot(blkNum uint32) (common.Uint256, error) {
-	if blk, present := self.pendingBlocks[blkNum]; blk != nil && present {
-		return blk.execResult.MerkleRoot, nil
-	}
-	merkleRoot, err := self.db.GetStateMerkleRoot(blkNum)
-	if err != nil {
-		log.Errorf("GetStateMerkleRoot blockNum:%d, error :%s", blkNum, err)
-		return common.Uint256{}, fmt.Errorf("GetStateMerkleRoot blockNum:%d, error :%s", blkNum, err)
-	} else {
-		return merkleRoot, nil
-	}
-
-}
-
 func (self *ChainStore) getCrossStateRoot(blkNum uint32) (common.Uint256, error) {
 	if blk, present := self.pendingBlocks[blkNum]; blk != nil && present {
-		return blk.execResult.CrossStatesRoot, nil
+		return blk.state.CrossStatesRoot, nil
 	}
-	crossStateRoot, err := self.db.GetCrossStateRoot(blkNum)
+	_, state, err := self.backend.CommittedBlock(blkNum)
 	if err != nil {
 		return common.Uint256{}, fmt.Errorf("GetCrossStateRoot blockNum:%d, error :%s", blkNum, err)
-	} else {
-		return crossStateRoot, nil
 	}
+	return state.CrossStatesRoot, nil
 }
 
-func (self *ChainStore) getExecWriteSet(blkNum uint32) *overlaydb.MemDB {
+// getExecState returns the state after block blkNum, committed blocks are read from ledger
+func (self *ChainStore) getExecState(blkNum uint32) *engine.State {
 	if blk, present := self.pendingBlocks[blkNum]; blk != nil && present {
-		return blk.execResult.WriteSet
+		return blk.state
 	}
-	return nil
+	return &engine.State{Height: blkNum}
 }
 
 func (self *ChainStore) ReloadFromLedger() {
-	height := self.db.GetCurrentBlockHeight()
+	height := self.backend.CommittedHeight()
 	if height > self.chainedBlockNum {
 		// update chainstore height
 		self.chainedBlockNum = height
@@ -145,12 +123,12 @@ func (self *ChainStore) AddBlock(block *Block) error {
 	if err != nil {
 		log.Errorf("chainstore blkNum:%d, SubmitBlock: %s", blkNum-1, err)
 	}
-	execResult, err := self.db.ExecuteBlock(block.Block)
+	state, err := self.backend.Execute(block.Block, self.getExecState(blkNum-1))
 	if err != nil {
 		log.Errorf("chainstore AddBlock GetBlockExecResult: %s", err)
 		return fmt.Errorf("chainstore AddBlock GetBlockExecResult: %s", err)
 	}
-	self.pendingBlocks[blkNum] = &PendingBlock{block: block, execResult: &execResult, hasSubmitted: false}
+	self.pendingBlocks[blkNum] = &PendingBlock{block: block, state: state, hasSubmitted: false}
 	if self.pid != nil {
 		self.pid.Tell(
 			&message.BlockConsensusComplete{
@@ -166,10 +144,14 @@ func (self *ChainStore) submitBlock(blkNum uint32) error {
 		return nil
 	}
 	if submitBlk, present := self.pendingBlocks[blkNum]; submitBlk != nil && submitBlk.hasSubmitted == false && present {
-		err := self.db.SubmitBlock(submitBlk.block.Block, *submitBlk.execResult)
+		err := self.backend.Commit(submitBlk.block.Block, submitBlk.state)
 		if err != nil && blkNum > self.GetChainedBlockNum() {
 			return fmt.Errorf("ledger add submitBlk (%d, %d) failed: %s", blkNum, self.GetChainedBlockNum(), err)
 		}
+		if err == nil {
+			// the ancestors are in ledger now
+			submitBlk.state.Parent = nil
+		}
 		if _, present := self.pendingBlocks[blkNum-1]; present {
 			delete(self.pendingBlocks, blkNum-1)
 		}
@@ -182,7 +164,7 @@ func (self *ChainStore) getBlock(blockNum uint32) (*Block, error) {
 	if blk, present := self.pendingBlocks[blockNum]; present {
 		return blk.block, nil
 	}
-	block, err := self.db.GetBlockByHeight(blockNum)
+	block, _, err := self.backend.CommittedBlock(blockNum)
 	if err != nil {
 		return nil, err
 	}
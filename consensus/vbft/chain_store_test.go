@@ -19,7 +19,7 @@
 package vbft
 
 import (
-	"fmt"
+	"io/ioutil"
 	"os"
 	"testing"
 
@@ -27,24 +27,26 @@ import (
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/consensus/engine"
 	"github.com/polynetwork/poly/core/genesis"
 	"github.com/polynetwork/poly/core/ledger"
 )
 
-func newChainStore() *ChainStore {
-	log.Init(log.PATH, log.Stdout)
-	var err error
-	acct := account.NewAccount("SHA256withECDSA")
-	if acct == nil {
-		fmt.Println("GetDefaultAccount error: acc is nil")
-		os.Exit(1)
+// newChainStore opens a block store over a ledger in a temp dir initialized with a new genesis block
+func newChainStore(t *testing.T) *ChainStore {
+	log.InitLog(log.InfoLog, log.Stdout)
+	dir, err := ioutil.TempDir("", "vbft_chain")
+	if err != nil {
+		t.Fatalf("TempDir error %s", err)
 	}
-
-	db, err := ledger.NewLedger(config.DEFAULT_DATA_DIR)
+	db, err := ledger.NewLedger(dir)
 	if err != nil {
-		log.Fatalf("NewLedger error %s", err)
-		os.Exit(1)
+		t.Fatalf("NewLedger error %s", err)
 	}
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
 	acc1 := account.NewAccount("")
 	acc2 := account.NewAccount("")
 	acc3 := account.NewAccount("")
@@ -57,28 +59,21 @@ func newChainStore() *ChainStore {
 	genesisConfig := config.DefConfig.Genesis
 	block, err := genesis.BuildGenesisBlock(bookkeepers, genesisConfig)
 	if err != nil {
-		log.Errorf("BuildGenesisBlock error %s", err)
-		os.Exit(1)
+		t.Fatalf("BuildGenesisBlock error %s", err)
 	}
 	err = db.Init(bookkeepers, block)
 	if err != nil {
-		log.Errorf("InitLedgerStoreWithGenesisBlock error %s", err)
-		os.Exit(1)
+		t.Fatalf("InitLedgerStoreWithGenesisBlock error %s", err)
 	}
-	chainstore, err := OpenBlockStore(db, nil)
+	chainstore, err := OpenBlockStore(engine.NewLedgerBackend(db, nil), nil)
 	if err != nil {
-		log.Errorf("openblockstore failed: %v\n", err)
-		return nil
+		t.Fatalf("openblockstore failed: %v", err)
 	}
 	return chainstore
 }
 
 func TestGetChainedBlockNum(t *testing.T) {
-	chainstore := newChainStore()
-	if chainstore == nil {
-		t.Error("newChainStrore error")
-		return
-	}
+	chainstore := newChainStore(t)
 	blocknum := chainstore.GetChainedBlockNum()
 	t.Logf("TestGetChainedBlockNum :%d", blocknum)
 }
@@ -163,12 +163,24 @@ func GenesisChainConfig(conf *config.VBFTConfig, peers []*config.VBFTPeerInfo, h
 	return chainConfig, nil
 }
 
-func GenesisConsensusPayload(height uint32) ([]byte, error) {
-	consensusType := strings.ToLower(config.DefConfig.Genesis.ConsensusType)
+func GenesisConsensusPayload(genesisConfig *config.GenesisConfig, height uint32) ([]byte, error) {
+	consensusType := strings.ToLower(genesisConfig.ConsensusType)
 
 	switch consensusType {
 	case "vbft":
-		return genConsensusPayload(config.DefConfig.Genesis.VBFT, height)
+		return genConsensusPayload(genesisConfig.VBFT, height)
+	case "hotstuff":
+		//hotstuff announces its genesis validators in the vbft payload format
+		hotstuff := genesisConfig.HotStuff
+		if hotstuff == nil {
+			return genConsensusPayload(genesisConfig.VBFT, height)
+		}
+		return genConsensusPayload(&config.VBFTConfig{
+			BlockMsgDelay:      hotstuff.BlockInterval,
+			HashMsgDelay:       hotstuff.RoundTimeout,
+			MaxBlockChangeView: hotstuff.MaxBlockChangeView,
+			Peers:              hotstuff.Peers,
+		}, height)
 	}
 	return nil, nil
 }
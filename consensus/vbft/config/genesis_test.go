@@ -27,52 +27,43 @@ import (
 
 func constructConfig() (*config.VBFTConfig, error) {
 	conf := &config.VBFTConfig{
-		N:                    7,
-		C:                    2,
-		K:                    7,
-		L:                    112,
 		BlockMsgDelay:        10000,
 		HashMsgDelay:         10000,
 		PeerHandshakeTimeout: 10,
 		MaxBlockChangeView:   1000,
+		VrfValue:             config.MainNetConfig.VBFT.VrfValue,
+		VrfProof:             config.MainNetConfig.VBFT.VrfProof,
 	}
-	var peersinfo []*config.VBFTPeerStakeInfo
-	peer1 := &config.VBFTPeerStakeInfo{
+	var peersinfo []*config.VBFTPeerInfo
+	peer1 := &config.VBFTPeerInfo{
 		Index:      1,
 		PeerPubkey: "0253ccfd439b29eca0fe90ca7c6eaa1f98572a054aa2d1d56e72ad96c466107a85",
-		Pos:        0,
 	}
-	peer2 := &config.VBFTPeerStakeInfo{
+	peer2 := &config.VBFTPeerInfo{
 		Index:      2,
 		PeerPubkey: "035eb654bad6c6409894b9b42289a43614874c7984bde6b03aaf6fc1d0486d9d45",
-		Pos:        0,
 	}
 
-	peer3 := &config.VBFTPeerStakeInfo{
+	peer3 := &config.VBFTPeerInfo{
 		Index:      3,
 		PeerPubkey: "0281d198c0dd3737a9c39191bc2d1af7d65a44261a8a64d6ef74d63f27cfb5ed92",
-		Pos:        0,
 	}
 
-	peer4 := &config.VBFTPeerStakeInfo{
+	peer4 := &config.VBFTPeerInfo{
 		Index:      4,
 		PeerPubkey: "023967bba3060bf8ade06d9bad45d02853f6c623e4d4f52d767eb56df4d364a99f",
-		Pos:        0,
 	}
-	peer5 := &config.VBFTPeerStakeInfo{
+	peer5 := &config.VBFTPeerInfo{
 		Index:      5,
 		PeerPubkey: "038bfc50b0e3f0e5df6d451069065cbfa7ab5d382a5839cce82e0c963edb026e94",
-		Pos:        0,
 	}
-	peer6 := &config.VBFTPeerStakeInfo{
+	peer6 := &config.VBFTPeerInfo{
 		Index:      6,
 		PeerPubkey: "03f1095289e7fddb882f1cb3e158acc1c30d9de606af21c97ba851821e8b6ea535",
-		Pos:        0,
 	}
-	peer7 := &config.VBFTPeerStakeInfo{
+	peer7 := &config.VBFTPeerInfo{
 		Index:      8,
 		PeerPubkey: "0215865baab70607f4a2413a7a9ba95ab2c3c0202d5b7731c6824eef48e899fc90",
-		Pos:        5000,
 	}
 	peersinfo = append(peersinfo, peer1, peer2, peer3, peer4, peer5, peer6, peer7)
 	conf.Peers = peersinfo
@@ -80,7 +71,7 @@ func constructConfig() (*config.VBFTConfig, error) {
 }
 
 func TestGenConsensusPayload(t *testing.T) {
-	log.Init(log.PATH, log.Stdout)
+	log.InitLog(log.InfoLog, log.Stdout)
 	config, err := constructConfig()
 	if err != nil {
 		t.Errorf("constructConfig failed:%s", err)
@@ -95,7 +86,7 @@ func TestGenConsensusPayload(t *testing.T) {
 }
 
 func TestGenesisChainConfig(t *testing.T) {
-	log.Init(log.PATH, log.Stdout)
+	log.InitLog(log.InfoLog, log.Stdout)
 	config, err := constructConfig()
 	if err != nil {
 		t.Errorf("constructConfig failed:%s", err)
@@ -0,0 +1,350 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+)
+
+const EVIDENCE_DIR = "evidence"
+
+// voteKey identifies one vote of a peer in a round. An honest peer signs the
+// vote digest of at most one block hash for each key.
+type voteKey struct {
+	signer   uint32
+	msgType  node_manager.EquivocationType
+	forEmpty bool
+}
+
+type signedVote struct {
+	hash common.Uint256
+	sig  []byte // signature of node_manager.EquivocationDigest
+}
+
+type equivocation struct {
+	blockNum uint32
+	key      voteKey
+	first    *signedVote
+	second   *signedVote
+}
+
+type EvidenceRound struct {
+	votes   map[voteKey]*signedVote
+	headers map[common.Uint256]*types.Header // proposed block headers, indexed by block hash
+}
+
+//
+// EvidencePool tracks block hashes voted by consensus peers in recent rounds,
+// to detect peers signing conflicting proposals, endorsements or commitments for
+// the same height. An honest proposer proposes at most once for a round and
+// once more for the empty round, so proposals are keyed by the proposer and
+// forEmpty like the other votes.
+//
+type EvidencePool struct {
+	lock       sync.Mutex
+	server     *Server
+	historyLen uint32
+	rounds     map[uint32]*EvidenceRound
+	reported   map[common.Uint256]bool
+}
+
+func newEvidencePool(server *Server, historyLen uint32) *EvidencePool {
+	return &EvidencePool{
+		server:     server,
+		historyLen: historyLen,
+		rounds:     make(map[uint32]*EvidenceRound),
+		reported:   make(map[common.Uint256]bool),
+	}
+}
+
+func (pool *EvidencePool) getRoundLocked(blkNum uint32) *EvidenceRound {
+	if r, present := pool.rounds[blkNum]; present {
+		return r
+	}
+	r := &EvidenceRound{
+		votes:   make(map[voteKey]*signedVote),
+		headers: make(map[common.Uint256]*types.Header),
+	}
+	pool.rounds[blkNum] = r
+	return r
+}
+
+func (pool *EvidencePool) addVoteLocked(blkNum uint32, key voteKey, vote *signedVote) *equivocation {
+	r := pool.getRoundLocked(blkNum)
+	prev, present := r.votes[key]
+	if !present {
+		r.votes[key] = vote
+		return nil
+	}
+	if prev.hash == vote.hash {
+		return nil
+	}
+	return &equivocation{
+		blockNum: blkNum,
+		key:      key,
+		first:    prev,
+		second:   vote,
+	}
+}
+
+// addMsg records the signed block hashes of a verified consensus msg from peer,
+// returns the equivocations found
+func (pool *EvidencePool) addMsg(peerIdx uint32, msg ConsensusMsg) []*equivocation {
+	curBlkNum := pool.server.GetCurrentBlockNo()
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	blkNum := msg.GetBlockNum()
+	if _, present := pool.rounds[blkNum]; !present && blkNum+pool.historyLen < curBlkNum {
+		return nil
+	}
+
+	result := make([]*equivocation, 0)
+	switch msg.Type() {
+	case BlockProposalMessage:
+		// keep proposed headers to build evidence of the votes for them
+		pMsg := msg.(*blockProposalMsg)
+		r := pool.getRoundLocked(blkNum)
+		for _, blk := range []*types.Block{pMsg.Block.Block, pMsg.Block.EmptyBlock} {
+			if blk != nil {
+				r.headers[blk.Hash()] = blk.Header
+			}
+		}
+		if pMsg.Block.getProposer() != peerIdx || len(pMsg.ProposerVoteSig) == 0 {
+			return nil
+		}
+		key := voteKey{
+			signer:   peerIdx,
+			msgType:  node_manager.ProposalEquivocation,
+			forEmpty: pMsg.ForEmpty,
+		}
+		if e := pool.addVoteLocked(blkNum, key, &signedVote{hash: pMsg.Block.Block.Hash(), sig: pMsg.ProposerVoteSig}); e != nil {
+			result = append(result, e)
+		}
+
+	case BlockEndorseMessage:
+		pMsg := msg.(*blockEndorseMsg)
+		if pMsg.Endorser != peerIdx || len(pMsg.EndorserVoteSig) == 0 {
+			return nil
+		}
+		key := voteKey{
+			signer:   pMsg.Endorser,
+			msgType:  node_manager.EndorseEquivocation,
+			forEmpty: pMsg.EndorseForEmpty,
+		}
+		if e := pool.addVoteLocked(blkNum, key, &signedVote{hash: pMsg.EndorsedBlockHash, sig: pMsg.EndorserVoteSig}); e != nil {
+			result = append(result, e)
+		}
+
+	case BlockCommitMessage:
+		pMsg := msg.(*blockCommitMsg)
+		if pMsg.Committer != peerIdx || len(pMsg.CommitterVoteSig) == 0 {
+			return nil
+		}
+		key := voteKey{
+			signer:   pMsg.Committer,
+			msgType:  node_manager.CommitEquivocation,
+			forEmpty: pMsg.CommitForEmpty,
+		}
+		if e := pool.addVoteLocked(blkNum, key, &signedVote{hash: pMsg.CommitBlockHash, sig: pMsg.CommitterVoteSig}); e != nil {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+// buildEvidence resolves the headers signed in the equivocation, evidence is
+// ordered by block hash so that all reporters submit the same evidence.
+func (pool *EvidencePool) buildEvidence(e *equivocation) (*node_manager.EquivocationEvidence, error) {
+	pk := pool.server.peerPool.GetPeerPubKey(e.key.signer)
+	if pk == nil {
+		return nil, fmt.Errorf("failed to get peer %d pubkey", e.key.signer)
+	}
+
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	r, present := pool.rounds[e.blockNum]
+	if !present {
+		return nil, fmt.Errorf("round %d expired", e.blockNum)
+	}
+	votes := []*signedVote{e.first, e.second}
+	if bytes.Compare(e.first.hash[:], e.second.hash[:]) > 0 {
+		votes[0], votes[1] = e.second, e.first
+	}
+	signed := make([]*node_manager.SignedHeader, 0, len(votes))
+	for _, vote := range votes {
+		header, present := r.headers[vote.hash]
+		if !present {
+			return nil, fmt.Errorf("header of block %x not found", vote.hash)
+		}
+		digest := node_manager.EquivocationDigest(e.key.msgType, e.key.forEmpty, header.Height, vote.hash)
+		if err := signature.Verify(pk, digest, vote.sig); err != nil {
+			return nil, fmt.Errorf("invalid signature of block %x: %s", vote.hash, err)
+		}
+		sink := common.NewZeroCopySink(nil)
+		if err := header.Serialization(sink); err != nil {
+			return nil, fmt.Errorf("failed to serialize header: %s", err)
+		}
+		signed = append(signed, &node_manager.SignedHeader{
+			Header: sink.Bytes(),
+			Sig:    vote.sig,
+		})
+	}
+
+	return &node_manager.EquivocationEvidence{
+		PeerPubkey: vconfig.PubkeyID(pk),
+		Type:       e.key.msgType,
+		ForEmpty:   e.key.forEmpty,
+		First:      signed[0],
+		Second:     signed[1],
+	}, nil
+}
+
+// markReported returns false if the evidence has already been reported
+func (pool *EvidencePool) markReported(evidence []byte) bool {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	h := common.Uint256(sha256.Sum256(evidence))
+	if pool.reported[h] {
+		return false
+	}
+	pool.reported[h] = true
+	return true
+}
+
+func (pool *EvidencePool) onBlockSealed(blockNum uint32) {
+	if blockNum <= pool.historyLen {
+		return
+	}
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	toFreeRound := make([]uint32, 0)
+	for n := range pool.rounds {
+		if n < blockNum-pool.historyLen {
+			toFreeRound = append(toFreeRound, n)
+		}
+	}
+	for _, n := range toFreeRound {
+		delete(pool.rounds, n)
+	}
+}
+
+func (self *Server) checkEquivocation(peerIdx uint32, msg ConsensusMsg) {
+	for _, e := range self.evidencePool.addMsg(peerIdx, msg) {
+		log.Warnf("server %d detected equivocation of peer %d, blk %d, type %d, forEmpty %t: %x, %x",
+			self.Index, e.key.signer, e.blockNum, e.key.msgType, e.key.forEmpty, e.first.hash, e.second.hash)
+
+		evidence, err := self.evidencePool.buildEvidence(e)
+		if err != nil {
+			log.Errorf("server %d failed to build equivocation evidence of peer %d, blk %d: %s",
+				self.Index, e.key.signer, e.blockNum, err)
+			continue
+		}
+		if err := self.reportEquivocation(evidence, e.blockNum); err != nil {
+			log.Errorf("server %d failed to report equivocation of peer %d, blk %d: %s",
+				self.Index, e.key.signer, e.blockNum, err)
+		}
+	}
+}
+
+// reportEquivocation persists the evidence to data dir, and submits it to
+// node manager contract, which blacks the peer once approved by consensus nodes
+func (self *Server) reportEquivocation(evidence *node_manager.EquivocationEvidence, blkNum uint32) error {
+	sink := common.NewZeroCopySink(nil)
+	evidence.Serialization(sink)
+	evidenceBytes := sink.Bytes()
+	if !self.evidencePool.markReported(evidenceBytes) {
+		return nil
+	}
+
+	h := sha256.Sum256(evidenceBytes)
+	dir := filepath.Join(self.dataDir, EVIDENCE_DIR)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create evidence dir: %s", err)
+	}
+	name := filepath.Join(dir, fmt.Sprintf("%d_%s", blkNum, hex.EncodeToString(h[:])))
+	if err := ioutil.WriteFile(name, []byte(hex.EncodeToString(evidenceBytes)), 0600); err != nil {
+		return fmt.Errorf("failed to persist evidence: %s", err)
+	}
+	log.Infof("server %d persisted equivocation evidence to %s", self.Index, name)
+
+	if self.nonConsensusNode() {
+		return nil
+	}
+	tx, err := self.createEquivocationTransaction(evidence, blkNum)
+	if err != nil {
+		return err
+	}
+	self.poolActor.SubmitTx(tx)
+	txHash := tx.Hash()
+	log.Infof("server %d submitted equivocation evidence in tx %s", self.Index, txHash.ToHexString())
+	return nil
+}
+
+func (self *Server) createEquivocationTransaction(evidence *node_manager.EquivocationEvidence, blkNum uint32) (*types.Transaction, error) {
+	params := &node_manager.ReportEquivocationParam{
+		Evidence: evidence,
+		Address:  self.account.Address,
+	}
+	args := common.NewZeroCopySink(nil)
+	params.Serialization(args)
+	contractInvokeParam := &states.ContractInvokeParam{Address: utils.NodeManagerContractAddress,
+		Method: node_manager.REPORT_EQUIVOCATION, Args: args.Bytes()}
+	invokeCode := new(common.ZeroCopySink)
+	contractInvokeParam.Serialization(invokeCode)
+	tx := genesis.NewInvokeTransaction(invokeCode.Bytes(), blkNum)
+
+	txHash := tx.Hash()
+	sig, err := signature.Sign(self.account, txHash[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign equivocation tx: %s", err)
+	}
+	tx.Sigs = append(tx.Sigs, types.Sig{
+		PubKeys: []keypair.PublicKey{self.account.PublicKey},
+		M:       1,
+		SigData: [][]byte{sig},
+	})
+	sink := common.NewZeroCopySink(nil)
+	if err := tx.Serialization(sink); err != nil {
+		return nil, fmt.Errorf("failed to serialize equivocation tx: %s", err)
+	}
+	return types.TransactionFromRawBytes(sink.Bytes())
+}
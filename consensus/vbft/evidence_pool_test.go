@@ -0,0 +1,235 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEvidenceTestServer(t *testing.T, index uint32, accts []*account.Account) *Server {
+	server := &Server{
+		Index:           index,
+		account:         accts[index],
+		currentBlockNum: 20,
+	}
+	server.peerPool = NewPeerPool(len(accts), server)
+	for i, acct := range accts {
+		peerConfig := &vconfig.PeerConfig{Index: uint32(i), ID: vconfig.PubkeyID(acct.PublicKey)}
+		assert.Nil(t, server.peerPool.addPeer(peerConfig))
+	}
+	server.evidencePool = newEvidencePool(server, 10)
+	server.voteStore = newTestVoteStore(t)
+	return server
+}
+
+func newEvidenceTestBlock(t *testing.T, acc *account.Account, proposer, height uint32, nonce uint64) *types.Block {
+	payload, err := json.Marshal(&vconfig.VbftBlockInfo{Proposer: proposer})
+	assert.Nil(t, err)
+	header := &types.Header{
+		Height:           height,
+		ConsensusData:    nonce,
+		ConsensusPayload: payload,
+	}
+	hash := header.Hash()
+	sig, err := signature.Sign(acc, hash[:])
+	assert.Nil(t, err)
+	header.SigData = [][]byte{sig}
+	return &types.Block{Header: header}
+}
+
+func newEvidenceTestProposal(t *testing.T, acc *account.Account, proposer, height uint32, nonce uint64) *blockProposalMsg {
+	return &blockProposalMsg{
+		Block: &Block{
+			Block:      newEvidenceTestBlock(t, acc, proposer, height, nonce),
+			EmptyBlock: newEvidenceTestBlock(t, acc, proposer, height, nonce+1),
+			Info:       &vconfig.VbftBlockInfo{Proposer: proposer},
+		},
+	}
+}
+
+//signVoteTest signs the proposal as proposed by acc for the round or the empty round
+func signVoteTest(t *testing.T, acc *account.Account, proposal *blockProposalMsg, forEmpty bool) *blockProposalMsg {
+	digest := node_manager.EquivocationDigest(node_manager.ProposalEquivocation, forEmpty, proposal.GetBlockNum(), proposal.Block.Block.Hash())
+	sig, err := signature.Sign(acc, digest)
+	assert.Nil(t, err)
+	proposal.ForEmpty = forEmpty
+	proposal.ProposerVoteSig = sig
+	return proposal
+}
+
+func TestEvidencePoolProposals(t *testing.T) {
+	accts := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	server := newEvidenceTestServer(t, 0, accts)
+
+	//proposals without vote sig are only kept for their headers
+	for _, nonce := range []uint64{1, 3} {
+		proposal := newEvidenceTestProposal(t, accts[1], 1, 20, nonce)
+		assert.Nil(t, proposal.Verify(accts[1].PublicKey))
+		assert.Empty(t, server.evidencePool.addMsg(1, proposal))
+	}
+	assert.Equal(t, 4, len(server.evidencePool.rounds[20].headers))
+	assert.Empty(t, server.evidencePool.rounds[20].votes)
+
+	//proposers may propose once for the round and once for the empty round
+	proposal1 := signVoteTest(t, accts[1], newEvidenceTestProposal(t, accts[1], 1, 21, 1), false)
+	assert.Nil(t, proposal1.Verify(accts[1].PublicKey))
+	assert.NotNil(t, proposal1.Verify(accts[2].PublicKey))
+	assert.Empty(t, server.evidencePool.addMsg(1, proposal1))
+	assert.Empty(t, server.evidencePool.addMsg(1, proposal1))
+	emptyProposal := signVoteTest(t, accts[1], newEvidenceTestProposal(t, accts[1], 1, 21, 3), true)
+	assert.Empty(t, server.evidencePool.addMsg(1, emptyProposal))
+
+	//vote sig for another round fails the msg verification
+	forged := *emptyProposal
+	forged.ForEmpty = false
+	assert.NotNil(t, forged.Verify(accts[1].PublicKey))
+
+	//proposals relayed by another peer are ignored
+	proposal2 := signVoteTest(t, accts[1], newEvidenceTestProposal(t, accts[1], 1, 21, 5), false)
+	assert.Empty(t, server.evidencePool.addMsg(2, proposal2))
+
+	equivocations := server.evidencePool.addMsg(1, proposal2)
+	assert.Equal(t, 1, len(equivocations))
+	assert.Equal(t, voteKey{signer: 1, msgType: node_manager.ProposalEquivocation}, equivocations[0].key)
+	evidence, err := server.evidencePool.buildEvidence(equivocations[0])
+	assert.Nil(t, err)
+	assert.Equal(t, node_manager.ProposalEquivocation, evidence.Type)
+	first, err := types.HeaderFromRawBytes(evidence.First.Header)
+	assert.Nil(t, err)
+	digest := node_manager.EquivocationDigest(node_manager.ProposalEquivocation, false, 21, first.Hash())
+	assert.Nil(t, signature.Verify(accts[1].PublicKey, digest, evidence.First.Sig))
+}
+
+func TestEvidencePoolEndorsements(t *testing.T) {
+	accts := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	server := newEvidenceTestServer(t, 0, accts)
+	endorser := newEvidenceTestServer(t, 2, accts)
+	proposal1 := newEvidenceTestProposal(t, accts[1], 1, 20, 1)
+	proposal2 := newEvidenceTestProposal(t, accts[1], 1, 20, 3)
+	server.evidencePool.addMsg(1, proposal1)
+	server.evidencePool.addMsg(1, proposal2)
+
+	endorse1, err := endorser.constructEndorseMsg(proposal1, false)
+	assert.Nil(t, err)
+	assert.Nil(t, endorse1.Verify(accts[2].PublicKey))
+	//the endorser refuses to vote twice, only a faulty copy of it does
+	_, err = endorser.constructEndorseMsg(proposal2, false)
+	assert.NotNil(t, err)
+	endorse2, err := newEvidenceTestServer(t, 2, accts).constructEndorseMsg(proposal2, false)
+	assert.Nil(t, err)
+	emptyEndorse, err := endorser.constructEndorseMsg(proposal2, true)
+	assert.Nil(t, err)
+
+	//votes for block and empty block are not conflicting
+	assert.Empty(t, server.evidencePool.addMsg(2, endorse1))
+	assert.Empty(t, server.evidencePool.addMsg(2, endorse1))
+	assert.Empty(t, server.evidencePool.addMsg(2, emptyEndorse))
+
+	//endorsements relayed by another peer or without vote sig are ignored
+	assert.Empty(t, server.evidencePool.addMsg(1, endorse2))
+	legacy := *endorse2
+	legacy.EndorserVoteSig = nil
+	assert.Nil(t, legacy.Verify(accts[2].PublicKey))
+	assert.Empty(t, server.evidencePool.addMsg(2, &legacy))
+
+	equivocations := server.evidencePool.addMsg(2, endorse2)
+	assert.Equal(t, 1, len(equivocations))
+	e := equivocations[0]
+	assert.Equal(t, voteKey{signer: 2, msgType: node_manager.EndorseEquivocation}, e.key)
+
+	evidence, err := server.evidencePool.buildEvidence(e)
+	assert.Nil(t, err)
+	assert.Equal(t, vconfig.PubkeyID(accts[2].PublicKey), evidence.PeerPubkey)
+	assert.Equal(t, node_manager.EndorseEquivocation, evidence.Type)
+	assert.False(t, evidence.ForEmpty)
+	first, err := types.HeaderFromRawBytes(evidence.First.Header)
+	assert.Nil(t, err)
+	second, err := types.HeaderFromRawBytes(evidence.Second.Header)
+	assert.Nil(t, err)
+	firstHash, secondHash := first.Hash(), second.Hash()
+	assert.True(t, bytes.Compare(firstHash[:], secondHash[:]) < 0)
+	digest := node_manager.EquivocationDigest(node_manager.EndorseEquivocation, false, 20, firstHash)
+	assert.Nil(t, signature.Verify(accts[2].PublicKey, digest, evidence.First.Sig))
+
+	//reporters of the same equivocation build the same evidence
+	e.first, e.second = e.second, e.first
+	evidence1, err := server.evidencePool.buildEvidence(e)
+	assert.Nil(t, err)
+	assert.Equal(t, evidence, evidence1)
+
+	sink := common.NewZeroCopySink(nil)
+	evidence.Serialization(sink)
+	assert.True(t, server.evidencePool.markReported(sink.Bytes()))
+	assert.False(t, server.evidencePool.markReported(sink.Bytes()))
+}
+
+func TestEvidencePoolCommits(t *testing.T) {
+	accts := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	server := newEvidenceTestServer(t, 0, accts)
+	committer := newEvidenceTestServer(t, 2, accts)
+	proposal1 := newEvidenceTestProposal(t, accts[1], 1, 20, 1)
+	proposal2 := newEvidenceTestProposal(t, accts[1], 1, 20, 3)
+
+	commit1, err := committer.constructCommitMsg(proposal1, nil, true)
+	assert.Nil(t, err)
+	assert.Nil(t, commit1.Verify(accts[2].PublicKey))
+	commit2, err := newEvidenceTestServer(t, 2, accts).constructCommitMsg(proposal2, nil, true)
+	assert.Nil(t, err)
+
+	//vote sig of another role fails the msg verification
+	forged := *commit2
+	forged.CommitterVoteSig = commit1.CommitterVoteSig
+	assert.NotNil(t, forged.Verify(accts[2].PublicKey))
+
+	assert.Empty(t, server.evidencePool.addMsg(2, commit1))
+	equivocations := server.evidencePool.addMsg(2, commit2)
+	assert.Equal(t, 1, len(equivocations))
+	assert.Equal(t, voteKey{signer: 2, msgType: node_manager.CommitEquivocation, forEmpty: true}, equivocations[0].key)
+
+	//evidence needs the headers of the proposals
+	_, err = server.evidencePool.buildEvidence(equivocations[0])
+	assert.NotNil(t, err)
+	server.evidencePool.addMsg(1, proposal1)
+	server.evidencePool.addMsg(1, proposal2)
+	_, err = server.evidencePool.buildEvidence(equivocations[0])
+	assert.Nil(t, err)
+}
+
+func TestEvidencePoolHistory(t *testing.T) {
+	accts := []*account.Account{account.NewAccount(""), account.NewAccount("")}
+	server := newEvidenceTestServer(t, 0, accts)
+	server.evidencePool.addMsg(1, newEvidenceTestProposal(t, accts[1], 1, 20, 1))
+	assert.Contains(t, server.evidencePool.rounds, uint32(20))
+
+	//rounds out of history are freed and not tracked again
+	server.evidencePool.onBlockSealed(31)
+	assert.NotContains(t, server.evidencePool.rounds, uint32(20))
+	server.currentBlockNum = 32
+	server.evidencePool.addMsg(1, newEvidenceTestProposal(t, accts[1], 1, 20, 3))
+	assert.NotContains(t, server.evidencePool.rounds, uint32(20))
+}
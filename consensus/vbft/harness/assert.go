@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package harness
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/cmd/utils"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+)
+
+const POLL_INTERVAL = 50 * time.Millisecond
+
+// Height returns the ledger height of node i.
+func (self *Cluster) Height(i int) uint32 {
+	return self.Nodes[i].Ledger.GetCurrentBlockHeight()
+}
+
+func (self *Cluster) nodesOrAll(nodes []int) []int {
+	if len(nodes) != 0 {
+		return nodes
+	}
+	all := make([]int, 0, len(self.Nodes))
+	for i := range self.Nodes {
+		all = append(all, i)
+	}
+	return all
+}
+
+// WaitForHeight is the liveness assertion, it fails unless the given nodes, all
+// nodes by default, reach height before timeout.
+func (self *Cluster) WaitForHeight(height uint32, timeout time.Duration, nodes ...int) error {
+	nodes = self.nodesOrAll(nodes)
+	deadline := time.Now().Add(timeout)
+	for {
+		behind := make([]string, 0)
+		for _, i := range nodes {
+			if h := self.Height(i); h < height {
+				behind = append(behind, fmt.Sprintf("node %d at %d", self.Nodes[i].Index, h))
+			}
+		}
+		if len(behind) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("height %d not reached in %s: %v", height, timeout, behind)
+		}
+		time.Sleep(POLL_INTERVAL)
+	}
+}
+
+// WaitForTransaction fails unless tx is in the ledger of the given nodes, all nodes
+// by default, before timeout.
+func (self *Cluster) WaitForTransaction(hash common.Uint256, timeout time.Duration, nodes ...int) error {
+	nodes = self.nodesOrAll(nodes)
+	deadline := time.Now().Add(timeout)
+	for {
+		missing := 0
+		for _, i := range nodes {
+			if exist, _ := self.Nodes[i].Ledger.IsContainTransaction(hash); !exist {
+				missing++
+			}
+		}
+		if missing == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("tx %s missing on %d nodes after %s", hash.ToHexString(), missing, timeout)
+		}
+		time.Sleep(POLL_INTERVAL)
+	}
+}
+
+// CheckSafety is the safety assertion, it fails if two nodes committed different
+// blocks at the same height.
+func (self *Cluster) CheckSafety() error {
+	var max uint32
+	for i := range self.Nodes {
+		if h := self.Height(i); h > max {
+			max = h
+		}
+	}
+	for height := uint32(1); height <= max; height++ {
+		var hash common.Uint256
+		var owner *Node
+		for _, node := range self.Nodes {
+			if node.Ledger.GetCurrentBlockHeight() < height {
+				continue
+			}
+			h := node.Ledger.GetBlockHash(height)
+			if owner == nil {
+				hash, owner = h, node
+			} else if h != hash {
+				return fmt.Errorf("fork at height %d: node %d has %s, node %d has %s", height,
+					owner.Index, hash.ToHexString(), node.Index, h.ToHexString())
+			}
+		}
+	}
+	return nil
+}
+
+// SignByConsensus signs tx as the multi-sig operator of the genesis consensus nodes,
+// the authority of node manager methods like UpdateConfig.
+func (self *Cluster) SignByConsensus(tx *types.Transaction) error {
+	pubKeys := make([]keypair.PublicKey, 0, len(self.Nodes))
+	for _, node := range self.Nodes {
+		pubKeys = append(pubKeys, node.Account.PublicKey)
+	}
+	if len(pubKeys) == 1 {
+		return utils.SignTransaction(self.Nodes[0].Account, tx)
+	}
+	m := len(pubKeys) - (len(pubKeys)-1)/3
+	for _, node := range self.Nodes[:m] {
+		if err := utils.MultiSigTransaction(tx, uint16(m), pubKeys, node.Account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package harness runs several vbft or hotstuff consensus servers in one process,
+// connected by an in-memory network and backed by in-memory ledgers, to exercise
+// consensus and governance changes end to end without real peers.
+package harness
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology-eventbus/eventhub"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/consensus"
+	actorTypes "github.com/polynetwork/poly/consensus/actor"
+	"github.com/polynetwork/poly/consensus/hotstuff"
+	"github.com/polynetwork/poly/consensus/vbft"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/events"
+	_ "github.com/polynetwork/poly/native/service" // register the native contracts like a real node
+	tc "github.com/polynetwork/poly/txnpool/common"
+)
+
+const (
+	DEFAULT_NODE_COUNT = 4
+	STOP_TIMEOUT       = 10 * time.Second
+)
+
+var clusterSeq uint32
+
+// Config describes the cluster to run.
+type Config struct {
+	Nodes     int                    // number of consensus nodes, DEFAULT_NODE_COUNT if zero
+	VBFT      *config.VBFTConfig     // genesis consensus config, peers are filled in by the harness
+	HotStuff  *config.HotStuffConfig // run hotstuff with this genesis config instead of vbft
+	KeepAlive bool                   // feed the leaders a transaction for every block
+	Seed      int64                  // seed of the network randomness
+}
+
+// Node is one consensus node of the cluster.
+type Node struct {
+	Index   uint32 // peer index in the genesis config
+	ID      uint64 // id on the network
+	Account *account.Account
+	Ledger  *ledger.Ledger
+	Server  consensus.ConsensusService // nil while the node is stopped
+
+	hub        *eventhub.EventHub
+	pool       *txPool
+	poolPid    *actor.PID
+	p2pPid     *actor.PID
+	generation int
+}
+
+// Cluster is a set of consensus nodes sharing a genesis block, every node has its own
+// ledger, data dir, event hub, transaction pool and p2p server.
+type Cluster struct {
+	Network *Network
+	Nodes   []*Node
+	Genesis *config.GenesisConfig
+
+	seq     uint32
+	dataDir string
+}
+
+// DefaultVBFTConfig returns the smallest timing accepted by the node manager.
+func DefaultVBFTConfig() *config.VBFTConfig {
+	return &config.VBFTConfig{
+		BlockMsgDelay:        5000,
+		HashMsgDelay:         5000,
+		PeerHandshakeTimeout: 10,
+		MaxBlockChangeView:   10000,
+		VrfValue:             config.MainNetConfig.VBFT.VrfValue,
+		VrfProof:             config.MainNetConfig.VBFT.VrfProof,
+	}
+}
+
+// DefaultHotStuffConfig returns the smallest timing accepted by the node manager.
+func DefaultHotStuffConfig() *config.HotStuffConfig {
+	return &config.HotStuffConfig{
+		BlockInterval:      5000,
+		RoundTimeout:       5000,
+		MaxBlockChangeView: 10000,
+	}
+}
+
+// NewCluster creates the nodes with their genesis block, call Start to run consensus.
+// The ledgers check the block headers by the consensus type of config.DefConfig, which
+// must match the engine of the cluster.
+func NewCluster(cfg *Config) (*Cluster, error) {
+	n := cfg.Nodes
+	if n == 0 {
+		n = DEFAULT_NODE_COUNT
+	}
+	vbftConfig := DefaultVBFTConfig()
+	if cfg.VBFT != nil {
+		c := *cfg.VBFT
+		vbftConfig = &c
+	}
+	accounts := make([]*account.Account, 0, n)
+	bookkeepers := make([]keypair.PublicKey, 0, n)
+	vbftConfig.Peers = make([]*config.VBFTPeerInfo, 0, n)
+	for i := 0; i < n; i++ {
+		acc := account.NewAccount("SHA256withECDSA")
+		accounts = append(accounts, acc)
+		bookkeepers = append(bookkeepers, acc.PublicKey)
+		vbftConfig.Peers = append(vbftConfig.Peers, &config.VBFTPeerInfo{
+			Index:      uint32(i + 1),
+			PeerPubkey: vconfig.PubkeyID(acc.PublicKey),
+			Address:    acc.Address.ToBase58(),
+		})
+	}
+
+	genesisConfig := &config.GenesisConfig{
+		SeedList:      []string{},
+		ConsensusType: config.CONSENSUS_TYPE_VBFT,
+		VBFT:          vbftConfig,
+		DBFT:          &config.DBFTConfig{},
+		SOLO:          &config.SOLOConfig{},
+	}
+	if cfg.HotStuff != nil {
+		hotstuffConfig := *cfg.HotStuff
+		hotstuffConfig.Peers = vbftConfig.Peers
+		genesisConfig.ConsensusType = config.CONSENSUS_TYPE_HOTSTUFF
+		genesisConfig.HotStuff = &hotstuffConfig
+	}
+
+	dataDir, err := ioutil.TempDir("", "vbft-harness")
+	if err != nil {
+		return nil, err
+	}
+	self := &Cluster{
+		Network: NewNetwork(cfg.Seed),
+		Genesis: genesisConfig,
+		seq:     atomic.AddUint32(&clusterSeq, 1),
+		dataDir: dataDir,
+	}
+
+	for i, acc := range accounts {
+		node := &Node{
+			Index:   uint32(i + 1),
+			ID:      uint64(i + 1),
+			Account: acc,
+			hub:     events.NewEvtHub(),
+		}
+		self.Nodes = append(self.Nodes, node)
+		if err := self.initNode(node, bookkeepers, cfg.KeepAlive); err != nil {
+			self.Stop()
+			return nil, fmt.Errorf("node %d: %s", node.Index, err)
+		}
+	}
+	return self, nil
+}
+
+func (self *Cluster) actorName(kind string, node *Node) string {
+	return fmt.Sprintf("harness%d_%s_%d_%d", self.seq, kind, node.ID, node.generation)
+}
+
+func (self *Cluster) initNode(node *Node, bookkeepers []keypair.PublicKey, keepAlive bool) error {
+	db, err := ledger.NewMemLedger(events.NewActorPublisher(nil, node.hub))
+	if err != nil {
+		return err
+	}
+	node.Ledger = db
+	block, err := genesis.BuildGenesisBlock(bookkeepers, self.Genesis)
+	if err != nil {
+		return err
+	}
+	if err := db.Init(bookkeepers, block); err != nil {
+		return err
+	}
+
+	node.pool = newTxPool(node.ID, db, keepAlive)
+	node.poolPid, err = actor.SpawnNamed(actor.FromProducer(func() actor.Actor { return node.pool }),
+		self.actorName("txpool", node))
+	if err != nil {
+		return err
+	}
+	node.p2pPid, err = self.Network.Join(node.ID, self.actorName("p2p", node), db)
+	return err
+}
+
+// Start runs consensus on all stopped nodes. All servers are created before any of
+// them starts, so that no node misses the first heartbeats of the others.
+func (self *Cluster) Start() error {
+	stopped := make([]int, 0, len(self.Nodes))
+	for i, node := range self.Nodes {
+		if node.Server != nil {
+			continue
+		}
+		if err := self.createServer(node); err != nil {
+			return err
+		}
+		stopped = append(stopped, i)
+	}
+	for _, i := range stopped {
+		if err := self.startServer(self.Nodes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartNode runs consensus on node i from what its ledger has, e.g. after StopNode.
+func (self *Cluster) StartNode(i int) error {
+	node := self.Nodes[i]
+	if node.Server != nil {
+		return fmt.Errorf("node %d is running", node.Index)
+	}
+	if err := self.createServer(node); err != nil {
+		return err
+	}
+	return self.startServer(node)
+}
+
+func (self *Cluster) createServer(node *Node) error {
+	node.generation++
+	name := self.actorName("consensus", node)
+	dataDir := filepath.Join(self.dataDir, fmt.Sprintf("node%d", node.ID))
+	var server consensus.ConsensusService
+	var err error
+	if self.Genesis.ConsensusType == config.CONSENSUS_TYPE_HOTSTUFF {
+		server, err = hotstuff.NewHotStuffServerWithLedger(node.Account, node.poolPid, node.p2pPid, node.Ledger,
+			name, dataDir, node.hub)
+	} else {
+		server, err = vbft.NewVbftServerWithLedger(node.Account, node.poolPid, node.p2pPid, node.Ledger,
+			name, dataDir, node.hub)
+	}
+	if err != nil {
+		return fmt.Errorf("node %d: %s", node.Index, err)
+	}
+	self.Network.Attach(node.ID, server.GetPID())
+	node.Server = server
+	return nil
+}
+
+func (self *Cluster) startServer(node *Node) error {
+	if err := node.Server.Start(); err != nil {
+		return fmt.Errorf("node %d: %s", node.Index, err)
+	}
+	return nil
+}
+
+// StopNode crashes node i, its ledger is kept for StartNode.
+func (self *Cluster) StopNode(i int) error {
+	node := self.Nodes[i]
+	if node.Server == nil {
+		return nil
+	}
+	self.Network.Detach(node.ID)
+	pid := node.Server.GetPID()
+	node.Server = nil
+	_, err := pid.RequestFuture(&actorTypes.StopConsensus{}, STOP_TIMEOUT).Result()
+	pid.Stop()
+	if err != nil {
+		return fmt.Errorf("node %d: stop consensus: %s", node.Index, err)
+	}
+	return nil
+}
+
+// Stop stops all nodes and releases their ledgers.
+func (self *Cluster) Stop() {
+	for i, node := range self.Nodes {
+		self.StopNode(i)
+		if node.poolPid != nil {
+			node.poolPid.Stop()
+		}
+		if node.p2pPid != nil {
+			node.p2pPid.Stop()
+		}
+		self.Network.Leave(node.ID)
+		if node.Ledger != nil {
+			node.Ledger.Close()
+		}
+	}
+	os.RemoveAll(self.dataDir)
+}
+
+// Accounts returns the accounts of the consensus nodes.
+func (self *Cluster) Accounts() []*account.Account {
+	accounts := make([]*account.Account, 0, len(self.Nodes))
+	for _, node := range self.Nodes {
+		accounts = append(accounts, node.Account)
+	}
+	return accounts
+}
+
+// SubmitTransaction hands tx to the transaction pool of every node.
+func (self *Cluster) SubmitTransaction(tx *types.Transaction) {
+	for _, node := range self.Nodes {
+		node.poolPid.Tell(&tc.TxReq{Tx: tx, Sender: tc.NilSender})
+	}
+}
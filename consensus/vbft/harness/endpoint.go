@@ -0,0 +1,209 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package harness
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	p2pcommon "github.com/polynetwork/poly/p2pserver/common"
+	p2pmsg "github.com/polynetwork/poly/p2pserver/message/types"
+	"github.com/polynetwork/poly/p2pserver/peer"
+)
+
+// endpoint is the p2p.P2P of one node, it runs under the real p2p server and actor
+// of the node but hands the messages to the network instead of sockets. Only
+// consensus messages travel, the received ones go to the consensus server of the
+// node like utils.ConsensusHandle does for the global consensus pid.
+type endpoint struct {
+	network  *Network
+	id       uint64
+	height   uint64
+	np       *peer.NbrPeers
+	syncChan chan *p2pmsg.MsgPayload
+	consChan chan *p2pmsg.MsgPayload
+	quit     chan struct{}
+	halt     sync.Once
+}
+
+func newEndpoint(network *Network, id uint64) *endpoint {
+	self := &endpoint{
+		network:  network,
+		id:       id,
+		np:       &peer.NbrPeers{},
+		syncChan: make(chan *p2pmsg.MsgPayload, p2pcommon.CHAN_CAPABILITY),
+		consChan: make(chan *p2pmsg.MsgPayload, p2pcommon.CHAN_CAPABILITY),
+		quit:     make(chan struct{}),
+	}
+	self.np.Init()
+	return self
+}
+
+// addPeer registers node id as an established neighbor.
+func (self *endpoint) addPeer(id uint64) {
+	p := peer.NewPeer()
+	p.UpdateInfo(time.Now(), p2pcommon.PROTOCOL_VERSION, p2pcommon.VERIFY_NODE, 0, 0, id, 1, 0, "")
+	p.SetSyncState(p2pcommon.ESTABLISH)
+	p.SetConsState(p2pcommon.ESTABLISH)
+	self.np.AddNbrNode(p)
+}
+
+// receive reads a message off the wire bytes and queues it for the consensus server.
+func (self *endpoint) receive(from uint64, buf []byte) {
+	msg, size, err := p2pmsg.ReadMessage(bytes.NewReader(buf))
+	if err != nil {
+		log.Errorf("harness: endpoint %d read message from %d: %s", self.id, from, err)
+		return
+	}
+	data := &p2pmsg.MsgPayload{
+		Id:          from,
+		Addr:        fmt.Sprintf("harness:%d", from),
+		PayloadSize: size,
+		Payload:     msg,
+	}
+	select {
+	case self.consChan <- data:
+	case <-self.quit:
+	}
+}
+
+func (self *endpoint) dispatch() {
+	for {
+		select {
+		case data := <-self.consChan:
+			self.handle(data)
+		case <-self.quit:
+			return
+		}
+	}
+}
+
+func (self *endpoint) handle(data *p2pmsg.MsgPayload) {
+	consensus, ok := data.Payload.(*p2pmsg.Consensus)
+	if !ok {
+		log.Debugf("harness: endpoint %d ignores %s from %d", self.id, data.Payload.CmdType(), data.Id)
+		return
+	}
+	if err := consensus.Cons.Verify(); err != nil {
+		log.Warnf("harness: drop consensus payload from %d: %s", data.Id, err)
+		return
+	}
+	consensus.Cons.PeerId = data.Id
+	if server := self.network.server(self.id); server != nil {
+		server.Tell(&consensus.Cons)
+	}
+}
+
+func (self *endpoint) pack(msg p2pmsg.Message, isConsensus bool) ([]byte, error) {
+	if !isConsensus {
+		return nil, nil
+	}
+	sink := common.NewZeroCopySink(nil)
+	if err := p2pmsg.WriteMessage(sink, msg); err != nil {
+		return nil, err
+	}
+	return sink.Bytes(), nil
+}
+
+func (self *endpoint) Start() {
+	go self.dispatch()
+}
+
+func (self *endpoint) Halt() {
+	self.halt.Do(func() {
+		close(self.quit)
+	})
+}
+
+func (self *endpoint) Xmit(msg p2pmsg.Message, isCons bool) {
+	buf, err := self.pack(msg, isCons)
+	if err != nil {
+		log.Errorf("harness: endpoint %d write %s: %s", self.id, msg.CmdType(), err)
+		return
+	}
+	if buf != nil {
+		self.network.broadcast(self.id, buf)
+	}
+}
+
+func (self *endpoint) Send(p *peer.Peer, msg p2pmsg.Message, isConsensus bool) error {
+	if p == nil {
+		return fmt.Errorf("harness: send to a invalid peer")
+	}
+	buf, err := self.pack(msg, isConsensus)
+	if err != nil {
+		return err
+	}
+	if buf != nil {
+		self.network.send(self.id, p.GetID(), buf)
+	}
+	return nil
+}
+
+func (self *endpoint) GetID() uint64           { return self.id }
+func (self *endpoint) GetVersion() uint32      { return p2pcommon.PROTOCOL_VERSION }
+func (self *endpoint) GetSyncPort() uint16     { return 0 }
+func (self *endpoint) GetConsPort() uint16     { return 0 }
+func (self *endpoint) GetHttpInfoPort() uint16 { return 0 }
+func (self *endpoint) GetRelay() bool          { return true }
+func (self *endpoint) GetHeight() uint64       { return atomic.LoadUint64(&self.height) }
+func (self *endpoint) SetHeight(height uint64) { atomic.StoreUint64(&self.height, height) }
+func (self *endpoint) GetTime() int64          { return time.Now().UnixNano() }
+func (self *endpoint) GetServices() uint64     { return p2pcommon.VERIFY_NODE }
+
+func (self *endpoint) GetNeighbors() []*peer.Peer              { return self.np.GetNeighbors() }
+func (self *endpoint) GetNeighborAddrs() []p2pcommon.PeerAddr  { return self.np.GetNeighborAddrs() }
+func (self *endpoint) GetConnectionCnt() uint32                { return self.np.GetNbrNodeCnt() }
+func (self *endpoint) GetNp() *peer.NbrPeers                   { return self.np }
+func (self *endpoint) GetPeer(id uint64) *peer.Peer            { return self.np.GetPeer(id) }
+func (self *endpoint) AddNbrNode(p *peer.Peer)                 { self.np.AddNbrNode(p) }
+func (self *endpoint) DelNbrNode(id uint64) (*peer.Peer, bool) { return self.np.DelNbrNode(id) }
+func (self *endpoint) NodeEstablished(id uint64) bool          { return self.np.NodeEstablished(id) }
+
+func (self *endpoint) IsPeerEstablished(p *peer.Peer) bool {
+	return p != nil && self.np.NodeEstablished(p.GetID())
+}
+
+func (self *endpoint) GetMsgChan(isConsensus bool) chan *p2pmsg.MsgPayload {
+	if isConsensus {
+		return self.consChan
+	}
+	return self.syncChan
+}
+
+// the network is fully connected from the start, there are no addresses to dial
+func (self *endpoint) Connect(addr string, isConsensus bool) error  { return nil }
+func (self *endpoint) GetPeerFromAddr(addr string) *peer.Peer       { return nil }
+func (self *endpoint) AddOutConnectingList(addr string) bool        { return false }
+func (self *endpoint) GetOutConnRecordLen() int                     { return 0 }
+func (self *endpoint) RemoveFromConnectingList(addr string)         {}
+func (self *endpoint) RemoveFromOutConnRecord(addr string)          {}
+func (self *endpoint) RemoveFromInConnRecord(addr string)           {}
+func (self *endpoint) AddPeerSyncAddress(addr string, p *peer.Peer) {}
+func (self *endpoint) AddPeerConsAddress(addr string, p *peer.Peer) {}
+func (self *endpoint) GetOutConnectingListLen() uint                { return 0 }
+func (self *endpoint) RemovePeerSyncAddress(addr string)            {}
+func (self *endpoint) RemovePeerConsAddress(addr string)            {}
+func (self *endpoint) SetOwnAddress(addr string)                    {}
+func (self *endpoint) IsOwnAddress(addr string) bool                { return false }
+func (self *endpoint) IsAddrFromConnecting(addr string) bool        { return false }
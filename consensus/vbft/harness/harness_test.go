@@ -0,0 +1,245 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package harness
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/consensus/engine"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	log.InitLog(log.ErrorLog, log.Stdout)
+	os.Exit(m.Run())
+}
+
+func newTestCluster(t *testing.T, cfg *Config) *Cluster {
+	if testing.Short() {
+		t.Skip("vbft needs seconds per block")
+	}
+	cluster, err := NewCluster(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cluster.Stop)
+	if err := cluster.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return cluster
+}
+
+func TestLiveness(t *testing.T) {
+	cluster := newTestCluster(t, &Config{KeepAlive: true})
+	start := time.Now()
+	assert.Nil(t, cluster.WaitForHeight(5, time.Minute))
+	t.Logf("5 blocks in %s", time.Since(start))
+	assert.Nil(t, cluster.CheckSafety())
+}
+
+func TestLossyNetwork(t *testing.T) {
+	cluster := newTestCluster(t, &Config{KeepAlive: true, Seed: 7})
+	cluster.Network.SetLatency(5*time.Millisecond, 50*time.Millisecond)
+	cluster.Network.SetDropRate(0.05)
+	start := time.Now()
+	assert.Nil(t, cluster.WaitForHeight(5, 2*time.Minute))
+	t.Logf("5 blocks in %s", time.Since(start))
+	assert.Nil(t, cluster.CheckSafety())
+	_, dropped := cluster.Network.Stats()
+	assert.NotZero(t, dropped)
+}
+
+func TestPartition(t *testing.T) {
+	cluster := newTestCluster(t, &Config{KeepAlive: true})
+	assert.Nil(t, cluster.WaitForHeight(2, time.Minute))
+
+	// node 4 alone, the others keep a quorum and make progress
+	cluster.Network.Partition([]uint64{4})
+	height := cluster.Height(0)
+	start := time.Now()
+	assert.Nil(t, cluster.WaitForHeight(height+3, 2*time.Minute, 0, 1, 2))
+	t.Logf("3 blocks without node 4 in %s", time.Since(start))
+	assert.True(t, cluster.Height(3) < cluster.Height(0))
+	assert.Nil(t, cluster.CheckSafety())
+
+	cluster.Network.Heal()
+	start = time.Now()
+	assert.Nil(t, cluster.WaitForHeight(cluster.Height(0)+2, 2*time.Minute))
+	t.Logf("healed in %s", time.Since(start))
+	assert.Nil(t, cluster.CheckSafety())
+}
+
+func TestCrashRecovery(t *testing.T) {
+	cluster := newTestCluster(t, &Config{KeepAlive: true})
+	assert.Nil(t, cluster.WaitForHeight(2, time.Minute))
+
+	assert.Nil(t, cluster.StopNode(2))
+	start := time.Now()
+	assert.Nil(t, cluster.WaitForHeight(cluster.Height(0)+3, 2*time.Minute, 0, 1, 3))
+	t.Logf("3 blocks without node 3 in %s", time.Since(start))
+
+	assert.Nil(t, cluster.StartNode(2))
+	start = time.Now()
+	assert.Nil(t, cluster.WaitForHeight(cluster.Height(0)+2, 2*time.Minute))
+	t.Logf("recovered in %s", time.Since(start))
+	assert.Nil(t, cluster.CheckSafety())
+}
+
+func TestCommitDpos(t *testing.T) {
+	cfg := DefaultVBFTConfig()
+	cfg.MaxBlockChangeView = 4
+	cluster := newTestCluster(t, &Config{VBFT: cfg, KeepAlive: true})
+	assert.Nil(t, cluster.WaitForHeight(9, 3*time.Minute))
+	assert.Nil(t, cluster.CheckSafety())
+
+	for _, node := range cluster.Nodes {
+		view, err := engine.NewLedgerBackend(node.Ledger, nil).GovernanceView(&engine.State{})
+		assert.Nil(t, err)
+		assert.True(t, view.View >= 3, "node %d at view %d", node.Index, view.View)
+	}
+	// every view change carries the new chain config in its block
+	views := make([]uint32, 0)
+	for height := uint32(1); height <= 9; height++ {
+		header, err := cluster.Nodes[0].Ledger.GetHeaderByHeight(height)
+		assert.Nil(t, err)
+		info, err := vconfig.VbftBlock(header)
+		assert.Nil(t, err)
+		if info.NewChainConfig != nil {
+			views = append(views, info.NewChainConfig.View)
+		}
+	}
+	assert.True(t, len(views) >= 2)
+	for i := 1; i < len(views); i++ {
+		assert.Equal(t, views[i-1]+1, views[i])
+	}
+}
+
+func TestUpdateConfig(t *testing.T) {
+	cfg := DefaultVBFTConfig()
+	cfg.MaxBlockChangeView = 6
+	cluster := newTestCluster(t, &Config{VBFT: cfg, KeepAlive: true})
+	assert.Nil(t, cluster.WaitForHeight(1, time.Minute))
+
+	newConfig := &node_manager.Configuration{
+		BlockMsgDelay:        6000,
+		HashMsgDelay:         6000,
+		PeerHandshakeTimeout: 10,
+		MaxBlockChangeView:   10000,
+	}
+	args := common.NewZeroCopySink(nil)
+	(&node_manager.UpdateConfigParam{Configuration: newConfig}).Serialization(args)
+	param := &states.ContractInvokeParam{Address: utils.NodeManagerContractAddress,
+		Method: node_manager.UPDATE_CONFIG, Args: args.Bytes()}
+	code := common.NewZeroCopySink(nil)
+	param.Serialization(code)
+	tx := genesis.NewInvokeTransaction(code.Bytes(), 1)
+	assert.Nil(t, cluster.SignByConsensus(tx))
+	cluster.SubmitTransaction(tx)
+	assert.Nil(t, cluster.WaitForTransaction(tx.Hash(), 2*time.Minute))
+
+	for _, node := range cluster.Nodes {
+		notify, err := node.Ledger.GetEventNotifyByTx(tx.Hash())
+		assert.Nil(t, err)
+		assert.Equal(t, event.CONTRACT_STATE_SUCCESS, notify.State)
+		data, err := node.Ledger.GetStorageItem(utils.NodeManagerContractAddress, []byte(node_manager.VBFT_CONFIG))
+		assert.Nil(t, err)
+		stored := new(node_manager.Configuration)
+		assert.Nil(t, stored.Deserialization(common.NewZeroCopySource(data)))
+		assert.Equal(t, newConfig, stored)
+	}
+
+	// the next view change hands the new config to consensus
+	_, txHeight, err := cluster.Nodes[0].Ledger.GetTransactionWithHeight(tx.Hash())
+	assert.Nil(t, err)
+	var updated *vconfig.ChainConfig
+	for height := txHeight + 1; height <= txHeight+2*cfg.MaxBlockChangeView && updated == nil; height++ {
+		if !assert.Nil(t, cluster.WaitForHeight(height, time.Minute)) {
+			break
+		}
+		header, err := cluster.Nodes[0].Ledger.GetHeaderByHeight(height)
+		assert.Nil(t, err)
+		info, err := vconfig.VbftBlock(header)
+		assert.Nil(t, err)
+		if info.NewChainConfig != nil && info.NewChainConfig.MaxBlockChangeView == newConfig.MaxBlockChangeView {
+			updated = info.NewChainConfig
+		}
+	}
+	assert.NotNil(t, updated)
+	assert.Nil(t, cluster.CheckSafety())
+}
+
+// newHotStuffCluster runs a hotstuff cluster, with the ledgers checking the headers the
+// hotstuff way
+func newHotStuffCluster(t *testing.T, cfg *Config) *Cluster {
+	consensusType := config.DefConfig.Genesis.ConsensusType
+	config.DefConfig.Genesis.ConsensusType = config.CONSENSUS_TYPE_HOTSTUFF
+	t.Cleanup(func() {
+		config.DefConfig.Genesis.ConsensusType = consensusType
+	})
+	cfg.HotStuff = DefaultHotStuffConfig()
+	return newTestCluster(t, cfg)
+}
+
+func TestHotStuffLiveness(t *testing.T) {
+	cluster := newHotStuffCluster(t, &Config{KeepAlive: true})
+	start := time.Now()
+	assert.Nil(t, cluster.WaitForHeight(5, time.Minute))
+	t.Logf("5 blocks in %s", time.Since(start))
+	assert.Nil(t, cluster.CheckSafety())
+
+	for _, node := range cluster.Nodes {
+		data, err := node.Ledger.GetStorageItem(utils.NodeManagerContractAddress, []byte(node_manager.VBFT_CONFIG))
+		assert.Nil(t, err)
+		stored := new(node_manager.Configuration)
+		assert.Nil(t, stored.Deserialization(common.NewZeroCopySource(data)))
+		assert.Equal(t, config.CONSENSUS_TYPE_HOTSTUFF, stored.ConsensusType)
+	}
+	// every block carries the quorum certificate of the validators
+	quorum := len(cluster.Nodes) - (len(cluster.Nodes)-1)/3
+	for height := uint32(1); height <= 5; height++ {
+		header, err := cluster.Nodes[0].Ledger.GetHeaderByHeight(height)
+		assert.Nil(t, err)
+		assert.Equal(t, len(cluster.Nodes), len(header.Bookkeepers))
+		hash := header.Hash()
+		assert.Nil(t, signature.VerifyMultiSignature(hash[:], header.Bookkeepers, quorum, header.SigData))
+	}
+}
+
+func TestHotStuffCrash(t *testing.T) {
+	cluster := newHotStuffCluster(t, &Config{KeepAlive: true})
+	assert.Nil(t, cluster.WaitForHeight(2, time.Minute))
+
+	// the three remaining validators are a quorum, the rounds led by node 3 time out
+	assert.Nil(t, cluster.StopNode(2))
+	start := time.Now()
+	assert.Nil(t, cluster.WaitForHeight(cluster.Height(0)+3, 2*time.Minute, 0, 1, 3))
+	t.Logf("3 blocks without node 3 in %s", time.Since(start))
+	assert.Nil(t, cluster.CheckSafety())
+}
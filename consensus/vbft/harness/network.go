@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package harness
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/p2pserver"
+	netActor "github.com/polynetwork/poly/p2pserver/actor/server"
+)
+
+// Network connects the nodes in memory. Every node runs the real p2p server and actor
+// on top of an endpoint, which passes the wire bytes of the consensus messages to the
+// other endpoints with the configured latency, loss and partitions applied. Jittered
+// latency reorders messages.
+type Network struct {
+	lock       sync.Mutex
+	rand       *rand.Rand
+	endpoints  map[uint64]*endpoint  // node id => endpoint
+	servers    map[uint64]*actor.PID // node id => consensus pid
+	groups     map[uint64]int        // node id => partition group
+	minLatency time.Duration
+	maxLatency time.Duration
+	dropRate   float64
+
+	delivered uint64
+	dropped   uint64
+}
+
+// NewNetwork creates a network with instant and reliable delivery, seed makes the
+// random latency and loss reproducible.
+func NewNetwork(seed int64) *Network {
+	return &Network{
+		rand:      rand.New(rand.NewSource(seed)),
+		endpoints: make(map[uint64]*endpoint),
+		servers:   make(map[uint64]*actor.PID),
+		groups:    make(map[uint64]int),
+	}
+}
+
+// SetLatency delays every message by a random duration in [min, max]. A non zero
+// range lets later messages overtake earlier ones.
+func (self *Network) SetLatency(min, max time.Duration) {
+	if max < min {
+		max = min
+	}
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.minLatency, self.maxLatency = min, max
+}
+
+// SetDropRate makes the network lose messages with the given probability.
+func (self *Network) SetDropRate(rate float64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.dropRate = rate
+}
+
+// Partition splits the network, only nodes in the same group can talk to each
+// other. Nodes not listed form one more group together.
+func (self *Network) Partition(groups ...[]uint64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.groups = make(map[uint64]int)
+	for i, group := range groups {
+		for _, id := range group {
+			self.groups[id] = i + 1
+		}
+	}
+}
+
+// Heal removes all partitions.
+func (self *Network) Heal() {
+	self.Partition()
+}
+
+// Stats returns the number of delivered and dropped messages.
+func (self *Network) Stats() (delivered, dropped uint64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.delivered, self.dropped
+}
+
+// Join connects node id to the network, the returned p2p actor is to be used as p2p
+// pid of its consensus server.
+func (self *Network) Join(id uint64, name string, db *ledger.Ledger) (*actor.PID, error) {
+	ep := newEndpoint(self, id)
+	self.lock.Lock()
+	for _, other := range self.endpoints {
+		other.addPeer(id)
+		ep.addPeer(other.id)
+	}
+	self.endpoints[id] = ep
+	self.lock.Unlock()
+
+	server := p2pserver.NewServerWithNetwork(ep, db)
+	props := actor.FromProducer(func() actor.Actor {
+		return netActor.NewP2PActor(server)
+	})
+	pid, err := actor.SpawnNamed(props, name)
+	if err != nil {
+		self.Leave(id)
+		return nil, err
+	}
+	ep.Start()
+	return pid, nil
+}
+
+// Leave disconnects node id from the network.
+func (self *Network) Leave(id uint64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	ep := self.endpoints[id]
+	if ep == nil {
+		return
+	}
+	delete(self.endpoints, id)
+	delete(self.servers, id)
+	for _, other := range self.endpoints {
+		other.DelNbrNode(id)
+	}
+	ep.Halt()
+}
+
+// Attach routes the messages for node id to its consensus server.
+func (self *Network) Attach(id uint64, server *actor.PID) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	self.servers[id] = server
+}
+
+// Detach stops delivering messages to node id, as if it crashed.
+func (self *Network) Detach(id uint64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	delete(self.servers, id)
+}
+
+func (self *Network) server(id uint64) *actor.PID {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.servers[id]
+}
+
+func (self *Network) broadcast(from uint64, buf []byte) {
+	self.lock.Lock()
+	targets := make([]uint64, 0, len(self.endpoints))
+	for id := range self.endpoints {
+		if id != from {
+			targets = append(targets, id)
+		}
+	}
+	self.lock.Unlock()
+
+	for _, to := range targets {
+		self.send(from, to, buf)
+	}
+}
+
+func (self *Network) send(from, to uint64, buf []byte) {
+	self.lock.Lock()
+	ep := self.endpoints[to]
+	if ep == nil || self.servers[to] == nil || self.groups[from] != self.groups[to] ||
+		self.rand.Float64() < self.dropRate {
+		self.dropped++
+		self.lock.Unlock()
+		return
+	}
+	delay := self.minLatency
+	if self.maxLatency > self.minLatency {
+		delay += time.Duration(self.rand.Int63n(int64(self.maxLatency - self.minLatency)))
+	}
+	self.delivered++
+	self.lock.Unlock()
+
+	if delay == 0 {
+		ep.receive(from, buf)
+		return
+	}
+	time.AfterFunc(delay, func() {
+		ep.receive(from, buf)
+	})
+}
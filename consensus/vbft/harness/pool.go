@@ -0,0 +1,124 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package harness
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/genesis"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/errors"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	tc "github.com/polynetwork/poly/txnpool/common"
+)
+
+// keepAliveMethod is not registered by the node manager, a transaction calling it
+// fails without touching any state.
+const keepAliveMethod = "harnessKeepAlive"
+
+// txPool is the transaction pool actor of a node. It trusts every transaction and
+// hands out the submitted ones until they show up in the ledger of the node.
+type txPool struct {
+	lock      sync.Mutex
+	id        uint64
+	ledger    *ledger.Ledger
+	keepAlive bool
+	seq       uint32
+	pending   []*types.Transaction
+}
+
+func newTxPool(id uint64, ledger *ledger.Ledger, keepAlive bool) *txPool {
+	return &txPool{
+		id:        id,
+		ledger:    ledger,
+		keepAlive: keepAlive,
+	}
+}
+
+func (self *txPool) add(tx *types.Transaction) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	hash := tx.Hash()
+	for _, t := range self.pending {
+		if t.Hash() == hash {
+			return
+		}
+	}
+	self.pending = append(self.pending, tx)
+}
+
+func (self *txPool) txs() []*tc.TXEntry {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	pending := self.pending[:0]
+	entries := make([]*tc.TXEntry, 0, len(self.pending)+1)
+	for _, tx := range self.pending {
+		if exist, _ := self.ledger.IsContainTransaction(tx.Hash()); exist {
+			continue
+		}
+		pending = append(pending, tx)
+		entries = append(entries, &tc.TXEntry{Tx: tx})
+	}
+	self.pending = pending
+	if len(entries) == 0 && self.keepAlive {
+		self.seq++
+		entries = append(entries, &tc.TXEntry{Tx: newKeepAliveTransaction(self.id, self.seq)})
+	}
+	return entries
+}
+
+func (self *txPool) Receive(context actor.Context) {
+	switch msg := context.Message().(type) {
+	case *actor.Started, *actor.Stopping, *actor.Stopped, *actor.Restarting:
+	case *tc.TxReq:
+		self.add(msg.Tx)
+	case *tc.GetTxnPoolReq:
+		entries := self.txs()
+		if sender := context.Sender(); sender != nil {
+			sender.Request(&tc.GetTxnPoolRsp{TxnPool: entries}, context.Self())
+		}
+	case *tc.VerifyBlockReq:
+		results := make([]*tc.VerifyTxResult, 0, len(msg.Txs))
+		for _, tx := range msg.Txs {
+			results = append(results, &tc.VerifyTxResult{Height: msg.Height, Tx: tx, ErrCode: errors.ErrNoError})
+		}
+		if sender := context.Sender(); sender != nil {
+			sender.Request(&tc.VerifyBlockRsp{TxnPool: results}, context.Self())
+		}
+	default:
+		log.Debugf("harness: txpool ignores %v", reflect.TypeOf(msg))
+	}
+}
+
+// newKeepAliveTransaction returns a transaction unique to (id, seq), so that the leader
+// always has something to propose and does not wait for the empty block timeout.
+func newKeepAliveTransaction(id uint64, seq uint32) *types.Transaction {
+	args := common.NewZeroCopySink(nil)
+	args.WriteUint64(id)
+	param := &states.ContractInvokeParam{Address: utils.NodeManagerContractAddress,
+		Method: keepAliveMethod, Args: args.Bytes()}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return genesis.NewInvokeTransaction(sink.Bytes(), seq)
+}
@@ -0,0 +1,408 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	MAX_TRACE_ROUNDS = 256 // rounds kept for consensus trace
+	MAX_TRACE_EVENTS = 256 // events kept per round
+)
+
+// trace event names
+const (
+	TraceRoundStart  = "round_start"
+	TraceProposal    = "proposal"
+	TraceEndorse     = "endorse"
+	TraceCommit      = "commit"
+	TraceTimeout     = "timeout"
+	TraceFastForward = "fast_forward"
+	TraceSealed      = "sealed"
+)
+
+// upper bounds of round duration histogram, in seconds
+var roundDurationBuckets = []float64{0.5, 1, 2, 5, 10, 30, 60, 120}
+
+var timerEventNames = map[TimerEventType]string{
+	EventProposeBlockTimeout:      "propose_block",
+	EventProposalBackoff:          "proposal_backoff",
+	EventRandomBackoff:            "random_backoff",
+	EventPropose2ndBlockTimeout:   "propose_2nd_block",
+	EventEndorseBlockTimeout:      "endorse_block",
+	EventEndorseEmptyBlockTimeout: "endorse_empty_block",
+	EventCommitBlockTimeout:       "commit_block",
+}
+
+// RoundTraceEvent is a consensus event observed during a round
+type RoundTraceEvent struct {
+	Event     string
+	Peer      uint32
+	Proposer  uint32
+	ForEmpty  bool
+	Detail    string `json:",omitempty"`
+	Timestamp int64  // unix time in ms
+	ElapsedMs int64  // ms since round start
+}
+
+// RoundTrace records the consensus progress of one block height
+type RoundTrace struct {
+	Height      uint32
+	Leader      uint32
+	Proposer    uint32
+	Empty       bool
+	FastForward bool
+	StartTime   int64 // unix time in ms
+	SealTime    int64 // unix time in ms, 0 if not sealed
+	DurationMs  int64
+	Events      []*RoundTraceEvent
+}
+
+type peerLatency struct {
+	count uint64
+	sumMs uint64
+	maxMs uint64
+}
+
+func (l *peerLatency) observe(ms uint64) {
+	l.count++
+	l.sumMs += ms
+	if ms > l.maxMs {
+		l.maxMs = ms
+	}
+}
+
+type consensusMetrics struct {
+	sync.RWMutex
+	roundCount        uint64
+	roundDurationSum  float64
+	roundBuckets      []uint64
+	lastRoundDuration float64
+	sealedBlocks      uint64
+	emptyBlocks       uint64
+	fastForwardBlocks uint64
+	timeouts          map[TimerEventType]uint64
+	proposerTimeouts  map[uint32]uint64
+	endorseLatency    map[uint32]*peerLatency
+	commitLatency     map[uint32]*peerLatency
+	rounds            map[uint32]*RoundTrace
+	roundHeights      []uint32 // heights in rounds, ascending
+}
+
+func newConsensusMetrics() *consensusMetrics {
+	return &consensusMetrics{
+		roundBuckets:     make([]uint64, len(roundDurationBuckets)),
+		timeouts:         make(map[TimerEventType]uint64),
+		proposerTimeouts: make(map[uint32]uint64),
+		endorseLatency:   make(map[uint32]*peerLatency),
+		commitLatency:    make(map[uint32]*peerLatency),
+		rounds:           make(map[uint32]*RoundTrace),
+	}
+}
+
+func nowMs() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// getRoundLocked returns the trace of the round, creating it if not present.
+// Heights are not traced in order once a block sync jumps ahead, so the lowest
+// traced height is evicted when full, and rounds below it are ignored.
+func (m *consensusMetrics) getRoundLocked(blkNum uint32) *RoundTrace {
+	if r, present := m.rounds[blkNum]; present {
+		return r
+	}
+	if len(m.roundHeights) >= MAX_TRACE_ROUNDS {
+		if blkNum < m.roundHeights[0] {
+			return nil
+		}
+		delete(m.rounds, m.roundHeights[0])
+		m.roundHeights = m.roundHeights[1:]
+	}
+	r := &RoundTrace{
+		Height:    blkNum,
+		StartTime: nowMs(),
+		Events:    make([]*RoundTraceEvent, 0),
+	}
+	m.rounds[blkNum] = r
+	i := sort.Search(len(m.roundHeights), func(i int) bool { return m.roundHeights[i] > blkNum })
+	m.roundHeights = append(m.roundHeights, 0)
+	copy(m.roundHeights[i+1:], m.roundHeights[i:])
+	m.roundHeights[i] = blkNum
+	return r
+}
+
+func (m *consensusMetrics) addEventLocked(r *RoundTrace, evt *RoundTraceEvent) {
+	if len(r.Events) >= MAX_TRACE_EVENTS {
+		return
+	}
+	evt.Timestamp = nowMs()
+	evt.ElapsedMs = evt.Timestamp - r.StartTime
+	r.Events = append(r.Events, evt)
+}
+
+func (m *consensusMetrics) onRoundStart(blkNum uint32, leader uint32) {
+	m.Lock()
+	defer m.Unlock()
+
+	if r := m.getRoundLocked(blkNum); r != nil {
+		r.Leader = leader
+		m.addEventLocked(r, &RoundTraceEvent{Event: TraceRoundStart, Peer: leader})
+	}
+}
+
+func (m *consensusMetrics) onProposal(blkNum uint32, proposer uint32) {
+	m.Lock()
+	defer m.Unlock()
+
+	if r := m.getRoundLocked(blkNum); r != nil {
+		m.addEventLocked(r, &RoundTraceEvent{Event: TraceProposal, Peer: proposer, Proposer: proposer})
+	}
+}
+
+func (m *consensusMetrics) onEndorse(blkNum uint32, endorser, proposer uint32, forEmpty bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	r := m.getRoundLocked(blkNum)
+	if r == nil {
+		return
+	}
+	m.addEventLocked(r, &RoundTraceEvent{
+		Event:    TraceEndorse,
+		Peer:     endorser,
+		Proposer: proposer,
+		ForEmpty: forEmpty,
+	})
+	if _, present := m.endorseLatency[endorser]; !present {
+		m.endorseLatency[endorser] = &peerLatency{}
+	}
+	m.endorseLatency[endorser].observe(uint64(nowMs() - r.StartTime))
+}
+
+func (m *consensusMetrics) onCommit(blkNum uint32, committer, proposer uint32, forEmpty bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	r := m.getRoundLocked(blkNum)
+	if r == nil {
+		return
+	}
+	m.addEventLocked(r, &RoundTraceEvent{
+		Event:    TraceCommit,
+		Peer:     committer,
+		Proposer: proposer,
+		ForEmpty: forEmpty,
+	})
+	if _, present := m.commitLatency[committer]; !present {
+		m.commitLatency[committer] = &peerLatency{}
+	}
+	m.commitLatency[committer].observe(uint64(nowMs() - r.StartTime))
+}
+
+// onTimeout records a bft timer event of the round. If the leader of the round
+// did not deliver its proposal in time, the timeout is counted against it.
+func (m *consensusMetrics) onTimeout(blkNum uint32, evtType TimerEventType, leader uint32, leaderStalled bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.timeouts[evtType]++
+	if leaderStalled {
+		m.proposerTimeouts[leader]++
+	}
+	if r := m.getRoundLocked(blkNum); r != nil {
+		m.addEventLocked(r, &RoundTraceEvent{
+			Event:  TraceTimeout,
+			Peer:   leader,
+			Detail: timerEventNames[evtType],
+		})
+	}
+}
+
+func (m *consensusMetrics) onFastForward(blkNum uint32, proposer uint32) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.fastForwardBlocks++
+	if r := m.getRoundLocked(blkNum); r != nil {
+		r.FastForward = true
+		m.addEventLocked(r, &RoundTraceEvent{Event: TraceFastForward, Proposer: proposer})
+	}
+}
+
+func (m *consensusMetrics) onBlockSealed(blkNum uint32, proposer uint32, empty bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.sealedBlocks++
+	if empty {
+		m.emptyBlocks++
+	}
+	r := m.getRoundLocked(blkNum)
+	if r == nil || r.SealTime != 0 {
+		return
+	}
+	m.addEventLocked(r, &RoundTraceEvent{Event: TraceSealed, Proposer: proposer, ForEmpty: empty})
+	r.Proposer = proposer
+	r.Empty = empty
+	r.SealTime = nowMs()
+	r.DurationMs = r.SealTime - r.StartTime
+
+	// fast-forwarded rounds are not timed by this node
+	if r.FastForward {
+		return
+	}
+	seconds := float64(r.DurationMs) / 1000
+	m.roundCount++
+	m.roundDurationSum += seconds
+	m.lastRoundDuration = seconds
+	for i, bound := range roundDurationBuckets {
+		if seconds <= bound {
+			m.roundBuckets[i]++
+		}
+	}
+}
+
+func (m *consensusMetrics) trace(blkNum uint32) *RoundTrace {
+	m.RLock()
+	defer m.RUnlock()
+
+	r, present := m.rounds[blkNum]
+	if !present {
+		return nil
+	}
+	t := *r
+	t.Events = make([]*RoundTraceEvent, 0, len(r.Events))
+	for _, evt := range r.Events {
+		e := *evt
+		t.Events = append(t.Events, &e)
+	}
+	return &t
+}
+
+func sortedPeers(m map[uint32]*peerLatency) []uint32 {
+	peers := make([]uint32, 0, len(m))
+	for peer := range m {
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i] < peers[j] })
+	return peers
+}
+
+func writeLatency(w io.Writer, name, help string, m map[uint32]*peerLatency) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s summary\n", name, help, name)
+	for _, peer := range sortedPeers(m) {
+		l := m[peer]
+		fmt.Fprintf(w, "%s_sum{peer=\"%d\"} %g\n", name, peer, float64(l.sumMs)/1000)
+		fmt.Fprintf(w, "%s_count{peer=\"%d\"} %d\n", name, peer, l.count)
+	}
+	fmt.Fprintf(w, "# HELP %s_max %s, maximum\n# TYPE %s_max gauge\n", name, help, name)
+	for _, peer := range sortedPeers(m) {
+		fmt.Fprintf(w, "%s_max{peer=\"%d\"} %g\n", name, peer, float64(m[peer].maxMs)/1000)
+	}
+}
+
+// writePrometheus writes all metrics in prometheus text exposition format
+func (m *consensusMetrics) writePrometheus(w io.Writer) {
+	m.RLock()
+	defer m.RUnlock()
+
+	fmt.Fprintf(w, "# HELP vbft_round_duration_seconds Duration from round start to block sealed\n")
+	fmt.Fprintf(w, "# TYPE vbft_round_duration_seconds histogram\n")
+	for i, bound := range roundDurationBuckets {
+		fmt.Fprintf(w, "vbft_round_duration_seconds_bucket{le=\"%g\"} %d\n", bound, m.roundBuckets[i])
+	}
+	fmt.Fprintf(w, "vbft_round_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.roundCount)
+	fmt.Fprintf(w, "vbft_round_duration_seconds_sum %g\n", m.roundDurationSum)
+	fmt.Fprintf(w, "vbft_round_duration_seconds_count %d\n", m.roundCount)
+
+	fmt.Fprintf(w, "# HELP vbft_last_round_duration_seconds Duration of the latest timed round\n")
+	fmt.Fprintf(w, "# TYPE vbft_last_round_duration_seconds gauge\n")
+	fmt.Fprintf(w, "vbft_last_round_duration_seconds %g\n", m.lastRoundDuration)
+
+	fmt.Fprintf(w, "# HELP vbft_sealed_blocks_total Blocks sealed by consensus\n")
+	fmt.Fprintf(w, "# TYPE vbft_sealed_blocks_total counter\n")
+	fmt.Fprintf(w, "vbft_sealed_blocks_total %d\n", m.sealedBlocks)
+
+	fmt.Fprintf(w, "# HELP vbft_empty_blocks_total Blocks sealed from empty proposals\n")
+	fmt.Fprintf(w, "# TYPE vbft_empty_blocks_total counter\n")
+	fmt.Fprintf(w, "vbft_empty_blocks_total %d\n", m.emptyBlocks)
+
+	fmt.Fprintf(w, "# HELP vbft_fast_forward_blocks_total Blocks sealed by fast forwarding\n")
+	fmt.Fprintf(w, "# TYPE vbft_fast_forward_blocks_total counter\n")
+	fmt.Fprintf(w, "vbft_fast_forward_blocks_total %d\n", m.fastForwardBlocks)
+
+	fmt.Fprintf(w, "# HELP vbft_timeouts_total Bft timer events fired, by timer\n")
+	fmt.Fprintf(w, "# TYPE vbft_timeouts_total counter\n")
+	evtTypes := make([]int, 0, len(m.timeouts))
+	for evtType := range m.timeouts {
+		evtTypes = append(evtTypes, int(evtType))
+	}
+	sort.Ints(evtTypes)
+	for _, evtType := range evtTypes {
+		fmt.Fprintf(w, "vbft_timeouts_total{timer=\"%s\"} %d\n",
+			timerEventNames[TimerEventType(evtType)], m.timeouts[TimerEventType(evtType)])
+	}
+
+	fmt.Fprintf(w, "# HELP vbft_proposer_timeouts_total Proposal timeouts without proposal from round leader\n")
+	fmt.Fprintf(w, "# TYPE vbft_proposer_timeouts_total counter\n")
+	proposers := make([]uint32, 0, len(m.proposerTimeouts))
+	for peer := range m.proposerTimeouts {
+		proposers = append(proposers, peer)
+	}
+	sort.Slice(proposers, func(i, j int) bool { return proposers[i] < proposers[j] })
+	for _, peer := range proposers {
+		fmt.Fprintf(w, "vbft_proposer_timeouts_total{peer=\"%d\"} %d\n", peer, m.proposerTimeouts[peer])
+	}
+
+	writeLatency(w, "vbft_endorse_latency_seconds", "Delay from round start to endorsement received", m.endorseLatency)
+	writeLatency(w, "vbft_commit_latency_seconds", "Delay from round start to commitment received", m.commitLatency)
+}
+
+// GetMetricsReq asks the vbft server for its metrics
+type GetMetricsReq struct{}
+
+// GetMetricsRsp returns the metrics in prometheus text format
+type GetMetricsRsp struct {
+	Metrics []byte
+}
+
+// GetTraceReq asks the vbft server for the consensus trace of the height
+type GetTraceReq struct {
+	Height uint32
+}
+
+// GetTraceRsp returns the trace, nil if the height is not traced
+type GetTraceRsp struct {
+	Trace *RoundTrace
+}
+
+// WriteMetrics writes the metrics of the server in prometheus text format
+func (self *Server) WriteMetrics(w io.Writer) {
+	self.metrics.writePrometheus(w)
+}
+
+// GetTrace returns the consensus trace of block height, nil if the height
+// is not traced by this server
+func (self *Server) GetTrace(blkNum uint32) *RoundTrace {
+	return self.metrics.trace(blkNum)
+}
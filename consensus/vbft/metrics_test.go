@@ -0,0 +1,202 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/stretchr/testify/assert"
+)
+
+// sealAfter traces a round of the height sealed the duration after its start
+func sealAfter(m *consensusMetrics, blkNum uint32, duration time.Duration) {
+	m.onRoundStart(blkNum, 0)
+	m.rounds[blkNum].StartTime = nowMs() - int64(duration/time.Millisecond)
+	m.onBlockSealed(blkNum, 0, false)
+}
+
+func TestRoundDurationBuckets(t *testing.T) {
+	m := newConsensusMetrics()
+	sealAfter(m, 1, 300*time.Millisecond)
+	sealAfter(m, 2, 1500*time.Millisecond)
+	sealAfter(m, 3, 7*time.Second)
+	sealAfter(m, 4, 200*time.Second)
+	//fast-forwarded rounds are not timed
+	m.onRoundStart(5, 0)
+	m.onFastForward(5, 1)
+	m.onBlockSealed(5, 1, true)
+
+	//buckets of 0.5, 1, 2, 5, 10, 30, 60, 120 seconds
+	assert.Equal(t, []uint64{1, 1, 2, 2, 3, 3, 3, 3}, m.roundBuckets)
+	assert.Equal(t, uint64(4), m.roundCount)
+	assert.InDelta(t, 208.8, m.roundDurationSum, 0.1)
+	assert.InDelta(t, 200, m.lastRoundDuration, 0.1)
+	assert.Equal(t, uint64(5), m.sealedBlocks)
+	assert.Equal(t, uint64(1), m.emptyBlocks)
+	assert.Equal(t, uint64(1), m.fastForwardBlocks)
+
+	trace := m.trace(5)
+	assert.True(t, trace.FastForward)
+	assert.True(t, trace.Empty)
+	assert.Equal(t, uint32(1), trace.Proposer)
+	assert.Nil(t, m.trace(6))
+}
+
+func TestTraceHeightsOutOfOrder(t *testing.T) {
+	m := newConsensusMetrics()
+	for h := uint32(1); h <= MAX_TRACE_ROUNDS; h++ {
+		m.onRoundStart(h, 0)
+	}
+	//a block sync jumps ahead, then a late message of a lower round arrives
+	m.onRoundStart(1000, 0)
+	m.onRoundStart(300, 0)
+	assert.Nil(t, m.trace(1))
+	assert.Nil(t, m.trace(2))
+	assert.NotNil(t, m.trace(300))
+	assert.NotNil(t, m.trace(1000))
+
+	//the lowest heights are evicted, not the earliest traced ones
+	for h := uint32(1001); h <= 1001+MAX_TRACE_ROUNDS-2; h++ {
+		m.onRoundStart(h, 0)
+	}
+	assert.Nil(t, m.trace(MAX_TRACE_ROUNDS))
+	assert.Nil(t, m.trace(300))
+	assert.NotNil(t, m.trace(1000))
+	m.onRoundStart(500, 0)
+	assert.Nil(t, m.trace(500))
+	assert.Equal(t, MAX_TRACE_ROUNDS, len(m.rounds))
+	assert.Equal(t, len(m.rounds), len(m.roundHeights))
+	for i := 1; i < len(m.roundHeights); i++ {
+		assert.True(t, m.roundHeights[i-1] < m.roundHeights[i])
+	}
+}
+
+var (
+	helpLine   = regexp.MustCompile(`^# HELP ([a-z_]+) .+$`)
+	typeLine   = regexp.MustCompile(`^# TYPE ([a-z_]+) (counter|gauge|histogram|summary)$`)
+	sampleLine = regexp.MustCompile(`^([a-z_]+)(\{[a-z]+="[^"]*"\})? (\S+)$`)
+)
+
+// parseExposition checks the lines of the text exposition format and
+// returns the samples by name and labels
+func parseExposition(t *testing.T, text []byte) map[string]float64 {
+	types := make(map[string]string)
+	samples := make(map[string]float64)
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "# HELP") {
+			assert.Regexp(t, helpLine, line)
+			continue
+		}
+		if match := typeLine.FindStringSubmatch(line); match != nil {
+			_, present := types[match[1]]
+			assert.False(t, present, "metric %s typed twice", match[1])
+			types[match[1]] = match[2]
+			continue
+		}
+		match := sampleLine.FindStringSubmatch(line)
+		if !assert.NotNil(t, match, "malformed line %q", line) {
+			continue
+		}
+		name := match[1]
+		family := name
+		for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+			base := strings.TrimSuffix(name, suffix)
+			if typ := types[base]; base != name && (typ == "histogram" || typ == "summary") {
+				family = base
+			}
+		}
+		assert.Contains(t, types, family, "metric %s not typed before its samples", name)
+		value, err := strconv.ParseFloat(match[3], 64)
+		assert.Nil(t, err, line)
+		samples[name+match[2]] = value
+	}
+	return samples
+}
+
+func TestWritePrometheus(t *testing.T) {
+	m := newConsensusMetrics()
+	sealAfter(m, 1, 300*time.Millisecond)
+	sealAfter(m, 2, 7*time.Second)
+	m.onRoundStart(3, 1)
+	m.onEndorse(3, 2, 1, false)
+	m.onCommit(3, 2, 1, false)
+	m.onTimeout(3, EventProposeBlockTimeout, 1, true)
+	m.onTimeout(3, EventProposeBlockTimeout, 1, false)
+	m.onTimeout(3, EventCommitBlockTimeout, 1, false)
+
+	buf := new(bytes.Buffer)
+	m.writePrometheus(buf)
+	samples := parseExposition(t, buf.Bytes())
+
+	//cumulative buckets, +Inf is the count
+	last := float64(0)
+	for _, bound := range []string{"0.5", "1", "2", "5", "10", "30", "60", "120", "+Inf"} {
+		value, present := samples[`vbft_round_duration_seconds_bucket{le="`+bound+`"}`]
+		assert.True(t, present, bound)
+		assert.True(t, value >= last, bound)
+		last = value
+	}
+	assert.Equal(t, float64(1), samples[`vbft_round_duration_seconds_bucket{le="0.5"}`])
+	assert.Equal(t, float64(2), samples[`vbft_round_duration_seconds_bucket{le="10"}`])
+	assert.Equal(t, samples["vbft_round_duration_seconds_count"], last)
+	assert.InDelta(t, 7.3, samples["vbft_round_duration_seconds_sum"], 0.1)
+	assert.Equal(t, float64(2), samples["vbft_sealed_blocks_total"])
+
+	assert.Equal(t, float64(2), samples[`vbft_timeouts_total{timer="propose_block"}`])
+	assert.Equal(t, float64(1), samples[`vbft_timeouts_total{timer="commit_block"}`])
+	assert.Equal(t, float64(1), samples[`vbft_proposer_timeouts_total{peer="1"}`])
+	assert.Equal(t, float64(1), samples[`vbft_endorse_latency_seconds_count{peer="2"}`])
+	assert.Equal(t, float64(1), samples[`vbft_commit_latency_seconds_count{peer="2"}`])
+	_, present := samples[`vbft_commit_latency_seconds_max{peer="2"}`]
+	assert.True(t, present)
+}
+
+func TestServerMetrics(t *testing.T) {
+	server1 := &Server{metrics: newConsensusMetrics()}
+	server2 := &Server{metrics: newConsensusMetrics()}
+	sealAfter(server1.metrics, 1, time.Second)
+
+	pid := actor.Spawn(actor.FromProducer(func() actor.Actor { return server1 }))
+	defer pid.Stop()
+	res, err := pid.RequestFuture(&GetMetricsReq{}, time.Second).Result()
+	assert.Nil(t, err)
+	samples := parseExposition(t, res.(*GetMetricsRsp).Metrics)
+	assert.Equal(t, float64(1), samples["vbft_sealed_blocks_total"])
+
+	res, err = pid.RequestFuture(&GetTraceReq{Height: 1}, time.Second).Result()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), res.(*GetTraceRsp).Trace.Height)
+	res, err = pid.RequestFuture(&GetTraceReq{Height: 2}, time.Second).Result()
+	assert.Nil(t, err)
+	assert.Nil(t, res.(*GetTraceRsp).Trace)
+
+	//the servers in one process keep their own metrics
+	buf := new(bytes.Buffer)
+	server2.WriteMetrics(buf)
+	assert.Equal(t, float64(0), parseExposition(t, buf.Bytes())["vbft_sealed_blocks_total"])
+	assert.Nil(t, server2.GetTrace(1))
+}
@@ -29,9 +29,9 @@ import (
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
-	"github.com/polynetwork/poly/core/ledger"
 	"github.com/polynetwork/poly/core/signature"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
 )
 
 type ConsensusMsgPayload struct {
@@ -192,7 +192,10 @@ func (self *Server) constructBlock(blkNum uint32, prevBlkHash common.Uint256, tx
 	}
 	txRoot := common.ComputeMerkleRoot(txHash)
 
-	blockRoot := ledger.DefLedger.GetBlockRootWithPreBlockHashes(blkNum-1, []common.Uint256{lastBlock.Block.Header.PrevBlockHash, prevBlkHash})
+	blockRoot := self.backend.BlockRoot(blkNum-1, []common.Uint256{lastBlock.Block.Header.PrevBlockHash, prevBlkHash})
+	if blockRoot == common.UINT256_EMPTY {
+		return nil, fmt.Errorf("constructBlock failed to get block root, blknum:%d, ledger:%d", blkNum, self.backend.CommittedHeight())
+	}
 	crossStateRoot, err := self.blockPool.getCrossStatesRoot(blkNum - 1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to GetCrossStatesRoot: %s,blkNum:%d", err, (blkNum - 1))
@@ -227,7 +230,7 @@ func (self *Server) constructBlock(blkNum uint32, prevBlkHash common.Uint256, tx
 	return blk, nil
 }
 
-func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types.Transaction, chainconfig *vconfig.ChainConfig) (*blockProposalMsg, error) {
+func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types.Transaction, chainconfig *vconfig.ChainConfig, forEmpty bool) (*blockProposalMsg, error) {
 
 	prevBlk, prevBlkHash := self.blockPool.getSealedBlock(blkNum - 1)
 	if prevBlk == nil {
@@ -287,6 +290,15 @@ func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types
 	if err != nil {
 		return nil, fmt.Errorf("failed to constuct blk: %s", err)
 	}
+	blkHash := blk.Hash()
+	if err := self.voteStore.addVote(blkNum, node_manager.ProposalEquivocation, forEmpty, blkHash); err != nil {
+		return nil, fmt.Errorf("proposer refused to sign vote: %s", err)
+	}
+	digest := node_manager.EquivocationDigest(node_manager.ProposalEquivocation, forEmpty, blkNum, blkHash)
+	proposerVoteSig, err := signature.Sign(self.account, digest)
+	if err != nil {
+		return nil, fmt.Errorf("proposer failed to sign vote. hash:%x, err: %s", blkHash, err)
+	}
 
 	msg := &blockProposalMsg{
 		Block: &Block{
@@ -294,6 +306,8 @@ func (self *Server) constructProposalMsg(blkNum uint32, sysTxs, userTxs []*types
 			EmptyBlock: emptyBlk,
 			Info:       vbftBlkInfo,
 		},
+		ForEmpty:        forEmpty,
+		ProposerVoteSig: proposerVoteSig,
 	}
 
 	return msg, nil
@@ -319,10 +333,18 @@ func (self *Server) constructEndorseMsg(proposal *blockProposalMsg, forEmpty boo
 		proposerSig = proposal.Block.EmptyBlock.Header.SigData[0]
 		blkHash = proposal.Block.EmptyBlock.Hash()
 	}
+	if err := self.voteStore.addVote(proposal.GetBlockNum(), node_manager.EndorseEquivocation, forEmpty, blkHash); err != nil {
+		return nil, fmt.Errorf("endorser refused to sign vote: %s", err)
+	}
 	endorserSig, err = signature.Sign(self.account, blkHash[:])
 	if err != nil {
 		return nil, fmt.Errorf("endorser failed to sign block. hash:%x, err: %s", blkHash, err)
 	}
+	digest := node_manager.EquivocationDigest(node_manager.EndorseEquivocation, forEmpty, proposal.GetBlockNum(), blkHash)
+	endorserVoteSig, err := signature.Sign(self.account, digest)
+	if err != nil {
+		return nil, fmt.Errorf("endorser failed to sign vote. hash:%x, err: %s", blkHash, err)
+	}
 
 	msg := &blockEndorseMsg{
 		Endorser:          self.Index,
@@ -332,6 +354,7 @@ func (self *Server) constructEndorseMsg(proposal *blockProposalMsg, forEmpty boo
 		EndorseForEmpty:   forEmpty,
 		ProposerSig:       proposerSig,
 		EndorserSig:       endorserSig,
+		EndorserVoteSig:   endorserVoteSig,
 	}
 
 	return msg, nil
@@ -357,10 +380,18 @@ func (self *Server) constructCommitMsg(proposal *blockProposalMsg, endorses []*b
 		proposerSig = proposal.Block.EmptyBlock.Header.SigData[0]
 		blkHash = proposal.Block.EmptyBlock.Hash()
 	}
+	if err := self.voteStore.addVote(proposal.GetBlockNum(), node_manager.CommitEquivocation, forEmpty, blkHash); err != nil {
+		return nil, fmt.Errorf("committer refused to sign vote: %s", err)
+	}
 	committerSig, err = signature.Sign(self.account, blkHash[:])
 	if err != nil {
 		return nil, fmt.Errorf("endorser failed to sign block. hash:%x, caused by: %s", blkHash, err)
 	}
+	digest := node_manager.EquivocationDigest(node_manager.CommitEquivocation, forEmpty, proposal.GetBlockNum(), blkHash)
+	committerVoteSig, err := signature.Sign(self.account, digest)
+	if err != nil {
+		return nil, fmt.Errorf("committer failed to sign vote. hash:%x, caused by: %s", blkHash, err)
+	}
 
 	endorsersSig := make(map[uint32][]byte)
 	for _, e := range endorses {
@@ -368,14 +399,15 @@ func (self *Server) constructCommitMsg(proposal *blockProposalMsg, endorses []*b
 	}
 
 	msg := &blockCommitMsg{
-		Committer:       self.Index,
-		BlockProposer:   proposal.Block.getProposer(),
-		BlockNum:        proposal.Block.getBlockNum(),
-		CommitBlockHash: blkHash,
-		CommitForEmpty:  forEmpty,
-		ProposerSig:     proposerSig,
-		EndorsersSig:    endorsersSig,
-		CommitterSig:    committerSig,
+		Committer:        self.Index,
+		BlockProposer:    proposal.Block.getProposer(),
+		BlockNum:         proposal.Block.getBlockNum(),
+		CommitBlockHash:  blkHash,
+		CommitForEmpty:   forEmpty,
+		ProposerSig:      proposerSig,
+		EndorsersSig:     endorsersSig,
+		CommitterSig:     committerSig,
+		CommitterVoteSig: committerVoteSig,
 	}
 
 	return msg, nil
@@ -20,9 +20,15 @@ package vbft
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/consensus/engine"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/stretchr/testify/assert"
 )
 
 func constructMsg() *blockProposalMsg {
@@ -58,3 +64,59 @@ func TestDeserializeVbftMsg(t *testing.T) {
 	}
 	t.Logf("TestDeserializeVbftMsg succ")
 }
+
+//rootlessBackend is the backend of a ledger behind consensus, which can't compute block roots
+type rootlessBackend struct {
+	engine.Backend
+}
+
+func (self rootlessBackend) BlockRoot(startHeight uint32, prevHashes []common.Uint256) common.Uint256 {
+	return common.UINT256_EMPTY
+}
+
+func (self rootlessBackend) CommittedHeight() uint32 {
+	return 0
+}
+
+func TestConstructBlockWithoutBlockRoot(t *testing.T) {
+	blk, err := constructBlock()
+	if err != nil {
+		t.Errorf("constructBlock failed: %v", err)
+		return
+	}
+	server := &Server{
+		blockPool: &BlockPool{candidateBlocks: map[uint32]*CandidateInfo{2: {SealedBlock: blk}}},
+		backend:   rootlessBackend{},
+	}
+	_, err = server.constructBlock(3, blk.Block.Hash(), nil, nil, 0, common.ADDRESS_EMPTY)
+	if err == nil || !strings.Contains(err.Error(), "block root") {
+		t.Errorf("TestConstructBlockWithoutBlockRoot failed: %v", err)
+	}
+}
+
+func TestDeserializeProposerVote(t *testing.T) {
+	acc := account.NewAccount("SHA256withECDSA")
+	msg := constructProposalMsgTest(acc)
+	digest := node_manager.EquivocationDigest(node_manager.ProposalEquivocation, true, msg.GetBlockNum(), msg.Block.Block.Hash())
+	sig, err := signature.Sign(acc, digest)
+	assert.Nil(t, err)
+	msg.ForEmpty = true
+	msg.ProposerVoteSig = sig
+
+	data, err := SerializeVbftMsg(msg)
+	assert.Nil(t, err)
+	m, err := DeserializeVbftMsg(data)
+	assert.Nil(t, err)
+	proposal := m.(*blockProposalMsg)
+	assert.True(t, proposal.ForEmpty)
+	assert.Equal(t, sig, proposal.ProposerVoteSig)
+	assert.Nil(t, proposal.Verify(acc.PublicKey))
+
+	//peers only reading the blocks skip the vote
+	payload, err := msg.Serialize()
+	assert.Nil(t, err)
+	blk := &Block{}
+	assert.Nil(t, blk.Deserialize(payload))
+	assert.Equal(t, msg.Block.Block.Hash(), blk.Block.Hash())
+	assert.NotNil(t, blk.EmptyBlock)
+}
@@ -23,12 +23,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-crypto/signature"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/serialization"
 	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
 )
 
 type MsgType uint8
@@ -56,7 +58,9 @@ type ConsensusMsg interface {
 }
 
 type blockProposalMsg struct {
-	Block *Block `json:"block"`
+	Block           *Block `json:"block"`
+	ForEmpty        bool   `json:"for_empty"`         // proposed for an empty round, without user txs
+	ProposerVoteSig []byte `json:"proposer_vote_sig"` // signature of the role-bound vote digest of Block
 }
 
 func (msg *blockProposalMsg) Type() MsgType {
@@ -95,6 +99,13 @@ func (msg *blockProposalMsg) Verify(pub keypair.PublicKey) error {
 		}
 	}
 
+	if len(msg.ProposerVoteSig) > 0 {
+		digest := node_manager.EquivocationDigest(node_manager.ProposalEquivocation, msg.ForEmpty, msg.GetBlockNum(), hash)
+		if err := verifyVoteSig(pub, digest, msg.ProposerVoteSig); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -102,22 +113,47 @@ func (msg *blockProposalMsg) GetBlockNum() uint32 {
 	return msg.Block.Block.Header.Height
 }
 
+// Serialize appends the proposer vote after the blocks, which is skipped by
+// the peers only reading the blocks
 func (msg *blockProposalMsg) Serialize() ([]byte, error) {
-	return msg.Block.Serialize()
+	payload, err := msg.Block.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	if len(msg.ProposerVoteSig) == 0 || msg.Block.EmptyBlock == nil {
+		return payload, nil
+	}
+	sink := common.NewZeroCopySink(payload)
+	sink.WriteBool(msg.ForEmpty)
+	sink.WriteVarBytes(msg.ProposerVoteSig)
+	return sink.Bytes(), nil
 }
 
 func (msg *blockProposalMsg) UnmarshalJSON(data []byte) error {
+	source := common.NewZeroCopySource(data)
 	blk := &Block{}
-	if err := blk.Deserialize(data); err != nil {
+	if err := blk.deserialization(source); err != nil {
 		return err
 	}
+	if source.Len() > 0 {
+		forEmpty, eof := source.NextBool()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		sig, eof := source.NextVarBytes()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		msg.ForEmpty = forEmpty
+		msg.ProposerVoteSig = sig
+	}
 
 	msg.Block = blk
 	return nil
 }
 
 func (msg *blockProposalMsg) MarshalJSON() ([]byte, error) {
-	return msg.Block.Serialize()
+	return msg.Serialize()
 }
 
 type FaultyReport struct {
@@ -134,6 +170,7 @@ type blockEndorseMsg struct {
 	FaultyProposals   []*FaultyReport `json:"faulty_proposals"`
 	ProposerSig       []byte          `json:"proposer_sig"`
 	EndorserSig       []byte          `json:"endorser_sig"`
+	EndorserVoteSig   []byte          `json:"endorser_vote_sig,omitempty"` // signature of the role-bound vote digest
 }
 
 func (msg *blockEndorseMsg) Type() MsgType {
@@ -149,6 +186,12 @@ func (msg *blockEndorseMsg) Verify(pub keypair.PublicKey) error {
 	if !signature.Verify(pub, hash[:], sig) {
 		return fmt.Errorf("failed to verify block sig")
 	}
+	if len(msg.EndorserVoteSig) > 0 {
+		digest := node_manager.EquivocationDigest(node_manager.EndorseEquivocation, msg.EndorseForEmpty, msg.BlockNum, hash)
+		if err := verifyVoteSig(pub, digest, msg.EndorserVoteSig); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -161,15 +204,16 @@ func (msg *blockEndorseMsg) Serialize() ([]byte, error) {
 }
 
 type blockCommitMsg struct {
-	Committer       uint32            `json:"committer"`
-	BlockProposer   uint32            `json:"block_proposer"`
-	BlockNum        uint32            `json:"block_num"`
-	CommitBlockHash common.Uint256    `json:"commit_block_hash"`
-	CommitForEmpty  bool              `json:"commit_for_empty"`
-	FaultyVerifies  []*FaultyReport   `json:"faulty_verifies"`
-	ProposerSig     []byte            `json:"proposer_sig"`
-	EndorsersSig    map[uint32][]byte `json:"endorsers_sig"`
-	CommitterSig    []byte            `json:"committer_sig"`
+	Committer        uint32            `json:"committer"`
+	BlockProposer    uint32            `json:"block_proposer"`
+	BlockNum         uint32            `json:"block_num"`
+	CommitBlockHash  common.Uint256    `json:"commit_block_hash"`
+	CommitForEmpty   bool              `json:"commit_for_empty"`
+	FaultyVerifies   []*FaultyReport   `json:"faulty_verifies"`
+	ProposerSig      []byte            `json:"proposer_sig"`
+	EndorsersSig     map[uint32][]byte `json:"endorsers_sig"`
+	CommitterSig     []byte            `json:"committer_sig"`
+	CommitterVoteSig []byte            `json:"committer_vote_sig,omitempty"` // signature of the role-bound vote digest
 }
 
 func (msg *blockCommitMsg) Type() MsgType {
@@ -185,6 +229,12 @@ func (msg *blockCommitMsg) Verify(pub keypair.PublicKey) error {
 	if !signature.Verify(pub, hash[:], sig) {
 		return fmt.Errorf("failed to verify block sig")
 	}
+	if len(msg.CommitterVoteSig) > 0 {
+		digest := node_manager.EquivocationDigest(node_manager.CommitEquivocation, msg.CommitForEmpty, msg.BlockNum, hash)
+		if err := verifyVoteSig(pub, digest, msg.CommitterVoteSig); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -197,6 +247,17 @@ func (msg *blockCommitMsg) Serialize() ([]byte, error) {
 	return json.Marshal(msg)
 }
 
+func verifyVoteSig(pub keypair.PublicKey, digest []byte, voteSig []byte) error {
+	sig, err := signature.Deserialize(voteSig)
+	if err != nil {
+		return fmt.Errorf("deserialize vote sig: %s", err)
+	}
+	if !signature.Verify(pub, digest, sig) {
+		return fmt.Errorf("failed to verify vote sig")
+	}
+	return nil
+}
+
 type peerHandshakeMsg struct {
 	CommittedBlockNumber uint32               `json:"committed_block_number"`
 	CommittedBlockHash   common.Uint256       `json:"committed_block_hash"`
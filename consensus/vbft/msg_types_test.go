@@ -62,8 +62,7 @@ func constructProposalMsgTest(acc *account.Account) *blockProposalMsg {
 			Header:       blkHeader,
 			Transactions: nil,
 		},
-		Info:                vbftBlkInfo,
-		PrevBlockMerkleRoot: common.Uint256{},
+		Info: vbftBlkInfo,
 	}
 	msg := &blockProposalMsg{
 		Block: blk,
@@ -384,8 +383,7 @@ func constructBlock() (*Block, error) {
 			Header:       blkHeader,
 			Transactions: nil,
 		},
-		Info:                vbftBlkInfo,
-		PrevBlockMerkleRoot: common.Uint256{},
+		Info: vbftBlkInfo,
 	}
 	blk.Block.Hash()
 	blk.Block.Transactions = txs
@@ -24,7 +24,6 @@ import (
 	"time"
 
 	"github.com/polynetwork/poly/common/log"
-	"github.com/polynetwork/poly/core/ledger"
 )
 
 type SyncCheckReq struct {
@@ -180,7 +179,7 @@ func (self *Syncer) run() {
 			for self.nextReqBlkNum <= self.targetBlkNum {
 				// FIXME: compete with ledger syncing
 				var blk *Block
-				if self.nextReqBlkNum <= ledger.DefLedger.GetCurrentBlockHeight() {
+				if self.nextReqBlkNum <= self.server.backend.CommittedHeight() {
 					blk, _ = self.server.chainStore.getBlock(self.nextReqBlkNum)
 				}
 				if blk == nil {
@@ -89,7 +89,25 @@ func (self *Server) isProposer(blockNum uint32, peerIdx uint32) bool {
 	return false
 }
 
+// getRoundLeader returns the first alive proposer of current round
+func (self *Server) getRoundLeader(blockNum uint32) uint32 {
+	self.metaLock.RLock()
+	defer self.metaLock.RUnlock()
+
+	for _, id := range self.currentParticipantConfig.Proposers {
+		if self.isPeerAlive(id, blockNum) {
+			return id
+		}
+	}
+	return math.MaxUint32
+}
+
 func (self *Server) is2ndProposer(blockNum uint32, peerIdx uint32) bool {
+	// like the leader, don't propose before synced, or the leader has no chance to
+	// propose first once it is synced
+	if peerIdx == self.Index && !isActive(self.getState()) {
+		return false
+	}
 	rank := self.getProposerRank(blockNum, peerIdx)
 	return rank > 0 && rank <= int(self.config.C)
 }
@@ -96,6 +96,19 @@ func TestIs2ndProposer(t *testing.T) {
 	server.peerPool = peerPool()
 	res := server.is2ndProposer(1, 1)
 	t.Logf("TestIs2ndProposer %v", res)
+
+	//a 2nd proposer doesn't propose before synced, like the leader
+	server.Index = 2
+	if server.is2ndProposer(1, 2) {
+		t.Errorf("TestIs2ndProposer: proposes while syncing")
+	}
+	if !server.is2ndProposer(1, 3) {
+		t.Errorf("TestIs2ndProposer: peer 3 is not 2nd proposer")
+	}
+	server.stateMgr.currentState = Synced
+	if !server.is2ndProposer(1, 2) {
+		t.Errorf("TestIs2ndProposer: doesn't propose once synced")
+	}
 }
 
 func TestIsEndorser(t *testing.T) {
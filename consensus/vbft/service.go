@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"time"
@@ -29,10 +30,13 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-crypto/vrf"
 	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology-eventbus/eventhub"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	actorTypes "github.com/polynetwork/poly/consensus/actor"
+	"github.com/polynetwork/poly/consensus/engine"
 	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
 	"github.com/polynetwork/poly/core/genesis"
 	"github.com/polynetwork/poly/core/ledger"
@@ -91,12 +95,14 @@ type Server struct {
 	account       *account.Account
 	poolActor     *actorTypes.TxPoolActor
 	p2p           *actorTypes.P2PActor
-	ledger        *ledger.Ledger
+	backend       engine.Backend
 	incrValidator *increment.IncrementValidator
 	pid           *actor.PID
+	metrics       *consensusMetrics
 
 	// some config
 	msgHistoryDuration uint32
+	dataDir            string // evidence of equivocation and votes of the server are persisted under it
 
 	//
 	// Note:
@@ -111,13 +117,15 @@ type Server struct {
 	config                   *vconfig.ChainConfig
 	currentParticipantConfig *BlockParticipantConfig
 
-	chainStore *ChainStore // block store
-	msgPool    *MsgPool    // consensus msg pool
-	blockPool  *BlockPool  // received block proposals
-	peerPool   *PeerPool   // consensus peers
-	syncer     *Syncer
-	stateMgr   *StateMgr
-	timer      *EventTimer
+	chainStore   *ChainStore   // block store
+	msgPool      *MsgPool      // consensus msg pool
+	blockPool    *BlockPool    // received block proposals
+	peerPool     *PeerPool     // consensus peers
+	evidencePool *EvidencePool // signed block hashes for equivocation detecting
+	voteStore    *VoteStore    // block hashes voted by the server
+	syncer       *Syncer
+	stateMgr     *StateMgr
+	timer        *EventTimer
 
 	msgRecvC   map[uint32]chan *p2pMsgPayload
 	msgC       chan ConsensusMsg
@@ -130,13 +138,25 @@ type Server struct {
 }
 
 func NewVbftServer(account *account.Account, txpool, p2p *actor.PID) (*Server, error) {
+	dataDir := filepath.Join(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName)
+	return NewVbftServerWithLedger(account, txpool, p2p, ledger.DefLedger, "consensus_vbft", dataDir, events.DefEvtHub)
+}
+
+// NewVbftServerWithLedger creates a vbft server on top of db, spawned as actor name, keeping
+// its files in dataDir and listening for saved blocks on evtHub, so that several servers can
+// live in one process.
+func NewVbftServerWithLedger(account *account.Account, txpool, p2p *actor.PID, db *ledger.Ledger,
+	name, dataDir string, evtHub *eventhub.EventHub) (*Server, error) {
+	poolActor := &actorTypes.TxPoolActor{Pool: txpool}
 	server := &Server{
 		msgHistoryDuration: 64,
+		dataDir:            dataDir,
 		account:            account,
-		poolActor:          &actorTypes.TxPoolActor{Pool: txpool},
+		poolActor:          poolActor,
 		p2p:                &actorTypes.P2PActor{P2P: p2p},
-		ledger:             ledger.DefLedger,
+		backend:            engine.NewLedgerBackend(db, poolActor),
 		incrValidator:      increment.NewIncrementValidator(20),
+		metrics:            newConsensusMetrics(),
 	}
 	server.stateMgr = newStateMgr(server)
 
@@ -144,12 +164,12 @@ func NewVbftServer(account *account.Account, txpool, p2p *actor.PID) (*Server, e
 		return server
 	})
 
-	pid, err := actor.SpawnNamed(props, "consensus_vbft")
+	pid, err := actor.SpawnNamed(props, name)
 	if err != nil {
 		return nil, err
 	}
 	server.pid = pid
-	server.sub = events.NewActorSubscriber(pid)
+	server.sub = events.NewActorSubscriber(pid, evtHub)
 
 	if err := server.initialize(); err != nil {
 		return nil, fmt.Errorf("vbft server start failed: %s", err)
@@ -173,6 +193,10 @@ func (self *Server) Receive(context actor.Context) {
 		log.Info("vbft actor start consensus")
 	case *actorTypes.StopConsensus:
 		self.stop()
+		// acknowledge callers waiting for the server to be fully stopped
+		if context.Sender() != nil {
+			context.Respond(msg)
+		}
 	case *message.SaveBlockCompleteMsg:
 		log.Infof("vbft actor SaveBlockCompleteMsg receives block complete event. block height=%d, numtx=%d",
 			msg.Block.Header.Height, len(msg.Block.Transactions))
@@ -182,7 +206,16 @@ func (self *Server) Receive(context actor.Context) {
 			msg.Block.Header.Height, len(msg.Block.Transactions))
 		self.handleBlockPersistCompleted(msg.Block)
 	case *p2pmsg.ConsensusPayload:
-		self.NewConsensusPayload(msg)
+		// peer receivers are closed once the server stopped
+		if !self.quit {
+			self.NewConsensusPayload(msg)
+		}
+	case *GetMetricsReq:
+		buf := new(bytes.Buffer)
+		self.WriteMetrics(buf)
+		context.Respond(&GetMetricsRsp{Metrics: buf.Bytes()})
+	case *GetTraceReq:
+		context.Respond(&GetTraceRsp{Trace: self.GetTrace(msg.Height)})
 
 	default:
 		log.Info("vbft actor: Unknown msg ", msg, "type", reflect.TypeOf(msg))
@@ -409,7 +442,7 @@ func (self *Server) initialize() error {
 	selfNodeId := vconfig.PubkeyID(self.account.PublicKey)
 	log.Infof("server: %s starting", selfNodeId)
 
-	store, err := OpenBlockStore(self.ledger, self.pid)
+	store, err := OpenBlockStore(self.backend, self.pid)
 	if err != nil {
 		log.Errorf("failed to open block store: %s", err)
 		return fmt.Errorf("failed to open block store: %s", err)
@@ -424,6 +457,8 @@ func (self *Server) initialize() error {
 	}
 	self.msgPool = newMsgPool(self, self.msgHistoryDuration)
 	self.peerPool = NewPeerPool(0, self) // FIXME: maxSize
+	self.evidencePool = newEvidencePool(self, self.msgHistoryDuration)
+	self.voteStore = newVoteStore(filepath.Join(self.dataDir, VOTE_DIR), self.msgHistoryDuration)
 	self.timer = NewEventTimer(self)
 	self.syncer = newSyncer(self)
 
@@ -609,6 +644,7 @@ func (self *Server) run(peerPubKey keypair.PublicKey) error {
 				if msg.Type() < 4 {
 					log.Infof("server %d received consensus msg, blk %d, type: %d from %d",
 						self.Index, msg.GetBlockNum(), msg.Type(), fromPeer)
+					self.checkEquivocation(fromPeer, msg)
 				}
 
 				self.onConsensusMsg(fromPeer, msg, hashData(msgData))
@@ -655,6 +691,8 @@ func (self *Server) startNewRound() error {
 		log.Errorf("startNewRound error:%s", err)
 		return err
 	}
+	self.metrics.onRoundStart(blkNum, self.getRoundLeader(blkNum))
+
 	// check proposals in msgpool
 	var proposal *blockProposalMsg
 	if proposals := self.msgPool.GetProposalMsgs(blkNum); len(proposals) > 0 {
@@ -1243,6 +1281,7 @@ func (self *Server) processMsgEvent() error {
 			msgBlkNum := pMsg.GetBlockNum()
 			if msgBlkNum == self.GetCurrentBlockNo() {
 				lbm.addMsg(msgBlkNum, msg)
+				self.metrics.onProposal(msgBlkNum, pMsg.Block.getProposer())
 				// add proposal to block-pool
 				if err := self.blockPool.newBlockProposal(pMsg); err != nil {
 					if err == errDupProposal {
@@ -1299,6 +1338,7 @@ func (self *Server) processMsgEvent() error {
 
 			if msgBlkNum == self.GetCurrentBlockNo() {
 				lbm.addMsg(msgBlkNum, msg)
+				self.metrics.onEndorse(msgBlkNum, pMsg.Endorser, pMsg.EndorsedProposer, pMsg.EndorseForEmpty)
 
 				if pMsg.EndorsedProposer != self.Index && len(self.msgPool.GetProposalMsgs(msgBlkNum)) == 0 {
 					self.fetchProposal(msgBlkNum, pMsg.EndorsedProposer)
@@ -1375,6 +1415,7 @@ func (self *Server) processMsgEvent() error {
 
 			if msgBlkNum == self.GetCurrentBlockNo() {
 				lbm.addMsg(msgBlkNum, msg)
+				self.metrics.onCommit(msgBlkNum, pMsg.Committer, pMsg.BlockProposer, pMsg.CommitForEmpty)
 				//              if countOfCommitment(msg.proposal) >= 2C + 1:
 				//                      stop WaitCommitsTimer
 				//                      sealProposal(msg.BlockHash)
@@ -1562,6 +1603,7 @@ func (self *Server) actionLoop() {
 
 					log.Infof("server %d fastforwarding block %d, proposer %d",
 						self.Index, blkNum, proposal.Block.getProposer())
+					self.metrics.onFastForward(blkNum, proposal.Block.getProposer())
 
 					// fastforward the block
 					if err := self.sealBlock(proposal.Block, forEmpty, true); err != nil {
@@ -1686,6 +1728,21 @@ func (self *Server) timerLoop() {
 }
 
 func (self *Server) processTimerEvent(evt *TimerEvent) error {
+	if _, present := timerEventNames[evt.evtType]; present && isReady(self.getState()) {
+		leader := self.getRoundLeader(evt.blockNum)
+		leaderStalled := false
+		if evt.evtType == EventProposeBlockTimeout {
+			leaderStalled = true
+			for _, p := range self.blockPool.getBlockProposals(evt.blockNum) {
+				if p.Block.getProposer() == leader {
+					leaderStalled = false
+					break
+				}
+			}
+		}
+		self.metrics.onTimeout(evt.blockNum, evt.evtType, leader, leaderStalled)
+	}
+
 	switch evt.evtType {
 	case EventProposalBackoff:
 		// 1. if endorsed, return
@@ -2083,6 +2140,7 @@ func (self *Server) fastForwardBlock(block *Block) error {
 		if len(block.Block.Header.SigData) <= 1 {
 			flag = true
 		}
+		self.metrics.onFastForward(block.getBlockNum(), block.getProposer())
 		return self.sealBlock(block, false, flag)
 	}
 	return fmt.Errorf("server %d: fastforward blk %d failed, current blkNum: %d",
@@ -2107,10 +2165,14 @@ func (self *Server) sealBlock(block *Block, empty bool, sigdata bool) error {
 
 	// TODO: also persistent the block endorsers and committer msgs
 
+	self.metrics.onBlockSealed(sealedBlkNum, block.getProposer(), empty)
+
 	// notify other modules that block sealed
 	self.timer.onBlockSealed(sealedBlkNum)
 	self.msgPool.onBlockSealed(sealedBlkNum)
 	self.blockPool.onBlockSealed(sealedBlkNum)
+	self.evidencePool.onBlockSealed(sealedBlkNum)
+	self.voteStore.onBlockSealed(sealedBlkNum)
 
 	_, h := self.blockPool.getSealedBlock(sealedBlkNum)
 	prevBlkHash := block.getPrevBlockHash()
@@ -2190,7 +2252,7 @@ func (self *Server) checkNeedUpdateChainConfig(blockNum uint32) bool {
 
 //checkUpdateChainConfig query leveldb check is force update
 func (self *Server) checkUpdateChainConfig(blkNum uint32) bool {
-	force, err := isUpdate(self.blockPool.getExecWriteSet(blkNum-1), self.config.View)
+	force, err := isUpdate(self.backend, self.blockPool.getExecState(blkNum-1), self.config.View)
 	if err != nil {
 		log.Errorf("checkUpdateChainConfig err:%s", err)
 		return false
@@ -2240,7 +2302,7 @@ func (self *Server) makeProposal(blkNum uint32, forEmpty bool) error {
 	cfg := &vconfig.ChainConfig{}
 	cfg = nil
 	if self.checkNeedUpdateChainConfig(blkNum) || self.checkUpdateChainConfig(blkNum) {
-		chainconfig, err := getChainConfig(self.blockPool.getExecWriteSet(blkNum-1), blkNum)
+		chainconfig, err := getChainConfig(self.backend, self.blockPool.getExecState(blkNum-1), blkNum)
 		if err != nil {
 			return fmt.Errorf("getChainConfig failed:%s", err)
 		}
@@ -2264,7 +2326,7 @@ func (self *Server) makeProposal(blkNum uint32, forEmpty bool) error {
 			}
 		}
 	}
-	proposal, err := self.constructProposalMsg(blkNum, sysTxs, userTxs, cfg)
+	proposal, err := self.constructProposalMsg(blkNum, sysTxs, userTxs, cfg, forEmpty)
 	if err != nil {
 		return fmt.Errorf("failed to construct proposal: %s", err)
 	}
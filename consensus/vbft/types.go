@@ -89,7 +89,10 @@ func (blk *Block) Serialize() ([]byte, error) {
 }
 
 func (blk *Block) Deserialize(data []byte) error {
-	source := common.NewZeroCopySource(data)
+	return blk.deserialization(common.NewZeroCopySource(data))
+}
+
+func (blk *Block) deserialization(source *common.ZeroCopySource) error {
 	buf1, eof := source.NextVarBytes()
 	if eof {
 		return io.ErrUnexpectedEOF
@@ -28,14 +28,10 @@ import (
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/consensus/engine"
 	"github.com/polynetwork/poly/consensus/vbft/config"
-	"github.com/polynetwork/poly/core/ledger"
 	"github.com/polynetwork/poly/core/signature"
-	"github.com/polynetwork/poly/core/states"
-	scommon "github.com/polynetwork/poly/core/store/common"
-	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
-	nutils "github.com/polynetwork/poly/native/service/utils"
 )
 
 func SignMsg(account *account.Account, msg ConsensusMsg) ([]byte, error) {
@@ -125,13 +121,8 @@ func verifyVrf(pk keypair.PublicKey, blkNum uint32, prevVrf, newVrf, proof []byt
 	}
 	return nil
 }
-func GetVbftConfigInfo(memdb *overlaydb.MemDB) (*config.VBFTConfig, error) {
-	data, err := GetStorageValue(memdb, ledger.DefLedger, nutils.NodeManagerContractAddress, []byte(node_manager.VBFT_CONFIG))
-	if err != nil {
-		return nil, err
-	}
-	cfg := new(node_manager.Configuration)
-	err = cfg.Deserialization(common.NewZeroCopySource(data))
+func GetVbftConfigInfo(backend engine.Backend, state *engine.State) (*config.VBFTConfig, error) {
+	cfg, err := backend.Configuration(state)
 	if err != nil {
 		return nil, err
 	}
@@ -145,21 +136,12 @@ func GetVbftConfigInfo(memdb *overlaydb.MemDB) (*config.VBFTConfig, error) {
 	return chainconfig, nil
 }
 
-func GetPeersConfig(memdb *overlaydb.MemDB) ([]*config.VBFTPeerInfo, error) {
-	goveranceview, err := GetGovernanceView(memdb)
-	if err != nil {
-		return nil, err
-	}
-	viewBytes := nutils.GetUint32Bytes(goveranceview.View)
-	key := append([]byte(node_manager.PEER_POOL), viewBytes...)
-	data, err := GetStorageValue(memdb, ledger.DefLedger, nutils.NodeManagerContractAddress, key)
+func GetPeersConfig(backend engine.Backend, state *engine.State) ([]*config.VBFTPeerInfo, error) {
+	goveranceview, err := backend.GovernanceView(state)
 	if err != nil {
 		return nil, err
 	}
-	peerMap := &node_manager.PeerPoolMap{
-		PeerPoolMap: make(map[string]*node_manager.PeerPoolItem),
-	}
-	err = peerMap.Deserialization(common.NewZeroCopySource(data))
+	peerMap, err := backend.PeerPool(state, goveranceview.View)
 	if err != nil {
 		return nil, err
 	}
@@ -176,8 +158,8 @@ func GetPeersConfig(memdb *overlaydb.MemDB) ([]*config.VBFTPeerInfo, error) {
 	return peerstakes, nil
 }
 
-func isUpdate(memdb *overlaydb.MemDB, view uint32) (bool, error) {
-	goveranceview, err := GetGovernanceView(memdb)
+func isUpdate(backend engine.Backend, state *engine.State, view uint32) (bool, error) {
+	goveranceview, err := backend.GovernanceView(state)
 	if err != nil {
 		return false, err
 	}
@@ -187,54 +169,17 @@ func isUpdate(memdb *overlaydb.MemDB, view uint32) (bool, error) {
 	return false, nil
 }
 
-func getRawStorageItemFromMemDb(memdb *overlaydb.MemDB, addr common.Address, key []byte) (value []byte, unkown bool) {
-	rawKey := make([]byte, 0, 1+common.ADDR_LEN+len(key))
-	rawKey = append(rawKey, byte(scommon.ST_STORAGE))
-	rawKey = append(rawKey, addr[:]...)
-	rawKey = append(rawKey, key...)
-	return memdb.Get(rawKey)
-}
-
-func GetStorageValue(memdb *overlaydb.MemDB, backend *ledger.Ledger, addr common.Address, key []byte) (value []byte, err error) {
-	if memdb == nil {
-		return backend.GetStorageItem(addr, key)
-	}
-	rawValue, unknown := getRawStorageItemFromMemDb(memdb, addr, key)
-	if unknown {
-		return backend.GetStorageItem(addr, key)
-	}
-	if len(rawValue) == 0 {
-		return nil, scommon.ErrNotFound
-	}
-
-	value, err = states.GetValueFromRawStorageItem(rawValue)
-	return
-}
-
-func GetGovernanceView(memdb *overlaydb.MemDB) (*node_manager.GovernanceView, error) {
-	value, err := GetStorageValue(memdb, ledger.DefLedger, nutils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW))
-	if err != nil {
-		return nil, err
-	}
-	governanceView := new(node_manager.GovernanceView)
-	err = governanceView.Deserialization(common.NewZeroCopySource(value))
-	if err != nil {
-		return nil, err
-	}
-	return governanceView, nil
-}
-
-func getChainConfig(memdb *overlaydb.MemDB, blkNum uint32) (*vconfig.ChainConfig, error) {
-	config, err := GetVbftConfigInfo(memdb)
+func getChainConfig(backend engine.Backend, state *engine.State, blkNum uint32) (*vconfig.ChainConfig, error) {
+	config, err := GetVbftConfigInfo(backend, state)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chainconfig from leveldb: %s", err)
 	}
 
-	peersinfo, err := GetPeersConfig(memdb)
+	peersinfo, err := GetPeersConfig(backend, state)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get peersinfo from leveldb: %s", err)
 	}
-	goverview, err := GetGovernanceView(memdb)
+	goverview, err := backend.GovernanceView(state)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get governanceview failed:%s", err)
 	}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+)
+
+const VOTE_DIR = "votes"
+
+//
+// VoteStore persists the block hashes voted by the server in recent rounds,
+// before the votes are signed, so that a restarted server never signs a vote
+// conflicting with the one it signed before the restart.
+//
+type VoteStore struct {
+	lock       sync.Mutex
+	dir        string
+	historyLen uint32
+}
+
+func newVoteStore(dir string, historyLen uint32) *VoteStore {
+	return &VoteStore{
+		dir:        dir,
+		historyLen: historyLen,
+	}
+}
+
+func (store *VoteStore) voteFile(blkNum uint32, typ node_manager.EquivocationType, forEmpty bool) string {
+	return filepath.Join(store.dir, fmt.Sprintf("%d_%d_%t", blkNum, typ, forEmpty))
+}
+
+// addVote records the block hash to be voted by the server, it fails if
+// another block hash has been voted in the same role for the height
+func (store *VoteStore) addVote(blkNum uint32, typ node_manager.EquivocationType, forEmpty bool, hash common.Uint256) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	name := store.voteFile(blkNum, typ, forEmpty)
+	data, err := ioutil.ReadFile(name)
+	if err == nil {
+		voted, err := common.Uint256FromHexString(string(data))
+		if err != nil {
+			return fmt.Errorf("invalid vote in %s: %s", name, err)
+		}
+		if voted != hash {
+			return fmt.Errorf("block %x conflicts with voted block %x, blk %d, type %d, forEmpty %t",
+				hash, voted, blkNum, typ, forEmpty)
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read vote: %s", err)
+	}
+
+	if err := os.MkdirAll(store.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create vote dir: %s", err)
+	}
+	// write to a temp file and rename it, so that no partial vote is left on crash
+	tmp := name + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create vote file: %s", err)
+	}
+	_, err = f.WriteString(hash.ToHexString())
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to persist vote: %s", err)
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		return fmt.Errorf("failed to persist vote: %s", err)
+	}
+	return nil
+}
+
+// onBlockSealed removes the votes out of history, which can't be voted again
+func (store *VoteStore) onBlockSealed(blockNum uint32) {
+	if blockNum <= store.historyLen {
+		return
+	}
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	files, err := ioutil.ReadDir(store.dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		n, err := strconv.ParseUint(strings.SplitN(f.Name(), "_", 2)[0], 10, 32)
+		if err != nil || uint32(n) >= blockNum-store.historyLen {
+			continue
+		}
+		os.Remove(filepath.Join(store.dir, f.Name()))
+	}
+}
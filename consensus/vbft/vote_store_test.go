@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestVoteStore opens an empty vote store in a temp dir
+func newTestVoteStore(t *testing.T) *VoteStore {
+	dir, err := ioutil.TempDir("", "votes")
+	assert.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return newVoteStore(dir, 10)
+}
+
+func TestVoteStore(t *testing.T) {
+	store := newTestVoteStore(t)
+	hash1, hash2 := common.Uint256{1}, common.Uint256{2}
+
+	assert.Nil(t, store.addVote(20, node_manager.EndorseEquivocation, false, hash1))
+	assert.Nil(t, store.addVote(20, node_manager.EndorseEquivocation, false, hash1))
+	assert.NotNil(t, store.addVote(20, node_manager.EndorseEquivocation, false, hash2))
+
+	//votes of another role, another round or another height
+	assert.Nil(t, store.addVote(20, node_manager.CommitEquivocation, false, hash2))
+	assert.Nil(t, store.addVote(20, node_manager.EndorseEquivocation, true, hash2))
+	assert.Nil(t, store.addVote(21, node_manager.EndorseEquivocation, false, hash2))
+
+	//votes are kept across restarts
+	restarted := newVoteStore(store.dir, 10)
+	assert.NotNil(t, restarted.addVote(20, node_manager.EndorseEquivocation, false, hash2))
+
+	//votes out of history are removed
+	restarted.onBlockSealed(31)
+	assert.Nil(t, restarted.addVote(20, node_manager.EndorseEquivocation, false, hash2))
+	assert.NotNil(t, restarted.addVote(21, node_manager.EndorseEquivocation, false, hash1))
+	files, err := ioutil.ReadDir(store.dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(files))
+}
@@ -53,11 +53,14 @@ func BuildGenesisBlock(defaultBookkeeper []keypair.PublicKey, genesisConfig *con
 		return nil, fmt.Errorf("[Block],BuildGenesisBlock err with GetBookkeeperAddress: %s", err)
 	}
 	conf := common.NewZeroCopySink(nil)
-	if genesisConfig.VBFT != nil {
+	switch {
+	case genesisConfig.ConsensusType == config.CONSENSUS_TYPE_HOTSTUFF && genesisConfig.HotStuff != nil:
+		genesisConfig.HotStuff.InitConfigParam().Serialization(conf)
+	case genesisConfig.VBFT != nil:
 		genesisConfig.VBFT.Serialization(conf)
 	}
 	nodeManagerConfig := newNodeManagerInit(conf.Bytes())
-	consensusPayload, err := vconfig.GenesisConsensusPayload(0)
+	consensusPayload, err := vconfig.GenesisConsensusPayload(genesisConfig, 0)
 	if err != nil {
 		return nil, fmt.Errorf("consensus genesis init failed: %s", err)
 	}
@@ -22,6 +22,10 @@ import (
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native/states"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
@@ -41,3 +45,45 @@ func TestGenesisBlockInit(t *testing.T) {
 	assert.NotNil(t, block)
 	assert.NotEqual(t, block.Header.TransactionsRoot, common.UINT256_EMPTY)
 }
+
+func initConfigArgs(t *testing.T, block *types.Block) []byte {
+	param := new(states.ContractInvokeParam)
+	code := block.Transactions[0].Payload.(*payload.InvokeCode).Code
+	assert.Nil(t, param.Deserialization(common.NewZeroCopySource(code)))
+	assert.Equal(t, INIT_CONFIG, param.Method)
+	return param.Args
+}
+
+func TestGenesisInitConfig(t *testing.T) {
+	_, pub, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	peers := []*config.VBFTPeerInfo{{Index: 1, PeerPubkey: "pk", Address: common.ADDRESS_EMPTY.ToBase58()}}
+	vbft := &config.VBFTConfig{BlockMsgDelay: 10000, HashMsgDelay: 10000, PeerHandshakeTimeout: 10,
+		MaxBlockChangeView: 100, VrfValue: config.MainNetConfig.VBFT.VrfValue, VrfProof: config.MainNetConfig.VBFT.VrfProof,
+		Peers: peers}
+	hotstuff := &config.HotStuffConfig{BlockInterval: 6000, RoundTimeout: 8000, MaxBlockChangeView: 200, Peers: peers}
+
+	// vbft genesis keeps the serialization of its genesis config
+	block, err := BuildGenesisBlock([]keypair.PublicKey{pub}, &config.GenesisConfig{ConsensusType: config.CONSENSUS_TYPE_VBFT, VBFT: vbft, HotStuff: hotstuff})
+	assert.Nil(t, err)
+	sink := common.NewZeroCopySink(nil)
+	assert.Nil(t, vbft.Serialization(sink))
+	args := initConfigArgs(t, block)
+	assert.Equal(t, sink.Bytes(), args)
+	decoded := new(config.VBFTConfig)
+	assert.Nil(t, decoded.Deserialization(common.NewZeroCopySource(args)))
+	assert.Equal(t, vbft, decoded)
+
+	block, err = BuildGenesisBlock([]keypair.PublicKey{pub}, &config.GenesisConfig{ConsensusType: config.CONSENSUS_TYPE_HOTSTUFF, VBFT: vbft, HotStuff: hotstuff})
+	assert.Nil(t, err)
+	param := new(config.InitConfigParam)
+	assert.Nil(t, param.Deserialization(common.NewZeroCopySource(initConfigArgs(t, block))))
+	assert.Equal(t, hotstuff.InitConfigParam(), param)
+	assert.Equal(t, uint32(6000), param.BlockMsgDelay)
+	assert.Equal(t, uint32(8000), param.HashMsgDelay)
+	assert.Equal(t, "", param.VrfValue)
+	// the genesis validators of hotstuff are announced from its own config
+	info, err := vconfig.VbftBlock(block.Header)
+	assert.Nil(t, err)
+	assert.Equal(t, hotstuff.MaxBlockChangeView, info.NewChainConfig.MaxBlockChangeView)
+	assert.Equal(t, len(hotstuff.Peers), len(info.NewChainConfig.Peers))
+}
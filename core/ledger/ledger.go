@@ -27,7 +27,10 @@ import (
 	"github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/store"
 	"github.com/polynetwork/poly/core/store/ledgerstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/events"
+	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/event"
 	cstate "github.com/polynetwork/poly/native/states"
 )
@@ -48,6 +51,18 @@ func NewLedger(dataDir string) (*Ledger, error) {
 	}, nil
 }
 
+//NewMemLedger return a ledger kept in memory which publishes block events to publisher,
+//events.DefActorPublisher is used if publisher is nil. For test
+func NewMemLedger(publisher *events.ActorPublisher) (*Ledger, error) {
+	ldgStore, err := ledgerstore.NewMemLedgerStore(publisher)
+	if err != nil {
+		return nil, fmt.Errorf("NewMemLedgerStore error %s", err)
+	}
+	return &Ledger{
+		ldgStore: ldgStore,
+	}, nil
+}
+
 func (self *Ledger) GetStore() store.LedgerStore {
 	return self.ldgStore
 }
@@ -80,6 +95,10 @@ func (self *Ledger) SubmitBlock(b *types.Block, exec store.ExecuteResult) error
 	return self.ldgStore.SubmitBlock(b, exec)
 }
 
+func (self *Ledger) ExecuteBlockWithWriteSet(b *types.Block, writeSet *overlaydb.MemDB) (store.ExecuteResult, error) {
+	return self.ldgStore.ExecuteBlockWithWriteSet(b, writeSet)
+}
+
 func (self *Ledger) GetStateMerkleRoot(height uint32) (result common.Uint256, err error) {
 	return self.ldgStore.GetStateMerkleRoot(height)
 }
@@ -181,6 +200,10 @@ func (self *Ledger) PreExecuteContract(tx *types.Transaction) (*cstate.PreExecRe
 	return self.ldgStore.PreExecuteContract(tx)
 }
 
+func (self *Ledger) NewQueryNativeService() (*native.NativeService, error) {
+	return self.ldgStore.NewQueryNativeService()
+}
+
 func (self *Ledger) GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error) {
 	return self.ldgStore.GetEventNotifyByTx(tx)
 }
@@ -63,6 +63,23 @@ func NewBlockStore(dbDir string, enableCache bool) (*BlockStore, error) {
 	return blockStore, nil
 }
 
+//NewMemBlockStore return the block store instance backed by memory, for test
+func NewMemBlockStore() (*BlockStore, error) {
+	cache, err := NewBlockCache()
+	if err != nil {
+		return nil, fmt.Errorf("NewBlockCache error %s", err)
+	}
+	store, err := leveldbstore.NewMemLevelDBStore()
+	if err != nil {
+		return nil, err
+	}
+	return &BlockStore{
+		enableCache: true,
+		store:       store,
+		cache:       cache,
+	}, nil
+}
+
 //NewBatch start a commit batch
 func (this *BlockStore) NewBatch() {
 	this.store.NewBatch()
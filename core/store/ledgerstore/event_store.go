@@ -49,6 +49,17 @@ func NewEventStore(dbDir string) (*EventStore, error) {
 	}, nil
 }
 
+//NewMemEventStore return event store instance backed by memory, for test
+func NewMemEventStore() (*EventStore, error) {
+	store, err := leveldbstore.NewMemLevelDBStore()
+	if err != nil {
+		return nil, err
+	}
+	return &EventStore{
+		store: store,
+	}, nil
+}
+
 //NewBatch start event commit batch
 func (this *EventStore) NewBatch() {
 	this.store.NewBatch()
@@ -19,6 +19,7 @@
 package ledgerstore
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/polynetwork/poly/core/payload"
 	"github.com/polynetwork/poly/core/states"
@@ -72,8 +73,9 @@ type LedgerStoreImp struct {
 	headerCache          map[common.Uint256]*types.Header //BlockHash => Header
 	headerIndex          map[uint32]common.Uint256        //Header index, Mapping header height => block hash
 	savingBlockSemaphore chan bool
-	vbftPeerInfoheader   map[string]uint32 //pubInfo save pubkey,peerindex
-	vbftPeerInfoblock    map[string]uint32 //pubInfo save pubkey,peerindex
+	vbftPeerInfoheader   map[string]uint32      //pubInfo save pubkey,peerindex
+	vbftPeerInfoblock    map[string]uint32      //pubInfo save pubkey,peerindex
+	publisher            *events.ActorPublisher //Publisher of block events, events.DefActorPublisher if nil
 	lock                 sync.RWMutex
 }
 
@@ -110,6 +112,33 @@ func NewLedgerStore(dataDir string) (*LedgerStoreImp, error) {
 	return ledgerStore, nil
 }
 
+//NewMemLedgerStore return LedgerStoreImp instance backed by memory, block events are sent to publisher. For test
+func NewMemLedgerStore(publisher *events.ActorPublisher) (*LedgerStoreImp, error) {
+	ledgerStore := &LedgerStoreImp{
+		headerIndex:          make(map[uint32]common.Uint256),
+		headerCache:          make(map[common.Uint256]*types.Header, 0),
+		vbftPeerInfoheader:   make(map[string]uint32),
+		vbftPeerInfoblock:    make(map[string]uint32),
+		savingBlockSemaphore: make(chan bool, 1),
+		publisher:            publisher,
+	}
+
+	blockStore, err := NewMemBlockStore()
+	if err != nil {
+		return nil, fmt.Errorf("NewMemBlockStore error %s", err)
+	}
+	ledgerStore.blockStore = blockStore
+	ledgerStore.stateStore = NewMemStateStore(0)
+
+	eventState, err := NewMemEventStore()
+	if err != nil {
+		return nil, fmt.Errorf("NewMemEventStore error %s", err)
+	}
+	ledgerStore.eventStore = eventState
+
+	return ledgerStore, nil
+}
+
 //InitLedgerStoreWithGenesisBlock init the ledger store with genesis block. It's the first operation after NewLedgerStore.
 func (this *LedgerStoreImp) InitLedgerStoreWithGenesisBlock(genesisBlock *types.Block, defaultBookkeeper []keypair.PublicKey) error {
 	hasInit, err := this.hasAlreadyInitGenesisBlock()
@@ -167,9 +196,9 @@ func (this *LedgerStoreImp) InitLedgerStoreWithGenesisBlock(genesisBlock *types.
 			return fmt.Errorf("init error %s", err)
 		}
 	}
-	//load vbft peerInfo
+	//load vbft peerInfo, hotstuff keeps the vbft payload format
 	consensusType := strings.ToLower(config.DefConfig.Genesis.ConsensusType)
-	if consensusType == "vbft" {
+	if consensusType == config.CONSENSUS_TYPE_VBFT || consensusType == config.CONSENSUS_TYPE_HOTSTUFF {
 		header, err := this.GetHeaderByHash(this.currBlockHash)
 		if err != nil {
 			return err
@@ -425,12 +454,17 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header, vbftPeerInfo map[
 		return vbftPeerInfo, fmt.Errorf("block timestamp is incorrect")
 	}
 	consensusType := strings.ToLower(config.DefConfig.Genesis.ConsensusType)
-	if consensusType == "vbft" {
+	if consensusType == config.CONSENSUS_TYPE_VBFT || consensusType == config.CONSENSUS_TYPE_HOTSTUFF {
 		//check bookkeeppers
 		m := len(vbftPeerInfo) - (len(vbftPeerInfo)*6)/7
+		if consensusType == config.CONSENSUS_TYPE_HOTSTUFF {
+			//hotstuff blocks are signed by the quorum certificate of the block
+			m = len(vbftPeerInfo) - (len(vbftPeerInfo)-1)/3
+		}
 		if len(header.Bookkeepers) < m {
-			return vbftPeerInfo, fmt.Errorf("header Bookkeepers %d more than 6/7 len vbftPeerInfo%d", len(header.Bookkeepers), len(vbftPeerInfo))
+			return vbftPeerInfo, fmt.Errorf("header Bookkeepers %d less than quorum %d of vbftPeerInfo %d", len(header.Bookkeepers), m, len(vbftPeerInfo))
 		}
+		bookkeepers := make(map[string]bool, len(header.Bookkeepers))
 		for _, bookkeeper := range header.Bookkeepers {
 			pubkey := vconfig.PubkeyID(bookkeeper)
 			_, present := vbftPeerInfo[pubkey]
@@ -438,6 +472,10 @@ func (this *LedgerStoreImp) verifyHeader(header *types.Header, vbftPeerInfo map[
 				log.Errorf("invalid pubkey :%v,height:%d", pubkey, header.Height)
 				return vbftPeerInfo, fmt.Errorf("invalid pubkey :%v", pubkey)
 			}
+			if consensusType == config.CONSENSUS_TYPE_HOTSTUFF && bookkeepers[pubkey] {
+				return vbftPeerInfo, fmt.Errorf("duplicated bookkeeper :%v", pubkey)
+			}
+			bookkeepers[pubkey] = true
 		}
 		hash := header.Hash()
 		err = signature.VerifyMultiSignature(hash[:], header.Bookkeepers, m, header.SigData)
@@ -622,9 +660,41 @@ func (this *LedgerStoreImp) saveBlockToBlockStore(block *types.Block) error {
 	return nil
 }
 
-func (this *LedgerStoreImp) executeBlock(block *types.Block) (result store.ExecuteResult, err error) {
+//ExecuteBlockWithWriteSet executes a block on top of the write set of its uncommitted ancestors, the write set of
+//the result only holds the changes made by the block itself. The result is only used by consensus to look ahead,
+//the block must be executed again by ExecuteBlock before submitting.
+func (this *LedgerStoreImp) ExecuteBlockWithWriteSet(block *types.Block, writeSet *overlaydb.MemDB) (result store.ExecuteResult, err error) {
+	this.getSavingBlockLock()
+	defer this.releaseSavingBlockLock()
 	overlay := this.stateStore.NewOverlayDB()
+	if writeSet != nil {
+		writeSet.ForEach(func(key, val []byte) {
+			if len(val) == 0 {
+				overlay.Delete(key)
+			} else {
+				overlay.Put(key, val)
+			}
+		})
+	}
+	result, err = this.executeBlockWithOverlay(block, overlay)
+	if err != nil || writeSet == nil {
+		return
+	}
+	own := overlaydb.NewMemDB(0, 0)
+	result.WriteSet.ForEach(func(key, val []byte) {
+		if prev, unknown := writeSet.Get(key); unknown || !bytes.Equal(prev, val) {
+			own.Put(key, val)
+		}
+	})
+	result.WriteSet = own
+	return
+}
 
+func (this *LedgerStoreImp) executeBlock(block *types.Block) (result store.ExecuteResult, err error) {
+	return this.executeBlockWithOverlay(block, this.stateStore.NewOverlayDB())
+}
+
+func (this *LedgerStoreImp) executeBlockWithOverlay(block *types.Block, overlay *overlaydb.OverlayDB) (result store.ExecuteResult, err error) {
 	cache := storage.NewCacheDB(overlay)
 	for _, tx := range block.Transactions {
 		cache.Reset()
@@ -774,8 +844,12 @@ func (this *LedgerStoreImp) submitBlock(block *types.Block, result store.Execute
 	}
 	this.setCurrentBlock(blockHeight, blockHash)
 
-	if events.DefActorPublisher != nil {
-		events.DefActorPublisher.Publish(
+	publisher := this.publisher
+	if publisher == nil {
+		publisher = events.DefActorPublisher
+	}
+	if publisher != nil {
+		publisher.Publish(
 			message.TOPIC_SAVE_BLOCK_COMPLETE,
 			&message.SaveBlockCompleteMsg{
 				Block: block,
@@ -872,9 +946,28 @@ func (this *LedgerStoreImp) PreExecuteContract(tx *types.Transaction) (*cstates.
 	}
 	res, err := service.Invoke()
 	if err != nil {
+		result.Notify = service.GetNotify()
 		return result, err
 	}
-	return &sstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS, Result: common.ToHexString(res.([]byte)), Notify: service.GetNotify()}, nil
+	out, ok := res.([]byte)
+	if !ok {
+		result.Notify = service.GetNotify()
+		return result, fmt.Errorf("PreExecuteContract: unexpected result type %T", res)
+	}
+	return &sstate.PreExecResult{State: event.CONTRACT_STATE_SUCCESS, Result: common.ToHexString(out), Notify: service.GetNotify()}, nil
+}
+
+//NewQueryNativeService return a native service on the current state to read the storage of native contracts,
+//what it writes is never committed
+func (this *LedgerStoreImp) NewQueryNativeService() (*native.NativeService, error) {
+	hash := this.GetCurrentBlockHash()
+	header, err := this.GetHeaderByHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("get current header error: %s", err)
+	}
+	cache := storage.NewCacheDB(this.stateStore.NewOverlayDB())
+	tx := &types.Transaction{ChainID: header.ChainID}
+	return native.NewNativeService(cache, tx, header.Timestamp, header.Height, hash, header.ChainID, nil, true)
 }
 
 //IsContainBlock return whether the block is in store
@@ -897,6 +990,11 @@ func (this *LedgerStoreImp) GetBlockRootWithPreBlockHashes(startHeight uint32, p
 		log.Errorf("this.currBlockHeight= %d, startHeight= %d, len(preBlockHashes)= %d\n", this.currBlockHeight, startHeight, len(preBlockHashes))
 		return common.UINT256_EMPTY
 	}
+	// the ledger is behind consensus, the hashes of the blocks in between are missing
+	if this.currBlockHeight+1 < startHeight {
+		log.Errorf("this.currBlockHeight= %d, startHeight= %d, ledger is behind consensus", this.currBlockHeight, startHeight)
+		return common.UINT256_EMPTY
+	}
 
 	needs := preBlockHashes[this.currBlockHeight+1-startHeight:]
 	return this.stateStore.GetBlockRootWithPreBlockHashes(needs)
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/genesis"
@@ -137,3 +138,19 @@ func TestInitLedgerStoreWithGenesisBlock(t *testing.T) {
 		return
 	}
 }
+
+func TestGetBlockRootWithPreBlockHashes(t *testing.T) {
+	curBlockHeight := testLedgerStore.GetCurrentBlockHeight()
+	hashes := []common.Uint256{{1}, {2}}
+	//consensus is two blocks ahead of ledger, the hashes don't reach back to it
+	root := testLedgerStore.GetBlockRootWithPreBlockHashes(curBlockHeight+3, hashes)
+	if root != common.UINT256_EMPTY {
+		t.Errorf("TestGetBlockRootWithPreBlockHashes failed root %x != empty", root)
+		return
+	}
+	root = testLedgerStore.GetBlockRootWithPreBlockHashes(curBlockHeight+1, hashes)
+	if root == common.UINT256_EMPTY {
+		t.Errorf("TestGetBlockRootWithPreBlockHashes failed empty root")
+		return
+	}
+}
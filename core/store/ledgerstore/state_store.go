@@ -75,9 +75,11 @@ func NewStateStore(dbDir, merklePath string) (*StateStore, error) {
 // for test
 func NewMemStateStore(stateHashHeight uint32) *StateStore {
 	store, _ := leveldbstore.NewMemLevelDBStore()
+	hashStore := merkle.NewMemHashStore()
 	stateStore := &StateStore{
 		store:                store,
-		merkleTree:           merkle.NewTree(0, nil, nil),
+		merkleHashStore:      hashStore,
+		merkleTree:           merkle.NewTree(0, nil, hashStore),
 		deltaMerkleTree:      merkle.NewTree(0, nil, nil),
 		stateHashCheckHeight: stateHashHeight,
 	}
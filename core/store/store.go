@@ -24,6 +24,7 @@ import (
 	"github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/event"
 	cstates "github.com/polynetwork/poly/native/states"
 )
@@ -45,6 +46,7 @@ type LedgerStore interface {
 	AddBlock(block *types.Block, stateMerkleRoot common.Uint256) error
 	ExecuteBlock(b *types.Block) (ExecuteResult, error)   // called by consensus
 	SubmitBlock(b *types.Block, exec ExecuteResult) error // called by consensus
+	ExecuteBlockWithWriteSet(b *types.Block, writeSet *overlaydb.MemDB) (ExecuteResult, error)
 	GetStateMerkleRoot(height uint32) (result common.Uint256, err error)
 	GetCrossStateRoot(height uint32) (result common.Uint256, err error)
 	GetCurrentBlockHash() common.Uint256
@@ -65,6 +67,7 @@ type LedgerStore interface {
 	GetBookkeeperState() (*states.BookkeeperState, error)
 	GetStorageItem(key *states.StorageKey) (*states.StorageItem, error)
 	PreExecuteContract(tx *types.Transaction) (*cstates.PreExecResult, error)
+	NewQueryNativeService() (*native.NativeService, error)
 	GetEventNotifyByTx(tx common.Uint256) (*event.ExecuteNotify, error)
 	GetEventNotifyByBlock(height uint32) ([]*event.ExecuteNotify, error)
 }
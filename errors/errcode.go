@@ -53,6 +53,7 @@ const (
 	ErrGasPrice             ErrCode = 45020
 	ErrVerifySignature      ErrCode = 45021
 	ErrInValidShard         ErrCode = 45022
+	ErrNotPermitted         ErrCode = 45023
 )
 
 func (err ErrCode) Error() string {
@@ -103,6 +104,8 @@ func (err ErrCode) Error() string {
 		return "transaction verify signature fail"
 	case ErrInValidShard:
 		return "transaction shardId unmatch"
+	case ErrNotPermitted:
+		return "transaction signer is not a relayer or consensus peer"
 
 	}
 
@@ -23,6 +23,7 @@ import (
 
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/ontio/ontology-eventbus/eventhub"
+	"github.com/orcaman/concurrent-map"
 )
 
 var DefEvtHub *eventhub.EventHub
@@ -41,6 +42,11 @@ func Init() {
 	DefActorPublisher = NewActorPublisher(DefPublisherPID)
 }
 
+//NewEvtHub return an event hub isolated from DefEvtHub, e.g. for running several nodes in one process
+func NewEvtHub() *eventhub.EventHub {
+	return &eventhub.EventHub{Subscribers: cmap.New()}
+}
+
 func NewActorPublisher(publisher *actor.PID, evtHub ...*eventhub.EventHub) *ActorPublisher {
 	var hub *eventhub.EventHub
 	if len(evtHub) == 0 {
@@ -0,0 +1,344 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package access guards the json rpc, restful, websocket and grpc servers with api keys or json web tokens, ip allow-lists,
+// cors origins, request size limits and rate limits
+package access
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/polynetwork/poly/common/config"
+	berr "github.com/polynetwork/poly/http/base/error"
+)
+
+const (
+	API_KEY_HEADER     = "X-Api-Key"
+	ACCESS_TOKEN_PARAM = "access_token" //query param of the api key or token, for websocket clients unable to set headers
+	CORS_ALLOW_HEADERS = "Content-Type, Authorization, X-Api-Key"
+)
+
+//DefGuard is the guard shared by the json rpc, restful, websocket and grpc servers, a nil guard lets everything through
+var DefGuard *Guard
+
+//credential level of a client
+type role int
+
+const (
+	ROLE_NONE role = iota
+	ROLE_READ
+	ROLE_WRITE
+)
+
+var (
+	writeLock    sync.RWMutex
+	writeMethods = map[string]bool{
+		"sendrawtransaction": true,
+		"startconsensus":     true,
+		"stopconsensus":      true,
+		"setdebuginfo":       true,
+	}
+)
+
+//SetWriteMethod marks method as changing node state, write methods can be restricted apart from read ones
+func SetWriteMethod(method string) {
+	writeLock.Lock()
+	defer writeLock.Unlock()
+	writeMethods[method] = true
+}
+
+func IsWriteMethod(method string) bool {
+	writeLock.RLock()
+	defer writeLock.RUnlock()
+	return writeMethods[method]
+}
+
+type guardKey struct{}
+
+type Guard struct {
+	keys           map[string]role
+	jwtSecret      []byte
+	authWriteOnly  bool
+	allowIPs       []*net.IPNet
+	writeAllowIPs  []*net.IPNet
+	corsOrigins    map[string]bool
+	maxRequestSize int64
+	ipLimiter      *limiter
+	methodLimiters map[string]*limiter
+}
+
+func NewGuard(cfg *config.ApiAccessConfig) (*Guard, error) {
+	guard := &Guard{
+		keys:           make(map[string]role),
+		jwtSecret:      []byte(cfg.JwtSecret),
+		authWriteOnly:  cfg.AuthWriteOnly,
+		corsOrigins:    make(map[string]bool),
+		maxRequestSize: int64(cfg.MaxRequestSize),
+		ipLimiter:      newLimiter(cfg.RateLimit, cfg.RateBurst),
+		methodLimiters: make(map[string]*limiter),
+	}
+	for _, key := range cfg.ReadOnlyApiKeys {
+		guard.keys[key] = ROLE_READ
+	}
+	for _, key := range cfg.ApiKeys {
+		guard.keys[key] = ROLE_WRITE
+	}
+	var err error
+	if guard.allowIPs, err = parseIPNets(cfg.AllowIPs); err != nil {
+		return nil, fmt.Errorf("AllowIPs: %s", err)
+	}
+	if guard.writeAllowIPs, err = parseIPNets(cfg.WriteAllowIPs); err != nil {
+		return nil, fmt.Errorf("WriteAllowIPs: %s", err)
+	}
+	for _, origin := range cfg.CorsOrigins {
+		guard.corsOrigins[strings.TrimRight(origin, "/")] = true
+	}
+	if guard.maxRequestSize == 0 {
+		guard.maxRequestSize = int64(config.DEFAULT_API_MAX_REQUEST_SIZE)
+	}
+	for method, rate := range cfg.MethodRateLimits {
+		if rate < 0 || math.IsNaN(rate) {
+			return nil, fmt.Errorf("MethodRateLimits: invalid rate %v of %s", rate, method)
+		}
+		guard.methodLimiters[method] = newLimiter(rate, 0)
+	}
+	if cfg.RateLimit < 0 || math.IsNaN(cfg.RateLimit) {
+		return nil, fmt.Errorf("RateLimit: invalid rate %v", cfg.RateLimit)
+	}
+	return guard, nil
+}
+
+//Handler rejects the requests of clients outside the ip allow-list, from origins not allowed, over the size limit
+//or without a credential when every call needs one. It answers cors preflight requests, and the requests it passes
+//carry the guard for Allow
+func (self *Guard) Handler(next http.Handler) http.Handler {
+	if self == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !matchIP(self.allowIPs, remoteIP(r)) {
+			reject(w, http.StatusForbidden, berr.FORBIDDEN)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if !self.originAllowed(origin) {
+				reject(w, http.StatusForbidden, berr.FORBIDDEN)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+			w.Header().Set("Access-Control-Allow-Headers", CORS_ALLOW_HEADERS)
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		if r.ContentLength > self.maxRequestSize {
+			reject(w, http.StatusRequestEntityTooLarge, berr.REQUEST_TOO_LARGE)
+			return
+		}
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, self.maxRequestSize)
+		}
+		if self.authEnabled() && !self.authWriteOnly && self.authenticate(r) == ROLE_NONE {
+			reject(w, http.StatusUnauthorized, berr.UNAUTHORIZED)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), guardKey{}, self)))
+	})
+}
+
+//Allow checks whether the client of r may call method now, it returns berr.SUCCESS or the error code to answer.
+//Requests not passed by a guard handler are always allowed
+func Allow(r *http.Request, method string) int64 {
+	guard := fromRequest(r)
+	if guard == nil {
+		return berr.SUCCESS
+	}
+	return guard.allow(remoteIP(r), method, func() role { return guard.authenticate(r) })
+}
+
+//Check is the guard of the servers not built on net/http, like the grpc server. It checks whether the client at ip
+//with the api key or token may call method now, and returns berr.SUCCESS or the error code to answer.
+//A nil guard allows everything
+func (self *Guard) Check(ip net.IP, token string, method string) int64 {
+	if self == nil {
+		return berr.SUCCESS
+	}
+	if !matchIP(self.allowIPs, ip) {
+		return berr.FORBIDDEN
+	}
+	auth := func() role { return self.roleOf(token) }
+	if self.authEnabled() && !self.authWriteOnly && auth() == ROLE_NONE {
+		return berr.UNAUTHORIZED
+	}
+	return self.allow(ip, method, auth)
+}
+
+//MaxRequestSize returns the size limit of the requests, 0 for a nil guard
+func (self *Guard) MaxRequestSize() int64 {
+	if self == nil {
+		return 0
+	}
+	return self.maxRequestSize
+}
+
+//allow checks the write restrictions and rate limits of method for the client at ip, auth returns its role
+func (self *Guard) allow(ip net.IP, method string, auth func() role) int64 {
+	if IsWriteMethod(method) {
+		if !matchIP(self.writeAllowIPs, ip) {
+			return berr.FORBIDDEN
+		}
+		if self.authEnabled() {
+			switch auth() {
+			case ROLE_NONE:
+				return berr.UNAUTHORIZED
+			case ROLE_READ:
+				return berr.FORBIDDEN
+			}
+		}
+	}
+	key := ip.String()
+	if !self.ipLimiter.allow(key) || !self.methodLimiters[method].allow(key) {
+		return berr.TOO_MANY_REQUESTS
+	}
+	return berr.SUCCESS
+}
+
+//Guarded reports whether r is passed by a guard handler
+func Guarded(r *http.Request) bool {
+	return fromRequest(r) != nil
+}
+
+//MaxRequestSize returns the size limit of the requests and websocket messages of r, 0 if r is not guarded
+func MaxRequestSize(r *http.Request) int64 {
+	return fromRequest(r).MaxRequestSize()
+}
+
+//CheckOrigin is the websocket origin check, any origin is allowed if r is not guarded
+func CheckOrigin(r *http.Request) bool {
+	guard := fromRequest(r)
+	origin := r.Header.Get("Origin")
+	return guard == nil || origin == "" || guard.originAllowed(origin)
+}
+
+func fromRequest(r *http.Request) *Guard {
+	guard, _ := r.Context().Value(guardKey{}).(*Guard)
+	return guard
+}
+
+func (self *Guard) authEnabled() bool {
+	return len(self.keys) > 0 || len(self.jwtSecret) > 0
+}
+
+//authenticate returns the role of the api key or token of r
+func (self *Guard) authenticate(r *http.Request) role {
+	token := r.Header.Get(API_KEY_HEADER)
+	if token == "" {
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimSpace(auth[len("Bearer "):])
+		}
+	}
+	if token == "" {
+		token = r.URL.Query().Get(ACCESS_TOKEN_PARAM)
+	}
+	return self.roleOf(token)
+}
+
+//roleOf returns the role of an api key or token
+func (self *Guard) roleOf(token string) role {
+	if token == "" {
+		return ROLE_NONE
+	}
+	if role, ok := self.keys[token]; ok {
+		return role
+	}
+	if len(self.jwtSecret) == 0 {
+		return ROLE_NONE
+	}
+	claims, err := verifyJwt(token, self.jwtSecret)
+	if err != nil {
+		return ROLE_NONE
+	}
+	if claims.Scope == JWT_SCOPE_READ {
+		return ROLE_READ
+	}
+	return ROLE_WRITE
+}
+
+func (self *Guard) originAllowed(origin string) bool {
+	return self.corsOrigins["*"] || self.corsOrigins[strings.TrimRight(origin, "/")]
+}
+
+func reject(w http.ResponseWriter, status int, code int64) {
+	http.Error(w, berr.ErrMap[code], status)
+}
+
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+//matchIP reports whether ip is in nets, an empty list matches every ip
+func matchIP(nets []*net.IPNet, ip net.IP) bool {
+	if len(nets) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+//parseIPNets parses ips and CIDRs, an ip is a network of itself
+func parseIPNets(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, s := range list {
+		if strings.Contains(s, "/") {
+			_, n, err := net.ParseCIDR(s)
+			if err != nil {
+				return nil, err
+			}
+			nets = append(nets, n)
+			continue
+		}
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip %s", s)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return nets, nil
+}
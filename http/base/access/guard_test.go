@@ -0,0 +1,228 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package access
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/poly/common/config"
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/stretchr/testify/assert"
+)
+
+//serve passes r through the guard of cfg, the handler answers the code of Allow for method
+func serve(t *testing.T, cfg *config.ApiAccessConfig, method string, r *http.Request) (*httptest.ResponseRecorder, int64) {
+	guard, err := NewGuard(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := int64(-1)
+	w := httptest.NewRecorder()
+	guard.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code = Allow(r, method)
+	})).ServeHTTP(w, r)
+	return w, code
+}
+
+func newRequest(remote string) *http.Request {
+	r := httptest.NewRequest("POST", "/", strings.NewReader("{}"))
+	r.RemoteAddr = remote + ":5000"
+	return r
+}
+
+func signJwt(secret string, claims string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(header + "." + payload))
+	return header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestNilGuard(t *testing.T) {
+	var guard *Guard
+	called := false
+	guard.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		assert.Equal(t, berr.SUCCESS, Allow(r, "sendrawtransaction"))
+		assert.False(t, Guarded(r))
+	})).ServeHTTP(httptest.NewRecorder(), newRequest("1.2.3.4"))
+	assert.True(t, called)
+	assert.Equal(t, berr.SUCCESS, guard.Check(nil, "", "sendrawtransaction"))
+	assert.Equal(t, int64(0), guard.MaxRequestSize())
+}
+
+func TestIPAllowList(t *testing.T) {
+	cfg := &config.ApiAccessConfig{AllowIPs: []string{"10.0.0.0/8", "::1"}, WriteAllowIPs: []string{"10.0.0.1"}}
+	w, _ := serve(t, cfg, "getblockcount", newRequest("1.2.3.4"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	_, code := serve(t, cfg, "getblockcount", newRequest("10.1.2.3"))
+	assert.Equal(t, berr.SUCCESS, code)
+	_, code = serve(t, cfg, "getblockcount", newRequest("[::1]"))
+	assert.Equal(t, berr.SUCCESS, code)
+
+	_, code = serve(t, cfg, "sendrawtransaction", newRequest("10.1.2.3"))
+	assert.Equal(t, berr.FORBIDDEN, code)
+	_, code = serve(t, cfg, "sendrawtransaction", newRequest("10.0.0.1"))
+	assert.Equal(t, berr.SUCCESS, code)
+
+	_, err := NewGuard(&config.ApiAccessConfig{AllowIPs: []string{"10.0.0.0/33"}})
+	assert.Error(t, err)
+	_, err = NewGuard(&config.ApiAccessConfig{WriteAllowIPs: []string{"localhost"}})
+	assert.Error(t, err)
+}
+
+func TestCors(t *testing.T) {
+	cfg := &config.ApiAccessConfig{CorsOrigins: []string{"https://explorer.poly.network/"}}
+	r := newRequest("1.2.3.4")
+	r.Header.Set("Origin", "https://explorer.poly.network")
+	w, code := serve(t, cfg, "getblockcount", r)
+	assert.Equal(t, berr.SUCCESS, code)
+	assert.Equal(t, "https://explorer.poly.network", w.Header().Get("Access-Control-Allow-Origin"))
+
+	r = httptest.NewRequest("OPTIONS", "/", nil)
+	r.Header.Set("Origin", "https://explorer.poly.network")
+	w, code = serve(t, cfg, "getblockcount", r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, int64(-1), code, "preflight is not passed on")
+
+	r = newRequest("1.2.3.4")
+	r.Header.Set("Origin", "https://evil.example")
+	w, _ = serve(t, cfg, "getblockcount", r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	cfg.CorsOrigins = []string{"*"}
+	w, code = serve(t, cfg, "getblockcount", r)
+	assert.Equal(t, berr.SUCCESS, code)
+	assert.Equal(t, "https://evil.example", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestMaxRequestSize(t *testing.T) {
+	cfg := &config.ApiAccessConfig{MaxRequestSize: 4}
+	r := httptest.NewRequest("POST", "/", strings.NewReader("123456"))
+	w, _ := serve(t, cfg, "getblockcount", r)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	guard, _ := NewGuard(&config.ApiAccessConfig{})
+	assert.Equal(t, int64(config.DEFAULT_API_MAX_REQUEST_SIZE), guard.maxRequestSize)
+}
+
+func TestApiKeys(t *testing.T) {
+	cfg := &config.ApiAccessConfig{ApiKeys: []string{"write-key"}, ReadOnlyApiKeys: []string{"read-key"}}
+	w, _ := serve(t, cfg, "getblockcount", newRequest("1.2.3.4"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	r := newRequest("1.2.3.4")
+	r.Header.Set(API_KEY_HEADER, "read-key")
+	_, code := serve(t, cfg, "getblockcount", r)
+	assert.Equal(t, berr.SUCCESS, code)
+	_, code = serve(t, cfg, "sendrawtransaction", r)
+	assert.Equal(t, berr.FORBIDDEN, code)
+
+	r = newRequest("1.2.3.4")
+	r.Header.Set("Authorization", "Bearer write-key")
+	_, code = serve(t, cfg, "sendrawtransaction", r)
+	assert.Equal(t, berr.SUCCESS, code)
+
+	r = httptest.NewRequest("GET", "/?access_token=write-key", nil)
+	_, code = serve(t, cfg, "sendrawtransaction", r)
+	assert.Equal(t, berr.SUCCESS, code)
+
+	//only write methods need a key
+	cfg.AuthWriteOnly = true
+	_, code = serve(t, cfg, "getblockcount", newRequest("1.2.3.4"))
+	assert.Equal(t, berr.SUCCESS, code)
+	_, code = serve(t, cfg, "sendrawtransaction", newRequest("1.2.3.4"))
+	assert.Equal(t, berr.UNAUTHORIZED, code)
+
+	SetWriteMethod("testwrite")
+	_, code = serve(t, cfg, "testwrite", newRequest("1.2.3.4"))
+	assert.Equal(t, berr.UNAUTHORIZED, code)
+}
+
+func TestJwt(t *testing.T) {
+	cfg := &config.ApiAccessConfig{JwtSecret: "secret", AuthWriteOnly: true}
+	call := func(token string) int64 {
+		r := newRequest("1.2.3.4")
+		r.Header.Set("Authorization", "Bearer "+token)
+		_, code := serve(t, cfg, "sendrawtransaction", r)
+		return code
+	}
+	assert.Equal(t, berr.SUCCESS, call(signJwt("secret", `{"sub":"relayer"}`)))
+	assert.Equal(t, berr.FORBIDDEN, call(signJwt("secret", `{"scope":"read"}`)))
+	assert.Equal(t, berr.UNAUTHORIZED, call(signJwt("other", `{}`)))
+	assert.Equal(t, berr.UNAUTHORIZED, call(signJwt("secret", `{"exp":1}`)))
+	assert.Equal(t, berr.UNAUTHORIZED, call(signJwt("secret", `{"nbf":4102444800}`)))
+	assert.Equal(t, berr.UNAUTHORIZED, call("not.a.token"))
+
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{}`)) + "."
+	assert.Equal(t, berr.UNAUTHORIZED, call(none))
+}
+
+func TestRateLimit(t *testing.T) {
+	cfg := &config.ApiAccessConfig{RateLimit: 2, MethodRateLimits: map[string]float64{"getstorage": 1}}
+	guard, err := NewGuard(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	guard.ipLimiter.now = func() time.Time { return now }
+	guard.methodLimiters["getstorage"].now = func() time.Time { return now }
+
+	codes := []int64{}
+	handler := guard.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := r.URL.Query().Get("method")
+		codes = append(codes, Allow(r, method))
+	}))
+	call := func(remote, method string) int64 {
+		r := httptest.NewRequest("POST", "/?method="+method, nil)
+		r.RemoteAddr = remote + ":5000"
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		return codes[len(codes)-1]
+	}
+	assert.Equal(t, berr.SUCCESS, call("1.1.1.1", "getstorage"))
+	assert.Equal(t, berr.TOO_MANY_REQUESTS, call("1.1.1.1", "getstorage"))
+	assert.Equal(t, berr.TOO_MANY_REQUESTS, call("1.1.1.1", "getblockcount"), "burst of 2 is used up")
+	assert.Equal(t, berr.SUCCESS, call("2.2.2.2", "getblockcount"), "buckets are per ip")
+
+	now = now.Add(time.Second)
+	assert.Equal(t, berr.SUCCESS, call("1.1.1.1", "getstorage"))
+
+	_, err = NewGuard(&config.ApiAccessConfig{MethodRateLimits: map[string]float64{"getstorage": -1}})
+	assert.Error(t, err)
+}
+
+func TestLimiterSweep(t *testing.T) {
+	l := newLimiter(1, 1)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	assert.True(t, l.allow("a"))
+	assert.Equal(t, 1, len(l.buckets))
+	now = now.Add(SWEEP_INTERVAL)
+	assert.True(t, l.allow("b"))
+	assert.Equal(t, 1, len(l.buckets), "the refilled bucket of a is dropped")
+	assert.Nil(t, newLimiter(0, 10))
+	assert.True(t, (*limiter)(nil).allow("a"))
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package access
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const JWT_SCOPE_READ = "read"
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Exp   int64  `json:"exp"`   //expiry in unix seconds, 0 never expires
+	Nbf   int64  `json:"nbf"`   //not valid before, unix seconds
+	Scope string `json:"scope"` //"read" limits the token to read methods
+}
+
+//verifyJwt checks the signature and validity period of a HS256 json web token and returns its claims
+func verifyJwt(token string, secret []byte) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("decode header: %s", err)
+	}
+	header := &jwtHeader{}
+	if err := json.Unmarshal(headerBytes, header); err != nil {
+		return nil, fmt.Errorf("unmarshal header: %s", err)
+	}
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("unsupported alg %s", header.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decode signature: %s", err)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decode claims: %s", err)
+	}
+	claims := &jwtClaims{}
+	if err := json.Unmarshal(claimsBytes, claims); err != nil {
+		return nil, fmt.Errorf("unmarshal claims: %s", err)
+	}
+	now := time.Now().Unix()
+	if claims.Exp != 0 && now >= claims.Exp {
+		return nil, fmt.Errorf("token expired")
+	}
+	if now < claims.Nbf {
+		return nil, fmt.Errorf("token not valid yet")
+	}
+	return claims, nil
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package access
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const SWEEP_INTERVAL = time.Minute
+
+//token bucket of a client
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+//limiter keeps a token bucket per client, a nil limiter allows everything
+type limiter struct {
+	sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newLimiter(rate float64, burst uint) *limiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst == 0 {
+		burst = uint(math.Ceil(rate))
+	}
+	return &limiter{
+		rate:      rate,
+		burst:     float64(burst),
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+//allow takes a token from the bucket of key
+func (self *limiter) allow(key string) bool {
+	if self == nil {
+		return true
+	}
+	self.Lock()
+	defer self.Unlock()
+	now := self.now()
+	self.sweep(now)
+	b, ok := self.buckets[key]
+	if !ok {
+		b = &bucket{tokens: self.burst, last: now}
+		self.buckets[key] = b
+	}
+	b.tokens = math.Min(self.burst, b.tokens+now.Sub(b.last).Seconds()*self.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+//sweep drops the buckets refilled to burst, they are the same as new ones
+func (self *limiter) sweep(now time.Time) {
+	if now.Sub(self.lastSweep) < SWEEP_INTERVAL {
+		return
+	}
+	self.lastSweep = now
+	for key, b := range self.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*self.rate >= self.burst {
+			delete(self.buckets, key)
+		}
+	}
+}
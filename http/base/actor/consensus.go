@@ -19,8 +19,14 @@
 package actor
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/common/log"
 	cactor "github.com/polynetwork/poly/consensus/actor"
+	"github.com/polynetwork/poly/consensus/vbft"
 )
 
 var consensusSrvPid *actor.PID
@@ -44,3 +50,45 @@ func ConsensusSrvHalt() error {
 	}
 	return nil
 }
+
+//get vbft metrics in prometheus text format from consensus actor, other engines answer an error
+func GetConsensusMetrics() ([]byte, error) {
+	if consensusSrvPid == nil {
+		return nil, errors.New("consensus is not started")
+	}
+	future := consensusSrvPid.RequestFuture(&vbft.GetMetricsReq{}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	if unsupported, ok := result.(*cactor.Unsupported); ok {
+		return nil, fmt.Errorf("consensus engine does not serve %s", unsupported.Request)
+	}
+	rsp, ok := result.(*vbft.GetMetricsRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return rsp.Metrics, nil
+}
+
+//get vbft round trace of height from consensus actor
+func GetConsensusTrace(height uint32) (*vbft.RoundTrace, error) {
+	if consensusSrvPid == nil {
+		return nil, errors.New("consensus is not started")
+	}
+	future := consensusSrvPid.RequestFuture(&vbft.GetTraceReq{Height: height}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	if unsupported, ok := result.(*cactor.Unsupported); ok {
+		return nil, fmt.Errorf("consensus engine does not serve %s", unsupported.Request)
+	}
+	rsp, ok := result.(*vbft.GetTraceRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return rsp.Trace, nil
+}
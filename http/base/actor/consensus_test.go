@@ -0,0 +1,40 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package actor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/consensus/solo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsensusWithoutMetrics(t *testing.T) {
+	pid := actor.Spawn(actor.FromProducer(func() actor.Actor { return &solo.SoloService{} }))
+	defer pid.Stop()
+	SetConsensusPid(pid)
+	defer SetConsensusPid(nil)
+
+	start := time.Now()
+	_, err := GetConsensusMetrics()
+	assert.EqualError(t, err, "consensus engine does not serve *vbft.GetMetricsReq")
+	_, err = GetConsensusTrace(1)
+	assert.EqualError(t, err, "consensus engine does not serve *vbft.GetTraceReq")
+	assert.True(t, time.Since(start) < time.Second)
+}
@@ -20,22 +20,14 @@
 package actor
 
 import (
-	"encoding/hex"
 	"errors"
-	"fmt"
 	"time"
 
-	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
-	scommon "github.com/polynetwork/poly/core/store/common"
 	"github.com/polynetwork/poly/core/types"
 	polyErrors "github.com/polynetwork/poly/errors"
-	"github.com/polynetwork/poly/native/service/governance/node_manager"
-	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
-	"github.com/polynetwork/poly/native/service/utils"
-	nutils "github.com/polynetwork/poly/native/service/utils"
 	tcomn "github.com/polynetwork/poly/txnpool/common"
 )
 
@@ -52,53 +44,13 @@ func SetTxnPoolPid(actr *actor.PID) {
 
 //append transaction to pool to txpool actor
 func AppendTxToPool(txn *types.Transaction) (polyErrors.ErrCode, string) {
-	// Get txn's signature addresses
-	addresses, err := txn.GetSignatureAddresses()
-	if err != nil {
-		return polyErrors.ErrUnknown, err.Error()
-	}
-
-	permittedAddrMap := make(map[common.Address]bool)
-	// flag is set to true, meaning not any address in the signed addresses is permitted.
-	flag := true
-	for _, address := range addresses {
-		key := append([]byte(relayer_manager.RELAYER), address[:]...)
-		value, err := GetStorageItem(utils.RelayerManagerContractAddress, key)
-		if err != nil {
-			if err != scommon.ErrNotFound {
-				return polyErrors.ErrUnknown, err.Error()
-			}
-		}
-		// Check if address is registreed relayer
-		if value != nil {
-			// Here means address is registered relayer
-			flag = false
-			break
-		}
-		// Check if permittedAddrMap is empty
-		if len(permittedAddrMap) == 0 {
-			// If empty, permittedAddrMap will be updated only one time
-			if err := UpdatePermittedAddrMap(permittedAddrMap); err != nil {
-				return polyErrors.ErrUnknown, err.Error()
-			}
-		}
-		// Check if address is included in permittedAddrMap, if so, the txn is permitted
-		if val, ok := permittedAddrMap[address]; val && ok {
-			flag = false
-			break
-		}
-	}
-	if flag {
-		// If flag is true, it means any address within addresses is not permitted address to send tx
-		return polyErrors.ErrUnknown, "address is not registered"
-	}
 	if DisableSyncVerifyTx {
 		txReq := &tcomn.TxReq{txn, tcomn.HttpSender, nil}
 		txnPid.Tell(txReq)
 		return polyErrors.ErrNoError, ""
 	}
 	//add Pre Execute Contract
-	_, err = PreExecuteContract(txn)
+	_, err := PreExecuteContract(txn)
 	if err != nil {
 		return polyErrors.ErrUnknown, err.Error()
 	}
@@ -177,47 +129,48 @@ func GetTxnCount() ([]uint32, error) {
 	return txnCnt.Count, nil
 }
 
-func UpdatePermittedAddrMap(permittedAddrMap map[common.Address]bool) error {
-	//get consensus node address
-	governanceViewBytes, err := GetStorageItem(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW))
+//GetTxnPoolStats from txnpool actor
+func GetTxnPoolStats(topSigners int) (*tcomn.PoolStats, error) {
+	future := txnPid.RequestFuture(&tcomn.GetTxnPoolStatsReq{TopSigners: topSigners}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
 	if err != nil {
-		return fmt.Errorf("UpdatePermittedAddrMap, get governance view bytes from storage error: %v", err)
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
 	}
-	governanceView := new(node_manager.GovernanceView)
-	err = governanceView.Deserialization(common.NewZeroCopySource(governanceViewBytes))
-	if err != nil {
-		return fmt.Errorf("UpdatePermittedAddrMap, governanceView.Deserialization error: %v", err)
+	rsp, ok := result.(*tcomn.GetTxnPoolStatsRsp)
+	if !ok {
+		return nil, errors.New("fail")
 	}
-	viewBytes := nutils.GetUint32Bytes(governanceView.View)
-	peerPoolMapBytes, err := GetStorageItem(utils.NodeManagerContractAddress, append([]byte(node_manager.PEER_POOL), viewBytes...))
+	return rsp.Stats, nil
+}
+
+//GetPoolTxs from txnpool actor
+func GetPoolTxs(filter *tcomn.TxFilter, offset, limit int) (int, []*tcomn.PoolTx, error) {
+	req := &tcomn.GetPoolTxsReq{Filter: filter, Offset: offset, Limit: limit}
+	future := txnPid.RequestFuture(req, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
 	if err != nil {
-		return fmt.Errorf("UpdatePermittedAddrMap, get peerPoolMap bytes from storage error: %v", err)
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return 0, nil, err
 	}
-	peerMap := &node_manager.PeerPoolMap{
-		PeerPoolMap: make(map[string]*node_manager.PeerPoolItem),
+	rsp, ok := result.(*tcomn.GetPoolTxsRsp)
+	if !ok {
+		return 0, nil, errors.New("fail")
 	}
-	err = peerMap.Deserialization(common.NewZeroCopySource(peerPoolMapBytes))
-	if err != nil {
-		return fmt.Errorf("UpdatePermittedAddrMap, peerPoolMap.Deserialization error: %v", err)
-	}
-	// Update permittedAddrMap
-	publicKeys := make([]keypair.PublicKey, 0)
-	for k := range peerMap.PeerPoolMap {
-		kb, err := hex.DecodeString(k)
-		if err != nil {
-			return fmt.Errorf("UpdatePermittedAddrMap, DecodeString PeerPoolMap public key error: %v", err)
-		}
-		pk, err := keypair.DeserializePublicKey(kb)
-		if err != nil {
-			return fmt.Errorf("UpdatePermittedAddrMap, DeserializePublicKey error: %v", err)
-		}
-		permittedAddrMap[types.AddressFromPubKey(pk)] = true
-		publicKeys = append(publicKeys, pk)
-	}
-	operatorAddress, err := types.AddressFromBookkeepers(publicKeys)
+	return rsp.Total, rsp.Txs, nil
+}
+
+//DropPoolTx from txnpool actor
+func DropPoolTx(hash common.Uint256) (bool, error) {
+	future := txnPid.RequestFuture(&tcomn.DropTxnReq{Hash: hash}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
 	if err != nil {
-		return fmt.Errorf("UpdatePermittedAddrMap, AddressFromBookkeepers error: %v", err)
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return false, err
+	}
+	rsp, ok := result.(*tcomn.DropTxnRsp)
+	if !ok {
+		return false, errors.New("fail")
 	}
-	permittedAddrMap[operatorAddress] = true
-	return nil
+	return rsp.Ok, nil
 }
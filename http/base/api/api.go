@@ -0,0 +1,112 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package api describes the methods served by the json rpc and restful servers,
+// the servers register their handlers from these descriptions and the discovery
+// and openapi documents are generated from the same descriptions
+package api
+
+import "regexp"
+
+//locations of restful params
+const (
+	IN_PATH  = "path"
+	IN_QUERY = "query"
+	IN_BODY  = "body"
+)
+
+//Param describes an input of a json rpc method or a restful route
+type Param struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+	Schema      Schema `json:"schema"`
+	In          string `json:"-"` //location of a restful param
+	Key         string `json:"-"` //key of a restful param in the command passed to the handler
+}
+
+//Method describes a json rpc method, params are listed in positional order
+type Method struct {
+	Name    string   `json:"name"`
+	Summary string   `json:"summary,omitempty"`
+	Params  []*Param `json:"params"`
+	Result  Schema   `json:"result"`
+	Write   bool     `json:"write,omitempty"`
+}
+
+//ParamNames returns the names of the positional params, used to resolve named params
+func (self *Method) ParamNames() []string {
+	names := make([]string, 0, len(self.Params))
+	for _, param := range self.Params {
+		names = append(names, param.Name)
+	}
+	return names
+}
+
+//Route describes a restful route, Path is the router pattern like /api/v1/block/height/:height
+type Route struct {
+	Action     string
+	HttpMethod string
+	Path       string
+	Summary    string
+	Params     []*Param
+	Result     Schema
+	Write      bool
+}
+
+var pathParamRegexp = regexp.MustCompile(`:(\w+)`)
+
+//OpenAPIPath converts the router pattern to an openapi path template like /api/v1/block/height/{height}
+func (self *Route) OpenAPIPath() string {
+	return pathParamRegexp.ReplaceAllString(self.Path, "{$1}")
+}
+
+//PathParams returns the names of the params in the router pattern
+func (self *Route) PathParams() []string {
+	names := make([]string, 0)
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(self.Path, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+//Discovery is the result of the rpc_discover method
+type Discovery struct {
+	Version string    `json:"version"`
+	Methods []*Method `json:"methods"`
+}
+
+//NewPathParam describes a param in the router pattern of a restful route
+func NewPathParam(name, key, description string, schema Schema) *Param {
+	return &Param{Name: name, Key: key, Description: description, Required: true, Schema: schema, In: IN_PATH}
+}
+
+//NewQueryParam describes an optional query param of a restful route
+func NewQueryParam(name, key, description string, schema Schema) *Param {
+	return &Param{Name: name, Key: key, Description: description, Schema: schema, In: IN_QUERY}
+}
+
+//NewBodyParam describes a field of the json body of a restful route
+func NewBodyParam(key, description string, required bool, schema Schema) *Param {
+	return &Param{Name: key, Key: key, Description: description, Required: required, Schema: schema, In: IN_BODY}
+}
+
+//NewParam describes a positional param of a json rpc method
+func NewParam(name, description string, required bool, schema Schema) *Param {
+	return &Param{Name: name, Description: description, Required: required, Schema: schema}
+}
+
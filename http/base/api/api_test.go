@@ -0,0 +1,104 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testInner struct {
+	Height uint32
+	Shadow string
+}
+
+type testNode struct {
+	testInner
+	Shadow   bool              `json:"shadow"`
+	Hash     string            `json:"hash"`
+	Raw      []byte            `json:",omitempty"`
+	Children []*testNode       `json:"children"`
+	Attrs    map[string]uint64 `json:"attrs"`
+	Key      [2]byte
+	Ignored  string `json:"-"`
+	private  int
+}
+
+func TestSchemaOf(t *testing.T) {
+	schema := SchemaOf(&testNode{})
+	assert.Equal(t, "object", schema["type"])
+	properties := schema["properties"].(map[string]Schema)
+	assert.Len(t, properties, 8)
+	assert.Equal(t, "integer", properties["Height"]["type"])
+	assert.Equal(t, "string", properties["Shadow"]["type"])
+	assert.Equal(t, "boolean", properties["shadow"]["type"])
+	assert.Equal(t, "string", properties["hash"]["type"])
+	assert.Equal(t, "string", properties["Raw"]["type"])
+	assert.Equal(t, "array", properties["children"]["type"])
+	assert.Equal(t, Any("api.testNode"), properties["children"]["items"])
+	assert.Equal(t, Integer(""), properties["attrs"]["additionalProperties"])
+	assert.Equal(t, 2, properties["Key"]["maxItems"])
+	assert.NotContains(t, properties, "Ignored")
+	assert.NotContains(t, properties, "private")
+
+	assert.Equal(t, Any(""), SchemaOf(nil))
+	assert.Equal(t, Array(String("")), SchemaOf([]string{}))
+}
+
+func TestRoute(t *testing.T) {
+	route := &Route{Action: "getmerkleproof", HttpMethod: http.MethodGet, Path: "/api/v1/merkleproof/:bheight/:rheight"}
+	assert.Equal(t, "/api/v1/merkleproof/{bheight}/{rheight}", route.OpenAPIPath())
+	assert.Equal(t, []string{"bheight", "rheight"}, route.PathParams())
+	assert.Equal(t, []string{}, (&Route{Path: "/api/v1/version"}).PathParams())
+}
+
+func TestOpenAPI(t *testing.T) {
+	routes := []*Route{
+		{Action: "getblockhash", HttpMethod: http.MethodGet, Path: "/api/v1/block/hash/:height",
+			Params: []*Param{NewPathParam("height", "Height", "block height", Integer(""))},
+			Result: Hex("")},
+		{Action: "sendrawtransaction", HttpMethod: http.MethodPost, Path: "/api/v1/transaction",
+			Params: []*Param{
+				NewBodyParam("Data", "transaction", true, Hex("")),
+				NewQueryParam("preExec", "PreExec", "", Integer("")),
+			},
+			Write: true},
+	}
+	doc := OpenAPI("test", "1.0.0", routes)
+	assert.Equal(t, OPENAPI_VERSION, doc["openapi"])
+	paths := doc["paths"].(map[string]map[string]interface{})
+	assert.Len(t, paths, 2)
+
+	get := paths["/api/v1/block/hash/{height}"]["get"].(map[string]interface{})
+	assert.Equal(t, "getblockhash", get["operationId"])
+	params := get["parameters"].([]map[string]interface{})
+	assert.Len(t, params, 1)
+	assert.Equal(t, IN_PATH, params[0]["in"])
+	assert.Equal(t, true, params[0]["required"])
+	assert.NotContains(t, get, "requestBody")
+
+	post := paths["/api/v1/transaction"]["post"].(map[string]interface{})
+	assert.Equal(t, true, post["x-write"])
+	params = post["parameters"].([]map[string]interface{})
+	assert.Len(t, params, 1)
+	assert.Equal(t, "preExec", params[0]["name"])
+	body := post["requestBody"].(map[string]interface{})
+	assert.Equal(t, true, body["required"])
+}
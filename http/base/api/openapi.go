@@ -0,0 +1,114 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"sort"
+	"strings"
+)
+
+const OPENAPI_VERSION = "3.0.3"
+
+//OpenAPI generates the openapi document of the restful routes, responses are wrapped
+//in the restful envelope with the route result as Result
+func OpenAPI(title, version string, routes []*Route) map[string]interface{} {
+	paths := make(map[string]map[string]interface{})
+	for _, route := range routes {
+		path := route.OpenAPIPath()
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+		paths[path][strings.ToLower(route.HttpMethod)] = operation(route)
+	}
+	return map[string]interface{}{
+		"openapi": OPENAPI_VERSION,
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": version,
+		},
+		"paths": paths,
+	}
+}
+
+func operation(route *Route) map[string]interface{} {
+	params := make([]map[string]interface{}, 0)
+	body := Schema{"type": "object", "properties": map[string]Schema{}}
+	required := make([]string, 0)
+	for _, param := range route.Params {
+		if param.In == IN_BODY {
+			body["properties"].(map[string]Schema)[param.Name] = param.Schema.Describe(param.Description)
+			if param.Required {
+				required = append(required, param.Name)
+			}
+			continue
+		}
+		params = append(params, map[string]interface{}{
+			"name":        param.Name,
+			"in":          param.In,
+			"description": param.Description,
+			"required":    param.Required,
+			"schema":      param.Schema,
+		})
+	}
+	op := map[string]interface{}{
+		"operationId": route.Action,
+		"summary":     route.Summary,
+		"parameters":  params,
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "Error is 0 on success, Desc describes the error otherwise",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": envelope(route)},
+				},
+			},
+		},
+	}
+	if len(body["properties"].(map[string]Schema)) > 0 {
+		if len(required) > 0 {
+			sort.Strings(required)
+			body["required"] = required
+		}
+		op["requestBody"] = map[string]interface{}{
+			"required": len(required) > 0,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": body},
+			},
+		}
+	}
+	if route.Write {
+		op["x-write"] = true
+	}
+	return op
+}
+
+func envelope(route *Route) Schema {
+	result := route.Result
+	if result == nil {
+		result = Any("")
+	}
+	return Schema{
+		"type": "object",
+		"properties": map[string]Schema{
+			"Action":  String("name of the action"),
+			"Desc":    String("description of the error code"),
+			"Error":   Integer("error code, 0 on success"),
+			"Result":  result,
+			"Version": String("version of the restful api"),
+		},
+	}
+}
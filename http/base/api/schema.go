@@ -0,0 +1,179 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+//Schema is a json schema object
+type Schema map[string]interface{}
+
+//Describe returns a copy of the schema with the description set
+func (self Schema) Describe(description string) Schema {
+	schema := make(Schema, len(self)+1)
+	for k, v := range self {
+		schema[k] = v
+	}
+	schema["description"] = description
+	return schema
+}
+
+func typed(typ, description string) Schema {
+	schema := Schema{"type": typ}
+	if description != "" {
+		schema["description"] = description
+	}
+	return schema
+}
+
+func String(description string) Schema {
+	return typed("string", description)
+}
+
+func Integer(description string) Schema {
+	return typed("integer", description)
+}
+
+func Number(description string) Schema {
+	return typed("number", description)
+}
+
+func Boolean(description string) Schema {
+	return typed("boolean", description)
+}
+
+//Hex is a hex encoded string, like a hash, an address or serialized bytes
+func Hex(description string) Schema {
+	schema := String(description)
+	schema["pattern"] = "^[0-9a-fA-F]*$"
+	return schema
+}
+
+func Array(items Schema) Schema {
+	return Schema{"type": "array", "items": items}
+}
+
+//Any matches every json value
+func Any(description string) Schema {
+	schema := Schema{}
+	if description != "" {
+		schema["description"] = description
+	}
+	return schema
+}
+
+//OneOf matches a value of any of the schemas
+func OneOf(description string, schemas ...Schema) Schema {
+	schema := Any(description)
+	schema["oneOf"] = schemas
+	return schema
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+//SchemaOf returns the schema of the json encoding of v, so results are documented from their go types
+func SchemaOf(v interface{}) Schema {
+	if v == nil {
+		return Any("")
+	}
+	return schemaOf(reflect.TypeOf(v), make(map[reflect.Type]bool))
+}
+
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) Schema {
+	if t.Implements(textMarshalerType) && !t.Implements(jsonMarshalerType) {
+		return String("")
+	}
+	if t.Implements(jsonMarshalerType) {
+		return Any(t.String())
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOf(t.Elem(), visiting)
+	case reflect.Bool:
+		return Boolean("")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer("")
+	case reflect.Float32, reflect.Float64:
+		return Number("")
+	case reflect.String:
+		return String("")
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return typed("string", "base64 encoded bytes")
+		}
+		return Array(schemaOf(t.Elem(), visiting))
+	case reflect.Array:
+		schema := Array(schemaOf(t.Elem(), visiting))
+		schema["minItems"], schema["maxItems"] = t.Len(), t.Len()
+		return schema
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": schemaOf(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return Any(t.String())
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		properties := make(map[string]Schema)
+		addFields(t, properties, visiting, false)
+		return Schema{"type": "object", "properties": properties}
+	default:
+		return Any("")
+	}
+}
+
+//addFields adds the fields encoded by encoding/json, fields of embedded structs are promoted
+//unless a field of the outer struct has the same name
+func addFields(t reflect.Type, properties map[string]Schema, visiting map[reflect.Type]bool, promoted bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addFields(ft, properties, visiting, true)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := properties[name]; ok && promoted {
+			continue
+		}
+		properties[name] = schemaOf(field.Type, visiting)
+	}
+}
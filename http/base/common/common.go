@@ -20,9 +20,11 @@
 package common
 
 import (
+
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/genesis"
 	"github.com/polynetwork/poly/core/types"
 	ontErrors "github.com/polynetwork/poly/errors"
 	bactor "github.com/polynetwork/poly/http/base/actor"
@@ -61,6 +63,12 @@ type PreExecuteResult struct {
 	Notify []NotifyEventInfo
 }
 
+//CallNativeResult is the result of a native method run read-only, Error is set when the method failed
+type CallNativeResult struct {
+	PreExecuteResult
+	Error string `json:",omitempty"`
+}
+
 type NotifyEventInfo struct {
 	ContractAddress string
 	States          interface{}
@@ -117,6 +125,12 @@ type BlockHead struct {
 	Hash string
 }
 
+type BlockTransactions struct {
+	Hash         string
+	Height       uint32
+	Transactions []string
+}
+
 type BlockInfo struct {
 	Hash         string
 	Size         int
@@ -170,6 +184,21 @@ func ConvertPreExecuteResult(obj *cstate.PreExecResult) PreExecuteResult {
 	return PreExecuteResult{obj.State, obj.Result, evts}
 }
 
+//CallNative runs method of the native contract at contract against current state in pre-exec mode.
+//The transaction carries no signature, so methods checking witness fail
+func CallNative(contract common.Address, method string, args []byte) (result CallNativeResult) {
+	sink := common.NewZeroCopySink(nil)
+	param := &cstate.ContractInvokeParam{Address: contract, Method: method, Args: args}
+	param.Serialization(sink)
+	tx := genesis.NewInvokeTransaction(sink.Bytes(), 0)
+	rst, err := bactor.PreExecuteContract(tx)
+	result.PreExecuteResult = ConvertPreExecuteResult(rst)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return
+}
+
 func SendTxToPool(txn *types.Transaction) (ontErrors.ErrCode, string) {
 	if errCode, desc := bactor.AppendTxToPool(txn); errCode != ontErrors.ErrNoError {
 		log.Warn("TxnPool verify error:", errCode.Error())
@@ -253,11 +282,6 @@ func GetBlockTransactions(block *types.Block) interface{} {
 		trans[i] = t.ToHexString()
 	}
 	hash := block.Hash()
-	type BlockTransactions struct {
-		Hash         string
-		Height       uint32
-		Transactions []string
-	}
 	b := BlockTransactions{
 		Hash:         hash.ToHexString(),
 		Height:       block.Header.Height,
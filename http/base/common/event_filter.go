@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+//position of the source and target chain ids in the states of cross chain events, -1 if absent
+type eventLayout struct {
+	fromChainId int
+	toChainId   int
+}
+
+//cross chain events of the native contracts, the event name is always the first state
+var eventLayouts = map[string]eventLayout{
+	"makeProof":         {fromChainId: 1, toChainId: 2},
+	"btcTxToRelay":      {fromChainId: 1, toChainId: 2},
+	"syncHeader":        {fromChainId: 1, toChainId: -1},
+	"syncCrossChainMsg": {fromChainId: 1, toChainId: -1},
+}
+
+//EventFilter selects notify events, an event is kept if it matches all the non empty filters
+type EventFilter struct {
+	Contracts    []string
+	EventNames   []string
+	FromChainIds []uint64
+	ToChainIds   []uint64
+}
+
+//IsEmpty returns whether all events are kept
+func (self *EventFilter) IsEmpty() bool {
+	return len(self.Contracts) == 0 && len(self.EventNames) == 0 &&
+		len(self.FromChainIds) == 0 && len(self.ToChainIds) == 0
+}
+
+//Filter returns the events of notify matching the filter, false if there is none
+func (self *EventFilter) Filter(notify ExecuteNotify) (ExecuteNotify, bool) {
+	if self.IsEmpty() {
+		return notify, true
+	}
+	filtered := notify
+	filtered.Notify = make([]NotifyEventInfo, 0, len(notify.Notify))
+	for _, evt := range notify.Notify {
+		if self.Match(evt) {
+			filtered.Notify = append(filtered.Notify, evt)
+		}
+	}
+	return filtered, len(filtered.Notify) > 0
+}
+
+//Match returns whether evt matches the filter
+func (self *EventFilter) Match(evt NotifyEventInfo) bool {
+	if len(self.Contracts) > 0 && !containsString(self.Contracts, evt.ContractAddress) {
+		return false
+	}
+	if len(self.EventNames) == 0 && len(self.FromChainIds) == 0 && len(self.ToChainIds) == 0 {
+		return true
+	}
+	name, fromChainId, toChainId := GetEventInfo(evt.States)
+	if len(self.EventNames) > 0 && !containsString(self.EventNames, name) {
+		return false
+	}
+	if len(self.FromChainIds) > 0 && (fromChainId == nil || !containsUint64(self.FromChainIds, *fromChainId)) {
+		return false
+	}
+	if len(self.ToChainIds) > 0 && (toChainId == nil || !containsUint64(self.ToChainIds, *toChainId)) {
+		return false
+	}
+	return true
+}
+
+//GetEventInfo decodes the name of an event from its states, and the chain ids of the cross chain events
+func GetEventInfo(states interface{}) (name string, fromChainId, toChainId *uint64) {
+	list, ok := states.([]interface{})
+	if !ok || len(list) == 0 {
+		return "", nil, nil
+	}
+	name, _ = list[0].(string)
+	layout, ok := eventLayouts[name]
+	if !ok {
+		return name, nil, nil
+	}
+	return name, chainIdAt(list, layout.fromChainId), chainIdAt(list, layout.toChainId)
+}
+
+func chainIdAt(states []interface{}, index int) *uint64 {
+	if index < 0 || index >= len(states) {
+		return nil
+	}
+	chainId, ok := toUint64(states[index])
+	if !ok {
+		return nil
+	}
+	return &chainId
+}
+
+//toUint64 accepts the states of live events as well as the ones decoded from the event store
+func toUint64(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case uint64:
+		return n, true
+	case uint32:
+		return uint64(n), true
+	case int:
+		return uint64(n), n >= 0
+	case int64:
+		return uint64(n), n >= 0
+	case float64:
+		return uint64(n), n >= 0 && n == float64(uint64(n))
+	default:
+		return 0, false
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint64(list []uint64, n uint64) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
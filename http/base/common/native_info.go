@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"sort"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+)
+
+type SideChainInfo struct {
+	Address      string
+	ChainId      uint64
+	Router       uint64
+	Name         string
+	BlocksToWait uint64
+	CCMCAddress  string
+	ExtraInfo    string
+}
+
+type PeerPoolItemInfo struct {
+	Index      uint32
+	PeerPubkey string
+	Address    string
+	Status     uint8
+}
+
+type GovernanceViewInfo struct {
+	View   uint32
+	Height uint32
+	TxHash string
+}
+
+type ProposalInfo struct {
+	ID           uint64
+	Proposer     string
+	Contract     string
+	Method       string
+	Input        string
+	Votes        []string
+	Rejects      []string
+	Height       uint32
+	ExpiryHeight uint32
+	Expired      bool
+}
+
+type HeaderSyncTip struct {
+	ChainId uint64
+	Height  uint64
+}
+
+func GetSideChainInfo(sideChain *side_chain_manager.SideChain) *SideChainInfo {
+	return &SideChainInfo{
+		Address:      sideChain.Address.ToBase58(),
+		ChainId:      sideChain.ChainId,
+		Router:       sideChain.Router,
+		Name:         sideChain.Name,
+		BlocksToWait: sideChain.BlocksToWait,
+		CCMCAddress:  common.ToHexString(sideChain.CCMCAddress),
+		ExtraInfo:    common.ToHexString(sideChain.ExtraInfo),
+	}
+}
+
+func GetSideChainInfos(sideChains []*side_chain_manager.SideChain) []*SideChainInfo {
+	infos := make([]*SideChainInfo, 0, len(sideChains))
+	for _, sideChain := range sideChains {
+		infos = append(infos, GetSideChainInfo(sideChain))
+	}
+	return infos
+}
+
+//GetPeerPoolInfo returns the peers of the pool in the order of their index
+func GetPeerPoolInfo(peerPoolMap *node_manager.PeerPoolMap) []*PeerPoolItemInfo {
+	infos := make([]*PeerPoolItemInfo, 0, len(peerPoolMap.PeerPoolMap))
+	for _, item := range peerPoolMap.PeerPoolMap {
+		infos = append(infos, &PeerPoolItemInfo{
+			Index:      item.Index,
+			PeerPubkey: item.PeerPubkey,
+			Address:    item.Address.ToBase58(),
+			Status:     uint8(item.Status),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Index < infos[j].Index
+	})
+	return infos
+}
+
+func GetGovernanceViewInfo(view *node_manager.GovernanceView) *GovernanceViewInfo {
+	return &GovernanceViewInfo{
+		View:   view.View,
+		Height: view.Height,
+		TxHash: view.TxHash.ToHexString(),
+	}
+}
+
+//GetProposalInfos returns the open proposals, which are expired if not voted before height
+func GetProposalInfos(proposals []*node_manager.Proposal, height uint32) []*ProposalInfo {
+	infos := make([]*ProposalInfo, 0, len(proposals))
+	for _, proposal := range proposals {
+		infos = append(infos, GetProposalInfo(proposal, height))
+	}
+	return infos
+}
+
+func GetProposalInfo(proposal *node_manager.Proposal, height uint32) *ProposalInfo {
+	return &ProposalInfo{
+		ID:           proposal.ID,
+		Proposer:     proposal.Proposer.ToBase58(),
+		Contract:     proposal.Contract.ToHexString(),
+		Method:       proposal.Method,
+		Input:        common.ToHexString(proposal.Input),
+		Votes:        GetAddresses(proposal.Votes),
+		Rejects:      GetAddresses(proposal.Rejects),
+		Height:       proposal.Height,
+		ExpiryHeight: proposal.ExpiryHeight,
+		Expired:      proposal.Expired(height),
+	}
+}
+
+func GetAddresses(addrs []common.Address) []string {
+	strs := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		strs = append(strs, addr.ToBase58())
+	}
+	return strs
+}
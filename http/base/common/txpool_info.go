@@ -0,0 +1,135 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"github.com/polynetwork/poly/common"
+	tcomn "github.com/polynetwork/poly/txnpool/common"
+)
+
+const (
+	DEFAULT_TOP_SIGNERS  = 10   //signers with the most transactions in the pool stats
+	DEFAULT_MEMPOOL_PAGE = 100  //transactions in a page of the pool by default
+	MAX_MEMPOOL_PAGE     = 1000 //max transactions in a page of the pool
+)
+
+type TxPoolPriorityInfo struct {
+	Priority uint8
+	Count    int
+	Size     uint64
+}
+
+type TxPoolSignerInfo struct {
+	Signer string
+	Count  uint
+}
+
+type TxPoolStatsInfo struct {
+	Count           int
+	Size            uint64
+	MaxSize         uint64
+	MaxTxsPerSigner uint
+	Signers         int
+	Priorities      []*TxPoolPriorityInfo
+	TopSigners      []*TxPoolSignerInfo
+	Evicted         uint64
+	SignerRejected  uint64
+	FullRejected    uint64
+	Replaced        uint64
+}
+
+func GetTxPoolStatsInfo(stats *tcomn.PoolStats) *TxPoolStatsInfo {
+	info := &TxPoolStatsInfo{
+		Count:           stats.Count,
+		Size:            stats.Size,
+		MaxSize:         stats.MaxSize,
+		MaxTxsPerSigner: stats.MaxTxsPerSigner,
+		Signers:         stats.Signers,
+		Priorities:      make([]*TxPoolPriorityInfo, 0, len(stats.Priorities)),
+		TopSigners:      make([]*TxPoolSignerInfo, 0, len(stats.TopSigners)),
+		Evicted:         stats.Evicted,
+		SignerRejected:  stats.SignerRejected,
+		FullRejected:    stats.FullRejected,
+		Replaced:        stats.Replaced,
+	}
+	for _, prio := range stats.Priorities {
+		info.Priorities = append(info.Priorities, &TxPoolPriorityInfo{
+			Priority: prio.Priority,
+			Count:    prio.Count,
+			Size:     prio.Size,
+		})
+	}
+	for _, signer := range stats.TopSigners {
+		info.TopSigners = append(info.TopSigners, &TxPoolSignerInfo{
+			Signer: signer.Signer.ToBase58(),
+			Count:  signer.Count,
+		})
+	}
+	return info
+}
+
+type MemPoolTxInfo struct {
+	Hash     string
+	Signer   string
+	Contract string
+	Method   string
+	Priority uint8
+	Size     int
+	Arrival  int64 //unix time the transaction entered the pool
+	State    []TXNAttrInfo
+	Tx       *Transactions
+}
+
+type MemPoolInfo struct {
+	Total  int
+	Offset int
+	Txs    []*MemPoolTxInfo
+}
+
+func GetMemPoolInfo(total, offset int, txs []*tcomn.PoolTx) *MemPoolInfo {
+	info := &MemPoolInfo{
+		Total:  total,
+		Offset: offset,
+		Txs:    make([]*MemPoolTxInfo, 0, len(txs)),
+	}
+	for _, t := range txs {
+		hash := t.Tx.Hash()
+		txInfo := &MemPoolTxInfo{
+			Hash:     hash.ToHexString(),
+			Signer:   t.Signer.ToBase58(),
+			Method:   t.Method,
+			Priority: t.Priority,
+			Size:     len(t.Tx.Raw),
+			Arrival:  t.Arrival.Unix(),
+			State:    make([]TXNAttrInfo, 0, len(t.Attrs)),
+			Tx:       TransArryByteToHexString(t.Tx),
+		}
+		if t.Contract != common.ADDRESS_EMPTY {
+			txInfo.Contract = t.Contract.ToHexString()
+		}
+		for _, attr := range t.Attrs {
+			txInfo.State = append(txInfo.State, TXNAttrInfo{
+				Height:  attr.Height,
+				Type:    int(attr.Type),
+				ErrCode: int(attr.ErrCode),
+			})
+		}
+		info.Txs = append(info.Txs, txInfo)
+	}
+	return info
+}
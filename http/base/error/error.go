@@ -27,6 +27,10 @@ const (
 	SERVICE_CEILING    int64 = 41002
 	ILLEGAL_DATAFORMAT int64 = 41003
 	INVALID_VERSION    int64 = 41004
+	UNAUTHORIZED       int64 = 41005
+	FORBIDDEN          int64 = 41006
+	TOO_MANY_REQUESTS  int64 = 41007
+	REQUEST_TOO_LARGE  int64 = 41008
 
 	INVALID_METHOD int64 = 42001
 	INVALID_PARAMS int64 = 42002
@@ -43,6 +47,11 @@ const (
 	INTERNAL_ERROR  int64 = 45001
 	SMARTCODE_ERROR int64 = 47001
 	PRE_EXEC_ERROR  int64 = 47002
+
+	JSONRPC_PARSE_ERROR      int64 = -32700
+	JSONRPC_INVALID_REQUEST  int64 = -32600
+	JSONRPC_METHOD_NOT_FOUND int64 = -32601
+	JSONRPC_INVALID_PARAMS   int64 = -32602
 )
 
 var ErrMap = map[int64]string{
@@ -51,6 +60,10 @@ var ErrMap = map[int64]string{
 	SERVICE_CEILING:    "SERVICE CEILING",
 	ILLEGAL_DATAFORMAT: "ILLEGAL DATAFORMAT",
 	INVALID_VERSION:    "INVALID VERSION",
+	UNAUTHORIZED:       "UNAUTHORIZED",
+	FORBIDDEN:          "FORBIDDEN",
+	TOO_MANY_REQUESTS:  "TOO MANY REQUESTS",
+	REQUEST_TOO_LARGE:  "REQUEST TOO LARGE",
 
 	INVALID_METHOD: "INVALID METHOD",
 	INVALID_PARAMS: "INVALID PARAMS",
@@ -67,6 +80,10 @@ var ErrMap = map[int64]string{
 	INTERNAL_ERROR:                           "INTERNAL ERROR",
 	SMARTCODE_ERROR:                          "SMARTCODE EXEC ERROR",
 	PRE_EXEC_ERROR:                           "SMARTCODE PREPARE EXEC ERROR",
+	JSONRPC_PARSE_ERROR:                      "Parse error",
+	JSONRPC_INVALID_REQUEST:                  "Invalid Request",
+	JSONRPC_METHOD_NOT_FOUND:                 "Method not found",
+	JSONRPC_INVALID_PARAMS:                   "Invalid params",
 	int64(ontErrors.ErrNoCode):               "INTERNAL ERROR, ErrNoCode",
 	int64(ontErrors.ErrUnknown):              "INTERNAL ERROR, ErrUnknown",
 	int64(ontErrors.ErrDuplicatedTx):         "INTERNAL ERROR, ErrDuplicatedTx",
@@ -29,6 +29,11 @@ import (
 	bactor "github.com/polynetwork/poly/http/base/actor"
 	bcomn "github.com/polynetwork/poly/http/base/common"
 	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/native/service/cross_chain_manager"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync"
 	"strconv"
 )
 
@@ -415,6 +420,25 @@ func GetMemPoolTxCount(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+//get statistics of the verified transactions in the memory pool
+func GetMemPoolStats(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	top := bcomn.DEFAULT_TOP_SIGNERS
+	if str, ok := cmd["Top"].(string); ok && str != "" {
+		t, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			return ResponsePack(berr.INVALID_PARAMS)
+		}
+		top = int(t)
+	}
+	stats, err := bactor.GetTxnPoolStats(top)
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetTxPoolStatsInfo(stats)
+	return resp
+}
+
 //get memory poll transaction state
 func GetMemPoolTxState(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
@@ -437,3 +461,212 @@ func GetMemPoolTxState(cmd map[string]interface{}) map[string]interface{} {
 	resp["Result"] = bcomn.TXNEntryInfo{attrs}
 	return resp
 }
+
+func getChainId(cmd map[string]interface{}) (uint64, bool) {
+	param, ok := cmd["ChainId"].(string)
+	if !ok || len(param) == 0 {
+		return 0, false
+	}
+	chainId, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return chainId, true
+}
+
+//get registered side chains
+func GetSideChains(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	sideChains, err := side_chain_manager.GetSideChains(ns)
+	if err != nil {
+		log.Errorf("GetSideChains error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetSideChainInfos(sideChains)
+	return resp
+}
+
+//get registered side chain by chain id
+func GetSideChain(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	chainId, ok := getChainId(cmd)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	sideChain, err := side_chain_manager.GetSideChain(ns, chainId)
+	if err != nil {
+		log.Errorf("GetSideChain error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	if sideChain == nil {
+		return resp
+	}
+	resp["Result"] = bcomn.GetSideChainInfo(sideChain)
+	return resp
+}
+
+//get registered relayers
+func GetRelayers(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	relayers, err := relayer_manager.GetRelayers(ns)
+	if err != nil {
+		log.Errorf("GetRelayers error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetAddresses(relayers)
+	return resp
+}
+
+//get consensus peer pool of current governance view
+func GetPeerPool(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	view, err := node_manager.GetGovernanceView(ns)
+	if err != nil {
+		log.Errorf("GetPeerPool error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	peerPoolMap, err := node_manager.GetPeerPoolMap(ns, view.View)
+	if err != nil {
+		log.Errorf("GetPeerPool error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetPeerPoolInfo(peerPoolMap)
+	return resp
+}
+
+//get current governance view
+func GetGovernanceView(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	view, err := node_manager.GetGovernanceView(ns)
+	if err != nil {
+		log.Errorf("GetGovernanceView error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetGovernanceViewInfo(view)
+	return resp
+}
+
+//get open governance proposals
+func GetProposals(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	proposals, err := node_manager.GetProposals(ns)
+	if err != nil {
+		log.Errorf("GetProposals error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = bcomn.GetProposalInfos(proposals, ns.GetHeight())
+	return resp
+}
+
+//get open governance proposal by id
+func GetProposal(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	param, ok := cmd["Id"].(string)
+	if !ok || len(param) == 0 {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	id, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	proposal, err := node_manager.GetProposal(ns, id)
+	if err != nil {
+		log.Errorf("GetProposal error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	if proposal == nil {
+		return resp
+	}
+	resp["Result"] = bcomn.GetProposalInfo(proposal, ns.GetHeight())
+	return resp
+}
+
+//get ids of blacked chains
+func GetBlackedChains(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	chainIds, err := cross_chain_manager.GetBlackedChains(ns)
+	if err != nil {
+		log.Errorf("GetBlackedChains error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	resp["Result"] = chainIds
+	return resp
+}
+
+//get height of latest synced header of each side chain, side chains without synced headers are left out
+func GetHeaderSyncTips(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	sideChains, err := side_chain_manager.GetSideChains(ns)
+	if err != nil {
+		log.Errorf("GetHeaderSyncTips error: %s", err)
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	tips := make([]*bcomn.HeaderSyncTip, 0, len(sideChains))
+	for _, sideChain := range sideChains {
+		height, err := header_sync.GetCurrentHeight(ns, sideChain.ChainId)
+		if err != nil {
+			log.Debugf("GetHeaderSyncTips, chain %d: %s", sideChain.ChainId, err)
+			continue
+		}
+		tips = append(tips, &bcomn.HeaderSyncTip{ChainId: sideChain.ChainId, Height: height})
+	}
+	resp["Result"] = tips
+	return resp
+}
+
+//get height of latest synced header of side chain
+func GetHeaderSyncTip(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	chainId, ok := getChainId(cmd)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return ResponsePack(berr.INTERNAL_ERROR)
+	}
+	height, err := header_sync.GetCurrentHeight(ns, chainId)
+	if err != nil {
+		resp = ResponsePack(berr.UNKNOWN_BLOCK)
+		resp["Result"] = err.Error()
+		return resp
+	}
+	resp["Result"] = &bcomn.HeaderSyncTip{ChainId: chainId, Height: height}
+	return resp
+}
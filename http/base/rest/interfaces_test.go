@@ -0,0 +1,87 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/consensus/vbft/harness"
+	"github.com/polynetwork/poly/core/ledger"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/stretchr/testify/assert"
+)
+
+//useGenesisLedger serves the genesis ledger of a harness node
+func useGenesisLedger(t *testing.T) *harness.Cluster {
+	cluster, err := harness.NewCluster(&harness.Config{Nodes: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := ledger.DefLedger
+	ledger.DefLedger = cluster.Nodes[0].Ledger
+	t.Cleanup(func() {
+		ledger.DefLedger = old
+		cluster.Stop()
+	})
+	return cluster
+}
+
+func TestGovernanceState(t *testing.T) {
+	useGenesisLedger(t)
+
+	resp := GetGovernanceView(nil)
+	assert.Equal(t, berr.SUCCESS, resp["Error"])
+	view := resp["Result"].(*bcomn.GovernanceViewInfo)
+	assert.Equal(t, uint32(0), view.Height)
+
+	resp = GetPeerPool(nil)
+	assert.Equal(t, berr.SUCCESS, resp["Error"])
+	peers := resp["Result"].([]*bcomn.PeerPoolItemInfo)
+	assert.Equal(t, 1, len(peers))
+
+	resp = GetSideChains(nil)
+	assert.Equal(t, berr.SUCCESS, resp["Error"])
+	assert.Empty(t, resp["Result"])
+
+	resp = GetRelayers(nil)
+	assert.Equal(t, berr.SUCCESS, resp["Error"])
+	assert.Empty(t, resp["Result"])
+
+	resp = GetBlackedChains(nil)
+	assert.Equal(t, berr.SUCCESS, resp["Error"])
+	assert.Empty(t, resp["Result"])
+
+	resp = GetHeaderSyncTips(nil)
+	assert.Equal(t, berr.SUCCESS, resp["Error"])
+	assert.Empty(t, resp["Result"])
+}
+
+func TestSideChainParams(t *testing.T) {
+	useGenesisLedger(t)
+
+	resp := GetSideChain(map[string]interface{}{"ChainId": "x"})
+	assert.Equal(t, berr.INVALID_PARAMS, resp["Error"])
+
+	resp = GetSideChain(map[string]interface{}{"ChainId": "2"})
+	assert.Equal(t, berr.SUCCESS, resp["Error"])
+	assert.Equal(t, "", resp["Result"])
+
+	resp = GetHeaderSyncTip(map[string]interface{}{"ChainId": "2"})
+	assert.Equal(t, berr.UNKNOWN_BLOCK, resp["Error"])
+}
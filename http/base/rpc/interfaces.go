@@ -21,6 +21,7 @@ package rpc
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
@@ -30,8 +31,13 @@ import (
 	"github.com/polynetwork/poly/core/types"
 	ontErrors "github.com/polynetwork/poly/errors"
 	bactor "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/http/base/api"
 	bcomn "github.com/polynetwork/poly/http/base/common"
 	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync"
+	tcomn "github.com/polynetwork/poly/txnpool/common"
 )
 
 //get best block hash
@@ -98,6 +104,29 @@ func GetLatestBlockMsgsSnap(params []interface{}) map[string]interface{} {
 	return responseSuccess(result)
 }
 
+// get consensus round trace by height
+// A JSON example for getconsensustrace method as following:
+//   {"jsonrpc": "2.0", "method": "getconsensustrace", "params": [1], "id": 0}
+func GetConsensusTrace(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	switch params[0].(type) {
+	case float64:
+		height := uint32(params[0].(float64))
+		trace, err := bactor.GetConsensusTrace(height)
+		if err != nil {
+			return responsePack(berr.INTERNAL_ERROR, "")
+		}
+		if trace == nil {
+			return responsePack(berr.UNKNOWN_BLOCK, "")
+		}
+		return responseSuccess(trace)
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+}
+
 //get block hash
 // A JSON example for getblockhash method as following:
 //   {"jsonrpc": "2.0", "method": "getblockhash", "params": [1], "id": 0}
@@ -149,6 +178,78 @@ func GetMemPoolTxCount(params []interface{}) map[string]interface{} {
 	return responseSuccess(count)
 }
 
+//get statistics of the verified transactions in the memory pool
+func GetMemPoolStats(params []interface{}) map[string]interface{} {
+	top := bcomn.DEFAULT_TOP_SIGNERS
+	if len(params) >= 1 {
+		switch t := params[0].(type) {
+		case float64:
+			if t < 0 {
+				return responsePack(berr.INVALID_PARAMS, nil)
+			}
+			top = int(t)
+		default:
+			return responsePack(berr.INVALID_PARAMS, nil)
+		}
+	}
+	stats, err := bactor.GetTxnPoolStats(top)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, nil)
+	}
+	return responseSuccess(bcomn.GetTxPoolStatsInfo(stats))
+}
+
+//get the verified transactions in memory pool page by page
+// A JSON example for getmempool method as following:
+//   {"jsonrpc": "2.0", "method": "getmempool", "params": [offset, limit, "signer", "contract", "method"], "id": 0}
+//the params are optional, the empty strings match every transaction
+func GetMemPool(params []interface{}) map[string]interface{} {
+	offset, limit := 0, bcomn.DEFAULT_MEMPOOL_PAGE
+	filter := &tcomn.TxFilter{}
+	for i, param := range params {
+		var err error
+		switch i {
+		case 0, 1:
+			num, ok := param.(float64)
+			if !ok || num < 0 {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			if i == 0 {
+				offset = int(num)
+			} else {
+				limit = int(num)
+			}
+		case 2, 3, 4:
+			str, ok := param.(string)
+			if !ok {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			if str == "" {
+				continue
+			}
+			switch i {
+			case 2:
+				filter.Signer, err = bcomn.GetAddress(str)
+			case 3:
+				filter.Contract, err = tcomn.ParseContract(str)
+			case 4:
+				filter.Method = str
+			}
+		}
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	if limit == 0 || limit > bcomn.MAX_MEMPOOL_PAGE {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	total, txs, err := bactor.GetPoolTxs(filter, offset, limit)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, nil)
+	}
+	return responseSuccess(bcomn.GetMemPoolInfo(total, offset, txs))
+}
+
 //get memory pool transaction state
 func GetMemPoolTxState(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
@@ -307,6 +408,38 @@ func SendRawTransaction(params []interface{}) map[string]interface{} {
 	return responseSuccess(hash.ToHexString())
 }
 
+//call native contract method read-only, without a signed transaction
+//   {"jsonrpc": "2.0", "method": "callnative", "params": ["contract address", "method", "args hex"], "id": 0}
+func CallNative(params []interface{}) map[string]interface{} {
+	if len(params) < 2 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	contract, err := bcomn.GetAddress(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	method, ok := params[1].(string)
+	if !ok || len(method) == 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var args []byte
+	if len(params) > 2 && params[2] != nil {
+		str, ok := params[2].(string)
+		if !ok {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		args, err = common.HexToBytes(str)
+		if err != nil {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	return responseSuccess(bcomn.CallNative(contract, method, args))
+}
+
 //get node version
 func GetNodeVersion(params []interface{}) map[string]interface{} {
 	return responseSuccess(config.Version)
@@ -317,6 +450,13 @@ func GetNetworkId(params []interface{}) map[string]interface{} {
 	return responseSuccess(config.DefConfig.P2PNode.NetworkId)
 }
 
+//list the registered methods with their params and result schemas
+// A JSON example for rpc_discover method as following:
+//   {"jsonrpc": "2.0", "method": "rpc_discover", "params": [], "id": 0}
+func Discover(params []interface{}) map[string]interface{} {
+	return responseSuccess(&api.Discovery{Version: config.Version, Methods: mainMux.methods()})
+}
+
 //get smartconstract event
 func GetSmartCodeEvent(params []interface{}) map[string]interface{} {
 	if !config.DefConfig.Common.EnableEventLog {
@@ -437,10 +577,19 @@ func GetCrossStatesProof(params []interface{}) map[string]interface{} {
 	return responseSuccess(bcomn.MerkleProof{"CrossStatesProof", hex.EncodeToString(proof)})
 }
 
+//get header by height, with a chain id it is the header synced from that side chain
+//   {"jsonrpc": "2.0", "method": "getheaderbyheight", "params": [100], "id": 0}
+//   {"jsonrpc": "2.0", "method": "getheaderbyheight", "params": [2, 100], "id": 0}
 func GetHeaderByHeight(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
 	}
+	if len(params) >= 2 {
+		if params[0] != nil {
+			return getSideChainHeaderByHeight(params[0], params[1])
+		}
+		params = params[1:]
+	}
 	height, ok := params[0].(float64)
 	if !ok {
 		return responsePack(berr.INVALID_PARAMS, "")
@@ -453,6 +602,121 @@ func GetHeaderByHeight(params []interface{}) map[string]interface{} {
 	return responseSuccess(hex.EncodeToString(header.ToArray()))
 }
 
+func getSideChainHeaderByHeight(chainIdParam, heightParam interface{}) map[string]interface{} {
+	chainId, ok := getUint64(chainIdParam)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	height, ok := getUint64(heightParam)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	header, err := header_sync.GetHeaderByHeight(ns, chainId, height)
+	if err != nil {
+		return responsePack(berr.UNKNOWN_BLOCK, err.Error())
+	}
+	return responseSuccess(header)
+}
+
+//get registered side chain by chain id
+//   {"jsonrpc": "2.0", "method": "getsidechain", "params": [2], "id": 0}
+func GetSideChain(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	chainId, ok := getUint64(params[0])
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	sideChain, err := side_chain_manager.GetSideChain(ns, chainId)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	if sideChain == nil {
+		return responseSuccess(nil)
+	}
+	return responseSuccess(bcomn.GetSideChainInfo(sideChain))
+}
+
+//list registered side chains
+func ListSideChains(params []interface{}) map[string]interface{} {
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	sideChains, err := side_chain_manager.GetSideChains(ns)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(bcomn.GetSideChainInfos(sideChains))
+}
+
+//get open governance proposal by id
+//   {"jsonrpc": "2.0", "method": "getproposal", "params": [1], "id": 0}
+func GetProposal(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	id, ok := getUint64(params[0])
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	proposal, err := node_manager.GetProposal(ns, id)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	if proposal == nil {
+		return responseSuccess(nil)
+	}
+	return responseSuccess(bcomn.GetProposalInfo(proposal, ns.GetHeight()))
+}
+
+//list open governance proposals
+func ListProposals(params []interface{}) map[string]interface{} {
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	proposals, err := node_manager.GetProposals(ns)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responseSuccess(bcomn.GetProposalInfos(proposals, ns.GetHeight()))
+}
+
+//get height of latest header synced from side chain
+//   {"jsonrpc": "2.0", "method": "getheadersynctip", "params": [2], "id": 0}
+func GetHeaderSyncTip(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, nil)
+	}
+	chainId, ok := getUint64(params[0])
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	ns, err := bactor.NewQueryNativeService()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	height, err := header_sync.GetCurrentHeight(ns, chainId)
+	if err != nil {
+		return responsePack(berr.UNKNOWN_BLOCK, err.Error())
+	}
+	return responseSuccess(&bcomn.HeaderSyncTip{ChainId: chainId, Height: height})
+}
+
 //get block transactions by height
 func GetBlockTxsByHeight(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
@@ -496,3 +760,11 @@ func GetStateMerkleRoot(params []interface{}) map[string]interface{} {
 	}
 
 }
+
+func getUint64(param interface{}) (uint64, bool) {
+	value, ok := param.(float64)
+	if !ok || value < 0 || value > math.MaxUint64 || value != math.Trunc(value) {
+		return 0, false
+	}
+	return uint64(value), true
+}
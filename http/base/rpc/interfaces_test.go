@@ -0,0 +1,284 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/consensus/vbft/harness"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/types"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	"github.com/polynetwork/poly/native/service/header_sync"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	tcomn "github.com/polynetwork/poly/txnpool/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func useGenesisLedger(t *testing.T) {
+	cluster, err := harness.NewCluster(&harness.Config{Nodes: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := ledger.DefLedger
+	ledger.DefLedger = cluster.Nodes[0].Ledger
+	t.Cleanup(func() {
+		ledger.DefLedger = old
+		cluster.Stop()
+	})
+}
+
+func TestSideChainQueries(t *testing.T) {
+	useGenesisLedger(t)
+
+	resp := ListSideChains(nil)
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	assert.Empty(t, resp["result"])
+
+	resp = GetSideChain([]interface{}{float64(2)})
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	assert.Nil(t, resp["result"])
+	resp = GetSideChain([]interface{}{float64(-1)})
+	assert.Equal(t, berr.INVALID_PARAMS, resp["error"])
+	resp = GetSideChain([]interface{}{"2"})
+	assert.Equal(t, berr.INVALID_PARAMS, resp["error"])
+
+	resp = GetHeaderSyncTip([]interface{}{float64(2)})
+	assert.Equal(t, berr.UNKNOWN_BLOCK, resp["error"])
+	resp = GetHeaderSyncTip(nil)
+	assert.Equal(t, berr.INVALID_PARAMS, resp["error"])
+}
+
+func TestGetHeaderByHeight(t *testing.T) {
+	useGenesisLedger(t)
+
+	//poly header, positional or named without chain id
+	resp := GetHeaderByHeight([]interface{}{float64(0)})
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	polyHeader := resp["result"]
+	resp = GetHeaderByHeight([]interface{}{nil, float64(0)})
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	assert.Equal(t, polyHeader, resp["result"])
+
+	resp = GetHeaderByHeight([]interface{}{float64(2), float64(0)})
+	assert.Equal(t, berr.UNKNOWN_BLOCK, resp["error"])
+	resp = GetHeaderByHeight([]interface{}{float64(2), float64(0.5)})
+	assert.Equal(t, berr.INVALID_PARAMS, resp["error"])
+}
+
+func TestCallNative(t *testing.T) {
+	useGenesisLedger(t)
+	contract := utils.SideChainManagerContractAddress.ToHexString()
+
+	resp := CallNative([]interface{}{contract, "noSuchMethod"})
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	result := resp["result"].(bcomn.CallNativeResult)
+	assert.Equal(t, byte(event.CONTRACT_STATE_FAIL), result.State)
+	assert.Contains(t, result.Error, "doesn't support")
+
+	//unsigned calls fail where the method checks witness
+	param := &side_chain_manager.RegisterSideChainParam{ChainId: 8, Name: "chain", BlocksToWait: 1}
+	sink := common.NewZeroCopySink(nil)
+	assert.Nil(t, param.Serialization(sink))
+	resp = CallNative([]interface{}{contract, side_chain_manager.REGISTER_SIDE_CHAIN, common.ToHexString(sink.Bytes())})
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	result = resp["result"].(bcomn.CallNativeResult)
+	assert.Equal(t, byte(event.CONTRACT_STATE_FAIL), result.State)
+	assert.Contains(t, result.Error, "checkWitness")
+
+	resp = CallNative([]interface{}{contract})
+	assert.Equal(t, berr.INVALID_PARAMS, resp["error"])
+	resp = CallNative([]interface{}{"x", "method"})
+	assert.Equal(t, berr.INVALID_PARAMS, resp["error"])
+	resp = CallNative([]interface{}{contract, "method", "zz"})
+	assert.Equal(t, berr.INVALID_PARAMS, resp["error"])
+}
+
+func TestCallNativeError(t *testing.T) {
+	useGenesisLedger(t)
+	//a contract calling itself until the context stack overflows
+	contract := common.Address{0xfe}
+	native.Contracts[contract] = func(ns *native.NativeService) {
+		ns.Register("recurse", func(ns *native.NativeService) ([]byte, error) {
+			if _, err := ns.NativeCall(contract, "recurse", nil); err != nil {
+				return utils.BYTE_FALSE, err
+			}
+			return utils.BYTE_TRUE, nil
+		})
+	}
+	defer delete(native.Contracts, contract)
+
+	resp := CallNative([]interface{}{contract.ToHexString(), "recurse"})
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	result := resp["result"].(bcomn.CallNativeResult)
+	assert.Equal(t, byte(event.CONTRACT_STATE_FAIL), result.State)
+	assert.Contains(t, result.Error, "context over max")
+}
+
+// useTestPool serves the pool requests of the tx actor from the pool
+func useTestPool(t *testing.T, pool *tcomn.TXPool) {
+	pid := actor.Spawn(actor.FromFunc(func(context actor.Context) {
+		switch msg := context.Message().(type) {
+		case *tcomn.GetPoolTxsReq:
+			total, txs := pool.GetPoolTxs(msg.Filter, msg.Offset, msg.Limit)
+			context.Sender().Request(&tcomn.GetPoolTxsRsp{Total: total, Txs: txs}, context.Self())
+		case *tcomn.DropTxnReq:
+			context.Sender().Request(&tcomn.DropTxnRsp{Ok: pool.DropTx(msg.Hash)}, context.Self())
+		}
+	}))
+	bactor.SetTxPid(pid)
+	t.Cleanup(func() {
+		bactor.SetTxPid(nil)
+		pid.Stop()
+	})
+}
+
+// newInvokeTx builds a transaction invoking the method of the contract signed by the signer
+func newInvokeTx(t *testing.T, signer *account.Account, nonce uint32, contract common.Address, method string) *types.Transaction {
+	sink := common.NewZeroCopySink(nil)
+	(&states.ContractInvokeParam{Address: contract, Method: method}).Serialization(sink)
+	tx := &types.Transaction{
+		TxType:  types.Invoke,
+		Nonce:   nonce,
+		Payload: &payload.InvokeCode{Code: sink.Bytes()},
+	}
+	raw := common.NewZeroCopySink(nil)
+	assert.Nil(t, tx.Serialization(raw))
+	tx, err := types.TransactionFromRawBytes(raw.Bytes())
+	assert.Nil(t, err)
+	hash := tx.Hash()
+	sig, err := signature.Sign(signer, hash[:])
+	assert.Nil(t, err)
+	tx.Sigs = []types.Sig{{PubKeys: []keypair.PublicKey{signer.PublicKey}, M: 1, SigData: [][]byte{sig}}}
+	raw = common.NewZeroCopySink(nil)
+	assert.Nil(t, tx.Serialization(raw))
+	tx, err = types.TransactionFromRawBytes(raw.Bytes())
+	assert.Nil(t, err)
+	return tx
+}
+
+func memPoolHashes(resp map[string]interface{}) []string {
+	hashes := make([]string, 0)
+	for _, tx := range resp["result"].(*bcomn.MemPoolInfo).Txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	return hashes
+}
+
+func TestGetMemPool(t *testing.T) {
+	pool := &tcomn.TXPool{}
+	assert.Nil(t, pool.InitWithConfig(nil))
+	useTestPool(t, pool)
+
+	//the hashes don't cover the signatures, the nonces tell the txs apart
+	acc1, acc2 := account.NewAccount(""), account.NewAccount("")
+	txs := []*types.Transaction{
+		newInvokeTx(t, acc1, 1, utils.HeaderSyncContractAddress, header_sync.SYNC_BLOCK_HEADER),
+		newInvokeTx(t, acc1, 2, utils.SideChainManagerContractAddress, side_chain_manager.REGISTER_SIDE_CHAIN),
+		newInvokeTx(t, acc2, 3, utils.HeaderSyncContractAddress, header_sync.SYNC_BLOCK_HEADER),
+		newInvokeTx(t, acc2, 4, utils.HeaderSyncContractAddress, header_sync.SYNC_GENESIS_HEADER),
+	}
+	hashes := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		assert.True(t, pool.AddTxList(&tcomn.TXEntry{Tx: tx}))
+		hash := tx.Hash()
+		hashes = append(hashes, hash.ToHexString())
+	}
+
+	resp := GetMemPool(nil)
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	info := resp["result"].(*bcomn.MemPoolInfo)
+	assert.Equal(t, len(txs), info.Total)
+	assert.Equal(t, hashes, memPoolHashes(resp))
+	assert.Equal(t, acc1.Address.ToBase58(), info.Txs[0].Signer)
+	assert.Equal(t, utils.HeaderSyncContractAddress.ToHexString(), info.Txs[0].Contract)
+	assert.Equal(t, header_sync.SYNC_BLOCK_HEADER, info.Txs[0].Method)
+	assert.Equal(t, len(txs[0].Raw), info.Txs[0].Size)
+
+	//pages
+	resp = GetMemPool([]interface{}{float64(1), float64(2)})
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	assert.Equal(t, len(txs), resp["result"].(*bcomn.MemPoolInfo).Total)
+	assert.Equal(t, 1, resp["result"].(*bcomn.MemPoolInfo).Offset)
+	assert.Equal(t, hashes[1:3], memPoolHashes(resp))
+	resp = GetMemPool([]interface{}{float64(3), float64(2)})
+	assert.Equal(t, hashes[3:], memPoolHashes(resp))
+	resp = GetMemPool([]interface{}{float64(4)})
+	assert.Equal(t, len(txs), resp["result"].(*bcomn.MemPoolInfo).Total)
+	assert.Empty(t, memPoolHashes(resp))
+
+	//filters, the contract by name or address
+	resp = GetMemPool([]interface{}{float64(0), float64(10), acc1.Address.ToBase58()})
+	assert.Equal(t, hashes[:2], memPoolHashes(resp))
+	resp = GetMemPool([]interface{}{float64(0), float64(10), "", "header_sync"})
+	assert.Equal(t, []string{hashes[0], hashes[2], hashes[3]}, memPoolHashes(resp))
+	resp = GetMemPool([]interface{}{float64(0), float64(10), "",
+		utils.HeaderSyncContractAddress.ToHexString(), header_sync.SYNC_BLOCK_HEADER})
+	assert.Equal(t, []string{hashes[0], hashes[2]}, memPoolHashes(resp))
+	resp = GetMemPool([]interface{}{float64(1), float64(1), acc2.Address.ToBase58(), "", header_sync.SYNC_BLOCK_HEADER})
+	assert.Equal(t, 1, resp["result"].(*bcomn.MemPoolInfo).Total)
+	assert.Empty(t, memPoolHashes(resp))
+
+	//invalid params
+	for _, params := range [][]interface{}{
+		{float64(-1)},
+		{"0"},
+		{float64(0), float64(0)},
+		{float64(0), float64(bcomn.MAX_MEMPOOL_PAGE + 1)},
+		{float64(0), float64(10), "not an address"},
+		{float64(0), float64(10), "", "no_such_contract"},
+		{float64(0), float64(10), "", "", 1},
+	} {
+		assert.Equal(t, berr.INVALID_PARAMS, GetMemPool(params)["error"], "%v", params)
+	}
+}
+
+func TestDropMemPoolTx(t *testing.T) {
+	pool := &tcomn.TXPool{}
+	assert.Nil(t, pool.InitWithConfig(nil))
+	useTestPool(t, pool)
+
+	tx := newInvokeTx(t, account.NewAccount(""), 1, utils.HeaderSyncContractAddress, header_sync.SYNC_BLOCK_HEADER)
+	assert.True(t, pool.AddTxList(&tcomn.TXEntry{Tx: tx}))
+	hash := tx.Hash()
+
+	resp := DropMemPoolTx([]interface{}{hash.ToHexString()})
+	assert.Equal(t, berr.SUCCESS, resp["error"])
+	assert.Equal(t, true, resp["result"])
+	assert.Nil(t, pool.GetTransaction(hash))
+
+	resp = DropMemPoolTx([]interface{}{hash.ToHexString()})
+	assert.Equal(t, berr.UNKNOWN_TRANSACTION, resp["error"])
+	assert.Equal(t, false, resp["result"])
+
+	assert.Equal(t, berr.INVALID_PARAMS, DropMemPoolTx(nil)["error"])
+	assert.Equal(t, berr.INVALID_PARAMS, DropMemPoolTx([]interface{}{1})["error"])
+	assert.Equal(t, berr.INVALID_PARAMS, DropMemPoolTx([]interface{}{"zz"})["error"])
+}
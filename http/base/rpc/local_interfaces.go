@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 
+	ocommon "github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
 	bactor "github.com/polynetwork/poly/http/base/actor"
 	"github.com/polynetwork/poly/http/base/common"
@@ -122,3 +123,27 @@ func SetDebugInfo(params []interface{}) map[string]interface{} {
 	}
 	return responsePack(berr.SUCCESS, true)
 }
+
+//drop a verified transaction from memory pool, it stays in the pools of the peers
+func DropMemPoolTx(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	hash, err := ocommon.Uint256FromHexString(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	dropped, err := bactor.DropPoolTx(hash)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, false)
+	}
+	if !dropped {
+		return responsePack(berr.UNKNOWN_TRANSACTION, false)
+	}
+	log.Infof("DropMemPoolTx: transaction %s dropped from tx pool", str)
+	return responsePack(berr.SUCCESS, true)
+}
@@ -20,37 +20,149 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/http/base/access"
+	"github.com/polynetwork/poly/http/base/api"
 	berr "github.com/polynetwork/poly/http/base/error"
 )
 
-func init() {
-	mainMux.m = make(map[string]func([]interface{}) map[string]interface{})
-}
+const (
+	JSON_RPC_VERSION = "2.0"
+	MAX_REQUEST_SIZE = 1 * 1024 * 1024 //body limit of requests not passed by the access guard
+)
 
-//an instance of the multiplexer
-var mainMux ServeMux
+//the multiplexer of the public rpc server
+var mainMux = NewServeMux()
 
 //multiplexer that keeps track of every function to be called on specific rpc call
 type ServeMux struct {
 	sync.RWMutex
-	m               map[string]func([]interface{}) map[string]interface{}
+	m               map[string]*method
+	maxBatchSize    uint
 	defaultFunction func(http.ResponseWriter, *http.Request)
 }
 
-//a function to register functions to be called for specific rpc calls
-func HandleFunc(pattern string, handler func([]interface{}) map[string]interface{}) {
-	mainMux.Lock()
-	defer mainMux.Unlock()
-	mainMux.m[pattern] = handler
+//NewServeMux returns a multiplexer without methods, e.g. for a server which
+//serves methods not to be exposed by the public rpc server
+func NewServeMux() *ServeMux {
+	return &ServeMux{
+		m:            make(map[string]*method),
+		maxBatchSize: config.DEFAULT_RPC_MAX_BATCH_SIZE,
+	}
+}
+
+//a registered rpc method
+type method struct {
+	handler func([]interface{}) map[string]interface{}
+	params  []string //names of the positional params, used to resolve named params
+	spec    *api.Method
+}
+
+//request object of JSON-RPC 2.0, a request without id is a notification. Id keeps the raw
+//value, so that an "id": null member is told apart from a missing one
+type request struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Id      json.RawMessage `json:"id"`
+}
+
+//legacy requests have no jsonrpc member, they are answered the way the server did before JSON-RPC 2.0
+func (self *request) legacy() bool {
+	return self.Version == ""
+}
+
+func (self *request) notification() bool {
+	return len(self.Id) == 0
+}
+
+//error object of JSON-RPC 2.0
+type responseError struct {
+	Code    int64       `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+//response object of JSON-RPC 2.0
+type response struct {
+	Version string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result"`
+	Error   *responseError  `json:"error,omitempty"`
+	Id      json.RawMessage `json:"id"`
+}
+
+//MarshalJSON leaves out result in error responses, as a null result is a valid one
+func (self *response) MarshalJSON() ([]byte, error) {
+	if self.Error != nil {
+		return json.Marshal(map[string]interface{}{
+			"jsonrpc": self.Version,
+			"error":   self.Error,
+			"id":      self.Id,
+		})
+	}
+	type plain response
+	return json.Marshal((*plain)(self))
+}
+
+//a function to register functions to be called for specific rpc calls,
+//params names the positional params of the function so that it can also be called with named params
+func HandleFunc(pattern string, handler func([]interface{}) map[string]interface{}, params ...string) {
+	spec := &api.Method{Name: pattern, Params: make([]*api.Param, 0, len(params)), Result: api.Any("")}
+	for _, name := range params {
+		spec.Params = append(spec.Params, api.NewParam(name, "", false, api.Any("")))
+	}
+	Register(spec, handler)
+}
+
+//Register registers a handler together with the description of the method,
+//the description is what rpc_discover reports and write methods are marked for the access guard
+func Register(spec *api.Method, handler func([]interface{}) map[string]interface{}) {
+	if spec.Write {
+		access.SetWriteMethod(spec.Name)
+	}
+	mainMux.Register(spec, handler)
+}
+
+//Register registers a handler together with the description of the method to the multiplexer
+func (self *ServeMux) Register(spec *api.Method, handler func([]interface{}) map[string]interface{}) {
+	self.Lock()
+	defer self.Unlock()
+	self.m[spec.Name] = &method{handler: handler, params: spec.ParamNames(), spec: spec}
+}
+
+//Methods returns the descriptions of the registered methods sorted by name
+func Methods() []*api.Method {
+	return mainMux.Methods()
+}
+
+//Methods returns the descriptions of the methods registered to the multiplexer sorted by name
+func (self *ServeMux) Methods() []*api.Method {
+	self.RLock()
+	defer self.RUnlock()
+	return self.methods()
+}
+
+//methods is called by handlers directly, as Handle holds the read lock while serving
+func (self *ServeMux) methods() []*api.Method {
+	methods := make([]*api.Method, 0, len(self.m))
+	for _, m := range self.m {
+		methods = append(methods, m.spec)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name < methods[j].Name
+	})
+	return methods
 }
 
 //a function to be called if the request is not a HTTP JSON RPC call
@@ -58,22 +170,37 @@ func SetDefaultFunc(def func(http.ResponseWriter, *http.Request)) {
 	mainMux.defaultFunction = def
 }
 
+//set the max number of requests in a batch
+func SetMaxBatchSize(size uint) {
+	mainMux.SetMaxBatchSize(size)
+}
+
+//set the max number of requests in a batch served by the multiplexer
+func (self *ServeMux) SetMaxBatchSize(size uint) {
+	self.Lock()
+	defer self.Unlock()
+	self.maxBatchSize = size
+}
+
 // this is the function that should be called in order to answer an rpc call
 // should be registered like "http.HandleFunc("/", httpjsonrpc.Handle)"
 func Handle(w http.ResponseWriter, r *http.Request) {
-	mainMux.RLock()
-	defer mainMux.RUnlock()
+	mainMux.ServeHTTP(w, r)
+}
+
+//ServeHTTP answers the rpc calls of the methods registered to the multiplexer
+func (self *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	self.RLock()
+	defer self.RUnlock()
+	//cors preflight requests are answered by the access guard
 	if r.Method == "OPTIONS" {
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
 		return
 	}
 	//JSON RPC commands should be POSTs
 	if r.Method != "POST" {
-		if mainMux.defaultFunction != nil {
+		if self.defaultFunction != nil {
 			log.Info("HTTP JSON RPC Handle - Method!=\"POST\"")
-			mainMux.defaultFunction(w, r)
+			self.defaultFunction(w, r)
 			return
 		} else {
 			log.Warn("HTTP JSON RPC Handle - Method!=\"POST\"")
@@ -83,9 +210,9 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 
 	//check if there is Request Body to read
 	if r.Body == nil {
-		if mainMux.defaultFunction != nil {
+		if self.defaultFunction != nil {
 			log.Info("HTTP JSON RPC Handle - Request body is nil")
-			mainMux.defaultFunction(w, r)
+			self.defaultFunction(w, r)
 			return
 		} else {
 			log.Warn("HTTP JSON RPC Handle - Request body is nil")
@@ -94,75 +221,194 @@ func Handle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	//read the body of the request
-	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+	if !access.Guarded(r) {
+		r.Body = http.MaxBytesReader(w, r.Body, MAX_REQUEST_SIZE)
+	}
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		log.Error("HTTP JSON RPC Handle - ioutil.ReadAll: ", err)
 		return
 	}
-	request := make(map[string]interface{})
-	err = json.Unmarshal(body, &request)
-	if err != nil {
-		log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
-		return
+
+	var result interface{}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		result = self.serveBatch(r, body)
+	} else {
+		result = self.serveRequest(r, body)
 	}
-	if request["method"] == nil {
-		log.Error("HTTP JSON RPC Handle - method not found: ")
+	w.Header().Set("content-type", "application/json;charset=utf-8")
+	//nothing is returned for notifications
+	if result == nil {
 		return
 	}
-	method, ok := request["method"].(string)
-	if !ok {
-		log.Error("HTTP JSON RPC Handle - method is not string: ")
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
 		return
 	}
-	//get the corresponding function
-	function, ok := mainMux.m[method]
-	if ok {
-		response := function(request["params"].([]interface{}))
-		data, err := json.Marshal(map[string]interface{}{
-			"jsonrpc": "2.0",
-			"error":   response["error"],
-			"desc":    response["desc"],
-			"result":  response["result"],
-			"id":      request["id"],
-		})
-		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
+	w.Write(data)
+}
+
+//serveBatch answers the requests of a batch in order, nil if all of them are notifications
+func (self *ServeMux) serveBatch(r *http.Request, body []byte) interface{} {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
+		return errorResponse(nil, berr.JSONRPC_PARSE_ERROR, nil)
+	}
+	if len(batch) == 0 {
+		return errorResponse(nil, berr.JSONRPC_INVALID_REQUEST, "empty batch")
+	}
+	if uint(len(batch)) > self.maxBatchSize {
+		log.Warnf("HTTP JSON RPC Handle - batch of %d requests exceeds %d", len(batch), self.maxBatchSize)
+		return errorResponse(nil, berr.JSONRPC_INVALID_REQUEST,
+			fmt.Sprintf("batch of %d requests exceeds the limit %d", len(batch), self.maxBatchSize))
+	}
+	responses := make([]interface{}, 0, len(batch))
+	for _, data := range batch {
+		if resp := self.serveRequest(r, data); resp != nil {
+			responses = append(responses, resp)
 		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
-	} else {
-		//if the function does not exist
-		log.Warn("HTTP JSON RPC Handle - No function to call for ", request["method"])
-		data, err := json.Marshal(map[string]interface{}{
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+//serveRequest calls the method of a single request, nil if it is a notification
+func (self *ServeMux) serveRequest(r *http.Request, data []byte) interface{} {
+	req := &request{}
+	if err := json.Unmarshal(data, req); err != nil {
+		if _, ok := err.(*json.SyntaxError); ok {
+			log.Error("HTTP JSON RPC Handle - json.Unmarshal: ", err)
+			return errorResponse(nil, berr.JSONRPC_PARSE_ERROR, nil)
+		}
+		return errorResponse(nil, berr.JSONRPC_INVALID_REQUEST, nil)
+	}
+	if req.Method == "" || (req.Version != "" && req.Version != JSON_RPC_VERSION) {
+		log.Error("HTTP JSON RPC Handle - invalid request: ", string(data))
+		return errorResponse(req.Id, berr.JSONRPC_INVALID_REQUEST, nil)
+	}
+	if req.legacy() {
+		return self.callLegacy(r, req)
+	}
+	resp := self.call(r, req)
+	if req.notification() {
+		return nil
+	}
+	return resp
+}
+
+//callLegacy answers every legacy request with the error code and desc of the handler result
+func (self *ServeMux) callLegacy(r *http.Request, req *request) map[string]interface{} {
+	m, ok := self.m[req.Method]
+	if !ok {
+		log.Warn("HTTP JSON RPC Handle - No function to call for ", req.Method)
+		return map[string]interface{}{
 			"error": berr.INVALID_METHOD,
 			"result": map[string]interface{}{
-				"code":    -32601,
-				"message": "Method not found",
+				"code":    berr.JSONRPC_METHOD_NOT_FOUND,
+				"message": berr.ErrMap[berr.JSONRPC_METHOD_NOT_FOUND],
 				"data":    "The called method was not found on the server",
 			},
-			"id": request["id"],
-		})
-		if err != nil {
-			log.Error("HTTP JSON RPC Handle - json.Marshal: ", err)
-			return
+			"id": req.Id,
 		}
-		w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
-		w.Header().Set("content-type", "application/json;charset=utf-8")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Write(data)
+	}
+	var result map[string]interface{}
+	if code := access.Allow(r, req.Method); code != berr.SUCCESS {
+		result = responsePack(code, "")
+	} else if params, err := m.parseParams(req.Params); err != nil {
+		result = responsePack(berr.INVALID_PARAMS, err.Error())
+	} else {
+		result = m.handler(params)
+	}
+	return map[string]interface{}{
+		"jsonrpc": JSON_RPC_VERSION,
+		"error":   result["error"],
+		"desc":    result["desc"],
+		"result":  result["result"],
+		"id":      req.Id,
+	}
+}
+
+func (self *ServeMux) call(r *http.Request, req *request) *response {
+	//get the corresponding function
+	m, ok := self.m[req.Method]
+	if !ok {
+		log.Warn("HTTP JSON RPC Handle - No function to call for ", req.Method)
+		return errorResponse(req.Id, berr.JSONRPC_METHOD_NOT_FOUND, "The called method was not found on the server")
+	}
+	if code := access.Allow(r, req.Method); code != berr.SUCCESS {
+		return errorResponse(req.Id, code, nil)
+	}
+	params, err := m.parseParams(req.Params)
+	if err != nil {
+		return errorResponse(req.Id, berr.JSONRPC_INVALID_PARAMS, err.Error())
+	}
+	result := m.handler(params)
+	errCode, _ := result["error"].(int64)
+	if errCode != berr.SUCCESS {
+		data := result["result"]
+		if data == "" {
+			data = nil
+		}
+		return errorResponse(req.Id, errCode, data)
+	}
+	return &response{Version: JSON_RPC_VERSION, Result: result["result"], Id: req.Id}
+}
+
+//parseParams returns by-position params, named params are put at the position of their name
+func (self *method) parseParams(data json.RawMessage) ([]interface{}, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return []interface{}{}, nil
+	}
+	if data[0] == '[' {
+		params := make([]interface{}, 0)
+		if err := json.Unmarshal(data, &params); err != nil {
+			return nil, err
+		}
+		return params, nil
+	}
+	named := make(map[string]interface{})
+	if err := json.Unmarshal(data, &named); err != nil {
+		return nil, fmt.Errorf("params should be an array or an object")
+	}
+	params := make([]interface{}, 0, len(self.params))
+	found := 0
+	for _, name := range self.params {
+		if found == len(named) {
+			break
+		}
+		value, ok := named[name]
+		if ok {
+			found++
+		}
+		params = append(params, value)
+	}
+	if found != len(named) {
+		return nil, fmt.Errorf("unknown named params, accepted: %s", strings.Join(self.params, ", "))
+	}
+	return params, nil
+}
+
+func errorResponse(id json.RawMessage, code int64, data interface{}) *response {
+	return &response{
+		Version: JSON_RPC_VERSION,
+		Error:   &responseError{Code: code, Message: berr.ErrMap[code], Data: data},
+		Id:      id,
 	}
 }
 
 // Call sends RPC request to server
 func Call(address string, method string, id interface{}, params []interface{}) ([]byte, error) {
 	data, err := json.Marshal(map[string]interface{}{
-		"method": method,
-		"id":     id,
-		"params": params,
+		"jsonrpc": JSON_RPC_VERSION,
+		"method":  method,
+		"id":      id,
+		"params":  params,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Marshal JSON request: %v\n", err)
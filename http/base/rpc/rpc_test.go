@@ -0,0 +1,256 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/http/base/access"
+	"github.com/polynetwork/poly/http/base/api"
+	berr "github.com/polynetwork/poly/http/base/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	HandleFunc("testecho", func(params []interface{}) map[string]interface{} {
+		return responseSuccess(params)
+	}, "first", "second")
+	HandleFunc("testfail", func(params []interface{}) map[string]interface{} {
+		return responsePack(berr.UNKNOWN_BLOCK, "unknown block")
+	})
+	Register(&api.Method{Name: "testwrite", Summary: "write method",
+		Params: []*api.Param{api.NewParam("value", "", true, api.Integer(""))},
+		Result: api.Boolean(""), Write: true}, func(params []interface{}) map[string]interface{} {
+		return responseSuccess(true)
+	})
+	Register(&api.Method{Name: "rpc_discover", Result: api.SchemaOf(api.Discovery{})}, Discover)
+}
+
+func post(t *testing.T, body string) []byte {
+	w := httptest.NewRecorder()
+	Handle(w, httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	return w.Body.Bytes()
+}
+
+type testResponse struct {
+	Version string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *responseError  `json:"error"`
+	Id      json.RawMessage `json:"id"`
+}
+
+func TestHandleSingle(t *testing.T) {
+	resp := &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"testecho","params":[1,"a"],"id":7}`), resp))
+	assert.Equal(t, JSON_RPC_VERSION, resp.Version)
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, `[1,"a"]`, string(resp.Result))
+	assert.Equal(t, "7", string(resp.Id))
+
+	resp = &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"testfail","id":"x"}`), resp))
+	assert.Nil(t, resp.Result)
+	assert.Equal(t, berr.UNKNOWN_BLOCK, resp.Error.Code)
+	assert.Equal(t, "unknown block", resp.Error.Data)
+	assert.Equal(t, `"x"`, string(resp.Id))
+
+	resp = &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"nosuchmethod","id":1}`), resp))
+	assert.Equal(t, berr.JSONRPC_METHOD_NOT_FOUND, resp.Error.Code)
+
+	resp = &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":`), resp))
+	assert.Equal(t, berr.JSONRPC_PARSE_ERROR, resp.Error.Code)
+	assert.Equal(t, "null", string(resp.Id))
+
+	resp = &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"1.0","method":"testecho","id":1}`), resp))
+	assert.Equal(t, berr.JSONRPC_INVALID_REQUEST, resp.Error.Code)
+}
+
+func TestHandleNamedParams(t *testing.T) {
+	resp := &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"testecho","params":{"second":2,"first":1},"id":1}`), resp))
+	assert.Equal(t, `[1,2]`, string(resp.Result))
+
+	resp = &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"testecho","params":{"first":1},"id":1}`), resp))
+	assert.Equal(t, `[1]`, string(resp.Result))
+
+	resp = &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"testecho","params":{"second":2},"id":1}`), resp))
+	assert.Equal(t, `[null,2]`, string(resp.Result))
+
+	resp = &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"testecho","params":{"third":3},"id":1}`), resp))
+	assert.Equal(t, berr.JSONRPC_INVALID_PARAMS, resp.Error.Code)
+
+	resp = &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"testecho","params":3,"id":1}`), resp))
+	assert.Equal(t, berr.JSONRPC_INVALID_PARAMS, resp.Error.Code)
+}
+
+func TestHandleNotification(t *testing.T) {
+	assert.Empty(t, post(t, `{"jsonrpc":"2.0","method":"testecho","params":[1]}`))
+	assert.Empty(t, post(t, `[{"jsonrpc":"2.0","method":"testecho"},{"jsonrpc":"2.0","method":"testfail"}]`))
+
+	//a null id is an id, not a notification
+	resp := &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"testecho","params":[1],"id":null}`), resp))
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, `[1]`, string(resp.Result))
+	assert.Equal(t, "null", string(resp.Id))
+}
+
+type legacyResponse struct {
+	Version string          `json:"jsonrpc"`
+	Error   int64           `json:"error"`
+	Desc    string          `json:"desc"`
+	Result  json.RawMessage `json:"result"`
+	Id      json.RawMessage `json:"id"`
+}
+
+func TestHandleLegacy(t *testing.T) {
+	resp := &legacyResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"method":"testecho","params":[1,"a"],"id":7}`), resp))
+	assert.Equal(t, berr.SUCCESS, resp.Error)
+	assert.Equal(t, "SUCCESS", resp.Desc)
+	assert.Equal(t, `[1,"a"]`, string(resp.Result))
+	assert.Equal(t, "7", string(resp.Id))
+
+	//legacy requests are answered without id too
+	resp = &legacyResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"method":"testfail","params":[]}`), resp))
+	assert.Equal(t, berr.UNKNOWN_BLOCK, resp.Error)
+	assert.Equal(t, `"unknown block"`, string(resp.Result))
+	assert.Equal(t, "null", string(resp.Id))
+
+	resp = &legacyResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"method":"nosuchmethod","id":1}`), resp))
+	assert.Equal(t, berr.INVALID_METHOD, resp.Error)
+
+	resp = &legacyResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"method":"testecho","params":{"third":3},"id":1}`), resp))
+	assert.Equal(t, berr.INVALID_PARAMS, resp.Error)
+
+	//legacy and JSON-RPC 2.0 requests are answered each in their own format in a batch
+	var resps []json.RawMessage
+	assert.Nil(t, json.Unmarshal(post(t, `[{"method":"testecho","id":1},{"jsonrpc":"2.0","method":"testfail","id":2}]`), &resps))
+	assert.Equal(t, 2, len(resps))
+	resp = &legacyResponse{}
+	assert.Nil(t, json.Unmarshal(resps[0], resp))
+	assert.Equal(t, "SUCCESS", resp.Desc)
+	resp2 := &testResponse{}
+	assert.Nil(t, json.Unmarshal(resps[1], resp2))
+	assert.Equal(t, berr.UNKNOWN_BLOCK, resp2.Error.Code)
+}
+
+func TestHandleBatch(t *testing.T) {
+	var resps []*testResponse
+	body := `[{"jsonrpc":"2.0","method":"testecho","params":[1],"id":1},
+		{"jsonrpc":"2.0","method":"testecho","params":[2]},
+		{"jsonrpc":"2.0","method":"testfail","id":3},
+		1]`
+	assert.Nil(t, json.Unmarshal(post(t, body), &resps))
+	assert.Equal(t, 3, len(resps))
+	assert.Equal(t, `[1]`, string(resps[0].Result))
+	assert.Equal(t, "1", string(resps[0].Id))
+	assert.Equal(t, berr.UNKNOWN_BLOCK, resps[1].Error.Code)
+	assert.Equal(t, "3", string(resps[1].Id))
+	assert.Equal(t, berr.JSONRPC_INVALID_REQUEST, resps[2].Error.Code)
+
+	resp := &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `[]`), resp))
+	assert.Equal(t, berr.JSONRPC_INVALID_REQUEST, resp.Error.Code)
+}
+
+func TestHandleBatchLimit(t *testing.T) {
+	SetMaxBatchSize(2)
+	defer SetMaxBatchSize(config.DEFAULT_RPC_MAX_BATCH_SIZE)
+	var resps []*testResponse
+	assert.Nil(t, json.Unmarshal(post(t, `[{"jsonrpc":"2.0","method":"testecho","id":1},{"jsonrpc":"2.0","method":"testecho","id":2}]`), &resps))
+	assert.Equal(t, 2, len(resps))
+
+	resp := &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `[{"method":"testecho","id":1},{"method":"testecho","id":2},{"method":"testecho","id":3}]`), resp))
+	assert.Equal(t, berr.JSONRPC_INVALID_REQUEST, resp.Error.Code)
+}
+
+func TestHandleGuarded(t *testing.T) {
+	access.SetWriteMethod("testfail")
+	guard, err := access.NewGuard(&config.ApiAccessConfig{ApiKeys: []string{"key"}, AuthWriteOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := guard.Handler(http.HandlerFunc(Handle))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/",
+		strings.NewReader(`[{"jsonrpc":"2.0","method":"testecho","id":1},{"jsonrpc":"2.0","method":"testfail","id":2}]`)))
+	resps := []*testResponse{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resps))
+	assert.Equal(t, 2, len(resps))
+	assert.Nil(t, resps[0].Error)
+	assert.Equal(t, berr.UNAUTHORIZED, resps[1].Error.Code, "write method without key")
+}
+
+func TestDiscover(t *testing.T) {
+	resp := &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"rpc_discover","id":1}`), resp))
+	assert.Nil(t, resp.Error)
+	discovery := &api.Discovery{}
+	assert.Nil(t, json.Unmarshal(resp.Result, discovery))
+
+	names := make([]string, 0)
+	for _, m := range discovery.Methods {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t, []string{"rpc_discover", "testecho", "testfail", "testwrite"}, names)
+	assert.Equal(t, []string{"first", "second"}, discovery.Methods[1].ParamNames())
+	assert.Equal(t, true, discovery.Methods[3].Write)
+	assert.Equal(t, "integer", discovery.Methods[3].Params[0].Schema["type"])
+	assert.True(t, access.IsWriteMethod("testwrite"))
+}
+
+func TestServeMux(t *testing.T) {
+	mux := NewServeMux()
+	mux.Register(&api.Method{Name: "testlocal", Result: api.Boolean(""), Write: true}, func(params []interface{}) map[string]interface{} {
+		return responseSuccess(true)
+	})
+	assert.Equal(t, 1, len(mux.Methods()))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/local", strings.NewReader(`{"jsonrpc":"2.0","method":"testlocal","id":1}`)))
+	resp := &testResponse{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, "true", string(resp.Result))
+
+	//methods of another multiplexer are neither served nor discovered by the public one
+	resp = &testResponse{}
+	assert.Nil(t, json.Unmarshal(post(t, `{"jsonrpc":"2.0","method":"testlocal","id":1}`), resp))
+	assert.Equal(t, berr.JSONRPC_METHOD_NOT_FOUND, resp.Error.Code)
+	for _, m := range Methods() {
+		assert.NotEqual(t, "testlocal", m.Name)
+	}
+	assert.False(t, access.IsWriteMethod("testlocal"))
+}
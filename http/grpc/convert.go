@@ -0,0 +1,128 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"encoding/json"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/core/types"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	"github.com/polynetwork/poly/http/grpc/pb"
+	"github.com/polynetwork/poly/native/event"
+	cstate "github.com/polynetwork/poly/native/states"
+)
+
+func convertHeader(header *types.Header) *pb.Header {
+	hash := header.Hash()
+	bookkeepers := make([][]byte, 0, len(header.Bookkeepers))
+	for _, pubKey := range header.Bookkeepers {
+		bookkeepers = append(bookkeepers, keypair.SerializePublicKey(pubKey))
+	}
+	return &pb.Header{
+		Version:          header.Version,
+		ChainId:          header.ChainID,
+		PrevBlockHash:    header.PrevBlockHash.ToHexString(),
+		TransactionsRoot: header.TransactionsRoot.ToHexString(),
+		CrossStateRoot:   header.CrossStateRoot.ToHexString(),
+		BlockRoot:        header.BlockRoot.ToHexString(),
+		Timestamp:        header.Timestamp,
+		Height:           header.Height,
+		ConsensusData:    header.ConsensusData,
+		ConsensusPayload: header.ConsensusPayload,
+		NextBookkeeper:   header.NextBookkeeper.ToHexString(),
+		Bookkeepers:      bookkeepers,
+		SigData:          header.SigData,
+		Hash:             hash.ToHexString(),
+		Raw:              header.ToArray(),
+	}
+}
+
+func convertBlock(block *types.Block) *pb.Block {
+	txs := make([]*pb.Transaction, 0, len(block.Transactions))
+	for _, tx := range block.Transactions {
+		txs = append(txs, convertTransaction(tx, block.Header.Height))
+	}
+	return &pb.Block{Header: convertHeader(block.Header), Transactions: txs}
+}
+
+func convertTransaction(tx *types.Transaction, height uint32) *pb.Transaction {
+	hash := tx.Hash()
+	return &pb.Transaction{
+		Hash:    hash.ToHexString(),
+		Height:  height,
+		Version: uint32(tx.Version),
+		TxType:  uint32(tx.TxType),
+		Nonce:   tx.Nonce,
+		ChainId: tx.ChainID,
+		Payer:   tx.Payer.ToHexString(),
+		Raw:     tx.ToArray(),
+	}
+}
+
+func convertNotifyEvents(evts []bcomn.NotifyEventInfo) []*pb.NotifyEvent {
+	result := make([]*pb.NotifyEvent, 0, len(evts))
+	for _, evt := range evts {
+		name, fromChainId, toChainId := bcomn.GetEventInfo(evt.States)
+		states, _ := json.Marshal(evt.States)
+		notify := &pb.NotifyEvent{
+			ContractAddress: evt.ContractAddress,
+			Name:            name,
+			States:          string(states),
+		}
+		if fromChainId != nil {
+			notify.FromChainId = *fromChainId
+		}
+		if toChainId != nil {
+			notify.ToChainId = *toChainId
+		}
+		result = append(result, notify)
+	}
+	return result
+}
+
+func convertExecuteNotify(notify bcomn.ExecuteNotify) *pb.ExecuteNotify {
+	return &pb.ExecuteNotify{
+		TxHash:      notify.TxHash,
+		State:       uint32(notify.State),
+		GasConsumed: notify.GasConsumed,
+		Notify:      convertNotifyEvents(notify.Notify),
+	}
+}
+
+//convertBlockEvents keeps the events matching filter
+func convertBlockEvents(height uint32, notifies []*event.ExecuteNotify, filter *bcomn.EventFilter) *pb.BlockEvents {
+	events := make([]*pb.ExecuteNotify, 0, len(notifies))
+	for _, n := range notifies {
+		_, notify := bcomn.GetExecuteNotify(n)
+		if filtered, ok := filter.Filter(notify); ok {
+			events = append(events, convertExecuteNotify(filtered))
+		}
+	}
+	return &pb.BlockEvents{Height: height, Events: events}
+}
+
+func convertPreExecResult(result *cstate.PreExecResult) *pb.PreExecResult {
+	converted := bcomn.ConvertPreExecuteResult(result)
+	data, _ := json.Marshal(converted.Result)
+	return &pb.PreExecResult{
+		State:  uint32(converted.State),
+		Result: string(data),
+		Notify: convertNotifyEvents(converted.Notify),
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/polynetwork/poly/http/base/access"
+	berr "github.com/polynetwork/poly/http/base/error"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+//metadata keys of the api key or token, grpc lowercases the http header names
+var (
+	apiKeyMetadata        = strings.ToLower(access.API_KEY_HEADER)
+	authorizationMetadata = "authorization"
+)
+
+//guardOptions applies the api access guard to every call of the server, a nil guard adds no option
+func guardOptions(guard *access.Guard) []gogrpc.ServerOption {
+	if guard == nil {
+		return nil
+	}
+	return []gogrpc.ServerOption{
+		gogrpc.MaxRecvMsgSize(int(guard.MaxRequestSize())),
+		gogrpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *gogrpc.UnaryServerInfo,
+			handler gogrpc.UnaryHandler) (interface{}, error) {
+			if err := checkCall(ctx, guard, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		gogrpc.StreamInterceptor(func(srv interface{}, stream gogrpc.ServerStream, info *gogrpc.StreamServerInfo,
+			handler gogrpc.StreamHandler) error {
+			if err := checkCall(stream.Context(), guard, info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, stream)
+		}),
+	}
+}
+
+//checkCall checks the client of ctx with the guard, the method name is the lowercased grpc method like the
+//json rpc ones, so that "/poly.Poly/SendRawTransaction" shares the write restrictions and rate limits of
+//"sendrawtransaction"
+func checkCall(ctx context.Context, guard *access.Guard, fullMethod string) error {
+	method := strings.ToLower(fullMethod[strings.LastIndex(fullMethod, "/")+1:])
+	switch code := guard.Check(peerIP(ctx), callToken(ctx), method); code {
+	case berr.SUCCESS:
+		return nil
+	case berr.UNAUTHORIZED:
+		return status.Error(codes.Unauthenticated, berr.ErrMap[code])
+	case berr.FORBIDDEN:
+		return status.Error(codes.PermissionDenied, berr.ErrMap[code])
+	case berr.TOO_MANY_REQUESTS:
+		return status.Error(codes.ResourceExhausted, berr.ErrMap[code])
+	default:
+		return status.Error(codes.Internal, berr.ErrMap[code])
+	}
+}
+
+func peerIP(ctx context.Context) net.IP {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return net.ParseIP(host)
+}
+
+//callToken returns the api key or the bearer token of the call metadata
+func callToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if keys := md.Get(apiKeyMetadata); len(keys) > 0 && keys[0] != "" {
+		return keys[0]
+	}
+	if auths := md.Get(authorizationMetadata); len(auths) > 0 && strings.HasPrefix(auths[0], "Bearer ") {
+		return strings.TrimSpace(auths[0][len("Bearer "):])
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/http/base/access"
+	"github.com/polynetwork/poly/http/grpc/pb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func newTestGuard(t *testing.T, cfg *config.ApiAccessConfig) *access.Guard {
+	guard, err := access.NewGuard(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return guard
+}
+
+//callContext is the server side context of a call from ip with the metadata pairs kv
+func callContext(ip string, kv ...string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 5000}})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(kv...))
+}
+
+func TestCheckCall(t *testing.T) {
+	guard := newTestGuard(t, &config.ApiAccessConfig{
+		ApiKeys:         []string{"write"},
+		ReadOnlyApiKeys: []string{"read"},
+		AllowIPs:        []string{"10.0.0.0/8"},
+	})
+	query, send := "/poly.Poly/GetBlock", "/poly.Poly/SendRawTransaction"
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(checkCall(callContext("192.168.0.1", "x-api-key", "write"), guard, query)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(checkCall(callContext("10.0.0.1"), guard, query)))
+	assert.Equal(t, codes.Unauthenticated, status.Code(checkCall(callContext("10.0.0.1", "x-api-key", "bad"), guard, query)))
+	assert.Nil(t, checkCall(callContext("10.0.0.1", "x-api-key", "read"), guard, query))
+	assert.Nil(t, checkCall(callContext("10.0.0.1", "authorization", "Bearer read"), guard, query))
+	assert.Equal(t, codes.PermissionDenied, status.Code(checkCall(callContext("10.0.0.1", "x-api-key", "read"), guard, send)))
+	assert.Nil(t, checkCall(callContext("10.0.0.1", "x-api-key", "write"), guard, send))
+}
+
+func TestCheckCallRateLimit(t *testing.T) {
+	guard := newTestGuard(t, &config.ApiAccessConfig{
+		MethodRateLimits: map[string]float64{"getblock": 1},
+	})
+	ctx := callContext("10.0.0.1")
+	assert.Nil(t, checkCall(ctx, guard, "/poly.Poly/GetBlock"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(checkCall(ctx, guard, "/poly.Poly/GetBlock")))
+	assert.Nil(t, checkCall(ctx, guard, "/poly.Poly/GetHeader"))
+	assert.Nil(t, checkCall(callContext("10.0.0.2"), guard, "/poly.Poly/GetBlock"))
+}
+
+func TestGuardedServer(t *testing.T) {
+	guard := newTestGuard(t, &config.ApiAccessConfig{ApiKeys: []string{"key"}})
+	client, _ := newTestClient(t, guardOptions(guard)...)
+
+	_, err := client.GetBlockCount(context.Background(), &pb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "key")
+	count, err := client.GetBlockCount(ctx, &pb.Empty{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), count.Count)
+
+	stream, err := client.SubscribeBlocks(context.Background(), &pb.SubscribeBlocksRequest{Resume: true})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	stream, err = client.SubscribeBlocks(ctx, &pb.SubscribeBlocksRequest{Resume: true})
+	assert.Nil(t, err)
+	block, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), block.Header.Height)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package grpc provides the gRPC server of the node
+package grpc
+
+import (
+	"net"
+	"strconv"
+	"sync"
+
+	cfg "github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/events/message"
+	"github.com/polynetwork/poly/http/base/access"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/http/grpc/pb"
+	gogrpc "google.golang.org/grpc"
+)
+
+//blockNotifier wakes up the waiting subscriptions when a block is saved
+type blockNotifier struct {
+	lock     sync.Mutex
+	newBlock chan struct{} //closed when the next block is saved
+}
+
+func newBlockNotifier() *blockNotifier {
+	return &blockNotifier{newBlock: make(chan struct{})}
+}
+
+//wait returns a channel closed when the next block is saved
+func (self *blockNotifier) wait() <-chan struct{} {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.newBlock
+}
+
+func (self *blockNotifier) notify() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	close(self.newBlock)
+	self.newBlock = make(chan struct{})
+}
+
+//StartServer serves pb.PolyServer on the configured port, behind the api access guard of the other servers
+func StartServer() {
+	notifier := newBlockNotifier()
+	bactor.SubscribeEvent(message.TOPIC_SAVE_BLOCK_COMPLETE, func(v interface{}) {
+		notifier.notify()
+	})
+
+	port := int(cfg.DefConfig.Grpc.GrpcPort)
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		log.Errorf("grpc server listen: %s", err)
+		return
+	}
+	server := gogrpc.NewServer(guardOptions(access.DefGuard)...)
+	pb.RegisterPolyServer(server, newPolyService(notifier))
+	if err := server.Serve(listener); err != nil {
+		log.Errorf("grpc server error: %s", err)
+	}
+}
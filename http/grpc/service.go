@@ -0,0 +1,306 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/polynetwork/poly/common"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	ontErrors "github.com/polynetwork/poly/errors"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	"github.com/polynetwork/poly/http/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//PolyService implements pb.PolyServer on the same backend calls as the json rpc and restful apis
+type PolyService struct {
+	notifier *blockNotifier
+}
+
+//newPolyService creates the service, notifier wakes up the subscriptions when a block is saved
+func newPolyService(notifier *blockNotifier) *PolyService {
+	return &PolyService{notifier: notifier}
+}
+
+func parseHash(str string) (common.Uint256, error) {
+	hash, err := common.Uint256FromHexString(str)
+	if err != nil {
+		return common.UINT256_EMPTY, status.Errorf(codes.InvalidArgument, "invalid hash %s: %s", str, err)
+	}
+	return hash, nil
+}
+
+//storeError converts a ledger error
+func storeError(err error, what string) error {
+	if err == scom.ErrNotFound {
+		return status.Errorf(codes.NotFound, "unknown %s", what)
+	}
+	return status.Errorf(codes.Internal, "get %s: %s", what, err)
+}
+
+func (self *PolyService) GetBlockCount(ctx context.Context, req *pb.Empty) (*pb.BlockCount, error) {
+	return &pb.BlockCount{Count: bactor.GetCurrentBlockHeight() + 1}, nil
+}
+
+func getBlock(req *pb.BlockRequest) (*types.Block, error) {
+	var block *types.Block
+	var err error
+	if req.Hash != "" {
+		var hash common.Uint256
+		hash, err = parseHash(req.Hash)
+		if err != nil {
+			return nil, err
+		}
+		block, err = bactor.GetBlockFromStore(hash)
+	} else {
+		block, err = bactor.GetBlockByHeight(req.Height)
+	}
+	if err != nil {
+		return nil, storeError(err, "block")
+	}
+	if block == nil || block.Header == nil {
+		return nil, status.Error(codes.NotFound, "unknown block")
+	}
+	return block, nil
+}
+
+func (self *PolyService) GetBlock(ctx context.Context, req *pb.BlockRequest) (*pb.Block, error) {
+	block, err := getBlock(req)
+	if err != nil {
+		return nil, err
+	}
+	return convertBlock(block), nil
+}
+
+func (self *PolyService) GetHeader(ctx context.Context, req *pb.BlockRequest) (*pb.Header, error) {
+	var header *types.Header
+	var err error
+	if req.Hash != "" {
+		var hash common.Uint256
+		hash, err = parseHash(req.Hash)
+		if err != nil {
+			return nil, err
+		}
+		header, err = bactor.GetHeaderByHash(hash)
+	} else {
+		header, err = bactor.GetHeaderByHeight(req.Height)
+	}
+	if err != nil {
+		return nil, storeError(err, "header")
+	}
+	if header == nil {
+		return nil, status.Error(codes.NotFound, "unknown header")
+	}
+	return convertHeader(header), nil
+}
+
+func (self *PolyService) GetTransaction(ctx context.Context, req *pb.TxRequest) (*pb.Transaction, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	height, tx, err := bactor.GetTxnWithHeightByTxHash(hash)
+	if err != nil && err != scom.ErrNotFound {
+		return nil, storeError(err, "transaction")
+	}
+	if tx != nil {
+		return convertTransaction(tx, height), nil
+	}
+	entry, err := bactor.GetTxFromPool(hash)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "unknown transaction")
+	}
+	return convertTransaction(entry.Tx, 0), nil
+}
+
+func (self *PolyService) SendRawTransaction(ctx context.Context, req *pb.SendRawTransactionRequest) (*pb.SendRawTransactionResponse, error) {
+	tx, err := types.TransactionFromRawBytes(req.Raw)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid transaction: %s", err)
+	}
+	hash := tx.Hash()
+	resp := &pb.SendRawTransactionResponse{Hash: hash.ToHexString()}
+	if req.PreExec {
+		if tx.TxType != types.Invoke {
+			return nil, status.Error(codes.InvalidArgument, "only invoke transactions can be pre-executed")
+		}
+		result, err := bactor.PreExecuteContract(tx)
+		if err != nil {
+			return nil, status.Errorf(codes.Aborted, "pre-execute: %s", err)
+		}
+		resp.PreExecResult = convertPreExecResult(result)
+		return resp, nil
+	}
+	if errCode, desc := bcomn.SendTxToPool(tx); errCode != ontErrors.ErrNoError {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s: %s", errCode.Error(), desc)
+	}
+	return resp, nil
+}
+
+func (self *PolyService) GetStorage(ctx context.Context, req *pb.StorageRequest) (*pb.Storage, error) {
+	address, err := bcomn.GetAddress(req.Contract)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid contract %s: %s", req.Contract, err)
+	}
+	value, err := bactor.GetStorageItem(address, req.Key)
+	if err != nil && err != scom.ErrNotFound {
+		return nil, storeError(err, "storage")
+	}
+	return &pb.Storage{Value: value}, nil
+}
+
+func (self *PolyService) GetEventsByTx(ctx context.Context, req *pb.TxRequest) (*pb.ExecuteNotify, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	notify, err := bactor.GetEventNotifyByTxHash(hash)
+	if err != nil {
+		return nil, storeError(err, "transaction events")
+	}
+	if notify == nil {
+		return nil, status.Error(codes.NotFound, "unknown transaction events")
+	}
+	_, converted := bcomn.GetExecuteNotify(notify)
+	return convertExecuteNotify(converted), nil
+}
+
+func getBlockEvents(height uint32, filter *bcomn.EventFilter) (*pb.BlockEvents, error) {
+	notifies, err := bactor.GetEventNotifyByHeight(height)
+	if err != nil && err != scom.ErrNotFound {
+		return nil, storeError(err, "block events")
+	}
+	return convertBlockEvents(height, notifies, filter), nil
+}
+
+func (self *PolyService) GetEventsByHeight(ctx context.Context, req *pb.BlockRequest) (*pb.BlockEvents, error) {
+	height := req.Height
+	if req.Hash != "" {
+		block, err := getBlock(req)
+		if err != nil {
+			return nil, err
+		}
+		height = block.Header.Height
+	} else if height > bactor.GetCurrentBlockHeight() {
+		return nil, status.Error(codes.NotFound, "unknown block")
+	}
+	return getBlockEvents(height, &bcomn.EventFilter{})
+}
+
+func (self *PolyService) GetMerkleProof(ctx context.Context, req *pb.MerkleProofRequest) (*pb.Proof, error) {
+	if req.Height == 0 || req.Height >= req.RootHeight {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot get proof of block hash at height: %d when the block root is at height: %d",
+			req.Height, req.RootHeight)
+	}
+	proof, err := bactor.GetMerkleProof(req.Height, req.RootHeight)
+	if err != nil {
+		return nil, storeError(err, "merkle proof")
+	}
+	return &pb.Proof{AuditPath: proof}, nil
+}
+
+func (self *PolyService) GetCrossStatesProof(ctx context.Context, req *pb.CrossStatesProofRequest) (*pb.Proof, error) {
+	proof, err := bactor.GetCrossStatesProof(req.Height, req.Key)
+	if err != nil {
+		return nil, storeError(err, "cross states proof")
+	}
+	return &pb.Proof{AuditPath: proof}, nil
+}
+
+func (self *PolyService) GetMemPoolTxCount(ctx context.Context, req *pb.Empty) (*pb.MemPoolTxCount, error) {
+	count, err := bactor.GetTxnCount()
+	if err != nil || len(count) < 2 {
+		return nil, status.Errorf(codes.Internal, "get mempool tx count: %v", err)
+	}
+	return &pb.MemPoolTxCount{Verified: count[0], Pending: count[1]}, nil
+}
+
+func (self *PolyService) GetMemPoolTxState(ctx context.Context, req *pb.TxRequest) (*pb.MemPoolTxState, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := bactor.GetTxFromPool(hash)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "unknown transaction")
+	}
+	results := make([]*pb.TxVerifyResult, 0, len(entry.Attrs))
+	for _, attr := range entry.Attrs {
+		results = append(results, &pb.TxVerifyResult{
+			Height:  attr.Height,
+			Type:    uint32(attr.Type),
+			ErrCode: uint32(attr.ErrCode),
+		})
+	}
+	return &pb.MemPoolTxState{Results: results}, nil
+}
+
+//startHeight is the first block streamed to a subscription
+func startHeight(resume bool, fromHeight uint32) uint32 {
+	if resume {
+		return fromHeight
+	}
+	return bactor.GetCurrentBlockHeight() + 1
+}
+
+//follow calls send for every block from height on, until send fails or the client is gone
+func (self *PolyService) follow(ctx context.Context, height uint32, send func(uint32) error) error {
+	for {
+		newBlock := self.notifier.wait()
+		for current := bactor.GetCurrentBlockHeight(); height <= current; height++ {
+			if err := send(height); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-newBlock:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (self *PolyService) SubscribeBlocks(req *pb.SubscribeBlocksRequest, stream pb.Poly_SubscribeBlocksServer) error {
+	return self.follow(stream.Context(), startHeight(req.Resume, req.FromHeight), func(height uint32) error {
+		block, err := bactor.GetBlockByHeight(height)
+		if err != nil {
+			return storeError(err, "block")
+		}
+		return stream.Send(convertBlock(block))
+	})
+}
+
+func (self *PolyService) SubscribeEvents(req *pb.SubscribeEventsRequest, stream pb.Poly_SubscribeEventsServer) error {
+	filter := &bcomn.EventFilter{
+		Contracts:    req.Contracts,
+		EventNames:   req.EventNames,
+		FromChainIds: req.FromChainIds,
+		ToChainIds:   req.ToChainIds,
+	}
+	return self.follow(stream.Context(), startHeight(req.Resume, req.FromHeight), func(height uint32) error {
+		events, err := getBlockEvents(height, filter)
+		if err != nil {
+			return err
+		}
+		return stream.Send(events)
+	})
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/consensus/vbft/harness"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/http/grpc/pb"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/stretchr/testify/assert"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+//newTestClient serves the genesis ledger of a harness node with the server options opts
+func newTestClient(t *testing.T, opts ...gogrpc.ServerOption) (pb.PolyClient, *blockNotifier) {
+	cluster, err := harness.NewCluster(&harness.Config{Nodes: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := ledger.DefLedger
+	ledger.DefLedger = cluster.Nodes[0].Ledger
+
+	notifier := newBlockNotifier()
+	listener := bufconn.Listen(1 << 20)
+	server := gogrpc.NewServer(opts...)
+	pb.RegisterPolyServer(server, newPolyService(notifier))
+	go server.Serve(listener)
+
+	conn, err := gogrpc.Dial("bufnet", gogrpc.WithInsecure(),
+		gogrpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.Dial()
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+		ledger.DefLedger = old
+		cluster.Stop()
+	})
+	return pb.NewPolyClient(conn), notifier
+}
+
+func TestQueries(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	count, err := client.GetBlockCount(ctx, &pb.Empty{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), count.Count)
+
+	block, err := client.GetBlock(ctx, &pb.BlockRequest{Height: 0})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), block.Header.Height)
+	assert.Equal(t, 1, len(block.Transactions))
+
+	header, err := client.GetHeader(ctx, &pb.BlockRequest{Hash: block.Header.Hash})
+	assert.Nil(t, err)
+	assert.Equal(t, block.Header.Raw, header.Raw)
+
+	tx, err := client.GetTransaction(ctx, &pb.TxRequest{Hash: block.Transactions[0].Hash})
+	assert.Nil(t, err)
+	assert.Equal(t, block.Transactions[0].Raw, tx.Raw)
+
+	_, err = client.GetBlock(ctx, &pb.BlockRequest{Height: 10})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetHeader(ctx, &pb.BlockRequest{Hash: common.UINT256_EMPTY.ToHexString()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetTransaction(ctx, &pb.TxRequest{Hash: "xx"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	storage, err := client.GetStorage(ctx, &pb.StorageRequest{
+		Contract: utils.NodeManagerContractAddress.ToHexString(),
+		Key:      []byte(node_manager.VBFT_CONFIG),
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, storage.Value)
+
+	events, err := client.GetEventsByHeight(ctx, &pb.BlockRequest{Height: 0})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(events.Events))
+	assert.Equal(t, block.Transactions[0].Hash, events.Events[0].TxHash)
+}
+
+func TestSubscribeBlocks(t *testing.T) {
+	client, notifier := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.SubscribeBlocks(ctx, &pb.SubscribeBlocksRequest{Resume: true, FromHeight: 0})
+	assert.Nil(t, err)
+	block, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), block.Header.Height)
+
+	events, err := client.SubscribeEvents(ctx, &pb.SubscribeEventsRequest{Resume: true, EventNames: []string{"makeProof"}})
+	assert.Nil(t, err)
+	blockEvents, err := events.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), blockEvents.Height)
+	assert.Empty(t, blockEvents.Events)
+
+	//no new block to stream
+	notifier.notify()
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package jsonrpc
+
+import (
+	"github.com/polynetwork/poly/consensus/vbft"
+	"github.com/polynetwork/poly/http/base/api"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	"github.com/polynetwork/poly/http/base/rpc"
+)
+
+//a json rpc method and its handler, the description is used both to register
+//the handler and to answer rpc_discover
+type rpcMethod struct {
+	spec    *api.Method
+	handler func([]interface{}) map[string]interface{}
+}
+
+var (
+	heightSchema  = api.Integer("block height")
+	hashSchema    = api.Hex("hash in hex")
+	chainIdSchema = api.Integer("side chain id")
+	verboseSchema = api.Integer("1 for the json form, the hex serialization otherwise")
+)
+
+var methods = []*rpcMethod{
+	{&api.Method{Name: "getbestblockhash", Summary: "hash of the current block",
+		Result: hashSchema}, rpc.GetBestBlockHash},
+	{&api.Method{Name: "getblock", Summary: "block by height or hash",
+		Params: []*api.Param{
+			api.NewParam("heightOrHash", "block height or block hash", true, api.OneOf("", heightSchema, hashSchema)),
+			api.NewParam("verbose", "", false, verboseSchema),
+		},
+		Result: api.OneOf("", api.Hex("serialized block"), api.SchemaOf(bcomn.BlockInfo{}))}, rpc.GetBlock},
+	{&api.Method{Name: "getblockcount", Summary: "number of blocks, the current height plus one",
+		Result: api.Integer("")}, rpc.GetBlockCount},
+	{&api.Method{Name: "getblockhash", Summary: "hash of the block at height",
+		Params: []*api.Param{api.NewParam("height", "", true, heightSchema)},
+		Result: hashSchema}, rpc.GetBlockHash},
+	{&api.Method{Name: "getlatestblockmsgssnap", Summary: "consensus messages of the latest block",
+		Result: api.SchemaOf(vbft.LatestBlockMsgsSnap{})}, rpc.GetLatestBlockMsgsSnap},
+	{&api.Method{Name: "getconsensustrace", Summary: "consensus round trace of the block at height",
+		Params: []*api.Param{api.NewParam("height", "", true, heightSchema)},
+		Result: api.SchemaOf(vbft.RoundTrace{})}, rpc.GetConsensusTrace},
+	{&api.Method{Name: "getconnectioncount", Summary: "number of connected peers",
+		Result: api.Integer("")}, rpc.GetConnectionCount},
+	{&api.Method{Name: "getrawtransaction", Summary: "transaction by hash",
+		Params: []*api.Param{
+			api.NewParam("hash", "transaction hash", true, hashSchema),
+			api.NewParam("verbose", "", false, verboseSchema),
+		},
+		Result: api.OneOf("", api.Hex("serialized transaction"), api.SchemaOf(bcomn.Transactions{}))}, rpc.GetRawTransaction},
+	{&api.Method{Name: "sendrawtransaction", Summary: "send a transaction to the pool, or pre-execute it",
+		Params: []*api.Param{
+			api.NewParam("tx", "serialized transaction in hex", true, api.Hex("")),
+			api.NewParam("preExec", "1 to pre-execute an invoke transaction without sending it", false, api.Integer("")),
+		},
+		Result: api.OneOf("", api.Hex("transaction hash"), api.SchemaOf(bcomn.PreExecuteResult{})),
+		Write:  true}, rpc.SendRawTransaction},
+	{&api.Method{Name: "callnative", Summary: "run a native contract method read-only",
+		Params: []*api.Param{
+			api.NewParam("contract", "native contract address in hex", true, api.Hex("")),
+			api.NewParam("method", "contract method", true, api.String("")),
+			api.NewParam("args", "serialized method args in hex", false, api.Hex("")),
+		},
+		Result: api.SchemaOf(bcomn.CallNativeResult{})}, rpc.CallNative},
+	{&api.Method{Name: "getstorage", Summary: "storage item of a contract, null if not found",
+		Params: []*api.Param{
+			api.NewParam("contract", "contract address in hex", true, api.Hex("")),
+			api.NewParam("key", "storage key in hex", true, api.Hex("")),
+		},
+		Result: api.Hex("storage value")}, rpc.GetStorage},
+	{&api.Method{Name: "getversion", Summary: "node version",
+		Result: api.String("")}, rpc.GetNodeVersion},
+	{&api.Method{Name: "getnetworkid", Summary: "p2p network id",
+		Result: api.Integer("")}, rpc.GetNetworkId},
+	{&api.Method{Name: "getmempooltxcount", Summary: "number of transactions in the pool",
+		Result: api.Array(api.Integer(""))}, rpc.GetMemPoolTxCount},
+	{&api.Method{Name: "getmempool", Summary: "verified transactions in the pool by priority and arrival, page by page",
+		Params: []*api.Param{
+			api.NewParam("offset", "matching transactions to skip", false, api.Integer("")),
+			api.NewParam("limit", "max transactions to return, 100 by default and 1000 at most", false, api.Integer("")),
+			api.NewParam("signer", "signer address to match, empty for any", false, api.String("")),
+			api.NewParam("contract", "native contract name or address in hex to match, empty for any", false, api.String("")),
+			api.NewParam("method", "contract method to match, empty for any", false, api.String("")),
+		},
+		Result: api.SchemaOf(bcomn.MemPoolInfo{})}, rpc.GetMemPool},
+	{&api.Method{Name: "getmempoolstats", Summary: "statistics of the verified transactions in the pool",
+		Params: []*api.Param{api.NewParam("top", "number of signers with the most transactions to list", false, api.Integer(""))},
+		Result: api.SchemaOf(bcomn.TxPoolStatsInfo{})}, rpc.GetMemPoolStats},
+	{&api.Method{Name: "getmempooltxstate", Summary: "verification state of a transaction in the pool",
+		Params: []*api.Param{api.NewParam("hash", "transaction hash", true, hashSchema)},
+		Result: api.SchemaOf(bcomn.TXNEntryInfo{})}, rpc.GetMemPoolTxState},
+	{&api.Method{Name: "getsmartcodeevent", Summary: "contract events of a block or a transaction",
+		Params: []*api.Param{api.NewParam("heightOrHash", "block height or transaction hash", true, api.OneOf("", heightSchema, hashSchema))},
+		Result: api.OneOf("", api.Array(api.SchemaOf(bcomn.ExecuteNotify{})), api.SchemaOf(bcomn.ExecuteNotify{}))}, rpc.GetSmartCodeEvent},
+	{&api.Method{Name: "getblockheightbytxhash", Summary: "height of the block including a transaction",
+		Params: []*api.Param{api.NewParam("hash", "transaction hash", true, hashSchema)},
+		Result: api.Integer("")}, rpc.GetBlockHeightByTxHash},
+	{&api.Method{Name: "getmerkleproof", Summary: "merkle proof of a block against the block root at rootHeight",
+		Params: []*api.Param{
+			api.NewParam("height", "", true, heightSchema),
+			api.NewParam("rootHeight", "", true, heightSchema),
+		},
+		Result: api.SchemaOf(bcomn.MerkleProof{})}, rpc.GetMerkleProof},
+	{&api.Method{Name: "getcrossstatesproof", Summary: "proof of a cross chain message against the cross states root at height",
+		Params: []*api.Param{
+			api.NewParam("height", "", true, heightSchema),
+			api.NewParam("key", "cross states key in hex", true, api.Hex("")),
+		},
+		Result: api.SchemaOf(bcomn.MerkleProof{})}, rpc.GetCrossStatesProof},
+	{&api.Method{Name: "getheaderbyheight", Summary: "poly header by height, or a synced side chain header when chainId is given",
+		Params: []*api.Param{
+			api.NewParam("chainId", "side chain id, omit or null for poly", false, chainIdSchema),
+			api.NewParam("height", "", true, heightSchema),
+		},
+		Result: api.OneOf("", api.Hex("serialized poly header"), api.Any("side chain header"))}, rpc.GetHeaderByHeight},
+	{&api.Method{Name: "getblocktxsbyheight", Summary: "transaction hashes of the block at height",
+		Params: []*api.Param{api.NewParam("height", "", true, heightSchema)},
+		Result: api.SchemaOf(bcomn.BlockTransactions{})}, rpc.GetBlockTxsByHeight},
+	{&api.Method{Name: "getstatemerkleroot", Summary: "cross states merkle root at height",
+		Params: []*api.Param{api.NewParam("height", "", true, heightSchema)},
+		Result: hashSchema}, rpc.GetStateMerkleRoot},
+	{&api.Method{Name: "getsidechain", Summary: "registered side chain, null if not found",
+		Params: []*api.Param{api.NewParam("chainId", "", true, chainIdSchema)},
+		Result: api.SchemaOf(bcomn.SideChainInfo{})}, rpc.GetSideChain},
+	{&api.Method{Name: "listsidechains", Summary: "registered side chains",
+		Result: api.Array(api.SchemaOf(bcomn.SideChainInfo{}))}, rpc.ListSideChains},
+	{&api.Method{Name: "getproposal", Summary: "open governance proposal, null if not found",
+		Params: []*api.Param{api.NewParam("id", "proposal id", true, api.Integer(""))},
+		Result: api.SchemaOf(bcomn.ProposalInfo{})}, rpc.GetProposal},
+	{&api.Method{Name: "listproposals", Summary: "open governance proposals, including the expired ones not closed yet",
+		Result: api.Array(api.SchemaOf(bcomn.ProposalInfo{}))}, rpc.ListProposals},
+	{&api.Method{Name: "getheadersynctip", Summary: "height of the latest synced header of a side chain",
+		Params: []*api.Param{api.NewParam("chainId", "", true, chainIdSchema)},
+		Result: api.SchemaOf(bcomn.HeaderSyncTip{})}, rpc.GetHeaderSyncTip},
+	{&api.Method{Name: "rpc_discover", Summary: "registered methods with their params and result schemas",
+		Result: api.SchemaOf(api.Discovery{})}, rpc.Discover},
+}
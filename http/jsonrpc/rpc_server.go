@@ -27,39 +27,21 @@ import (
 
 	cfg "github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/http/base/access"
 	"github.com/polynetwork/poly/http/base/rpc"
 )
 
 func StartRPCServer() error {
 	log.Debug()
-	http.HandleFunc("/", rpc.Handle)
+	mux := http.NewServeMux()
+	mux.Handle("/", access.DefGuard.Handler(http.HandlerFunc(rpc.Handle)))
+	rpc.SetMaxBatchSize(cfg.DefConfig.Rpc.HttpMaxBatchSize)
 
-	rpc.HandleFunc("getbestblockhash", rpc.GetBestBlockHash)
-	rpc.HandleFunc("getblock", rpc.GetBlock)
-	rpc.HandleFunc("getblockcount", rpc.GetBlockCount)
-	rpc.HandleFunc("getblockhash", rpc.GetBlockHash)
-	rpc.HandleFunc("getlatestblockmsgssnap", rpc.GetLatestBlockMsgsSnap)
-	rpc.HandleFunc("getconnectioncount", rpc.GetConnectionCount)
-	//HandleFunc("getrawmempool", GetRawMemPool)
-
-	rpc.HandleFunc("getrawtransaction", rpc.GetRawTransaction)
-	rpc.HandleFunc("sendrawtransaction", rpc.SendRawTransaction)
-	rpc.HandleFunc("getstorage", rpc.GetStorage)
-	rpc.HandleFunc("getversion", rpc.GetNodeVersion)
-	rpc.HandleFunc("getnetworkid", rpc.GetNetworkId)
-
-	rpc.HandleFunc("getmempooltxcount", rpc.GetMemPoolTxCount)
-	rpc.HandleFunc("getmempooltxstate", rpc.GetMemPoolTxState)
-	rpc.HandleFunc("getsmartcodeevent", rpc.GetSmartCodeEvent)
-	rpc.HandleFunc("getblockheightbytxhash", rpc.GetBlockHeightByTxHash)
-
-	rpc.HandleFunc("getmerkleproof", rpc.GetMerkleProof)
-	rpc.HandleFunc("getcrossstatesproof", rpc.GetCrossStatesProof)
-	rpc.HandleFunc("getheaderbyheight", rpc.GetHeaderByHeight)
-	rpc.HandleFunc("getblocktxsbyheight", rpc.GetBlockTxsByHeight)
-	rpc.HandleFunc("getstatemerkleroot", rpc.GetStateMerkleRoot)
+	for _, m := range methods {
+		rpc.Register(m.spec, m.handler)
+	}
 
-	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpJsonPort)), nil)
+	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpJsonPort)), mux)
 	if err != nil {
 		return fmt.Errorf("ListenAndServe error:%s", err)
 	}
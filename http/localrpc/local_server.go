@@ -26,7 +26,10 @@ import (
 	"fmt"
 	cfg "github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/http/base/api"
+	"github.com/polynetwork/poly/http/base/common"
 	"github.com/polynetwork/poly/http/base/rpc"
+	p2pcom "github.com/polynetwork/poly/p2pserver/common"
 )
 
 const (
@@ -34,18 +37,30 @@ const (
 	LOCAL_DIR  string = "/local"
 )
 
+//StartLocalServer serves the node management methods on local host, they are
+//registered to a multiplexer of their own so that the public rpc server doesn't expose them
 func StartLocalServer() error {
 	log.Debug()
-	http.HandleFunc(LOCAL_DIR, rpc.Handle)
+	localMux := rpc.NewServeMux()
+	mux := http.NewServeMux()
+	mux.Handle(LOCAL_DIR, localMux)
 
-	rpc.HandleFunc("getneighbor", rpc.GetNeighbor)
-	rpc.HandleFunc("getnodestate", rpc.GetNodeState)
-	rpc.HandleFunc("startconsensus", rpc.StartConsensus)
-	rpc.HandleFunc("stopconsensus", rpc.StopConsensus)
-	rpc.HandleFunc("setdebuginfo", rpc.SetDebugInfo)
+	localMux.Register(&api.Method{Name: "getneighbor", Summary: "addresses of the connected peers",
+		Result: api.Array(api.SchemaOf(p2pcom.PeerAddr{}))}, rpc.GetNeighbor)
+	localMux.Register(&api.Method{Name: "getnodestate", Summary: "state of the local node",
+		Result: api.SchemaOf(common.NodeInfo{})}, rpc.GetNodeState)
+	localMux.Register(&api.Method{Name: "startconsensus", Summary: "start the consensus service",
+		Result: api.Boolean(""), Write: true}, rpc.StartConsensus)
+	localMux.Register(&api.Method{Name: "stopconsensus", Summary: "stop the consensus service",
+		Result: api.Boolean(""), Write: true}, rpc.StopConsensus)
+	localMux.Register(&api.Method{Name: "setdebuginfo", Summary: "set the log level",
+		Params: []*api.Param{api.NewParam("level", "log level", true, api.Integer(""))},
+		Result: api.Boolean(""), Write: true}, rpc.SetDebugInfo)
+	localMux.Register(&api.Method{Name: "dropmempooltx", Summary: "drop a verified transaction from the local pool",
+		Params: []*api.Param{api.NewParam("hash", "transaction hash", true, api.Hex(""))},
+		Result: api.Boolean(""), Write: true}, rpc.DropMemPoolTx)
 
-	// TODO: only listen to local host
-	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpLocalPort)), nil)
+	err := http.ListenAndServe(LOCAL_HOST+":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpLocalPort)), mux)
 	if err != nil {
 		return fmt.Errorf("ListenAndServe error:%s", err)
 	}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package metrics provides the prometheus metrics server
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/common/log"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+)
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	metrics, err := bactor.GetConsensusMetrics()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(metrics)
+}
+
+// StartServer serves the consensus metrics in prometheus text format on /metrics
+func StartServer() {
+	port := int(config.DefConfig.Metrics.HttpMetricsPort)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", metricsHandler)
+	if err := http.ListenAndServe(":"+strconv.Itoa(port), mux); err != nil {
+		log.Errorf("metrics server error: %s", err)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package restful
+
+import (
+	"net/http"
+
+	"github.com/polynetwork/poly/http/base/api"
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	"github.com/polynetwork/poly/http/base/rest"
+)
+
+var (
+	heightParam  = api.NewPathParam("height", "Height", "block height", api.Integer(""))
+	hashParam    = api.NewPathParam("hash", "Hash", "hash in hex", api.Hex(""))
+	chainIdParam = api.NewPathParam("chainid", "ChainId", "side chain id", api.Integer(""))
+	rawParam     = api.NewQueryParam("raw", "Raw", "1 for the hex serialization instead of the json form", api.Integer(""))
+)
+
+//routes served by the restful server, the routes are registered to the router
+//and documented by the openapi document from this table
+var routes = []*Action{
+	{route: &api.Route{Action: "getconnectioncount", HttpMethod: http.MethodGet, Path: GET_CONN_COUNT,
+		Summary: "number of connected peers",
+		Result:  api.Integer("")}, handler: rest.GetConnectionCount},
+	{route: &api.Route{Action: "getblocktxsbyheight", HttpMethod: http.MethodGet, Path: GET_BLK_TXS_BY_HEIGHT,
+		Summary: "transaction hashes of the block at height",
+		Params:  []*api.Param{heightParam},
+		Result:  api.SchemaOf(bcomn.BlockTransactions{})}, handler: rest.GetBlockTxsByHeight},
+	{route: &api.Route{Action: "getblockbyheight", HttpMethod: http.MethodGet, Path: GET_BLK_BY_HEIGHT,
+		Summary: "block at height",
+		Params:  []*api.Param{heightParam, rawParam},
+		Result:  api.OneOf("", api.Hex("serialized block"), api.SchemaOf(bcomn.BlockInfo{}))}, handler: rest.GetBlockByHeight},
+	{route: &api.Route{Action: "getblockbyhash", HttpMethod: http.MethodGet, Path: GET_BLK_BY_HASH,
+		Summary: "block by hash",
+		Params:  []*api.Param{hashParam, rawParam},
+		Result:  api.OneOf("", api.Hex("serialized block"), api.SchemaOf(bcomn.BlockInfo{}))}, handler: rest.GetBlockByHash},
+	{route: &api.Route{Action: "getblockheight", HttpMethod: http.MethodGet, Path: GET_BLK_HEIGHT,
+		Summary: "current block height",
+		Result:  api.Integer("")}, handler: rest.GetBlockHeight},
+	{route: &api.Route{Action: "getblockhash", HttpMethod: http.MethodGet, Path: GET_BLK_HASH,
+		Summary: "hash of the block at height",
+		Params:  []*api.Param{heightParam},
+		Result:  api.Hex("")}, handler: rest.GetBlockHash},
+	{route: &api.Route{Action: "gettransaction", HttpMethod: http.MethodGet, Path: GET_TX,
+		Summary: "transaction by hash",
+		Params:  []*api.Param{hashParam, rawParam},
+		Result:  api.OneOf("", api.Hex("serialized transaction"), api.SchemaOf(bcomn.Transactions{}))}, handler: rest.GetTransactionByHash},
+	{route: &api.Route{Action: "getsmartcodeeventbyheight", HttpMethod: http.MethodGet, Path: GET_SMTCOCE_EVT_TXS,
+		Summary: "contract events of the block at height",
+		Params:  []*api.Param{heightParam},
+		Result:  api.Array(api.SchemaOf(bcomn.ExecuteNotify{}))}, handler: rest.GetSmartCodeEventTxsByHeight},
+	{route: &api.Route{Action: "getsmartcodeeventbyhash", HttpMethod: http.MethodGet, Path: GET_SMTCOCE_EVTS,
+		Summary: "contract events of a transaction",
+		Params:  []*api.Param{hashParam},
+		Result:  api.SchemaOf(bcomn.ExecuteNotify{})}, handler: rest.GetSmartCodeEventByTxHash},
+	{route: &api.Route{Action: "getblockheightbytxhash", HttpMethod: http.MethodGet, Path: GET_BLK_HGT_BY_TXHASH,
+		Summary: "height of the block including a transaction",
+		Params:  []*api.Param{hashParam},
+		Result:  api.Integer("")}, handler: rest.GetBlockHeightByTxHash},
+	{route: &api.Route{Action: "getstorage", HttpMethod: http.MethodGet, Path: GET_STORAGE,
+		Summary: "storage item of a contract",
+		Params: []*api.Param{
+			api.NewPathParam("hash", "Hash", "contract address in hex", api.Hex("")),
+			api.NewPathParam("key", "Key", "storage key in hex", api.Hex("")),
+		},
+		Result: api.Hex("storage value")}, handler: rest.GetStorage},
+	{route: &api.Route{Action: "getmerkleproof", HttpMethod: http.MethodGet, Path: GET_MERKLE_PROOF,
+		Summary: "merkle proof of a block against the block root at rheight",
+		Params: []*api.Param{
+			api.NewPathParam("bheight", "BlockHeight", "block height", api.Integer("")),
+			api.NewPathParam("rheight", "RootHeight", "root height", api.Integer("")),
+		},
+		Result: api.SchemaOf(bcomn.MerkleProof{})}, handler: rest.GetMerkleProof},
+	{route: &api.Route{Action: "getmempooltxcount", HttpMethod: http.MethodGet, Path: GET_MEMPOOL_TXCOUNT,
+		Summary: "number of transactions in the pool",
+		Result:  api.Array(api.Integer(""))}, handler: rest.GetMemPoolTxCount},
+	{route: &api.Route{Action: "getmempoolstats", HttpMethod: http.MethodGet, Path: GET_MEMPOOL_STATS,
+		Summary: "statistics of the verified transactions in the pool",
+		Params:  []*api.Param{api.NewQueryParam("top", "Top", "number of signers with the most transactions to list", api.Integer(""))},
+		Result:  api.SchemaOf(bcomn.TxPoolStatsInfo{})}, handler: rest.GetMemPoolStats},
+	{route: &api.Route{Action: "getmempooltxstate", HttpMethod: http.MethodGet, Path: GET_MEMPOOL_TXSTATE,
+		Summary: "verification state of a transaction in the pool",
+		Params:  []*api.Param{hashParam},
+		Result:  api.SchemaOf(bcomn.TXNEntryInfo{})}, handler: rest.GetMemPoolTxState},
+	{route: &api.Route{Action: "getversion", HttpMethod: http.MethodGet, Path: GET_VERSION,
+		Summary: "node version",
+		Result:  api.String("")}, handler: rest.GetNodeVersion},
+	{route: &api.Route{Action: "getnetworkid", HttpMethod: http.MethodGet, Path: GET_NETWORKID,
+		Summary: "p2p network id",
+		Result:  api.Integer("")}, handler: rest.GetNetworkId},
+	{route: &api.Route{Action: "getsidechains", HttpMethod: http.MethodGet, Path: GET_SIDE_CHAINS,
+		Summary: "registered side chains",
+		Result:  api.Array(api.SchemaOf(bcomn.SideChainInfo{}))}, handler: rest.GetSideChains},
+	{route: &api.Route{Action: "getsidechain", HttpMethod: http.MethodGet, Path: GET_SIDE_CHAIN,
+		Summary: "registered side chain",
+		Params:  []*api.Param{chainIdParam},
+		Result:  api.SchemaOf(bcomn.SideChainInfo{})}, handler: rest.GetSideChain},
+	{route: &api.Route{Action: "getrelayers", HttpMethod: http.MethodGet, Path: GET_RELAYERS,
+		Summary: "addresses of the registered relayers",
+		Result:  api.Array(api.Hex(""))}, handler: rest.GetRelayers},
+	{route: &api.Route{Action: "getpeerpool", HttpMethod: http.MethodGet, Path: GET_PEER_POOL,
+		Summary: "consensus peers",
+		Result:  api.Array(api.SchemaOf(bcomn.PeerPoolItemInfo{}))}, handler: rest.GetPeerPool},
+	{route: &api.Route{Action: "getgovernanceview", HttpMethod: http.MethodGet, Path: GET_GOVERNANCE_VIEW,
+		Summary: "current governance view",
+		Result:  api.SchemaOf(bcomn.GovernanceViewInfo{})}, handler: rest.GetGovernanceView},
+	{route: &api.Route{Action: "getproposals", HttpMethod: http.MethodGet, Path: GET_PROPOSALS,
+		Summary: "open governance proposals",
+		Result:  api.Array(api.SchemaOf(bcomn.ProposalInfo{}))}, handler: rest.GetProposals},
+	{route: &api.Route{Action: "getproposal", HttpMethod: http.MethodGet, Path: GET_PROPOSAL,
+		Summary: "open governance proposal",
+		Params:  []*api.Param{api.NewPathParam("id", "Id", "proposal id", api.Integer(""))},
+		Result:  api.SchemaOf(bcomn.ProposalInfo{})}, handler: rest.GetProposal},
+	{route: &api.Route{Action: "getblackedchains", HttpMethod: http.MethodGet, Path: GET_BLACKED_CHAINS,
+		Summary: "ids of the blacked side chains",
+		Result:  api.Array(api.Integer(""))}, handler: rest.GetBlackedChains},
+	{route: &api.Route{Action: "getheadersynctips", HttpMethod: http.MethodGet, Path: GET_HEADER_SYNC_TIPS,
+		Summary: "height of the latest synced header of each side chain",
+		Result:  api.Array(api.SchemaOf(bcomn.HeaderSyncTip{}))}, handler: rest.GetHeaderSyncTips},
+	{route: &api.Route{Action: "getheadersynctip", HttpMethod: http.MethodGet, Path: GET_HEADER_SYNC_TIP,
+		Summary: "height of the latest synced header of a side chain",
+		Params:  []*api.Param{chainIdParam},
+		Result:  api.SchemaOf(bcomn.HeaderSyncTip{})}, handler: rest.GetHeaderSyncTip},
+
+	{route: &api.Route{Action: "sendrawtransaction", HttpMethod: http.MethodPost, Path: POST_RAW_TX,
+		Summary: "send a transaction to the pool, or pre-execute it",
+		Params: []*api.Param{
+			api.NewBodyParam("Data", "serialized transaction in hex", true, api.Hex("")),
+			api.NewQueryParam("preExec", "PreExec", "1 to pre-execute an invoke transaction without sending it", api.Integer("")),
+		},
+		Result: api.OneOf("", api.Hex("transaction hash"), api.SchemaOf(bcomn.PreExecuteResult{})),
+		Write:  true}, handler: rest.SendRawTransaction},
+}
@@ -25,6 +25,8 @@ import (
 	"encoding/json"
 	cfg "github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/http/base/access"
+	"github.com/polynetwork/poly/http/base/api"
 	berr "github.com/polynetwork/poly/http/base/error"
 	"github.com/polynetwork/poly/http/base/rest"
 	"golang.org/x/net/netutil"
@@ -32,7 +34,6 @@ import (
 	"net"
 	"net/http"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 )
@@ -40,15 +41,15 @@ import (
 type handler func(map[string]interface{}) map[string]interface{}
 type Action struct {
 	sync.RWMutex
-	name    string
+	route   *api.Route
 	handler handler
 }
 type restServer struct {
 	router   *Router
 	listener net.Listener
 	server   *http.Server
-	postMap  map[string]Action //post method map
-	getMap   map[string]Action //get method map
+	postMap  map[string]*Action //post method map
+	getMap   map[string]*Action //get method map
 }
 
 const (
@@ -60,22 +61,31 @@ const (
 	GET_BLK_HASH          = "/api/v1/block/hash/:height"
 	GET_TX                = "/api/v1/transaction/:hash"
 	GET_STORAGE           = "/api/v1/storage/:hash/:key"
-	GET_BALANCE           = "/api/v1/balance/:addr"
 	GET_CONTRACT_STATE    = "/api/v1/contract/:hash"
 	GET_SMTCOCE_EVT_TXS   = "/api/v1/smartcode/event/transactions/:height"
 	GET_SMTCOCE_EVTS      = "/api/v1/smartcode/event/txhash/:hash"
 	GET_BLK_HGT_BY_TXHASH = "/api/v1/block/height/txhash/:hash"
 	GET_MERKLE_PROOF      = "/api/v1/merkleproof/:bheight/:rheight"
 	GET_GAS_PRICE         = "/api/v1/gasprice"
-	GET_ALLOWANCE         = "/api/v1/allowance/:asset/:from/:to"
-	GET_UNBOUNDONG        = "/api/v1/unboundong/:addr"
-	GET_GRANTONG          = "/api/v1/grantong/:addr"
 	GET_MEMPOOL_TXCOUNT   = "/api/v1/mempool/txcount"
 	GET_MEMPOOL_TXSTATE   = "/api/v1/mempool/txstate/:hash"
+	GET_MEMPOOL_STATS     = "/api/v1/mempool/stats"
 	GET_VERSION           = "/api/v1/version"
 	GET_NETWORKID         = "/api/v1/networkid"
+	GET_SIDE_CHAINS       = "/api/v1/sidechains"
+	GET_SIDE_CHAIN        = "/api/v1/sidechain/:chainid"
+	GET_RELAYERS          = "/api/v1/relayers"
+	GET_PEER_POOL         = "/api/v1/peerpool"
+	GET_GOVERNANCE_VIEW   = "/api/v1/governanceview"
+	GET_BLACKED_CHAINS    = "/api/v1/blackedchains"
+	GET_PROPOSALS         = "/api/v1/proposals"
+	GET_PROPOSAL          = "/api/v1/proposal/:id"
+	GET_HEADER_SYNC_TIPS  = "/api/v1/headersync/tips"
+	GET_HEADER_SYNC_TIP   = "/api/v1/headersync/tip/:chainid"
 
 	POST_RAW_TX = "/api/v1/transaction"
+
+	GET_OPENAPI = "/api/v1/openapi.json"
 )
 
 //init restful server
@@ -113,7 +123,7 @@ func (this *restServer) Start() error {
 			return err
 		}
 	}
-	this.server = &http.Server{Handler: this.router}
+	this.server = &http.Server{Handler: access.DefGuard.Handler(this.router)}
 	//set LimitListener number
 	if cfg.DefConfig.Restful.HttpMaxConnections > 0 {
 		this.listener = netutil.LimitListener(this.listener, int(cfg.DefConfig.Restful.HttpMaxConnections))
@@ -130,140 +140,81 @@ func (this *restServer) Start() error {
 
 //resigtry handler method
 func (this *restServer) registryMethod() {
-
-	getMethodMap := map[string]Action{
-		GET_CONN_COUNT:        {name: "getconnectioncount", handler: rest.GetConnectionCount},
-		GET_BLK_TXS_BY_HEIGHT: {name: "getblocktxsbyheight", handler: rest.GetBlockTxsByHeight},
-		GET_BLK_BY_HEIGHT:     {name: "getblockbyheight", handler: rest.GetBlockByHeight},
-		GET_BLK_BY_HASH:       {name: "getblockbyhash", handler: rest.GetBlockByHash},
-		GET_BLK_HEIGHT:        {name: "getblockheight", handler: rest.GetBlockHeight},
-		GET_BLK_HASH:          {name: "getblockhash", handler: rest.GetBlockHash},
-		GET_TX:                {name: "gettransaction", handler: rest.GetTransactionByHash},
-		GET_SMTCOCE_EVT_TXS:   {name: "getsmartcodeeventbyheight", handler: rest.GetSmartCodeEventTxsByHeight},
-		GET_SMTCOCE_EVTS:      {name: "getsmartcodeeventbyhash", handler: rest.GetSmartCodeEventByTxHash},
-		GET_BLK_HGT_BY_TXHASH: {name: "getblockheightbytxhash", handler: rest.GetBlockHeightByTxHash},
-		GET_STORAGE:           {name: "getstorage", handler: rest.GetStorage},
-		GET_MERKLE_PROOF:      {name: "getmerkleproof", handler: rest.GetMerkleProof},
-		GET_MEMPOOL_TXCOUNT:   {name: "getmempooltxcount", handler: rest.GetMemPoolTxCount},
-		GET_MEMPOOL_TXSTATE:   {name: "getmempooltxstate", handler: rest.GetMemPoolTxState},
-		GET_VERSION:           {name: "getversion", handler: rest.GetNodeVersion},
-		GET_NETWORKID:         {name: "getnetworkid", handler: rest.GetNetworkId},
-	}
-
-	postMethodMap := map[string]Action{
-		POST_RAW_TX: {name: "sendrawtransaction", handler: rest.SendRawTransaction},
+	this.getMap = make(map[string]*Action)
+	this.postMap = make(map[string]*Action)
+	for _, action := range routes {
+		switch action.route.HttpMethod {
+		case http.MethodGet:
+			this.getMap[action.route.Path] = action
+		case http.MethodPost:
+			this.postMap[action.route.Path] = action
+		}
+		if action.route.Write {
+			access.SetWriteMethod(action.route.Action)
+		}
 	}
-	this.postMap = postMethodMap
-	this.getMap = getMethodMap
 }
-func (this *restServer) getPath(url string) string {
-
-	if strings.Contains(url, strings.TrimRight(GET_BLK_TXS_BY_HEIGHT, ":height")) {
-		return GET_BLK_TXS_BY_HEIGHT
-	} else if strings.Contains(url, strings.TrimRight(GET_BLK_BY_HEIGHT, ":height")) {
-		return GET_BLK_BY_HEIGHT
-	} else if strings.Contains(url, strings.TrimRight(GET_BLK_HASH, ":height")) {
-		return GET_BLK_HASH
-	} else if strings.Contains(url, strings.TrimRight(GET_BLK_BY_HASH, ":hash")) {
-		return GET_BLK_BY_HASH
-	} else if strings.Contains(url, strings.TrimRight(GET_TX, ":hash")) {
-		return GET_TX
-	} else if strings.Contains(url, strings.TrimRight(GET_CONTRACT_STATE, ":hash")) {
-		return GET_CONTRACT_STATE
-	} else if strings.Contains(url, strings.TrimRight(GET_SMTCOCE_EVT_TXS, ":height")) {
-		return GET_SMTCOCE_EVT_TXS
-	} else if strings.Contains(url, strings.TrimRight(GET_SMTCOCE_EVTS, ":hash")) {
-		return GET_SMTCOCE_EVTS
-	} else if strings.Contains(url, strings.TrimRight(GET_BLK_HGT_BY_TXHASH, ":hash")) {
-		return GET_BLK_HGT_BY_TXHASH
-	} else if strings.Contains(url, strings.TrimRight(GET_STORAGE, ":hash/:key")) {
-		return GET_STORAGE
-	} else if strings.Contains(url, strings.TrimRight(GET_BALANCE, ":addr")) {
-		return GET_BALANCE
-	} else if strings.Contains(url, strings.TrimRight(GET_MERKLE_PROOF, ":bheight/:rheight")) {
-		return GET_MERKLE_PROOF
-	} else if strings.Contains(url, strings.TrimRight(GET_ALLOWANCE, ":asset/:from/:to")) {
-		return GET_ALLOWANCE
-	} else if strings.Contains(url, strings.TrimRight(GET_UNBOUNDONG, ":addr")) {
-		return GET_UNBOUNDONG
-	} else if strings.Contains(url, strings.TrimRight(GET_GRANTONG, ":addr")) {
-		return GET_GRANTONG
-	} else if strings.Contains(url, strings.TrimRight(GET_MEMPOOL_TXSTATE, ":hash")) {
-		return GET_MEMPOOL_TXSTATE
+
+//get request params, path and query params are put to the command by their keys
+func (this *restServer) getParams(r *http.Request, route *api.Route, req map[string]interface{}) map[string]interface{} {
+	for _, param := range route.Params {
+		switch param.In {
+		case api.IN_PATH:
+			req[param.Key] = getParam(r, param.Name)
+		case api.IN_QUERY:
+			req[param.Key] = r.FormValue(param.Name)
+		}
 	}
-	return url
+	return req
 }
 
-//get request params
-func (this *restServer) getParams(r *http.Request, url string, req map[string]interface{}) map[string]interface{} {
-	switch url {
-	case GET_CONN_COUNT:
-	case GET_BLK_TXS_BY_HEIGHT:
-		req["Height"] = getParam(r, "height")
-	case GET_BLK_BY_HEIGHT:
-		req["Raw"], req["Height"] = r.FormValue("raw"), getParam(r, "height")
-	case GET_BLK_BY_HASH:
-		req["Raw"], req["Hash"] = r.FormValue("raw"), getParam(r, "hash")
-	case GET_BLK_HEIGHT:
-	case GET_BLK_HASH:
-		req["Height"] = getParam(r, "height")
-	case GET_TX:
-		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
-	case GET_CONTRACT_STATE:
-		req["Hash"], req["Raw"] = getParam(r, "hash"), r.FormValue("raw")
-	case POST_RAW_TX:
-		req["PreExec"] = r.FormValue("preExec")
-	case GET_STORAGE:
-		req["Hash"], req["Key"] = getParam(r, "hash"), getParam(r, "key")
-	case GET_SMTCOCE_EVT_TXS:
-		req["Height"] = getParam(r, "height")
-	case GET_SMTCOCE_EVTS:
-		req["Hash"] = getParam(r, "hash")
-	case GET_BLK_HGT_BY_TXHASH:
-		req["Hash"] = getParam(r, "hash")
-	case GET_BALANCE:
-		req["Addr"] = getParam(r, "addr")
-	case GET_MERKLE_PROOF:
-		req["BlockHeight"], req["RootHeight"] = getParam(r, "bheight"), getParam(r, "rheight")
-	case GET_ALLOWANCE:
-		req["Asset"] = getParam(r, "asset")
-		req["From"], req["To"] = getParam(r, "from"), getParam(r, "to")
-	case GET_UNBOUNDONG:
-		req["Addr"] = getParam(r, "addr")
-	case GET_GRANTONG:
-		req["Addr"] = getParam(r, "addr")
-	case GET_MEMPOOL_TXSTATE:
-		req["Hash"] = getParam(r, "hash")
-	default:
+//openapi document of the restful routes
+func (this *restServer) openAPI(w http.ResponseWriter, r *http.Request) {
+	if code := access.Allow(r, "getopenapi"); code != berr.SUCCESS {
+		resp := rest.ResponsePack(code)
+		resp["Action"] = "getopenapi"
+		this.response(w, resp)
+		return
 	}
-	return req
+	specs := make([]*api.Route, 0, len(routes))
+	for _, action := range routes {
+		specs = append(specs, action.route)
+	}
+	data, err := json.Marshal(api.OpenAPI("poly restful api", cfg.Version, specs))
+	if err != nil {
+		log.Errorf("HTTP Handle - json.Marshal: %v", err)
+		return
+	}
+	this.write(w, data)
 }
 
 //init get handler
 func (this *restServer) initGetHandler() {
 
-	for k, _ := range this.getMap {
+	for k, h := range this.getMap {
+		h := h
 		this.router.Get(k, func(w http.ResponseWriter, r *http.Request) {
 
 			var req = make(map[string]interface{})
 			var resp map[string]interface{}
 
-			url := this.getPath(r.URL.Path)
-			if h, ok := this.getMap[url]; ok {
-				req = this.getParams(r, url, req)
-				resp = h.handler(req)
-				resp["Action"] = h.name
+			if code := access.Allow(r, h.route.Action); code != berr.SUCCESS {
+				resp = rest.ResponsePack(code)
 			} else {
-				resp = rest.ResponsePack(berr.INVALID_METHOD)
+				req = this.getParams(r, h.route, req)
+				resp = h.handler(req)
 			}
+			resp["Action"] = h.route.Action
 			this.response(w, resp)
 		})
 	}
+	this.router.Get(GET_OPENAPI, this.openAPI)
 }
 
 //init post handler
 func (this *restServer) initPostHandler() {
-	for k, _ := range this.postMap {
+	for k, h := range this.postMap {
+		h := h
 		this.router.Post(k, func(w http.ResponseWriter, r *http.Request) {
 
 			body, _ := ioutil.ReadAll(r.Body)
@@ -272,19 +223,15 @@ func (this *restServer) initPostHandler() {
 			var req = make(map[string]interface{})
 			var resp map[string]interface{}
 
-			url := this.getPath(r.URL.Path)
-			if h, ok := this.postMap[url]; ok {
-				if err := json.Unmarshal(body, &req); err == nil {
-					req = this.getParams(r, url, req)
-					resp = h.handler(req)
-					resp["Action"] = h.name
-				} else {
-					resp = rest.ResponsePack(berr.ILLEGAL_DATAFORMAT)
-					resp["Action"] = h.name
-				}
+			if code := access.Allow(r, h.route.Action); code != berr.SUCCESS {
+				resp = rest.ResponsePack(code)
+			} else if err := json.Unmarshal(body, &req); err == nil {
+				req = this.getParams(r, h.route, req)
+				resp = h.handler(req)
 			} else {
-				resp = rest.ResponsePack(berr.INVALID_METHOD)
+				resp = rest.ResponsePack(berr.ILLEGAL_DATAFORMAT)
 			}
+			resp["Action"] = h.route.Action
 			this.response(w, resp)
 		})
 	}
@@ -297,9 +244,7 @@ func (this *restServer) initPostHandler() {
 
 }
 func (this *restServer) write(w http.ResponseWriter, data []byte) {
-	w.Header().Add("Access-Control-Allow-Headers", "Content-Type")
 	w.Header().Set("content-type", "application/json;charset=utf-8")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Write(data)
 }
 
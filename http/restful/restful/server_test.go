@@ -0,0 +1,81 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package restful
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/polynetwork/poly/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutesDocumented(t *testing.T) {
+	rt := InitRestServer().(*restServer)
+	w := httptest.NewRecorder()
+	rt.router.ServeHTTP(w, httptest.NewRequest("GET", GET_OPENAPI, nil))
+	doc := struct {
+		Paths map[string]map[string]struct {
+			OperationId string `json:"operationId"`
+			Parameters  []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &doc))
+
+	count := 0
+	for method, actions := range map[string]map[string]*Action{"get": rt.getMap, "post": rt.postMap} {
+		for path, action := range actions {
+			count++
+			op, ok := doc.Paths[action.route.OpenAPIPath()][method]
+			assert.True(t, ok, "%s %s not documented", method, path)
+			assert.Equal(t, action.route.Action, op.OperationId)
+			pathParams := make([]string, 0)
+			for _, param := range op.Parameters {
+				if param.In == "path" {
+					pathParams = append(pathParams, param.Name)
+				}
+			}
+			assert.Equal(t, action.route.PathParams(), pathParams, "path params of %s", path)
+		}
+	}
+	assert.Equal(t, len(routes), count)
+}
+
+func TestRouteParams(t *testing.T) {
+	rt := InitRestServer().(*restServer)
+	w := httptest.NewRecorder()
+	rt.router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/version", nil))
+	resp := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "getversion", resp["Action"])
+	assert.Equal(t, config.Version, resp["Result"])
+
+	r := httptest.NewRequest("GET", "/api/v1/merkleproof/3/5", nil)
+	r = r.WithContext(context.WithValue(r.Context(), "params", paramsMap{"bheight": "3", "rheight": "5"}))
+	assert.Equal(t, map[string]interface{}{"BlockHeight": "3", "RootHeight": "5"},
+		rt.getParams(r, rt.getMap[GET_MERKLE_PROOF].route, make(map[string]interface{})))
+
+	r = httptest.NewRequest("POST", "/api/v1/transaction?preExec=1", strings.NewReader(`{"Data":"00"}`))
+	assert.Equal(t, map[string]interface{}{"PreExec": "1"}, rt.getParams(r, rt.postMap[POST_RAW_TX].route, make(map[string]interface{})))
+}
@@ -45,6 +45,9 @@ func StartServer() {
 }
 func sendBlock2WSclient(v interface{}) {
 	if cfg.DefConfig.Ws.HttpWsPort != 0 {
+		if ws != nil {
+			ws.WakeUpReplay()
+		}
 		go func() {
 			pushBlock(v)
 			pushBlockTransactions(v)
@@ -0,0 +1,56 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	bcomn "github.com/polynetwork/poly/http/base/common"
+)
+
+//hasEventFilter returns whether events are filtered on their content, not only on contracts
+func (self *subscribe) hasEventFilter() bool {
+	return len(self.EventNames) > 0 || len(self.FromChainIds) > 0 || len(self.ToChainIds) > 0
+}
+
+func (self *subscribe) matchContracts(contractAddrs map[string]bool) bool {
+	if len(self.ContractsFilter) == 0 {
+		return true
+	}
+	for _, addr := range self.ContractsFilter {
+		if contractAddrs[addr] {
+			return true
+		}
+	}
+	return false
+}
+
+//filterNotify returns the events of notify the subscriber asked for, false if there is none
+func (self *subscribe) filterNotify(contractAddrs map[string]bool, notify bcomn.ExecuteNotify) (bcomn.ExecuteNotify, bool) {
+	if !self.matchContracts(contractAddrs) {
+		return notify, false
+	}
+	if !self.hasEventFilter() {
+		return notify, true
+	}
+	filter := &bcomn.EventFilter{
+		Contracts:    self.ContractsFilter,
+		EventNames:   self.EventNames,
+		FromChainIds: self.FromChainIds,
+		ToChainIds:   self.ToChainIds,
+	}
+	return filter.Filter(notify)
+}
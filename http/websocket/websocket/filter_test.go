@@ -0,0 +1,74 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package websocket
+
+import (
+	"testing"
+
+	bcomn "github.com/polynetwork/poly/http/base/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterNotify(t *testing.T) {
+	ccm := "0300000000000000000000000000000000000000"
+	notify := bcomn.ExecuteNotify{
+		TxHash: "00",
+		Notify: []bcomn.NotifyEventInfo{
+			{ContractAddress: ccm, States: []interface{}{"makeProof", uint64(2), uint64(3), "tx", uint32(10), "key"}},
+			//decoded from the event store
+			{ContractAddress: ccm, States: []interface{}{"makeProof", float64(2), float64(4), "tx", float64(10), "key"}},
+			{ContractAddress: ccm, States: []interface{}{"btcTxToRelay", uint64(1), uint64(3), "tx", "hash", "key"}},
+			{ContractAddress: "01", States: []interface{}{"syncHeader", uint64(2), uint64(100), "hash", uint32(10)}},
+		},
+	}
+	contractAddrs := map[string]bool{ccm: true, "01": true}
+
+	sub := &subscribe{}
+	filtered, ok := sub.filterNotify(contractAddrs, notify)
+	assert.True(t, ok)
+	assert.Equal(t, 4, len(filtered.Notify))
+
+	sub = &subscribe{EventNames: []string{"makeProof"}}
+	filtered, ok = sub.filterNotify(contractAddrs, notify)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(filtered.Notify))
+	assert.Equal(t, 4, len(notify.Notify))
+
+	sub = &subscribe{FromChainIds: []uint64{2}, ToChainIds: []uint64{4}}
+	filtered, ok = sub.filterNotify(contractAddrs, notify)
+	assert.True(t, ok)
+	assert.Equal(t, []bcomn.NotifyEventInfo{notify.Notify[1]}, filtered.Notify)
+
+	sub = &subscribe{FromChainIds: []uint64{2}}
+	filtered, ok = sub.filterNotify(contractAddrs, notify)
+	assert.True(t, ok)
+	assert.Equal(t, 3, len(filtered.Notify))
+
+	sub = &subscribe{ContractsFilter: []string{"01"}, FromChainIds: []uint64{2}}
+	filtered, ok = sub.filterNotify(contractAddrs, notify)
+	assert.True(t, ok)
+	assert.Equal(t, []bcomn.NotifyEventInfo{notify.Notify[3]}, filtered.Notify)
+
+	sub = &subscribe{EventNames: []string{"btcTxToRelay"}, ToChainIds: []uint64{4}}
+	_, ok = sub.filterNotify(contractAddrs, notify)
+	assert.False(t, ok)
+
+	sub = &subscribe{ContractsFilter: []string{"02"}}
+	_, ok = sub.filterNotify(contractAddrs, notify)
+	assert.False(t, ok)
+}
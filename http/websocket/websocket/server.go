@@ -33,9 +33,14 @@ import (
 	"github.com/polynetwork/poly/common"
 	cfg "github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
+	scom "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/http/base/access"
+	bactor "github.com/polynetwork/poly/http/base/actor"
+	bcomn "github.com/polynetwork/poly/http/base/common"
 	Err "github.com/polynetwork/poly/http/base/error"
 	"github.com/polynetwork/poly/http/base/rest"
 	"github.com/polynetwork/poly/http/websocket/session"
+	"github.com/polynetwork/poly/native/event"
 )
 
 const (
@@ -43,6 +48,8 @@ const (
 	WSTOPIC_JSON_BLOCK = 2
 	WSTOPIC_RAW_BLOCK  = 3
 	WSTOPIC_TXHASHS    = 4
+
+	MAX_REPLAY_BLOCKS = 1000 //max blocks replayed to a session before serving the others
 )
 
 type handler func(map[string]interface{}) map[string]interface{}
@@ -58,6 +65,11 @@ type subscribe struct {
 	SubscribeJsonBlock    bool     `json:"SubscribeJsonBlock"`
 	SubscribeRawBlock     bool     `json:"SubscribeRawBlock"`
 	SubscribeBlockTxHashs bool     `json:"SubscribeBlockTxHashs"`
+	EventNames            []string `json:"EventNames"`
+	FromChainIds          []uint64 `json:"FromChainIds"`
+	ToChainIds            []uint64 `json:"ToChainIds"`
+	Resume                bool     `json:"Resume"`     //events are pushed block by block from the event store
+	NextHeight            uint32   `json:"NextHeight"` //next block whose events are pushed when Resume
 }
 type WsServer struct {
 	sync.RWMutex
@@ -68,6 +80,7 @@ type WsServer struct {
 	ActionMap    map[string]Handler   //handler functions
 	TxHashMap    map[string]string    //key: txHash   value:sessionid
 	SubscribeMap map[string]subscribe //key: sessionId   value:subscribeInfo
+	replayC      chan struct{}        //wakes up the replay of resumed subscriptions
 }
 
 //init websocket server
@@ -77,6 +90,7 @@ func InitWsServer() *WsServer {
 		SessionList:  session.NewSessionList(),
 		TxHashMap:    make(map[string]string),
 		SubscribeMap: make(map[string]subscribe),
+		replayC:      make(chan struct{}, 1),
 	}
 	return ws
 }
@@ -89,9 +103,7 @@ func (self *WsServer) Start() error {
 		return nil
 	}
 	self.registryMethod()
-	self.Upgrader.CheckOrigin = func(r *http.Request) bool {
-		return true
-	}
+	self.Upgrader.CheckOrigin = access.CheckOrigin
 
 	tlsFlag := false
 	if tlsFlag || wsPort%1000 == rest.TLS_PORT {
@@ -111,11 +123,12 @@ func (self *WsServer) Start() error {
 	}
 	var done = make(chan bool)
 	go self.checkSessionsTimeout(done)
+	go self.replayEvents(done)
 
-	self.server = &http.Server{Handler: http.HandlerFunc(self.webSocketHandler)}
+	self.server = &http.Server{Handler: access.DefGuard.Handler(http.HandlerFunc(self.webSocketHandler))}
 	err := self.server.Serve(self.listener)
 
-	done <- true
+	close(done)
 	if err != nil {
 		log.Fatal("ListenAndServe: ", err.Error())
 		return err
@@ -165,7 +178,34 @@ func (self *WsServer) registryMethod() {
 				}
 			}
 		}
+		if names, ok := cmd["EventNames"].([]interface{}); ok {
+			sub.EventNames = []string{}
+			for _, v := range names {
+				if name, k := v.(string); k {
+					sub.EventNames = append(sub.EventNames, name)
+				}
+			}
+		}
+		if ids, ok := cmd["FromChainIds"].([]interface{}); ok {
+			sub.FromChainIds = parseChainIds(ids)
+		}
+		if ids, ok := cmd["ToChainIds"].([]interface{}); ok {
+			sub.ToChainIds = parseChainIds(ids)
+		}
+		if height, ok := cmd["FromHeight"].(float64); ok {
+			if !cfg.DefConfig.Common.EnableEventLog || height < 0 {
+				return rest.ResponsePack(Err.INVALID_PARAMS)
+			}
+			sub.Resume = true
+			sub.NextHeight = uint32(height)
+		} else if b, ok := cmd["Resume"].(bool); ok && !b {
+			sub.Resume = false
+			sub.NextHeight = 0
+		}
 		self.SubscribeMap[sessionId] = sub
+		if sub.Resume {
+			self.WakeUpReplay()
+		}
 
 		resp["Action"] = "subscribe"
 		resp["Result"] = sub
@@ -203,6 +243,16 @@ func (self *WsServer) registryMethod() {
 	self.ActionMap = actionMap
 }
 
+func parseChainIds(ids []interface{}) []uint64 {
+	chainIds := []uint64{}
+	for _, v := range ids {
+		if id, ok := v.(float64); ok && id >= 0 {
+			chainIds = append(chainIds, uint64(id))
+		}
+	}
+	return chainIds
+}
+
 func (self *WsServer) Stop() {
 	if self.server != nil {
 		self.server.Shutdown(context.Background())
@@ -252,6 +302,9 @@ func (self *WsServer) webSocketHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer wsConn.Close()
+	if limit := access.MaxRequestSize(r); limit > 0 {
+		wsConn.SetReadLimit(limit)
+	}
 	nsSession, err := self.SessionList.NewSession(wsConn)
 	if err != nil {
 		log.Error("websocket NewSession:", err)
@@ -316,6 +369,13 @@ func (self *WsServer) OnDataHandle(curSession *session.Session, bysMsg []byte, r
 		curSession.Send(marshalResp(resp))
 		return false
 	}
+	if code := access.Allow(r, actionName); code != Err.SUCCESS {
+		resp := rest.ResponsePack(code)
+		resp["Action"] = actionName
+		resp["Id"] = req["Id"]
+		curSession.Send(marshalResp(resp))
+		return true
+	}
 	if !self.IsValidMsg(req) {
 		resp := rest.ResponsePack(Err.INVALID_PARAMS)
 		curSession.Send(marshalResp(resp))
@@ -388,17 +448,12 @@ func (self *WsServer) PushTxResult(contractAddrs map[string]bool, txHashStr stri
 	delete(self.TxHashMap, txHashStr)
 	//avoid twice, will send in BroadcastToSubscribers
 	sub := self.SubscribeMap[sessionId]
-	if sub.SubscribeEvent {
-		if len(sub.ContractsFilter) == 0 {
+	if sub.SubscribeEvent && !sub.Resume {
+		notify, _ := resp["Result"].(bcomn.ExecuteNotify)
+		if _, ok := sub.filterNotify(contractAddrs, notify); ok {
 			self.Unlock()
 			return
 		}
-		for _, addr := range sub.ContractsFilter {
-			if contractAddrs[addr] {
-				self.Unlock()
-				return
-			}
-		}
 	}
 	self.Unlock()
 
@@ -423,19 +478,106 @@ func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub
 			s.Send(data)
 		} else if sub == WSTOPIC_TXHASHS && v.SubscribeBlockTxHashs {
 			s.Send(data)
-		} else if sub == WSTOPIC_EVENT && v.SubscribeEvent {
-			if len(v.ContractsFilter) == 0 {
-				s.Send(data)
+		} else if sub == WSTOPIC_EVENT && v.SubscribeEvent && !v.Resume {
+			if !v.hasEventFilter() {
+				if v.matchContracts(contractAddrs) {
+					s.Send(data)
+				}
 				continue
 			}
-			for _, addr := range v.ContractsFilter {
-				if contractAddrs[addr] {
-					s.Send(data)
-					break
+			notify, _ := resp["Result"].(bcomn.ExecuteNotify)
+			if filtered, ok := v.filterNotify(contractAddrs, notify); ok {
+				s.Send(marshalResp(eventResp(resp, filtered)))
+			}
+		}
+	}
+}
+
+//eventResp copies an event response with another result
+func eventResp(resp map[string]interface{}, result interface{}) map[string]interface{} {
+	filtered := make(map[string]interface{}, len(resp))
+	for k, v := range resp {
+		filtered[k] = v
+	}
+	filtered["Result"] = result
+	return filtered
+}
+
+//WakeUpReplay pushes the events of newly saved blocks to the resumed subscriptions
+func (self *WsServer) WakeUpReplay() {
+	select {
+	case self.replayC <- struct{}{}:
+	default:
+	}
+}
+
+//replayEvents pushes the events of the blocks from NextHeight to the current height to the resumed subscriptions
+func (self *WsServer) replayEvents(done chan bool) {
+	for {
+		select {
+		case <-self.replayC:
+			current := bactor.GetCurrentBlockHeight()
+			self.RLock()
+			sessionIds := make([]string, 0)
+			for sid, v := range self.SubscribeMap {
+				if v.Resume && v.SubscribeEvent && v.NextHeight <= current {
+					sessionIds = append(sessionIds, sid)
+				}
+			}
+			self.RUnlock()
+
+			pending := false
+			for _, sid := range sessionIds {
+				if !self.replaySession(sid, current) {
+					pending = true
 				}
 			}
+			if pending {
+				self.WakeUpReplay()
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+//replaySession pushes at most MAX_REPLAY_BLOCKS blocks of events, returns whether the session caught up with current
+func (self *WsServer) replaySession(sessionId string, current uint32) bool {
+	self.RLock()
+	sub, ok := self.SubscribeMap[sessionId]
+	self.RUnlock()
+	s := self.SessionList.GetSessionById(sessionId)
+	if !ok || s == nil {
+		return true
+	}
+
+	height := sub.NextHeight
+	for ; height <= current && height-sub.NextHeight < MAX_REPLAY_BLOCKS; height++ {
+		evts, err := bactor.GetEventNotifyByHeight(height)
+		if err != nil && err != scom.ErrNotFound {
+			log.Errorf("websocket replay events of block %d: %s", height, err)
+			break
+		}
+		for _, evt := range evts {
+			contractAddrs, notify := bcomn.GetExecuteNotify(evt)
+			if filtered, ok := sub.filterNotify(contractAddrs, notify); ok {
+				resp := rest.ResponsePack(Err.SUCCESS)
+				resp["Action"] = event.EVENT_NOTIFY
+				resp["Result"] = filtered
+				resp["Height"] = height
+				s.Send(marshalResp(resp))
+			}
 		}
 	}
+
+	self.Lock()
+	defer self.Unlock()
+	//the subscription may have been changed during replay
+	if latest, ok := self.SubscribeMap[sessionId]; ok && latest.Resume && latest.NextHeight == sub.NextHeight {
+		latest.NextHeight = height
+		self.SubscribeMap[sessionId] = latest
+	}
+	return height > current
 }
 
 func (self *WsServer) initTlsListen() (net.Listener, error) {
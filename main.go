@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"syscall"
@@ -43,10 +44,13 @@ import (
 	"github.com/polynetwork/poly/core/genesis"
 	"github.com/polynetwork/poly/core/ledger"
 	"github.com/polynetwork/poly/events"
+	"github.com/polynetwork/poly/http/base/access"
 	bactor "github.com/polynetwork/poly/http/base/actor"
 	hserver "github.com/polynetwork/poly/http/base/actor"
+	"github.com/polynetwork/poly/http/grpc"
 	"github.com/polynetwork/poly/http/jsonrpc"
 	"github.com/polynetwork/poly/http/localrpc"
+	"github.com/polynetwork/poly/http/metrics"
 	"github.com/polynetwork/poly/http/nodeinfo"
 	"github.com/polynetwork/poly/http/restful"
 	"github.com/polynetwork/poly/http/websocket"
@@ -76,6 +80,9 @@ func setupAPP() *cli.App {
 		cmd.SigTxCommand,
 		cmd.MultiSigAddrCommand,
 		cmd.MultiSigTxCommand,
+		cmd.PSTCommand,
+		cmd.NativeCommand,
+		cmd.DecodeCommand,
 		cmd.SendTxCommand,
 		cmd.ShowTxCommand,
 	}
@@ -96,6 +103,11 @@ func setupAPP() *cli.App {
 		utils.TxpoolPreExecDisableFlag,
 		utils.DisableSyncVerifyTxFlag,
 		utils.DisableBroadcastNetTxFlag,
+		utils.TxpoolMaxTxsPerSignerFlag,
+		utils.TxpoolMaxSizeFlag,
+		utils.TxpoolPrioritiesFlag,
+		utils.TxpoolJournalFlag,
+		utils.TxpoolJournalSizeFlag,
 		//p2p setting
 		utils.ReservedPeersOnlyFlag,
 		utils.ReservedPeersFileFlag,
@@ -115,6 +127,7 @@ func setupAPP() *cli.App {
 		utils.RPCPortFlag,
 		utils.RPCLocalEnableFlag,
 		utils.RPCLocalProtFlag,
+		utils.RPCMaxBatchSizeFlag,
 		//rest setting
 		utils.RestfulEnableFlag,
 		utils.RestfulPortFlag,
@@ -122,6 +135,14 @@ func setupAPP() *cli.App {
 		//ws setting
 		utils.WsEnabledFlag,
 		utils.WsPortFlag,
+		//api access setting
+		utils.ApiAccessFileFlag,
+		//metrics setting
+		utils.MetricsEnabledFlag,
+		utils.MetricsPortFlag,
+		//grpc setting
+		utils.GrpcEnabledFlag,
+		utils.GrpcPortFlag,
 	}
 	app.Before = func(context *cli.Context) error {
 		runtime.GOMAXPROCS(runtime.NumCPU())
@@ -176,6 +197,11 @@ func startOntology(ctx *cli.Context) {
 		log.Errorf("initConsensus error:%s", err)
 		return
 	}
+	err = initApiAccess(ctx)
+	if err != nil {
+		log.Errorf("initApiAccess error:%s", err)
+		return
+	}
 	err = initRpc(ctx)
 	if err != nil {
 		log.Errorf("initRpc error:%s", err)
@@ -188,6 +214,8 @@ func startOntology(ctx *cli.Context) {
 	}
 	initRestful(ctx)
 	initWs(ctx)
+	initMetrics(ctx)
+	initGrpc(ctx)
 	initNodeInfo(ctx, p2pSvr)
 
 	go logCurrBlockHeight()
@@ -280,6 +308,19 @@ func initTxPool(ctx *cli.Context) (*proc.TXPoolServer, error) {
 	stlValidator2.Register(txPoolServer.GetPID(tc.VerifyRspActor))
 	stfValidator, _ := stateful.NewValidator("stateful_validator")
 	stfValidator.Register(txPoolServer.GetPID(tc.VerifyRspActor))
+	permValidator, err := stateless.NewPermissionValidator("permission_validator")
+	if err != nil {
+		return nil, fmt.Errorf("Init permission validator error:%s", err)
+	}
+	permValidator.Register(txPoolServer.GetPID(tc.VerifyRspActor))
+
+	if config.DefConfig.TxPool.EnableJournal {
+		dbDir := utils.GetStoreDirPath(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName)
+		journal := filepath.Join(dbDir, config.DEFAULT_TXPOOL_JOURNAL_FILE)
+		if err := txPoolServer.LoadJournal(journal, config.DefConfig.TxPool.JournalMaxSize); err != nil {
+			return nil, fmt.Errorf("Load txpool journal error:%s", err)
+		}
+	}
 
 	hserver.SetTxnPoolPid(txPoolServer.GetPID(tc.TxPoolActor))
 	hserver.SetTxPid(txPoolServer.GetPID(tc.TxActor))
@@ -305,6 +346,8 @@ func initP2PNode(ctx *cli.Context, txpoolSvr *proc.TXPoolServer) (*p2pserver.P2P
 		return nil, nil, fmt.Errorf("p2p service start error %s", err)
 	}
 	netreqactor.SetTxnPoolPid(txpoolSvr.GetPID(tc.TxActor))
+	netreqactor.SetTxnPoolLookup(txpoolSvr.HasTransaction)
+	netreqactor.SetTxnPoolGetter(txpoolSvr.GetTransaction)
 	txpoolSvr.RegisterActor(tc.NetActor, p2pPID)
 	hserver.SetNetServerPID(p2pPID)
 	p2p.WaitForPeersStart()
@@ -332,6 +375,15 @@ func initConsensus(ctx *cli.Context, p2pPid *actor.PID, txpoolSvr *proc.TXPoolSe
 	return consensusService, nil
 }
 
+func initApiAccess(ctx *cli.Context) error {
+	guard, err := access.NewGuard(config.DefConfig.Access)
+	if err != nil {
+		return err
+	}
+	access.DefGuard = guard
+	return nil
+}
+
 func initRpc(ctx *cli.Context) error {
 	if !config.DefConfig.Rpc.EnableHttpJsonRpc {
 		return nil
@@ -399,6 +451,24 @@ func initWs(ctx *cli.Context) {
 	log.Infof("Ws init success")
 }
 
+func initMetrics(ctx *cli.Context) {
+	if !config.DefConfig.Metrics.EnableHttpMetrics {
+		return
+	}
+	go metrics.StartServer()
+
+	log.Infof("Metrics init success")
+}
+
+func initGrpc(ctx *cli.Context) {
+	if !config.DefConfig.Grpc.EnableGrpc {
+		return
+	}
+	go grpc.StartServer()
+
+	log.Infof("gRPC init success")
+}
+
 func initNodeInfo(ctx *cli.Context, p2pSvr *p2pserver.P2PServer) {
 	if config.DefConfig.P2PNode.HttpInfoPort == 0 {
 		return
@@ -104,7 +104,7 @@ func (this *NativeService) Invoke() (interface{}, error) {
 	hashes := this.crossHashes
 	this.crossHashes = []common.Uint256{}
 	if err := this.PushContext(invokeParam.Address); err != nil {
-		return err, nil
+		return nil, err
 	}
 	result, err := service(this)
 	if err != nil {
@@ -48,3 +48,22 @@ func RemoveBlackChain(native *native.NativeService, chainID uint64) {
 	chainIDBytes := utils.GetUint64Bytes(chainID)
 	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(BLACKED_CHAIN), chainIDBytes))
 }
+
+//GetBlackedChains returns the ids of all the blacked chains
+func GetBlackedChains(native *native.NativeService) ([]uint64, error) {
+	prefix := utils.ConcatKey(utils.CrossChainManagerContractAddress, []byte(BLACKED_CHAIN))
+	iter := native.GetCacheDB().NewIterator(prefix)
+	defer iter.Release()
+	chainIDs := make([]uint64, 0)
+	for has := iter.First(); has; has = iter.Next() {
+		key := iter.Key()
+		if len(key) != len(prefix)+8 {
+			continue
+		}
+		chainIDs = append(chainIDs, utils.GetBytesUint64(key[len(prefix):]))
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("GetBlackedChains, iterate blacked chains error: %v", err)
+	}
+	return chainIDs, nil
+}
@@ -18,7 +18,11 @@
 package node_manager
 
 import (
+	"encoding/hex"
 	"fmt"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/service/utils"
 )
@@ -68,3 +72,43 @@ func executeCommitDpos(native *native.NativeService) error {
 	putGovernanceView(native, governanceView)
 	return nil
 }
+
+func executeBlackNode(native *native.NativeService, peerPoolMap *PeerPoolMap, view uint32, peerPubkeyList []string) error {
+	contract := utils.NodeManagerContractAddress
+	commit := false
+	for _, peerPubkey := range peerPubkeyList {
+		peerPubkeyPrefix, err := hex.DecodeString(peerPubkey)
+		if err != nil {
+			return fmt.Errorf("executeBlackNode, peerPubkey format error: %v", err)
+		}
+		peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
+		if !ok {
+			return fmt.Errorf("executeBlackNode, peerPubkey is not in peerPoolMap")
+		}
+
+		blackListItem := &BlackListItem{
+			PeerPubkey: peerPoolItem.PeerPubkey,
+			Address:    peerPoolItem.Address,
+		}
+		sink := common.NewZeroCopySink(nil)
+		blackListItem.Serialization(sink)
+		//put peer into black list
+		native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(BLACK_LIST), peerPubkeyPrefix), cstates.GenRawStorageItem(sink.Bytes()))
+
+		//change peerPool status
+		if peerPoolItem.Status == ConsensusStatus {
+			commit = true
+		}
+		peerPoolItem.Status = BlackStatus
+		peerPoolMap.PeerPoolMap[peerPubkey] = peerPoolItem
+	}
+	putPeerPoolMap(native, peerPoolMap, view)
+
+	//commitDpos
+	if commit {
+		if err := executeCommitDpos(native); err != nil {
+			return fmt.Errorf("executeBlackNode, executeCommitDpos error: %v", err)
+		}
+	}
+	return nil
+}
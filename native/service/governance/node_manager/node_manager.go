@@ -18,6 +18,7 @@
 package node_manager
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"github.com/polynetwork/poly/common"
@@ -45,21 +46,39 @@ const (
 	QUIT_NODE            = "quitNode"
 	UPDATE_CONFIG        = "updateConfig"
 	COMMIT_DPOS          = "commitDpos"
+	REPORT_EQUIVOCATION  = "reportEquivocation"
+	REJECT_PROPOSAL      = "rejectProposal"
 
 	//key prefix
-	GOVERNANCE_VIEW = "governanceView"
-	VBFT_CONFIG     = "vbftConfig"
-	CANDIDITE_INDEX = "candidateIndex"
-	PEER_APPLY      = "peerApply"
-	PEER_POOL       = "peerPool"
-	PEER_INDEX      = "peerIndex"
-	BLACK_LIST      = "blackList"
-	CONSENSUS_SIGNS = "consensusSigns"
+	GOVERNANCE_VIEW    = "governanceView"
+	VBFT_CONFIG        = "vbftConfig"
+	CANDIDITE_INDEX    = "candidateIndex"
+	PEER_APPLY         = "peerApply"
+	PEER_POOL          = "peerPool"
+	PEER_INDEX         = "peerIndex"
+	BLACK_LIST         = "blackList"
+	CONSENSUS_SIGNS    = "consensusSigns"
+	EQUIVOCATION       = "equivocation"
+	PROPOSAL           = "openProposal"
+	PROPOSAL_INDEX     = "proposalIndex"
+	PROPOSAL_ID        = "proposalID"
+	PROPOSAL_EXPIRY    = "proposalExpiry"
+	PROPOSER_PROPOSALS = "proposerProposals"
 
 	//const
 	MIN_PEER_NUM = 4
+	//blocks for an open proposal to collect the votes before it expires
+	PROPOSAL_EXPIRY_BLOCKS = 200000
+	//max number of open proposals of a proposer
+	MAX_PROPOSER_PROPOSALS = 32
 )
 
+//ProposalCloser drops the pending request of the proposal when it is rejected or expired
+type ProposalCloser func(native *native.NativeService, proposal *Proposal) error
+
+//ProposalClosers are the proposal closers by the contracts opening proposals
+var ProposalClosers = make(map[common.Address]ProposalCloser)
+
 //Register methods of node_manager contract
 func RegisterNodeManagerContract(native *native.NativeService) {
 	native.Register(genesis.INIT_CONFIG, InitConfig)
@@ -71,11 +90,13 @@ func RegisterNodeManagerContract(native *native.NativeService) {
 	native.Register(WHITE_NODE, WhiteNode)
 	native.Register(UPDATE_CONFIG, UpdateConfig)
 	native.Register(COMMIT_DPOS, CommitDpos)
+	native.Register(REPORT_EQUIVOCATION, ReportEquivocation)
+	native.Register(REJECT_PROPOSAL, RejectProposal)
 }
 
 //Init node_manager contract
 func InitConfig(native *native.NativeService) ([]byte, error) {
-	configuration := new(config.VBFTConfig)
+	configuration := new(config.InitConfigParam)
 	if err := configuration.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("initConfig, contract params deserialize error: %v", err)
 	}
@@ -91,9 +112,9 @@ func InitConfig(native *native.NativeService) ([]byte, error) {
 	}
 
 	//check the configuration
-	err = CheckVBFTConfig(configuration)
+	err = CheckInitConfig(configuration)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("initConfig, checkVBFTConfig failed: %v", err)
+		return utils.BYTE_FALSE, fmt.Errorf("initConfig, checkInitConfig failed: %v", err)
 	}
 
 	var view uint32 = 1
@@ -147,6 +168,7 @@ func InitConfig(native *native.NativeService) ([]byte, error) {
 		HashMsgDelay:         configuration.HashMsgDelay,
 		PeerHandshakeTimeout: configuration.PeerHandshakeTimeout,
 		MaxBlockChangeView:   configuration.MaxBlockChangeView,
+		ConsensusType:        configuration.ConsensusType,
 	}
 	putConfig(native, config)
 
@@ -363,7 +385,6 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("blackNode, contract params deserialize error: %v", err)
 	}
-	contract := utils.NodeManagerContractAddress
 
 	//check witness
 	err := utils.ValidateOwner(native, params.Address)
@@ -415,46 +436,100 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_TRUE, nil
 	}
 
-	commit := false
-	for _, peerPubkey := range params.PeerPubkeyList {
-		peerPubkeyPrefix, err := hex.DecodeString(peerPubkey)
-		if err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("blackNode, peerPubkey format error: %v", err)
-		}
-		peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
-		if !ok {
-			return utils.BYTE_FALSE, fmt.Errorf("blackNode, peerPubkey is not in peerPoolMap")
-		}
+	if err := executeBlackNode(native, peerPoolMap, view, params.PeerPubkeyList); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("blackNode, %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{"blackNode", params.PeerPubkeyList},
+		})
+	return utils.BYTE_TRUE, nil
+}
 
-		blackListItem := &BlackListItem{
-			PeerPubkey: peerPoolItem.PeerPubkey,
-			Address:    peerPoolItem.Address,
-		}
-		sink := common.NewZeroCopySink(nil)
-		blackListItem.Serialization(sink)
-		//put peer into black list
-		native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(BLACK_LIST), peerPubkeyPrefix), cstates.GenRawStorageItem(sink.Bytes()))
-
-		//change peerPool status
-		if peerPoolItem.Status == ConsensusStatus {
-			commit = true
-		}
-		peerPoolItem.Status = BlackStatus
-		peerPoolMap.PeerPoolMap[peerPubkey] = peerPoolItem
+//Report a consensus peer which signed two conflicting messages for the same height,
+//put it into black list once the evidence is approved by consensus nodes. It is enabled
+//together with the governance proposals at the fork height
+func ReportEquivocation(native *native.NativeService) ([]byte, error) {
+	if !ProposalEnabled(native) {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, governance proposals are not enabled at height %d", native.GetHeight())
+	}
+	params := new(ReportEquivocationParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, contract params deserialize error: %v", err)
 	}
-	putPeerPoolMap(native, peerPoolMap, view)
 
-	//commitDpos
-	if commit {
-		err = executeCommitDpos(native)
-		if err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("blackNode, executeCommitDpos error: %v", err)
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, checkWitness error: %v", err)
+	}
+
+	//check evidence
+	height, err := verifyEquivocationEvidence(params.Evidence)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, verify evidence error: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	params.Evidence.Serialization(sink)
+	evidence := sink.Bytes()
+	key := sha256.Sum256(evidence)
+	reported, err := native.GetCacheDB().Get(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(EQUIVOCATION), key[:]))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, get evidence error: %v", err)
+	}
+	if reported != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, evidence is already reported")
+	}
+
+	//get current view
+	view, err := GetView(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, get view error: %v", err)
+	}
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, view)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, get peerPoolMap error: %v", err)
+	}
+	peerPubkey := params.Evidence.PeerPubkey
+	peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, peerPubkey: %s is not in peerPoolMap", peerPubkey)
+	}
+	if peerPoolItem.Status == BlackStatus {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, peerPubkey: %s is already blacked", peerPubkey)
+	}
+
+	//check peers num
+	num := 0
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Status == CandidateStatus || peerPoolItem.Status == ConsensusStatus {
+			num = num + 1
 		}
 	}
+	if num <= MIN_PEER_NUM {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, num of peers is less than 4")
+	}
+
+	//check consensus signs
+	ok, err = CheckConsensusSigns(native, REPORT_EQUIVOCATION, evidence, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, CheckConsensusSigns error: %v", err)
+	}
+	if !ok {
+		return utils.BYTE_TRUE, nil
+	}
+
+	//persist evidence and black the offender
+	native.GetCacheDB().Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(EQUIVOCATION), key[:]), cstates.GenRawStorageItem(evidence))
+	if err := executeBlackNode(native, peerPoolMap, view, []string{peerPubkey}); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportEquivocation, %v", err)
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.NodeManagerContractAddress,
-			States:          []interface{}{"blackNode", params.PeerPubkeyList},
+			States:          []interface{}{"reportEquivocation", peerPubkey, height, hex.EncodeToString(key[:])},
 		})
 	return utils.BYTE_TRUE, nil
 }
@@ -626,13 +701,23 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("updateConfig, checkWitness error: %v", err)
 	}
 
+	//the consensus engine of a chain can't be changed
+	curConfig, err := GetConfig(native)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("updateConfig, get config error: %v", err)
+	}
+	if params.Configuration.ConsensusType != curConfig.ConsensusType {
+		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. consensus type can not be changed from %s to %s",
+			curConfig.ConsensusType, params.Configuration.ConsensusType)
+	}
 	if params.Configuration.BlockMsgDelay < 5000 {
 		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. BlockMsgDelay must >= 5000")
 	}
 	if params.Configuration.HashMsgDelay < 5000 {
 		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. HashMsgDelay must >= 5000")
 	}
-	if params.Configuration.PeerHandshakeTimeout < 10 {
+	//hotstuff has no peer handshake
+	if curConfig.ConsensusType != config.CONSENSUS_TYPE_HOTSTUFF && params.Configuration.PeerHandshakeTimeout < 10 {
 		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. PeerHandshakeTimeout must >= 10")
 	}
 	if params.Configuration.MaxBlockChangeView < 10000 {
@@ -647,3 +732,59 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 		})
 	return utils.BYTE_TRUE, nil
 }
+
+//Vote against an open proposal, the proposal is rejected once it can't reach 2/3 votes of consensus nodes
+func RejectProposal(native *native.NativeService) ([]byte, error) {
+	if !ProposalEnabled(native) {
+		return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, governance proposals are not enabled at height %d", native.GetHeight())
+	}
+	params := new(ProposalParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, contract params deserialize error: %v", err)
+	}
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, checkWitness error: %v", err)
+	}
+
+	proposal, err := GetProposal(native, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, GetProposal error: %v", err)
+	}
+	if proposal == nil {
+		return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, proposal %d is not open", params.ID)
+	}
+	if proposal.Expired(native.GetHeight()) {
+		return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, proposal %d expired at height %d", proposal.ID, proposal.ExpiryHeight)
+	}
+	if err := CloseExpiredProposals(native); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, %v", err)
+	}
+
+	proposal.Votes = removeAddress(proposal.Votes, params.Address)
+	if !containsAddress(proposal.Rejects, params.Address) {
+		proposal.Rejects = append(proposal.Rejects, params.Address)
+	}
+	num, sum, err := countConsensusVotes(native, proposal.Rejects)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{"rejectProposal", proposal.ID, len(proposal.Rejects)},
+		})
+	//approval is impossible with the rejects
+	if num > sum-(2*sum+2)/3 {
+		if err := dropProposal(native, proposal, "RejectProposal"); err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, %v", err)
+		}
+		return utils.BYTE_TRUE, nil
+	}
+	if err := putProposal(native, proposal); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("rejectProposal, %v", err)
+	}
+	return utils.BYTE_TRUE, nil
+}
@@ -137,3 +137,59 @@ func (this *UpdateConfigParam) Deserialization(source *common.ZeroCopySource) er
 	this.Configuration = configuration
 	return nil
 }
+
+type ReportEquivocationParam struct {
+	Evidence *EquivocationEvidence
+	Address  common.Address
+}
+
+func (this *ReportEquivocationParam) Serialization(sink *common.ZeroCopySink) {
+	this.Evidence.Serialization(sink)
+	sink.WriteVarBytes(this.Address[:])
+}
+
+func (this *ReportEquivocationParam) Deserialization(source *common.ZeroCopySource) error {
+	evidence := new(EquivocationEvidence)
+	if err := evidence.Deserialization(source); err != nil {
+		return fmt.Errorf("evidence.Deserialization, deserialize evidence error: %s", err)
+	}
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("common.AddressParseFromBytes, deserialize address error: %s", err)
+	}
+	this.Evidence = evidence
+	this.Address = addr
+	return nil
+}
+
+type ProposalParam struct {
+	ID      uint64
+	Address common.Address
+}
+
+func (this *ProposalParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ID)
+	sink.WriteVarBytes(this.Address[:])
+}
+
+func (this *ProposalParam) Deserialization(source *common.ZeroCopySource) error {
+	id, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("source.NextUint64, deserialize id error")
+	}
+	address, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize address error")
+	}
+	addr, err := common.AddressParseFromBytes(address)
+	if err != nil {
+		return fmt.Errorf("common.AddressParseFromBytes, deserialize address error: %s", err)
+	}
+	this.ID = id
+	this.Address = addr
+	return nil
+}
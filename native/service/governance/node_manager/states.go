@@ -228,6 +228,7 @@ type Configuration struct {
 	HashMsgDelay         uint32
 	PeerHandshakeTimeout uint32
 	MaxBlockChangeView   uint32
+	ConsensusType        string //consensus engine of the chain, empty for vbft
 }
 
 func (this *Configuration) Serialization(sink *common.ZeroCopySink) {
@@ -235,6 +236,9 @@ func (this *Configuration) Serialization(sink *common.ZeroCopySink) {
 	sink.WriteUint32(this.HashMsgDelay)
 	sink.WriteUint32(this.PeerHandshakeTimeout)
 	sink.WriteUint32(this.MaxBlockChangeView)
+	if this.ConsensusType != "" {
+		sink.WriteString(this.ConsensusType)
+	}
 }
 
 func (this *Configuration) Deserialization(source *common.ZeroCopySource) error {
@@ -254,10 +258,208 @@ func (this *Configuration) Deserialization(source *common.ZeroCopySource) error
 	if eof {
 		return fmt.Errorf("source.NextUint32, deserialize maxBlockChangeView error")
 	}
+	var consensusType string
+	if source.Len() > 0 {
+		consensusType, eof = source.NextString()
+		if eof {
+			return fmt.Errorf("source.NextString, deserialize consensusType error")
+		}
+	}
 
 	this.BlockMsgDelay = blockMsgDelay
 	this.HashMsgDelay = hashMsgDelay
 	this.PeerHandshakeTimeout = peerHandshakeTimeout
 	this.MaxBlockChangeView = maxBlockChangeView
+	this.ConsensusType = consensusType
+	return nil
+}
+
+type EquivocationType uint8
+
+const (
+	ProposalEquivocation EquivocationType = iota + 1
+	EndorseEquivocation
+	CommitEquivocation
+)
+
+//block header voted by the offender in a consensus message
+type SignedHeader struct {
+	Header []byte //raw block header
+	Sig    []byte //offender's signature of EquivocationDigest of the header
+}
+
+func (this *SignedHeader) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Header)
+	sink.WriteVarBytes(this.Sig)
+}
+
+func (this *SignedHeader) Deserialization(source *common.ZeroCopySource) error {
+	header, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize header error")
+	}
+	sig, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize sig error")
+	}
+	this.Header = header
+	this.Sig = sig
+	return nil
+}
+
+//two conflicting votes signed by the same peer in the same role for the same height
+type EquivocationEvidence struct {
+	PeerPubkey string
+	Type       EquivocationType
+	ForEmpty   bool
+	First      *SignedHeader
+	Second     *SignedHeader
+}
+
+func (this *EquivocationEvidence) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteString(this.PeerPubkey)
+	sink.WriteUint8(uint8(this.Type))
+	sink.WriteBool(this.ForEmpty)
+	this.First.Serialization(sink)
+	this.Second.Serialization(sink)
+}
+
+func (this *EquivocationEvidence) Deserialization(source *common.ZeroCopySource) error {
+	peerPubkey, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("source.NextString, deserialize peerPubkey error")
+	}
+	typ, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("source.NextUint8, deserialize type error")
+	}
+	forEmpty, eof := source.NextBool()
+	if eof {
+		return fmt.Errorf("source.NextBool, deserialize forEmpty error")
+	}
+	first := new(SignedHeader)
+	if err := first.Deserialization(source); err != nil {
+		return fmt.Errorf("deserialize first signed header error: %v", err)
+	}
+	second := new(SignedHeader)
+	if err := second.Deserialization(source); err != nil {
+		return fmt.Errorf("deserialize second signed header error: %v", err)
+	}
+	this.PeerPubkey = peerPubkey
+	this.Type = EquivocationType(typ)
+	this.ForEmpty = forEmpty
+	this.First = first
+	this.Second = second
+	return nil
+}
+
+//Proposal collects the votes of consensus peers for method of contract with input,
+//it is open until approved, rejected or expired
+type Proposal struct {
+	ID           uint64
+	Proposer     common.Address
+	Contract     common.Address
+	Method       string
+	Input        []byte
+	Votes        []common.Address
+	Rejects      []common.Address
+	Height       uint32
+	ExpiryHeight uint32
+}
+
+//Expired returns whether the proposal can no longer be voted at height
+func (this *Proposal) Expired(height uint32) bool {
+	return height > this.ExpiryHeight
+}
+
+func (this *Proposal) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ID)
+	sink.WriteVarBytes(this.Proposer[:])
+	sink.WriteVarBytes(this.Contract[:])
+	sink.WriteString(this.Method)
+	sink.WriteVarBytes(this.Input)
+	serializeAddresses(sink, this.Votes)
+	serializeAddresses(sink, this.Rejects)
+	sink.WriteUint32(this.Height)
+	sink.WriteUint32(this.ExpiryHeight)
+}
+
+func (this *Proposal) Deserialization(source *common.ZeroCopySource) error {
+	id, eof := source.NextUint64()
+	if eof {
+		return fmt.Errorf("source.NextUint64, deserialize id error")
+	}
+	proposer, err := deserializeAddress(source)
+	if err != nil {
+		return fmt.Errorf("deserialize proposer error: %v", err)
+	}
+	contract, err := deserializeAddress(source)
+	if err != nil {
+		return fmt.Errorf("deserialize contract error: %v", err)
+	}
+	method, eof := source.NextString()
+	if eof {
+		return fmt.Errorf("source.NextString, deserialize method error")
+	}
+	input, eof := source.NextVarBytes()
+	if eof {
+		return fmt.Errorf("source.NextVarBytes, deserialize input error")
+	}
+	votes, err := deserializeAddresses(source)
+	if err != nil {
+		return fmt.Errorf("deserialize votes error: %v", err)
+	}
+	rejects, err := deserializeAddresses(source)
+	if err != nil {
+		return fmt.Errorf("deserialize rejects error: %v", err)
+	}
+	height, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize height error")
+	}
+	expiryHeight, eof := source.NextUint32()
+	if eof {
+		return fmt.Errorf("source.NextUint32, deserialize expiryHeight error")
+	}
+	this.ID = id
+	this.Proposer = proposer
+	this.Contract = contract
+	this.Method = method
+	this.Input = input
+	this.Votes = votes
+	this.Rejects = rejects
+	this.Height = height
+	this.ExpiryHeight = expiryHeight
 	return nil
 }
+
+func serializeAddresses(sink *common.ZeroCopySink, addrs []common.Address) {
+	sink.WriteVarUint(uint64(len(addrs)))
+	for _, addr := range addrs {
+		sink.WriteVarBytes(addr[:])
+	}
+}
+
+func deserializeAddress(source *common.ZeroCopySource) (common.Address, error) {
+	address, eof := source.NextVarBytes()
+	if eof {
+		return common.ADDRESS_EMPTY, fmt.Errorf("source.NextVarBytes, deserialize address error")
+	}
+	return common.AddressParseFromBytes(address)
+}
+
+func deserializeAddresses(source *common.ZeroCopySource) ([]common.Address, error) {
+	n, eof := source.NextVarUint()
+	if eof {
+		return nil, fmt.Errorf("source.NextVarUint, deserialize length of addresses error")
+	}
+	addrs := make([]common.Address, 0)
+	for i := uint64(0); i < n; i++ {
+		addr, err := deserializeAddress(source)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
@@ -38,3 +38,48 @@ func Test_Deserialize_GovernanceView(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, *govView, *govView1)
 }
+
+func TestProposalSerialization(t *testing.T) {
+	proposal := &Proposal{
+		ID:           3,
+		Proposer:     common.Address{1},
+		Contract:     common.Address{2},
+		Method:       "approveRegisterSideChain",
+		Input:        []byte{1, 2, 3},
+		Votes:        []common.Address{{3}, {4}},
+		Rejects:      []common.Address{{5}},
+		Height:       10,
+		ExpiryHeight: 10 + PROPOSAL_EXPIRY_BLOCKS,
+	}
+	sink := common.NewZeroCopySink(nil)
+	proposal.Serialization(sink)
+
+	proposal1 := new(Proposal)
+	err := proposal1.Deserialization(common.NewZeroCopySource(sink.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, proposal, proposal1)
+	assert.False(t, proposal1.Expired(10+PROPOSAL_EXPIRY_BLOCKS))
+	assert.True(t, proposal1.Expired(11+PROPOSAL_EXPIRY_BLOCKS))
+}
+
+func TestConfigurationSerialization(t *testing.T) {
+	cfg := &Configuration{
+		BlockMsgDelay:        10000,
+		HashMsgDelay:         10000,
+		PeerHandshakeTimeout: 10,
+		MaxBlockChangeView:   60000,
+	}
+	sink := common.NewZeroCopySink(nil)
+	cfg.Serialization(sink)
+	assert.Equal(t, 16, len(sink.Bytes()), "vbft config is serialized as before")
+	cfg1 := new(Configuration)
+	assert.Nil(t, cfg1.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, cfg, cfg1)
+
+	cfg.ConsensusType = "hotstuff"
+	sink = common.NewZeroCopySink(nil)
+	cfg.Serialization(sink)
+	cfg1 = new(Configuration)
+	assert.Nil(t, cfg1.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, cfg, cfg1)
+}
@@ -20,13 +20,17 @@ package node_manager
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"sort"
+
 	"github.com/polynetwork/poly/native/event"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/signature"
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native"
@@ -102,21 +106,29 @@ func putPeerPoolMap(native *native.NativeService, peerPoolMap *PeerPoolMap, view
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(PEER_POOL), viewBytes), cstates.GenRawStorageItem(sink.Bytes()))
 }
 
-func CheckVBFTConfig(configuration *config.VBFTConfig) error {
+func CheckInitConfig(configuration *config.InitConfigParam) error {
 	if configuration.BlockMsgDelay < 5000 {
 		return fmt.Errorf("initConfig. BlockMsgDelay must >= 5000")
 	}
 	if configuration.HashMsgDelay < 5000 {
 		return fmt.Errorf("initConfig. HashMsgDelay must >= 5000")
 	}
-	if configuration.PeerHandshakeTimeout < 10 {
-		return fmt.Errorf("initConfig. PeerHandshakeTimeout must >= 10")
-	}
-	if len(configuration.VrfProof) < 128 {
-		return fmt.Errorf("initConfig. VrfProof must >= 128")
+	switch configuration.ConsensusType {
+	case "", config.CONSENSUS_TYPE_VBFT, config.CONSENSUS_TYPE_HOTSTUFF:
+	default:
+		return fmt.Errorf("initConfig. unsupported consensus type %s", configuration.ConsensusType)
 	}
-	if len(configuration.VrfValue) < 128 {
-		return fmt.Errorf("initConfig. VrfValue must >= 128")
+	//hotstuff has no peer handshake nor vrf
+	if configuration.ConsensusType != config.CONSENSUS_TYPE_HOTSTUFF {
+		if configuration.PeerHandshakeTimeout < 10 {
+			return fmt.Errorf("initConfig. PeerHandshakeTimeout must >= 10")
+		}
+		if len(configuration.VrfProof) < 128 {
+			return fmt.Errorf("initConfig. VrfProof must >= 128")
+		}
+		if len(configuration.VrfValue) < 128 {
+			return fmt.Errorf("initConfig. VrfValue must >= 128")
+		}
 	}
 
 	indexMap := make(map[uint32]struct{})
@@ -268,9 +280,89 @@ func deleteConsensusSigns(native *native.NativeService, key common.Uint256) {
 	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(CONSENSUS_SIGNS), key.ToArray()))
 }
 
+//ProposalEnabled returns whether the governance approvals are voted by proposals at current height,
+//the consensus signs are collected without expiry and rejects below the height
+func ProposalEnabled(native *native.NativeService) bool {
+	return native.GetHeight() >= config.GetGovernanceProposalHeight(config.DefConfig.P2PNode.NetworkId)
+}
+
+//CheckConsensusSigns votes for the proposal of method with input by address, the proposal is opened by the first
+//vote if not opened yet. It returns true once the votes of consensus peers reach 2/3 and the proposal is approved
 func CheckConsensusSigns(native *native.NativeService, method string, input []byte, address common.Address) (bool, error) {
-	message := append([]byte(method), input...)
-	key := sha256.Sum256(message)
+	if !ProposalEnabled(native) {
+		return checkConsensusSigns(native, method, input, address)
+	}
+	key := proposalKey(method, input)
+	proposal, err := getProposalByKey(native, key)
+	if err != nil {
+		return false, fmt.Errorf("CheckConsensusSigns, getProposalByKey error: %v", err)
+	}
+	//the expired proposal voted again is renewed with its request kept, the others are closed
+	if proposal != nil && proposal.Expired(native.GetHeight()) {
+		if err := deleteProposal(native, proposal); err != nil {
+			return false, fmt.Errorf("CheckConsensusSigns, %v", err)
+		}
+		native.AddNotify(
+			&event.NotifyEventInfo{
+				ContractAddress: utils.NodeManagerContractAddress,
+				States:          []interface{}{"RenewProposal", proposal.ID},
+			})
+		proposal = nil
+	}
+	if err := CloseExpiredProposals(native); err != nil {
+		return false, fmt.Errorf("CheckConsensusSigns, CloseExpiredProposals error: %v", err)
+	}
+	if proposal == nil {
+		proposal, err = openProposal(native, key, method, input, address)
+		if err != nil {
+			return false, fmt.Errorf("CheckConsensusSigns, openProposal error: %v", err)
+		}
+		//the consensus signs collected before proposals are taken as votes
+		consensusSigns, err := getConsensusSigns(native, key)
+		if err != nil {
+			return false, fmt.Errorf("CheckConsensusSigns, getConsensusSigns error: %v", err)
+		}
+		for addr := range consensusSigns.SignsMap {
+			proposal.Votes = append(proposal.Votes, addr)
+		}
+		sort.Slice(proposal.Votes, func(i, j int) bool {
+			return bytes.Compare(proposal.Votes[i][:], proposal.Votes[j][:]) < 0
+		})
+		deleteConsensusSigns(native, key)
+	}
+	proposal.Rejects = removeAddress(proposal.Rejects, address)
+	if !containsAddress(proposal.Votes, address) {
+		proposal.Votes = append(proposal.Votes, address)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{"CheckConsensusSigns", len(proposal.Votes), proposal.ID},
+		})
+	num, sum, err := countConsensusVotes(native, proposal.Votes)
+	if err != nil {
+		return false, fmt.Errorf("CheckConsensusSigns, %v", err)
+	}
+	if num >= (2*sum+2)/3 {
+		if err := deleteProposal(native, proposal); err != nil {
+			return false, fmt.Errorf("CheckConsensusSigns, %v", err)
+		}
+		native.AddNotify(
+			&event.NotifyEventInfo{
+				ContractAddress: utils.NodeManagerContractAddress,
+				States:          []interface{}{"ApproveProposal", proposal.ID},
+			})
+		return true, nil
+	}
+	if err := putProposal(native, proposal); err != nil {
+		return false, fmt.Errorf("CheckConsensusSigns, %v", err)
+	}
+	return false, nil
+}
+
+//checkConsensusSigns collects the consensus signs before the proposals are enabled
+func checkConsensusSigns(native *native.NativeService, method string, input []byte, address common.Address) (bool, error) {
+	key := proposalKey(method, input)
 	consensusSigns, err := getConsensusSigns(native, key)
 	if err != nil {
 		return false, fmt.Errorf("CheckConsensusSigns, GetConsensusSigns error: %v", err)
@@ -281,16 +373,33 @@ func CheckConsensusSigns(native *native.NativeService, method string, input []by
 			ContractAddress: utils.NodeManagerContractAddress,
 			States:          []interface{}{"CheckConsensusSigns", len(consensusSigns.SignsMap)},
 		})
-	//check signs num
+	votes := make([]common.Address, 0, len(consensusSigns.SignsMap))
+	for addr := range consensusSigns.SignsMap {
+		votes = append(votes, addr)
+	}
+	num, sum, err := countConsensusVotes(native, votes)
+	if err != nil {
+		return false, fmt.Errorf("CheckConsensusSigns, %v", err)
+	}
+	if num >= (2*sum+2)/3 {
+		deleteConsensusSigns(native, key)
+		return true, nil
+	}
+	putConsensusSigns(native, key, consensusSigns)
+	return false, nil
+}
+
+//countConsensusVotes returns the number of consensus peers in votes and the number of all consensus peers
+func countConsensusVotes(native *native.NativeService, votes []common.Address) (int, int, error) {
 	//get view
 	view, err := GetView(native)
 	if err != nil {
-		return false, fmt.Errorf("CheckConsensusSigns, GetView error: %v", err)
+		return 0, 0, fmt.Errorf("countConsensusVotes, GetView error: %v", err)
 	}
 	//get consensus peer
 	peerPoolMap, err := GetPeerPoolMap(native, view)
 	if err != nil {
-		return false, fmt.Errorf("CheckConsensusSigns, GetPeerPoolMap error: %v", err)
+		return 0, 0, fmt.Errorf("countConsensusVotes, GetPeerPoolMap error: %v", err)
 	}
 	num := 0
 	sum := 0
@@ -298,26 +407,332 @@ func CheckConsensusSigns(native *native.NativeService, method string, input []by
 		if v.Status == ConsensusStatus {
 			k, err := hex.DecodeString(key)
 			if err != nil {
-				return false, fmt.Errorf("CheckConsensusSigns, hex.DecodeString public key error: %v", err)
+				return 0, 0, fmt.Errorf("countConsensusVotes, hex.DecodeString public key error: %v", err)
 			}
 			publicKey, err := keypair.DeserializePublicKey(k)
 			if err != nil {
-				return false, fmt.Errorf("CheckConsensusSigns, keypair.DeserializePublicKey error: %v", err)
+				return 0, 0, fmt.Errorf("countConsensusVotes, keypair.DeserializePublicKey error: %v", err)
 			}
-			_, ok := consensusSigns.SignsMap[types.AddressFromPubKey(publicKey)]
-			if ok {
+			if containsAddress(votes, types.AddressFromPubKey(publicKey)) {
 				num = num + 1
 			}
 			sum = sum + 1
 		}
 	}
-	if num >= (2*sum+2)/3 {
-		deleteConsensusSigns(native, key)
-		return true, nil
-	} else {
-		putConsensusSigns(native, key, consensusSigns)
-		return false, nil
+	return num, sum, nil
+}
+
+//NewProposal opens a proposal of method of the current contract with input for the consensus peers to vote,
+//the open proposal of the same method and input is replaced along with its votes
+func NewProposal(native *native.NativeService, method string, input []byte, proposer common.Address) (*Proposal, error) {
+	if err := CloseExpiredProposals(native); err != nil {
+		return nil, fmt.Errorf("NewProposal, CloseExpiredProposals error: %v", err)
+	}
+	key := proposalKey(method, input)
+	proposal, err := getProposalByKey(native, key)
+	if err != nil {
+		return nil, fmt.Errorf("NewProposal, getProposalByKey error: %v", err)
+	}
+	if proposal != nil {
+		if err := deleteProposal(native, proposal); err != nil {
+			return nil, fmt.Errorf("NewProposal, %v", err)
+		}
+		native.AddNotify(
+			&event.NotifyEventInfo{
+				ContractAddress: utils.NodeManagerContractAddress,
+				States:          []interface{}{"ReplaceProposal", proposal.ID},
+			})
+	}
+	proposal, err = openProposal(native, key, method, input, proposer)
+	if err != nil {
+		return nil, fmt.Errorf("NewProposal, openProposal error: %v", err)
+	}
+	//the consensus signs collected before proposals are for the previous request
+	deleteConsensusSigns(native, key)
+	if err := putProposal(native, proposal); err != nil {
+		return nil, fmt.Errorf("NewProposal, %v", err)
+	}
+	return proposal, nil
+}
+
+//GetProposalByInput returns the open proposal of method with input, nil if not found
+func GetProposalByInput(native *native.NativeService, method string, input []byte) (*Proposal, error) {
+	return getProposalByKey(native, proposalKey(method, input))
+}
+
+//GetProposal returns the open proposal by id, nil if not found
+func GetProposal(native *native.NativeService, id uint64) (*Proposal, error) {
+	proposalStore, err := native.GetCacheDB().Get(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(PROPOSAL), utils.GetUint64Bytes(id)))
+	if err != nil {
+		return nil, fmt.Errorf("GetProposal, get proposalStore error: %v", err)
+	}
+	if proposalStore == nil {
+		return nil, nil
+	}
+	proposalBytes, err := cstates.GetValueFromRawStorageItem(proposalStore)
+	if err != nil {
+		return nil, fmt.Errorf("GetProposal, deserialize from raw storage item err:%v", err)
+	}
+	proposal := new(Proposal)
+	if err := proposal.Deserialization(common.NewZeroCopySource(proposalBytes)); err != nil {
+		return nil, fmt.Errorf("GetProposal, deserialize proposal error: %v", err)
 	}
+	return proposal, nil
+}
+
+//GetProposals returns the open proposals in the order of id, including the expired ones not closed yet
+func GetProposals(native *native.NativeService) ([]*Proposal, error) {
+	prefix := utils.ConcatKey(utils.NodeManagerContractAddress, []byte(PROPOSAL))
+	iter := native.GetCacheDB().NewIterator(prefix)
+	defer iter.Release()
+	proposals := make([]*Proposal, 0)
+	for has := iter.First(); has; has = iter.Next() {
+		proposalBytes, err := cstates.GetValueFromRawStorageItem(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("GetProposals, deserialize from raw storage item err:%v", err)
+		}
+		proposal := new(Proposal)
+		if err := proposal.Deserialization(common.NewZeroCopySource(proposalBytes)); err != nil {
+			return nil, fmt.Errorf("GetProposals, deserialize proposal error: %v", err)
+		}
+		proposals = append(proposals, proposal)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("GetProposals, iterate proposals error: %v", err)
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		return proposals[i].ID < proposals[j].ID
+	})
+	return proposals, nil
+}
+
+//CloseExpiredProposals closes the open proposals expired at current height, the pending requests of
+//the proposals are dropped by the ProposalClosers of their contracts. Only the proposals due are read,
+//by the index of the proposals in the order of expiry height
+func CloseExpiredProposals(native *native.NativeService) error {
+	prefix := utils.ConcatKey(utils.NodeManagerContractAddress, []byte(PROPOSAL_EXPIRY))
+	iter := native.GetCacheDB().NewIterator(prefix)
+	ids := make([]uint64, 0)
+	for has := iter.First(); has; has = iter.Next() {
+		key := iter.Key()[len(prefix):]
+		if len(key) != 12 {
+			continue
+		}
+		if binary.BigEndian.Uint32(key[:4]) >= native.GetHeight() {
+			break
+		}
+		ids = append(ids, binary.BigEndian.Uint64(key[4:]))
+	}
+	err := iter.Error()
+	iter.Release()
+	if err != nil {
+		return fmt.Errorf("CloseExpiredProposals, iterate proposal expiry error: %v", err)
+	}
+	for _, id := range ids {
+		proposal, err := GetProposal(native, id)
+		if err != nil {
+			return fmt.Errorf("CloseExpiredProposals, %v", err)
+		}
+		if proposal == nil {
+			continue
+		}
+		if err := dropProposal(native, proposal, "ExpireProposal"); err != nil {
+			return fmt.Errorf("CloseExpiredProposals, %v", err)
+		}
+	}
+	return nil
+}
+
+//dropProposal closes the proposal which is rejected or expired, and drops its pending request
+func dropProposal(native *native.NativeService, proposal *Proposal, reason string) error {
+	if closer, ok := ProposalClosers[proposal.Contract]; ok {
+		if err := closer(native, proposal); err != nil {
+			return fmt.Errorf("dropProposal, close proposal %d error: %v", proposal.ID, err)
+		}
+	}
+	if err := deleteProposal(native, proposal); err != nil {
+		return fmt.Errorf("dropProposal, %v", err)
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{reason, proposal.ID, proposal.Method, hex.EncodeToString(proposal.Input)},
+		})
+	return nil
+}
+
+//openProposal creates the proposal with a new id, the proposer can't open more than MAX_PROPOSER_PROPOSALS proposals
+func openProposal(native *native.NativeService, key common.Uint256, method string, input []byte, proposer common.Address) (*Proposal, error) {
+	count, err := getProposerProposals(native, proposer)
+	if err != nil {
+		return nil, fmt.Errorf("openProposal, getProposerProposals error: %v", err)
+	}
+	if count >= MAX_PROPOSER_PROPOSALS {
+		return nil, fmt.Errorf("openProposal, proposer %s has %d open proposals", proposer.ToBase58(), count)
+	}
+	id, err := getProposalID(native)
+	if err != nil {
+		return nil, fmt.Errorf("openProposal, getProposalID error: %v", err)
+	}
+	putProposalID(native, id+1)
+	proposal := &Proposal{
+		ID:           id,
+		Proposer:     proposer,
+		Contract:     native.CurrentContext(),
+		Method:       method,
+		Input:        input,
+		Votes:        make([]common.Address, 0),
+		Rejects:      make([]common.Address, 0),
+		Height:       native.GetHeight(),
+		ExpiryHeight: native.GetHeight() + PROPOSAL_EXPIRY_BLOCKS,
+	}
+	native.AddNotify(
+		&event.NotifyEventInfo{
+			ContractAddress: utils.NodeManagerContractAddress,
+			States:          []interface{}{"OpenProposal", proposal.ID, proposal.Contract.ToHexString(), method, hex.EncodeToString(input)},
+		})
+	return proposal, nil
+}
+
+func proposalKey(method string, input []byte) common.Uint256 {
+	message := append([]byte(method), input...)
+	return sha256.Sum256(message)
+}
+
+func getProposalByKey(native *native.NativeService, key common.Uint256) (*Proposal, error) {
+	contract := utils.NodeManagerContractAddress
+	idStore, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(PROPOSAL_INDEX), key.ToArray()))
+	if err != nil {
+		return nil, fmt.Errorf("getProposalByKey, get idStore error: %v", err)
+	}
+	if idStore == nil {
+		return nil, nil
+	}
+	idBytes, err := cstates.GetValueFromRawStorageItem(idStore)
+	if err != nil {
+		return nil, fmt.Errorf("getProposalByKey, deserialize from raw storage item err:%v", err)
+	}
+	return GetProposal(native, utils.GetBytesUint64(idBytes))
+}
+
+func putProposal(native *native.NativeService, proposal *Proposal) error {
+	contract := utils.NodeManagerContractAddress
+	idBytes := utils.GetUint64Bytes(proposal.ID)
+	storeKey := utils.ConcatKey(contract, []byte(PROPOSAL), idBytes)
+	opened, err := native.GetCacheDB().Get(storeKey)
+	if err != nil {
+		return fmt.Errorf("putProposal, get proposal error: %v", err)
+	}
+	if opened == nil {
+		count, err := getProposerProposals(native, proposal.Proposer)
+		if err != nil {
+			return fmt.Errorf("putProposal, getProposerProposals error: %v", err)
+		}
+		putProposerProposals(native, proposal.Proposer, count+1)
+		native.GetCacheDB().Put(proposalExpiryKey(proposal), cstates.GenRawStorageItem(idBytes))
+	}
+	sink := common.NewZeroCopySink(nil)
+	proposal.Serialization(sink)
+	native.GetCacheDB().Put(storeKey, cstates.GenRawStorageItem(sink.Bytes()))
+	key := proposalKey(proposal.Method, proposal.Input)
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(PROPOSAL_INDEX), key.ToArray()), cstates.GenRawStorageItem(idBytes))
+	return nil
+}
+
+func deleteProposal(native *native.NativeService, proposal *Proposal) error {
+	contract := utils.NodeManagerContractAddress
+	storeKey := utils.ConcatKey(contract, []byte(PROPOSAL), utils.GetUint64Bytes(proposal.ID))
+	opened, err := native.GetCacheDB().Get(storeKey)
+	if err != nil {
+		return fmt.Errorf("deleteProposal, get proposal error: %v", err)
+	}
+	if opened != nil {
+		count, err := getProposerProposals(native, proposal.Proposer)
+		if err != nil {
+			return fmt.Errorf("deleteProposal, getProposerProposals error: %v", err)
+		}
+		if count > 0 {
+			putProposerProposals(native, proposal.Proposer, count-1)
+		}
+		native.GetCacheDB().Delete(proposalExpiryKey(proposal))
+	}
+	native.GetCacheDB().Delete(storeKey)
+	key := proposalKey(proposal.Method, proposal.Input)
+	native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(PROPOSAL_INDEX), key.ToArray()))
+	return nil
+}
+
+//proposalExpiryKey indexes the proposal by expiry height, in big endian so that the keys are in the order of height
+func proposalExpiryKey(proposal *Proposal) []byte {
+	key := make([]byte, 12)
+	binary.BigEndian.PutUint32(key[:4], proposal.ExpiryHeight)
+	binary.BigEndian.PutUint64(key[4:], proposal.ID)
+	return utils.ConcatKey(utils.NodeManagerContractAddress, []byte(PROPOSAL_EXPIRY), key)
+}
+
+func getProposerProposals(native *native.NativeService, proposer common.Address) (uint64, error) {
+	contract := utils.NodeManagerContractAddress
+	countStore, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(PROPOSER_PROPOSALS), proposer[:]))
+	if err != nil {
+		return 0, fmt.Errorf("getProposerProposals, get countStore error: %v", err)
+	}
+	if countStore == nil {
+		return 0, nil
+	}
+	countBytes, err := cstates.GetValueFromRawStorageItem(countStore)
+	if err != nil {
+		return 0, fmt.Errorf("getProposerProposals, deserialize from raw storage item err:%v", err)
+	}
+	return utils.GetBytesUint64(countBytes), nil
+}
+
+func putProposerProposals(native *native.NativeService, proposer common.Address, count uint64) {
+	key := utils.ConcatKey(utils.NodeManagerContractAddress, []byte(PROPOSER_PROPOSALS), proposer[:])
+	if count == 0 {
+		native.GetCacheDB().Delete(key)
+		return
+	}
+	native.GetCacheDB().Put(key, cstates.GenRawStorageItem(utils.GetUint64Bytes(count)))
+}
+
+func getProposalID(native *native.NativeService) (uint64, error) {
+	contract := utils.NodeManagerContractAddress
+	idStore, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(PROPOSAL_ID)))
+	if err != nil {
+		return 0, fmt.Errorf("getProposalID, get idStore error: %v", err)
+	}
+	var id uint64 = 0
+	if idStore != nil {
+		idBytes, err := cstates.GetValueFromRawStorageItem(idStore)
+		if err != nil {
+			return 0, fmt.Errorf("getProposalID, deserialize from raw storage item err:%v", err)
+		}
+		id = utils.GetBytesUint64(idBytes)
+	}
+	return id, nil
+}
+
+func putProposalID(native *native.NativeService, id uint64) {
+	contract := utils.NodeManagerContractAddress
+	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(PROPOSAL_ID)), cstates.GenRawStorageItem(utils.GetUint64Bytes(id)))
+}
+
+func containsAddress(addrs []common.Address, address common.Address) bool {
+	for _, addr := range addrs {
+		if addr == address {
+			return true
+		}
+	}
+	return false
+}
+
+func removeAddress(addrs []common.Address, address common.Address) []common.Address {
+	for i, addr := range addrs {
+		if addr == address {
+			return append(addrs[:i], addrs[i+1:]...)
+		}
+	}
+	return addrs
 }
 
 // Get current epoch operator derived from current epoch consensus book keepers' public keys
@@ -354,3 +769,58 @@ func GetCurConOperator(native *native.NativeService) (common.Address, error) {
 	}
 	return operator, nil
 }
+
+//EquivocationDigest returns the message signed by a consensus peer voting for block hash at height,
+//it binds the signature to the vote role and height, so that signatures made for other roles, e.g.
+//proposer signatures of both block and empty block, can not be taken as equivocation evidence
+func EquivocationDigest(typ EquivocationType, forEmpty bool, height uint32, hash common.Uint256) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteString(EQUIVOCATION)
+	sink.WriteUint8(uint8(typ))
+	sink.WriteBool(forEmpty)
+	sink.WriteUint32(height)
+	sink.WriteHash(hash)
+	digest := sha256.Sum256(sink.Bytes())
+	return digest[:]
+}
+
+// Verify that both headers of the evidence are voted by the peer in the same role for the same height,
+// returns the height of the conflicting headers
+func verifyEquivocationEvidence(evidence *EquivocationEvidence) (uint32, error) {
+	if evidence.Type != ProposalEquivocation && evidence.Type != EndorseEquivocation && evidence.Type != CommitEquivocation {
+		return 0, fmt.Errorf("verifyEquivocationEvidence, unsupported equivocation type: %d", evidence.Type)
+	}
+	k, err := hex.DecodeString(evidence.PeerPubkey)
+	if err != nil {
+		return 0, fmt.Errorf("verifyEquivocationEvidence, peerPubkey format error: %v", err)
+	}
+	pubKey, err := keypair.DeserializePublicKey(k)
+	if err != nil {
+		return 0, fmt.Errorf("verifyEquivocationEvidence, keypair.DeserializePublicKey error: %v", err)
+	}
+	first, err := types.HeaderFromRawBytes(evidence.First.Header)
+	if err != nil {
+		return 0, fmt.Errorf("verifyEquivocationEvidence, deserialize first header error: %v", err)
+	}
+	second, err := types.HeaderFromRawBytes(evidence.Second.Header)
+	if err != nil {
+		return 0, fmt.Errorf("verifyEquivocationEvidence, deserialize second header error: %v", err)
+	}
+	if first.Height != second.Height {
+		return 0, fmt.Errorf("verifyEquivocationEvidence, headers of different height: %d, %d", first.Height, second.Height)
+	}
+	firstHash, secondHash := first.Hash(), second.Hash()
+	//headers must be in hash order so that all reporters build the same evidence
+	if bytes.Compare(firstHash[:], secondHash[:]) >= 0 {
+		return 0, fmt.Errorf("verifyEquivocationEvidence, headers are identical or not in order")
+	}
+	digest := EquivocationDigest(evidence.Type, evidence.ForEmpty, first.Height, firstHash)
+	if err := signature.Verify(pubKey, digest, evidence.First.Sig); err != nil {
+		return 0, fmt.Errorf("verifyEquivocationEvidence, verify first signature error: %v", err)
+	}
+	digest = EquivocationDigest(evidence.Type, evidence.ForEmpty, second.Height, secondHash)
+	if err := signature.Verify(pubKey, digest, evidence.Second.Sig); err != nil {
+		return 0, fmt.Errorf("verifyEquivocationEvidence, verify second signature error: %v", err)
+	}
+	return first.Height, nil
+}
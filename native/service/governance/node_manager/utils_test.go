@@ -0,0 +1,478 @@
+/*
+ * Copyright (C) 2020 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package node_manager
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/signature"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func signedHeader(t *testing.T, acc *account.Account, typ EquivocationType, forEmpty bool, header *types.Header) *SignedHeader {
+	sig, err := signature.Sign(acc, EquivocationDigest(typ, forEmpty, header.Height, header.Hash()))
+	assert.Nil(t, err)
+	sink := common.NewZeroCopySink(nil)
+	assert.Nil(t, header.Serialization(sink))
+	return &SignedHeader{Header: sink.Bytes(), Sig: sig}
+}
+
+func newEquivocationEvidence(t *testing.T, acc *account.Account, height1, height2 uint32) *EquivocationEvidence {
+	h1 := &types.Header{Height: height1, Timestamp: 1}
+	h2 := &types.Header{Height: height2, Timestamp: 2}
+	hash1, hash2 := h1.Hash(), h2.Hash()
+	if bytes.Compare(hash1[:], hash2[:]) > 0 {
+		h1, h2 = h2, h1
+	}
+	return &EquivocationEvidence{
+		PeerPubkey: hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)),
+		Type:       EndorseEquivocation,
+		First:      signedHeader(t, acc, EndorseEquivocation, false, h1),
+		Second:     signedHeader(t, acc, EndorseEquivocation, false, h2),
+	}
+}
+
+func TestVerifyEquivocationEvidence(t *testing.T) {
+	acc := account.NewAccount("")
+	evidence := newEquivocationEvidence(t, acc, 10, 10)
+	height, err := verifyEquivocationEvidence(evidence)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+
+	sink := common.NewZeroCopySink(nil)
+	evidence.Serialization(sink)
+	evidence1 := new(EquivocationEvidence)
+	assert.Nil(t, evidence1.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, evidence, evidence1)
+
+	// votes of another role or for empty block
+	evidence1.Type = CommitEquivocation
+	_, err = verifyEquivocationEvidence(evidence1)
+	assert.NotNil(t, err)
+	evidence1.Type, evidence1.ForEmpty = EndorseEquivocation, true
+	_, err = verifyEquivocationEvidence(evidence1)
+	assert.NotNil(t, err)
+
+	// endorsement votes are not proposal votes
+	evidence1.Type, evidence1.ForEmpty = ProposalEquivocation, false
+	_, err = verifyEquivocationEvidence(evidence1)
+	assert.NotNil(t, err)
+
+	// proposer votes of the same round
+	first, err := types.HeaderFromRawBytes(evidence1.First.Header)
+	assert.Nil(t, err)
+	second, err := types.HeaderFromRawBytes(evidence1.Second.Header)
+	assert.Nil(t, err)
+	proposal := &EquivocationEvidence{
+		PeerPubkey: evidence1.PeerPubkey,
+		Type:       ProposalEquivocation,
+		First:      signedHeader(t, acc, ProposalEquivocation, false, first),
+		Second:     signedHeader(t, acc, ProposalEquivocation, false, second),
+	}
+	_, err = verifyEquivocationEvidence(proposal)
+	assert.Nil(t, err)
+
+	// bare block hash signatures, e.g. taken from proposed headers
+	for _, signed := range []*SignedHeader{evidence1.First, evidence1.Second} {
+		header, err := types.HeaderFromRawBytes(signed.Header)
+		assert.Nil(t, err)
+		hash := header.Hash()
+		signed.Sig, err = signature.Sign(acc, hash[:])
+		assert.Nil(t, err)
+	}
+	evidence1.Type = EndorseEquivocation
+	_, err = verifyEquivocationEvidence(evidence1)
+	assert.NotNil(t, err)
+
+	// headers of different heights
+	_, err = verifyEquivocationEvidence(newEquivocationEvidence(t, acc, 10, 11))
+	assert.NotNil(t, err)
+
+	// headers out of order
+	evidence.First, evidence.Second = evidence.Second, evidence.First
+	_, err = verifyEquivocationEvidence(evidence)
+	assert.NotNil(t, err)
+
+	// signed by another peer
+	evidence = newEquivocationEvidence(t, account.NewAccount(""), 10, 10)
+	evidence.PeerPubkey = hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))
+	_, err = verifyEquivocationEvidence(evidence)
+	assert.NotNil(t, err)
+}
+
+func TestReportEquivocation(t *testing.T) {
+	accts := newProposalTestAccounts(5)
+	db := newProposalTestDB(t, accts)
+	offender := hex.EncodeToString(keypair.SerializePublicKey(accts[4].PublicKey))
+	evidence := newEquivocationEvidence(t, accts[4], 10, 10)
+	report := func(signer, reporter *account.Account, evidence *EquivocationEvidence) ([]byte, error) {
+		sink := common.NewZeroCopySink(nil)
+		(&ReportEquivocationParam{Evidence: evidence, Address: reporter.Address}).Serialization(sink)
+		return ReportEquivocation(newProposalTestNative(db, signer.Address, sink.Bytes(), 1))
+	}
+
+	//reports are rejected before the fork height
+	enableProposals(t, 2)
+	_, err := report(accts[0], accts[0], evidence)
+	assert.NotNil(t, err)
+	enableProposals(t, 0)
+
+	//reports of invalid evidence or not witnessed by the reporter are rejected
+	invalid := newEquivocationEvidence(t, accts[4], 10, 10)
+	invalid.Type = ProposalEquivocation
+	_, err = report(accts[0], accts[0], invalid)
+	assert.NotNil(t, err)
+	_, err = report(accts[0], accts[1], evidence)
+	assert.NotNil(t, err)
+
+	//the offender is blacked once 4 of 5 consensus peers report it
+	for _, acct := range accts[:3] {
+		ret, err := report(acct, acct, evidence)
+		assert.Nil(t, err)
+		assert.Equal(t, utils.BYTE_TRUE, ret)
+	}
+	ns := newProposalTestNative(db, accts[0].Address, nil, 1)
+	view, err := GetView(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), view)
+	_, err = report(accts[3], accts[3], evidence)
+	assert.Nil(t, err)
+
+	view, err = GetView(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), view)
+	peerPoolMap, err := GetPeerPoolMap(ns, view)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(peerPoolMap.PeerPoolMap))
+	assert.NotContains(t, peerPoolMap.PeerPoolMap, offender)
+	k, _ := hex.DecodeString(offender)
+	black, err := db.Get(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(BLACK_LIST), k))
+	assert.Nil(t, err)
+	assert.NotNil(t, black)
+
+	//the evidence can not be reported twice
+	_, err = report(accts[0], accts[0], evidence)
+	assert.NotNil(t, err)
+}
+
+//enableProposals enables the governance proposals from height in the test
+func enableProposals(t *testing.T, height uint32) {
+	id := config.DefConfig.P2PNode.NetworkId
+	old, ok := config.GOVERNANCE_PROPOSAL_HEIGHT[id]
+	config.GOVERNANCE_PROPOSAL_HEIGHT[id] = height
+	t.Cleanup(func() {
+		if ok {
+			config.GOVERNANCE_PROPOSAL_HEIGHT[id] = old
+		} else {
+			delete(config.GOVERNANCE_PROPOSAL_HEIGHT, id)
+		}
+	})
+}
+
+func newProposalTestDB(t *testing.T, accts []*account.Account) *storage.CacheDB {
+	store, err := leveldbstore.NewMemLevelDBStore()
+	assert.Nil(t, err)
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns := newProposalTestNative(db, common.ADDRESS_EMPTY, nil, 0)
+	peerPoolMap := &PeerPoolMap{PeerPoolMap: make(map[string]*PeerPoolItem)}
+	for i, acct := range accts {
+		pkStr := hex.EncodeToString(keypair.SerializePublicKey(acct.PublicKey))
+		peerPoolMap.PeerPoolMap[pkStr] = &PeerPoolItem{
+			Index:      uint32(i),
+			PeerPubkey: pkStr,
+			Address:    acct.Address,
+			Status:     ConsensusStatus,
+		}
+	}
+	putPeerPoolMap(ns, peerPoolMap, 0)
+	putGovernanceView(ns, &GovernanceView{View: 0, Height: 0, TxHash: common.UINT256_EMPTY})
+	return db
+}
+
+func newProposalTestNative(db *storage.CacheDB, signer common.Address, input []byte, height uint32) *native.NativeService {
+	tx := &types.Transaction{SignedAddr: []common.Address{signer}}
+	ns, _ := native.NewNativeService(db, tx, 0, height, common.Uint256{}, 0, input, false)
+	ns.PushContext(utils.SideChainManagerContractAddress)
+	return ns
+}
+
+func newProposalTestAccounts(n int) []*account.Account {
+	accts := make([]*account.Account, n)
+	for i := range accts {
+		accts[i] = account.NewAccount("")
+	}
+	return accts
+}
+
+func TestProposalApprove(t *testing.T) {
+	enableProposals(t, 0)
+	accts := newProposalTestAccounts(4)
+	db := newProposalTestDB(t, accts)
+	input := []byte{1}
+
+	ns := newProposalTestNative(db, accts[0].Address, nil, 1)
+	proposal, err := NewProposal(ns, "approve", input, accts[0].Address)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), proposal.ID)
+	assert.Equal(t, utils.SideChainManagerContractAddress, proposal.Contract)
+	assert.Equal(t, uint32(1+PROPOSAL_EXPIRY_BLOCKS), proposal.ExpiryHeight)
+
+	//votes of non consensus peers are not counted
+	for _, acct := range append(newProposalTestAccounts(3), accts[:2]...) {
+		ok, err := CheckConsensusSigns(ns, "approve", input, acct.Address)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	}
+	proposals, err := GetProposals(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(proposals))
+	assert.Equal(t, 5, len(proposals[0].Votes))
+
+	ok, err := CheckConsensusSigns(ns, "approve", input, accts[2].Address)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	proposal, err = GetProposal(ns, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, proposal)
+
+	//a new proposal of the same method and input gets a new id
+	ok, err = CheckConsensusSigns(ns, "approve", input, accts[0].Address)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	proposal, err = GetProposalByInput(ns, "approve", input)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), proposal.ID)
+}
+
+func TestProposalReject(t *testing.T) {
+	enableProposals(t, 0)
+	accts := newProposalTestAccounts(4)
+	db := newProposalTestDB(t, accts)
+	input := []byte{2}
+	var closed []uint64
+	ProposalClosers[utils.SideChainManagerContractAddress] = func(native *native.NativeService, proposal *Proposal) error {
+		closed = append(closed, proposal.ID)
+		return nil
+	}
+	defer delete(ProposalClosers, utils.SideChainManagerContractAddress)
+
+	ns := newProposalTestNative(db, accts[0].Address, nil, 1)
+	_, err := NewProposal(ns, "approve", input, accts[0].Address)
+	assert.Nil(t, err)
+	ok, err := CheckConsensusSigns(ns, "approve", input, accts[0].Address)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	reject := func(acct *account.Account) error {
+		sink := common.NewZeroCopySink(nil)
+		(&ProposalParam{ID: 0, Address: acct.Address}).Serialization(sink)
+		_, err := RejectProposal(newProposalTestNative(db, acct.Address, sink.Bytes(), 1))
+		return err
+	}
+	//the vote is taken back by rejecting
+	assert.Nil(t, reject(accts[0]))
+	proposal, err := GetProposal(ns, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(proposal.Votes))
+	assert.Equal(t, []common.Address{accts[0].Address}, proposal.Rejects)
+	assert.Nil(t, closed)
+
+	//2 of 4 rejects make the approval impossible
+	assert.Nil(t, reject(accts[1]))
+	proposal, err = GetProposal(ns, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, proposal)
+	assert.Equal(t, []uint64{0}, closed)
+	assert.NotNil(t, reject(accts[2]))
+}
+
+func TestProposalExpiry(t *testing.T) {
+	enableProposals(t, 0)
+	accts := newProposalTestAccounts(4)
+	db := newProposalTestDB(t, accts)
+	var closed []uint64
+	ProposalClosers[utils.SideChainManagerContractAddress] = func(native *native.NativeService, proposal *Proposal) error {
+		closed = append(closed, proposal.ID)
+		return nil
+	}
+	defer delete(ProposalClosers, utils.SideChainManagerContractAddress)
+
+	ns := newProposalTestNative(db, accts[0].Address, nil, 1)
+	_, err := NewProposal(ns, "approve", []byte{3}, accts[0].Address)
+	assert.Nil(t, err)
+	_, err = NewProposal(ns, "approve", []byte{4}, accts[0].Address)
+	assert.Nil(t, err)
+	ok, err := CheckConsensusSigns(ns, "approve", []byte{3}, accts[1].Address)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	ns = newProposalTestNative(db, accts[0].Address, nil, 2+PROPOSAL_EXPIRY_BLOCKS)
+	proposal, err := GetProposal(ns, 0)
+	assert.Nil(t, err)
+	assert.True(t, proposal.Expired(ns.GetHeight()))
+
+	//the expired proposal voted again is renewed without the old votes, the other one is closed
+	ok, err = CheckConsensusSigns(ns, "approve", []byte{3}, accts[0].Address)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, []uint64{1}, closed)
+	proposals, err := GetProposals(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(proposals))
+	assert.Equal(t, uint64(2), proposals[0].ID)
+	assert.Equal(t, []common.Address{accts[0].Address}, proposals[0].Votes)
+	assert.Equal(t, uint32(2+2*PROPOSAL_EXPIRY_BLOCKS), proposals[0].ExpiryHeight)
+
+	//only the proposals due are indexed by expiry height
+	assert.Equal(t, 1, countKeys(t, ns, PROPOSAL_EXPIRY))
+	assert.Nil(t, CloseExpiredProposals(newProposalTestNative(db, accts[0].Address, nil, 2+2*PROPOSAL_EXPIRY_BLOCKS)))
+	assert.Equal(t, []uint64{1}, closed)
+	assert.Nil(t, CloseExpiredProposals(newProposalTestNative(db, accts[0].Address, nil, 3+2*PROPOSAL_EXPIRY_BLOCKS)))
+	assert.Equal(t, []uint64{1, 2}, closed)
+	assert.Equal(t, 0, countKeys(t, ns, PROPOSAL_EXPIRY))
+	assert.Equal(t, 0, countKeys(t, ns, PROPOSER_PROPOSALS))
+}
+
+//countKeys returns the number of keys of node manager with prefix
+func countKeys(t *testing.T, native *native.NativeService, prefix string) int {
+	iter := native.GetCacheDB().NewIterator(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(prefix)))
+	defer iter.Release()
+	n := 0
+	for has := iter.First(); has; has = iter.Next() {
+		n++
+	}
+	assert.Nil(t, iter.Error())
+	return n
+}
+
+func TestProposalLimit(t *testing.T) {
+	enableProposals(t, 0)
+	accts := newProposalTestAccounts(4)
+	db := newProposalTestDB(t, accts)
+	proposer := account.NewAccount("")
+	ns := newProposalTestNative(db, proposer.Address, nil, 1)
+
+	for i := 0; i < MAX_PROPOSER_PROPOSALS; i++ {
+		_, err := NewProposal(ns, "approve", []byte{byte(i)}, proposer.Address)
+		assert.Nil(t, err)
+	}
+	_, err := NewProposal(ns, "approve", []byte{MAX_PROPOSER_PROPOSALS}, proposer.Address)
+	assert.NotNil(t, err)
+	//replacing an open proposal doesn't count
+	_, err = NewProposal(ns, "approve", []byte{0}, proposer.Address)
+	assert.Nil(t, err)
+	_, err = NewProposal(ns, "approve", []byte{MAX_PROPOSER_PROPOSALS}, accts[0].Address)
+	assert.Nil(t, err)
+
+	//the proposer can propose again once its proposal is closed
+	for _, acct := range accts[:3] {
+		_, err := CheckConsensusSigns(ns, "approve", []byte{1}, acct.Address)
+		assert.Nil(t, err)
+	}
+	proposal, err := GetProposalByInput(ns, "approve", []byte{1})
+	assert.Nil(t, err)
+	assert.Nil(t, proposal)
+	_, err = NewProposal(ns, "approve", []byte{MAX_PROPOSER_PROPOSALS + 1}, proposer.Address)
+	assert.Nil(t, err)
+	proposals, err := GetProposals(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, MAX_PROPOSER_PROPOSALS+1, len(proposals))
+}
+
+func TestConsensusSignsBeforeProposal(t *testing.T) {
+	enableProposals(t, 10)
+	accts := newProposalTestAccounts(4)
+	db := newProposalTestDB(t, accts)
+	input := []byte{5}
+
+	//the consensus signs are collected below the height
+	ns := newProposalTestNative(db, accts[0].Address, nil, 9)
+	assert.False(t, ProposalEnabled(ns))
+	for _, acct := range accts[:2] {
+		ok, err := CheckConsensusSigns(ns, "approve", input, acct.Address)
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	}
+	proposals, err := GetProposals(ns)
+	assert.Nil(t, err)
+	assert.Empty(t, proposals)
+	consensusSigns, err := getConsensusSigns(ns, proposalKey("approve", input))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(consensusSigns.SignsMap))
+	sink := common.NewZeroCopySink(nil)
+	(&ProposalParam{ID: 0, Address: accts[0].Address}).Serialization(sink)
+	_, err = RejectProposal(newProposalTestNative(db, accts[0].Address, sink.Bytes(), 9))
+	assert.NotNil(t, err)
+
+	//the consensus signs are taken as the votes of the proposal from the height
+	ns = newProposalTestNative(db, accts[2].Address, nil, 10)
+	assert.True(t, ProposalEnabled(ns))
+	ok, err := CheckConsensusSigns(ns, "approve", input, accts[2].Address)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	consensusSigns, err = getConsensusSigns(ns, proposalKey("approve", input))
+	assert.Nil(t, err)
+	assert.Empty(t, consensusSigns.SignsMap)
+	proposals, err = GetProposals(ns)
+	assert.Nil(t, err)
+	assert.Empty(t, proposals)
+}
+
+func TestCheckInitConfig(t *testing.T) {
+	var peers []*config.VBFTPeerInfo
+	for i := uint32(1); i <= 3; i++ {
+		acc := account.NewAccount("")
+		peers = append(peers, &config.VBFTPeerInfo{Index: i, PeerPubkey: hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey)),
+			Address: acc.Address.ToBase58()})
+	}
+	hotstuff := &config.HotStuffConfig{BlockInterval: 6000, RoundTimeout: 8000, Peers: peers[:2]}
+
+	param := hotstuff.InitConfigParam()
+	assert.Nil(t, CheckInitConfig(param))
+	param.ConsensusType = ""
+	assert.NotNil(t, CheckInitConfig(param), "vbft config without vrf")
+	param.ConsensusType = "dbft"
+	assert.NotNil(t, CheckInitConfig(param), "unsupported consensus type")
+
+	//the consensus type survives the genesis transaction
+	sink := common.NewZeroCopySink(nil)
+	assert.Nil(t, hotstuff.InitConfigParam().Serialization(sink))
+	param = new(config.InitConfigParam)
+	assert.Nil(t, param.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, config.CONSENSUS_TYPE_HOTSTUFF, param.ConsensusType)
+	assert.Nil(t, CheckInitConfig(param))
+
+	hotstuff.BlockInterval = 1000
+	assert.NotNil(t, CheckInitConfig(hotstuff.InitConfigParam()), "short block interval")
+	hotstuff.BlockInterval = 6000
+	peers[2].Index = 1
+	hotstuff.Peers = peers
+	assert.NotNil(t, CheckInitConfig(hotstuff.InitConfigParam()), "duplicated index")
+}
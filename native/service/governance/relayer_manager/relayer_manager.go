@@ -151,6 +151,10 @@ func ApproveRemoveRelayer(native *native.NativeService) ([]byte, error) {
 	for _, address := range relayerListParam.AddressList {
 		native.GetCacheDB().Delete(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER), address[:]))
 	}
+	//the remove request was left before the proposals are enabled
+	if node_manager.ProposalEnabled(native) {
+		native.GetCacheDB().Delete(utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER_REMOVE), utils.GetUint64Bytes(params.ID)))
+	}
 	native.AddNotify(
 		&event.NotifyEventInfo{
 			ContractAddress: utils.RelayerManagerContractAddress,
@@ -158,3 +162,15 @@ func ApproveRemoveRelayer(native *native.NativeService) ([]byte, error) {
 		})
 	return utils.BYTE_TRUE, nil
 }
+
+//CloseProposal drops the pending request of the proposal rejected or expired
+func CloseProposal(native *native.NativeService, proposal *node_manager.Proposal) error {
+	contract := utils.RelayerManagerContractAddress
+	switch proposal.Method {
+	case APPROVE_REGISTER_RELAYER:
+		native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(RELAYER_APPLY), proposal.Input))
+	case APPROVE_REMOVE_RELAYER:
+		native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(RELAYER_REMOVE), proposal.Input))
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
 	"github.com/polynetwork/poly/core/genesis"
 	cstates "github.com/polynetwork/poly/core/states"
@@ -197,3 +198,144 @@ func TestRemoveRelayer(t *testing.T) {
 		}
 	}
 }
+
+func TestGetRelayers(t *testing.T) {
+	ns := NewNative(nil, new(types.Transaction), nil)
+	relayers := []common.Address{{1, 2}, {3, 4}}
+	for _, relayer := range relayers {
+		assert.Nil(t, putRelayer(ns, relayer))
+	}
+	//an applying relayer is not registered yet
+	assert.Nil(t, putRelayerApply(ns, &RelayerListParam{AddressList: []common.Address{{5, 6}}, Address: acct.Address}))
+
+	got, err := GetRelayers(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, relayers, got)
+}
+
+//enableProposals enables the governance proposals from genesis in the test
+func enableProposals(t *testing.T) {
+	id := config.DefConfig.P2PNode.NetworkId
+	old, ok := config.GOVERNANCE_PROPOSAL_HEIGHT[id]
+	config.GOVERNANCE_PROPOSAL_HEIGHT[id] = 0
+	node_manager.ProposalClosers[utils.RelayerManagerContractAddress] = CloseProposal
+	t.Cleanup(func() {
+		if ok {
+			config.GOVERNANCE_PROPOSAL_HEIGHT[id] = old
+		} else {
+			delete(config.GOVERNANCE_PROPOSAL_HEIGHT, id)
+		}
+		delete(node_manager.ProposalClosers, utils.RelayerManagerContractAddress)
+	})
+}
+
+//newContractNative invokes the contract with args at height, signed by signer
+func newContractNative(args []byte, signer common.Address, db *storage.CacheDB, height uint32) *native.NativeService {
+	tx := &types.Transaction{SignedAddr: []common.Address{signer}}
+	ns, _ := native.NewNativeService(db, tx, 0, height, common.Uint256{0}, 0, args, false)
+	ns.PushContext(utils.RelayerManagerContractAddress)
+	return ns
+}
+
+func relayerListArgs(relayers ...common.Address) []byte {
+	sink := common.NewZeroCopySink(nil)
+	(&RelayerListParam{AddressList: relayers, Address: acct.Address}).Serialization(sink)
+	return sink.Bytes()
+}
+
+func approveRelayerArgs(peer *account.Account, id uint64) []byte {
+	sink := common.NewZeroCopySink(nil)
+	(&ApproveRelayerParam{ID: id, Address: peer.Address}).Serialization(sink)
+	return sink.Bytes()
+}
+
+func TestRejectRegisterRelayer(t *testing.T) {
+	enableProposals(t)
+	peers := conAccts()[:4]
+	db := NewNative(nil, new(types.Transaction), nil).GetCacheDB()
+	putPeerMapPoolAndView(db, peers)
+
+	_, err := RegisterRelayer(newContractNative(relayerListArgs(common.Address{1}), acct.Address, db, 1))
+	assert.Nil(t, err)
+	ns := newContractNative(nil, acct.Address, db, 1)
+	proposal, err := node_manager.GetProposalByInput(ns, APPROVE_REGISTER_RELAYER, utils.GetUint64Bytes(0))
+	assert.Nil(t, err)
+	assert.Equal(t, utils.RelayerManagerContractAddress, proposal.Contract)
+
+	for _, peer := range peers[:2] {
+		sink := common.NewZeroCopySink(nil)
+		(&node_manager.ProposalParam{ID: proposal.ID, Address: peer.Address}).Serialization(sink)
+		_, err := node_manager.RejectProposal(newContractNative(sink.Bytes(), peer.Address, db, 1))
+		assert.Nil(t, err)
+	}
+	//the apply is dropped once the approval is impossible
+	_, err = getRelayerApply(ns, 0)
+	assert.NotNil(t, err)
+	_, err = ApproveRegisterRelayer(newContractNative(approveRelayerArgs(peers[2], 0), peers[2].Address, db, 1))
+	assert.NotNil(t, err)
+	relayers, err := GetRelayers(ns)
+	assert.Nil(t, err)
+	assert.Empty(t, relayers)
+}
+
+func TestExpireRegisterRelayer(t *testing.T) {
+	enableProposals(t)
+	peers := conAccts()[:4]
+	db := NewNative(nil, new(types.Transaction), nil).GetCacheDB()
+	putPeerMapPoolAndView(db, peers)
+
+	_, err := RegisterRelayer(newContractNative(relayerListArgs(common.Address{1}), acct.Address, db, 1))
+	assert.Nil(t, err)
+	_, err = RemoveRelayer(newContractNative(relayerListArgs(common.Address{2}), acct.Address, db, 1))
+	assert.Nil(t, err)
+	_, err = ApproveRegisterRelayer(newContractNative(approveRelayerArgs(peers[0], 0), peers[0].Address, db, 1))
+	assert.Nil(t, err)
+
+	//the apply voted after expiry is renewed without the old votes, the remove request is dropped
+	height := uint32(2 + node_manager.PROPOSAL_EXPIRY_BLOCKS)
+	ns := newContractNative(nil, acct.Address, db, height)
+	for i, peer := range peers[1:] {
+		_, err = ApproveRegisterRelayer(newContractNative(approveRelayerArgs(peer, 0), peer.Address, db, height))
+		assert.Nil(t, err)
+		if i == 0 {
+			_, err = getRelayerRemove(ns, 0)
+			assert.NotNil(t, err)
+			relayers, err := GetRelayers(ns)
+			assert.Nil(t, err)
+			assert.Empty(t, relayers)
+		}
+	}
+	relayers, err := GetRelayers(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, []common.Address{{1}}, relayers)
+}
+
+func TestApproveRemoveRelayer(t *testing.T) {
+	peers := conAccts()[:4]
+	remove := func(db *storage.CacheDB) {
+		assert.Nil(t, putRelayer(NewNative(nil, new(types.Transaction), db), common.Address{1}))
+		_, err := RemoveRelayer(newContractNative(relayerListArgs(common.Address{1}), acct.Address, db, 1))
+		assert.Nil(t, err)
+		for _, peer := range peers[:3] {
+			_, err = ApproveRemoveRelayer(newContractNative(approveRelayerArgs(peer, 0), peer.Address, db, 1))
+			assert.Nil(t, err)
+		}
+		relayers, err := GetRelayers(NewNative(nil, new(types.Transaction), db))
+		assert.Nil(t, err)
+		assert.Empty(t, relayers)
+	}
+
+	//the remove request is left before the proposals are enabled
+	db := NewNative(nil, new(types.Transaction), nil).GetCacheDB()
+	putPeerMapPoolAndView(db, peers)
+	remove(db)
+	_, err := getRelayerRemove(NewNative(nil, new(types.Transaction), db), 0)
+	assert.Nil(t, err)
+
+	enableProposals(t)
+	db = NewNative(nil, new(types.Transaction), nil).GetCacheDB()
+	putPeerMapPoolAndView(db, peers)
+	remove(db)
+	_, err = getRelayerRemove(NewNative(nil, new(types.Transaction), db), 0)
+	assert.NotNil(t, err)
+}
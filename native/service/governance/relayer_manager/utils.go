@@ -24,6 +24,7 @@ import (
 	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/native"
 	"github.com/polynetwork/poly/native/event"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/utils"
 )
 
@@ -33,6 +34,30 @@ func putRelayer(native *native.NativeService, relayer common.Address) error {
 	return nil
 }
 
+//GetRelayers returns the addresses of all the registered relayers
+func GetRelayers(native *native.NativeService) ([]common.Address, error) {
+	prefix := utils.ConcatKey(utils.RelayerManagerContractAddress, []byte(RELAYER))
+	iter := native.GetCacheDB().NewIterator(prefix)
+	defer iter.Release()
+	relayers := make([]common.Address, 0)
+	for has := iter.First(); has; has = iter.Next() {
+		//skip the keys of other prefixes starting with relayer, e.g. relayerApply
+		key := iter.Key()
+		if len(key) != len(prefix)+common.ADDR_LEN {
+			continue
+		}
+		relayer, err := common.AddressParseFromBytes(key[len(prefix):])
+		if err != nil {
+			return nil, fmt.Errorf("GetRelayers, parse address error: %v", err)
+		}
+		relayers = append(relayers, relayer)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("GetRelayers, iterate relayers error: %v", err)
+	}
+	return relayers, nil
+}
+
 func putRelayerApply(native *native.NativeService, relayerListParam *RelayerListParam) error {
 	contract := utils.RelayerManagerContractAddress
 	applyID, err := getApplyID(native)
@@ -44,6 +69,12 @@ func putRelayerApply(native *native.NativeService, relayerListParam *RelayerList
 	if err != nil {
 		return fmt.Errorf("putRelayerApply, putApplyID error: %v", err)
 	}
+	if node_manager.ProposalEnabled(native) {
+		_, err = node_manager.NewProposal(native, APPROVE_REGISTER_RELAYER, utils.GetUint64Bytes(applyID), relayerListParam.Address)
+		if err != nil {
+			return fmt.Errorf("putRelayerApply, NewProposal error: %v", err)
+		}
+	}
 	sink := common.NewZeroCopySink(nil)
 	relayerListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER_APPLY), utils.GetUint64Bytes(applyID)),
@@ -113,6 +144,12 @@ func putRelayerRemove(native *native.NativeService, relayerListParam *RelayerLis
 	if err != nil {
 		return fmt.Errorf("putRelayerRemove, putRemoveID error: %v", err)
 	}
+	if node_manager.ProposalEnabled(native) {
+		_, err = node_manager.NewProposal(native, APPROVE_REMOVE_RELAYER, utils.GetUint64Bytes(removeID), relayerListParam.Address)
+		if err != nil {
+			return fmt.Errorf("putRelayerRemove, NewProposal error: %v", err)
+		}
+	}
 	sink := common.NewZeroCopySink(nil)
 	relayerListParam.Serialization(sink)
 	native.GetCacheDB().Put(utils.ConcatKey(contract, []byte(RELAYER_REMOVE), utils.GetUint64Bytes(removeID)),
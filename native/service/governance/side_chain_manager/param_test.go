@@ -28,6 +28,8 @@ func TestRegisterSideChain(t *testing.T) {
 		ChainId:      123,
 		Name:         "123456",
 		BlocksToWait: 1234,
+		CCMCAddress:  []byte{},
+		ExtraInfo:    []byte{},
 	}
 	sink := common.NewZeroCopySink(nil)
 	err := param.Serialization(sink)
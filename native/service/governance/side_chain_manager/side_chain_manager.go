@@ -77,6 +77,13 @@ func RegisterSideChain(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, checkWitness error: %v", err)
 	}
 
+	//drop the applies of expired proposals
+	if node_manager.ProposalEnabled(native) {
+		err = node_manager.CloseExpiredProposals(native)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, CloseExpiredProposals error: %v", err)
+		}
+	}
 	registerSideChain, err := getSideChainApply(native, params.ChainId)
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, getRegisterSideChain error: %v", err)
@@ -100,6 +107,12 @@ func RegisterSideChain(native *native.NativeService) ([]byte, error) {
 		CCMCAddress:  params.CCMCAddress,
 		ExtraInfo:    params.ExtraInfo,
 	}
+	if node_manager.ProposalEnabled(native) {
+		_, err = node_manager.NewProposal(native, APPROVE_REGISTER_SIDE_CHAIN, utils.GetUint64Bytes(params.ChainId), params.Address)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, NewProposal error: %v", err)
+		}
+	}
 	err = putSideChainApply(native, sideChain)
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("RegisterSideChain, putRegisterSideChain error: %v", err)
@@ -186,6 +199,13 @@ func UpdateSideChain(native *native.NativeService) ([]byte, error) {
 		CCMCAddress:  params.CCMCAddress,
 		ExtraInfo:    params.ExtraInfo,
 	}
+	//the votes for the previous update are dropped
+	if node_manager.ProposalEnabled(native) {
+		_, err = node_manager.NewProposal(native, APPROVE_UPDATE_SIDE_CHAIN, utils.GetUint64Bytes(params.ChainId), params.Address)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("UpdateSideChain, NewProposal error: %v", err)
+		}
+	}
 	err = putUpdateSideChain(native, updateSideChain)
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("UpdateSideChain, putUpdateSideChain error: %v", err)
@@ -265,6 +285,20 @@ func QuitSideChain(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("QuitSideChain, side chain owner is wrong")
 	}
 
+	//keep the votes of the open proposal, the request is the same
+	if node_manager.ProposalEnabled(native) {
+		chainidByte := utils.GetUint64Bytes(params.Chainid)
+		proposal, err := node_manager.GetProposalByInput(native, QUIT_SIDE_CHAIN, chainidByte)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("QuitSideChain, GetProposalByInput error: %v", err)
+		}
+		if proposal == nil || proposal.Expired(native.GetHeight()) {
+			_, err = node_manager.NewProposal(native, QUIT_SIDE_CHAIN, chainidByte, params.Address)
+			if err != nil {
+				return utils.BYTE_FALSE, fmt.Errorf("QuitSideChain, NewProposal error: %v", err)
+			}
+		}
+	}
 	err = putQuitSideChain(native, params.Chainid)
 	if err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("QuitSideChain, putUpdateSideChain error: %v", err)
@@ -305,7 +339,12 @@ func ApproveQuitSideChain(native *native.NativeService) ([]byte, error) {
 	}
 
 	chainidByte := utils.GetUint64Bytes(params.Chainid)
-	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(QUIT_SIDE_CHAIN), chainidByte))
+	//the quit request was left by deleting a wrong key before the proposals are enabled
+	if node_manager.ProposalEnabled(native) {
+		native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(QUIT_SIDE_CHAIN_REQUEST), chainidByte))
+	} else {
+		native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(QUIT_SIDE_CHAIN), chainidByte))
+	}
 	native.GetCacheDB().Delete(utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(SIDE_CHAIN), chainidByte))
 	native.AddNotify(
 		&event.NotifyEventInfo{
@@ -315,6 +354,20 @@ func ApproveQuitSideChain(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+//CloseProposal drops the pending request of the proposal rejected or expired
+func CloseProposal(native *native.NativeService, proposal *node_manager.Proposal) error {
+	contract := utils.SideChainManagerContractAddress
+	switch proposal.Method {
+	case APPROVE_REGISTER_SIDE_CHAIN:
+		native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(SIDE_CHAIN_APPLY), proposal.Input))
+	case APPROVE_UPDATE_SIDE_CHAIN:
+		native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(UPDATE_SIDE_CHAIN_REQUEST), proposal.Input))
+	case QUIT_SIDE_CHAIN:
+		native.GetCacheDB().Delete(utils.ConcatKey(contract, []byte(QUIT_SIDE_CHAIN_REQUEST), proposal.Input))
+	}
+	return nil
+}
+
 func RegisterRedeem(native *native.NativeService) ([]byte, error) {
 	params := new(RegisterRedeemParam)
 	if err := params.Deserialization(common.NewZeroCopySource(native.GetInput())); err != nil {
@@ -22,11 +22,15 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/polynetwork/poly/account"
 	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	vconfig "github.com/polynetwork/poly/consensus/vbft/config"
 	"github.com/polynetwork/poly/core/genesis"
+	cstates "github.com/polynetwork/poly/core/states"
 	"github.com/polynetwork/poly/core/store/leveldbstore"
 	"github.com/polynetwork/poly/core/store/overlaydb"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
 	"github.com/polynetwork/poly/native/service/utils"
 	"github.com/polynetwork/poly/native/storage"
 	"github.com/stretchr/testify/assert"
@@ -55,6 +59,29 @@ func init() {
 	setBKers()
 }
 
+//putPeerMapPoolAndView makes accts the consensus peers
+func putPeerMapPoolAndView(db *storage.CacheDB, accts ...*account.Account) {
+	peerPoolMap := new(node_manager.PeerPoolMap)
+	peerPoolMap.PeerPoolMap = make(map[string]*node_manager.PeerPoolItem)
+	for i, acct := range accts {
+		pkStr := vconfig.PubkeyID(acct.PublicKey)
+		peerPoolMap.PeerPoolMap[pkStr] = &node_manager.PeerPoolItem{
+			Index:      uint32(i),
+			PeerPubkey: pkStr,
+			Address:    acct.Address,
+			Status:     node_manager.ConsensusStatus,
+		}
+	}
+	sink := common.NewZeroCopySink(nil)
+	peerPoolMap.Serialization(sink)
+	db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.PEER_POOL), utils.GetUint32Bytes(0)), cstates.GenRawStorageItem(sink.Bytes()))
+
+	govView := node_manager.GovernanceView{View: 0, Height: 10, TxHash: common.UINT256_EMPTY}
+	sink = common.NewZeroCopySink(nil)
+	govView.Serialization(sink)
+	db.Put(utils.ConcatKey(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW)), cstates.GenRawStorageItem(sink.Bytes()))
+}
+
 func NewNative(args []byte, tx *types.Transaction, db *storage.CacheDB) *native.NativeService {
 	if db == nil {
 		store, _ := leveldbstore.NewMemLevelDBStore()
@@ -82,6 +109,7 @@ func TestRegisterSideChainManager(t *testing.T) {
 		SignedAddr: []common.Address{acct.Address},
 	}
 	nativeService = NewNative(sink.Bytes(), tx, nil)
+	putPeerMapPoolAndView(nativeService.GetCacheDB(), acct)
 	res, err := RegisterSideChain(nativeService)
 	assert.Equal(t, res, []byte{1})
 	assert.Nil(t, err)
@@ -98,6 +126,7 @@ func TestRegisterSideChainManager(t *testing.T) {
 func TestApproveRegisterSideChain(t *testing.T) {
 	param := new(ChainidParam)
 	param.Chainid = 8
+	param.Address = acct.Address
 
 	sink := common.NewZeroCopySink(nil)
 	param.Serialization(sink)
@@ -114,7 +143,7 @@ func TestApproveRegisterSideChain(t *testing.T) {
 
 func TestUpdateSideChain(t *testing.T) {
 	param := new(RegisterSideChainParam)
-	param.Address = common.Address{1, 2, 3}
+	param.Address = acct.Address
 	param.BlocksToWait = 10
 	param.ChainId = 8
 	param.Name = "own"
@@ -137,6 +166,7 @@ func TestUpdateSideChain(t *testing.T) {
 func TestApproveUpdateSideChain(t *testing.T) {
 	param := new(ChainidParam)
 	param.Chainid = 8
+	param.Address = acct.Address
 	sink := common.NewZeroCopySink(nil)
 	param.Serialization(sink)
 
@@ -242,3 +272,163 @@ func TestSetBtcTxParam(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, utils.BYTE_FALSE, ok)
 }
+
+func TestGetSideChains(t *testing.T) {
+	ns := NewNative(nil, new(types.Transaction), nil)
+	for _, chainID := range []uint64{2, 6} {
+		assert.Nil(t, PutSideChain(ns, &SideChain{Address: acct.Address, ChainId: chainID, Router: chainID, Name: "chain"}))
+	}
+	//an applying side chain is not registered yet
+	assert.Nil(t, putSideChainApply(ns, &SideChain{Address: acct.Address, ChainId: 7, Name: "apply"}))
+
+	sideChains, err := GetSideChains(ns)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(sideChains))
+	assert.Equal(t, uint64(2), sideChains[0].ChainId)
+	assert.Equal(t, uint64(6), sideChains[1].Router)
+}
+
+//enableProposals enables the governance proposals from genesis in the test
+func enableProposals(t *testing.T) {
+	id := config.DefConfig.P2PNode.NetworkId
+	old, ok := config.GOVERNANCE_PROPOSAL_HEIGHT[id]
+	config.GOVERNANCE_PROPOSAL_HEIGHT[id] = 0
+	node_manager.ProposalClosers[utils.SideChainManagerContractAddress] = CloseProposal
+	t.Cleanup(func() {
+		if ok {
+			config.GOVERNANCE_PROPOSAL_HEIGHT[id] = old
+		} else {
+			delete(config.GOVERNANCE_PROPOSAL_HEIGHT, id)
+		}
+		delete(node_manager.ProposalClosers, utils.SideChainManagerContractAddress)
+	})
+}
+
+//newContractNative invokes the contract with args at height, signed by signer
+func newContractNative(args []byte, signer common.Address, db *storage.CacheDB, height uint32) *native.NativeService {
+	tx := &types.Transaction{SignedAddr: []common.Address{signer}}
+	ns, _ := native.NewNativeService(db, tx, 0, height, common.Uint256{0}, 0, args, false)
+	ns.PushContext(utils.SideChainManagerContractAddress)
+	return ns
+}
+
+func registerSideChainArgs(chainID uint64) []byte {
+	param := &RegisterSideChainParam{Address: acct.Address, ChainId: chainID, Name: "chain", BlocksToWait: 1}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	return sink.Bytes()
+}
+
+func approveRegisterSideChain(peer *account.Account, chainID uint64, db *storage.CacheDB, height uint32) error {
+	sink := common.NewZeroCopySink(nil)
+	(&ChainidParam{Chainid: chainID, Address: peer.Address}).Serialization(sink)
+	_, err := ApproveRegisterSideChain(newContractNative(sink.Bytes(), peer.Address, db, height))
+	return err
+}
+
+func TestRejectRegisterSideChain(t *testing.T) {
+	enableProposals(t)
+	peers := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	db := NewNative(nil, new(types.Transaction), nil).GetCacheDB()
+	putPeerMapPoolAndView(db, peers...)
+
+	_, err := RegisterSideChain(newContractNative(registerSideChainArgs(9), acct.Address, db, 1))
+	assert.Nil(t, err)
+	ns := newContractNative(nil, acct.Address, db, 1)
+	proposal, err := node_manager.GetProposalByInput(ns, APPROVE_REGISTER_SIDE_CHAIN, utils.GetUint64Bytes(9))
+	assert.Nil(t, err)
+	assert.Equal(t, acct.Address, proposal.Proposer)
+	assert.Equal(t, utils.SideChainManagerContractAddress, proposal.Contract)
+	assert.Nil(t, approveRegisterSideChain(peers[0], 9, db, 1))
+
+	reject := func(peer *account.Account) error {
+		sink := common.NewZeroCopySink(nil)
+		(&node_manager.ProposalParam{ID: proposal.ID, Address: peer.Address}).Serialization(sink)
+		_, err := node_manager.RejectProposal(newContractNative(sink.Bytes(), peer.Address, db, 1))
+		return err
+	}
+	assert.Nil(t, reject(peers[1]))
+	sideChain, err := getSideChainApply(ns, 9)
+	assert.Nil(t, err)
+	assert.NotNil(t, sideChain)
+
+	//the apply is dropped once the approval is impossible
+	assert.Nil(t, reject(peers[2]))
+	sideChain, err = getSideChainApply(ns, 9)
+	assert.Nil(t, err)
+	assert.Nil(t, sideChain)
+	assert.NotNil(t, approveRegisterSideChain(peers[3], 9, db, 1))
+	assert.NotNil(t, reject(peers[3]))
+
+	//the chain id can be requested again
+	_, err = RegisterSideChain(newContractNative(registerSideChainArgs(9), acct.Address, db, 1))
+	assert.Nil(t, err)
+}
+
+func TestExpireRegisterSideChain(t *testing.T) {
+	enableProposals(t)
+	peers := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	db := NewNative(nil, new(types.Transaction), nil).GetCacheDB()
+	putPeerMapPoolAndView(db, peers...)
+
+	for _, chainID := range []uint64{9, 10} {
+		_, err := RegisterSideChain(newContractNative(registerSideChainArgs(chainID), acct.Address, db, 1))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, approveRegisterSideChain(peers[0], 9, db, 1))
+
+	//the apply voted after expiry is renewed without the old votes, the other one is dropped
+	height := uint32(2 + node_manager.PROPOSAL_EXPIRY_BLOCKS)
+	assert.Nil(t, approveRegisterSideChain(peers[1], 9, db, height))
+	ns := newContractNative(nil, acct.Address, db, height)
+	proposal, err := node_manager.GetProposalByInput(ns, APPROVE_REGISTER_SIDE_CHAIN, utils.GetUint64Bytes(9))
+	assert.Nil(t, err)
+	assert.Equal(t, []common.Address{peers[1].Address}, proposal.Votes)
+	sideChain, err := getSideChainApply(ns, 10)
+	assert.Nil(t, err)
+	assert.Nil(t, sideChain)
+	_, err = RegisterSideChain(newContractNative(registerSideChainArgs(10), acct.Address, db, height))
+	assert.Nil(t, err)
+
+	assert.Nil(t, approveRegisterSideChain(peers[2], 9, db, height))
+	assert.Nil(t, approveRegisterSideChain(peers[3], 9, db, height))
+	sideChain, err = GetSideChain(ns, 9)
+	assert.Nil(t, err)
+	assert.NotNil(t, sideChain)
+}
+
+func TestApproveQuitSideChain(t *testing.T) {
+	peers := []*account.Account{account.NewAccount(""), account.NewAccount(""), account.NewAccount(""), account.NewAccount("")}
+	quit := func(db *storage.CacheDB) {
+		_, err := RegisterSideChain(newContractNative(registerSideChainArgs(9), acct.Address, db, 1))
+		assert.Nil(t, err)
+		for _, peer := range peers[:3] {
+			assert.Nil(t, approveRegisterSideChain(peer, 9, db, 1))
+		}
+		sink := common.NewZeroCopySink(nil)
+		(&ChainidParam{Chainid: 9, Address: acct.Address}).Serialization(sink)
+		_, err = QuitSideChain(newContractNative(sink.Bytes(), acct.Address, db, 1))
+		assert.Nil(t, err)
+		for _, peer := range peers[:3] {
+			sink := common.NewZeroCopySink(nil)
+			(&ChainidParam{Chainid: 9, Address: peer.Address}).Serialization(sink)
+			_, err = ApproveQuitSideChain(newContractNative(sink.Bytes(), peer.Address, db, 1))
+			assert.Nil(t, err)
+		}
+		sideChain, err := GetSideChain(newContractNative(nil, acct.Address, db, 1), 9)
+		assert.Nil(t, err)
+		assert.Nil(t, sideChain)
+	}
+
+	//the quit request is left before the proposals are enabled
+	db := NewNative(nil, new(types.Transaction), nil).GetCacheDB()
+	putPeerMapPoolAndView(db, peers...)
+	quit(db)
+	assert.Nil(t, getQuitSideChain(newContractNative(nil, acct.Address, db, 1), 9))
+
+	enableProposals(t)
+	db = NewNative(nil, new(types.Transaction), nil).GetCacheDB()
+	putPeerMapPoolAndView(db, peers...)
+	quit(db)
+	assert.NotNil(t, getQuitSideChain(newContractNative(nil, acct.Address, db, 1), 9))
+}
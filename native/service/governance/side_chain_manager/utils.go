@@ -95,6 +95,33 @@ func GetSideChain(native *native.NativeService, chainID uint64) (*SideChain, err
 
 }
 
+//GetSideChains returns all the registered side chains
+func GetSideChains(native *native.NativeService) ([]*SideChain, error) {
+	prefix := utils.ConcatKey(utils.SideChainManagerContractAddress, []byte(SIDE_CHAIN))
+	iter := native.GetCacheDB().NewIterator(prefix)
+	defer iter.Release()
+	sideChains := make([]*SideChain, 0)
+	for has := iter.First(); has; has = iter.Next() {
+		//skip the keys of other prefixes starting with sideChain, e.g. sideChainApply
+		if len(iter.Key()) != len(prefix)+8 {
+			continue
+		}
+		sideChainBytes, err := cstates.GetValueFromRawStorageItem(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("GetSideChains, deserialize from raw storage item err:%v", err)
+		}
+		sideChain := new(SideChain)
+		if err := sideChain.Deserialization(common.NewZeroCopySource(sideChainBytes)); err != nil {
+			return nil, fmt.Errorf("GetSideChains, deserialize sideChain error: %v", err)
+		}
+		sideChains = append(sideChains, sideChain)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("GetSideChains, iterate side chains error: %v", err)
+	}
+	return sideChains, nil
+}
+
 func PutSideChain(native *native.NativeService, sideChain *SideChain) error {
 	contract := utils.SideChainManagerContractAddress
 	chainidByte := utils.GetUint64Bytes(sideChain.ChainId)
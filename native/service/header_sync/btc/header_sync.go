@@ -21,6 +21,7 @@ import (
 	"encoding/binary"
 	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"math"
 	"math/big"
 
 	"fmt"
@@ -101,6 +102,25 @@ func (this *BTCHandler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
 
+func (this *BTCHandler) GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	bestHeader, err := GetBestBlockHeader(native, chainID)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(bestHeader.Height), nil
+}
+
+func (this *BTCHandler) GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	if height > math.MaxUint32 {
+		return nil, fmt.Errorf("BTCHandler GetHeaderByHeight, height %d is out of range", height)
+	}
+	storedHeader, err := GetHeaderByHeight(native, chainID, uint32(height))
+	if err != nil {
+		return nil, err
+	}
+	return NewHeaderInfo(storedHeader), nil
+}
+
 func getGenesisHeader(input []byte) (*wire.BlockHeader, uint32, error) {
 	params := new(scom.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(input)); err != nil {
@@ -32,6 +32,31 @@ type StoredHeader struct {
 	totalWork *big.Int
 }
 
+//HeaderInfo is the readable form of a StoredHeader, with hashes in the usual reversed hex
+type HeaderInfo struct {
+	Hash       string
+	Height     uint32
+	Version    int32
+	PrevBlock  string
+	MerkleRoot string
+	Timestamp  int64
+	Bits       uint32
+	Nonce      uint32
+}
+
+func NewHeaderInfo(sh *StoredHeader) *HeaderInfo {
+	return &HeaderInfo{
+		Hash:       sh.Header.BlockHash().String(),
+		Height:     sh.Height,
+		Version:    sh.Header.Version,
+		PrevBlock:  sh.Header.PrevBlock.String(),
+		MerkleRoot: sh.Header.MerkleRoot.String(),
+		Timestamp:  sh.Header.Timestamp.Unix(),
+		Bits:       sh.Header.Bits,
+		Nonce:      sh.Header.Nonce,
+	}
+}
+
 /*----- header serialization ------- */
 /* byteLength   desc          at offset
    80	       header	           0
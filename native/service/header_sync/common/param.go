@@ -49,6 +49,11 @@ type HeaderSyncHandler interface {
 	SyncGenesisHeader(service *native.NativeService) error
 	SyncBlockHeader(service *native.NativeService) error
 	SyncCrossChainMsg(service *native.NativeService) error
+
+	//GetCurrentHeight returns the height of the latest header synced from the side chain
+	GetCurrentHeight(service *native.NativeService, chainID uint64) (uint64, error)
+	//GetHeaderByHeight returns the stored side chain header at height, in a form that marshals to json
+	GetHeaderByHeight(service *native.NativeService, chainID uint64, height uint64) (interface{}, error)
 }
 
 type SyncGenesisHeaderParam struct {
@@ -132,3 +132,23 @@ func (this *CosmosHandler) SyncBlockHeader(native *native.NativeService) error {
 func (this *CosmosHandler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
+
+func (this *CosmosHandler) GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	info, err := GetEpochSwitchInfo(native, chainID)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(info.Height), nil
+}
+
+//GetHeaderByHeight returns the epoch switch info, it is all poly keeps of the header at the last validators change
+func (this *CosmosHandler) GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	info, err := GetEpochSwitchInfo(native, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(info.Height) != height {
+		return nil, fmt.Errorf("CosmosHandler GetHeaderByHeight, only the epoch switch header at height %d is stored", info.Height)
+	}
+	return info, nil
+}
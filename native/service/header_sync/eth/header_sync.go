@@ -203,6 +203,18 @@ func (this *ETHHandler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
 
+func (this *ETHHandler) GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	return GetCurrentHeaderHeight(native, chainID)
+}
+
+func (this *ETHHandler) GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	header, _, err := GetHeaderByHeight(native, height, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
 func getGenesisHeader(input []byte) (cty.Header, error) {
 	params := new(scom.SyncGenesisHeaderParam)
 	if err := params.Deserialization(common.NewZeroCopySource(input)); err != nil {
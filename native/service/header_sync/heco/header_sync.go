@@ -820,3 +820,18 @@ func ParseValidators(validatorsBytes []byte) ([]ecommon.Address, error) {
 func (h *Handler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
+
+func (h *Handler) GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	return GetCanonicalHeight(native, chainID)
+}
+
+func (h *Handler) GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	headerWithSum, err := GetCanonicalHeader(native, chainID, height)
+	if err != nil {
+		return nil, err
+	}
+	if headerWithSum == nil {
+		return nil, fmt.Errorf("heco Handler GetHeaderByHeight, can not find header at height %d", height)
+	}
+	return headerWithSum, nil
+}
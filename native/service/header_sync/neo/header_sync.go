@@ -104,3 +104,16 @@ func (this *NEOHandler) SyncBlockHeader(native *native.NativeService) error {
 func (this *NEOHandler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
+
+func (this *NEOHandler) GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	height, err := GetConsensusHeight(native, chainID)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(height), nil
+}
+
+//GetHeaderByHeight always fails, neo headers are verified on sync but never stored
+func (this *NEOHandler) GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	return nil, fmt.Errorf("NEOHandler GetHeaderByHeight, neo headers are not stored")
+}
@@ -77,6 +77,15 @@ func VerifyCrossChainMsgSig(native *native.NativeService, chainID uint64, crossC
 	return nil
 }
 
+//GetConsensusHeight returns the height of the latest synced header, headers are only synced when they switch consensus
+func GetConsensusHeight(native *native.NativeService, chainID uint64) (uint32, error) {
+	neoConsensus, err := getConsensusValByChainId(native, chainID)
+	if err != nil {
+		return 0, err
+	}
+	return neoConsensus.Height, nil
+}
+
 func getConsensusValByChainId(native *native.NativeService, chainID uint64) (*NeoConsensus, error) {
 	contract := utils.HeaderSyncContractAddress
 	chainIDBytes := utils.GetUint64Bytes(chainID)
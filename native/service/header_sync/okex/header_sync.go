@@ -143,6 +143,26 @@ func (h *Handler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
 
+func (h *Handler) GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	info, err := GetEpochSwitchInfo(native, chainID)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(info.Height), nil
+}
+
+//GetHeaderByHeight returns the epoch switch info, it is all poly keeps of the header at the last validators change
+func (h *Handler) GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	info, err := GetEpochSwitchInfo(native, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(info.Height) != height {
+		return nil, fmt.Errorf("okex Handler GetHeaderByHeight, only the epoch switch header at height %d is stored", info.Height)
+	}
+	return info, nil
+}
+
 func GetEpochSwitchInfo(service *native.NativeService, chainId uint64) (*CosmosEpochSwitchInfo, error) {
 	val, err := service.GetCacheDB().Get(
 		utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.EPOCH_SWITCH), utils.GetUint64Bytes(chainId)))
@@ -20,6 +20,7 @@ package ont
 import (
 	"fmt"
 	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"math"
 
 	"github.com/ontio/ontology-crypto/keypair"
 	ocommon "github.com/ontio/ontology/common"
@@ -146,3 +147,22 @@ func (this *ONTHandler) SyncCrossChainMsg(native *native.NativeService) error {
 	}
 	return nil
 }
+
+func (this *ONTHandler) GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	height, err := GetCurrentHeaderHeight(native, chainID)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(height), nil
+}
+
+func (this *ONTHandler) GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	if height > math.MaxUint32 {
+		return nil, fmt.Errorf("ONTHandler GetHeaderByHeight, height %d is out of range", height)
+	}
+	header, err := GetHeaderByHeight(native, chainID, uint32(height))
+	if err != nil {
+		return nil, err
+	}
+	return header, nil
+}
@@ -90,6 +90,23 @@ func PutBlockHeader(native *native.NativeService, chainID uint64, blockHeader *o
 	return nil
 }
 
+func GetCurrentHeaderHeight(native *native.NativeService, chainID uint64) (uint32, error) {
+	contract := utils.HeaderSyncContractAddress
+	heightStore, err := native.GetCacheDB().Get(utils.ConcatKey(contract, []byte(hscommon.CURRENT_HEADER_HEIGHT),
+		utils.GetUint64Bytes(chainID)))
+	if err != nil {
+		return 0, fmt.Errorf("GetCurrentHeaderHeight, get heightStore error: %v", err)
+	}
+	if heightStore == nil {
+		return 0, fmt.Errorf("GetCurrentHeaderHeight, can not find any height records")
+	}
+	heightBytes, err := cstates.GetValueFromRawStorageItem(heightStore)
+	if err != nil {
+		return 0, fmt.Errorf("GetCurrentHeaderHeight, deserialize heightBytes from raw storage item err:%v", err)
+	}
+	return utils.GetBytesUint32(heightBytes), nil
+}
+
 func GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint32) (*otypes.Header, error) {
 	contract := utils.HeaderSyncContractAddress
 	chainIDBytes := utils.GetUint64Bytes(chainID)
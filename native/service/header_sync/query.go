@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package header_sync
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+)
+
+//GetCurrentHeight returns the height of the latest header synced from the side chain of chainID
+func GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	handler, err := getSideChainHandler(native, chainID)
+	if err != nil {
+		return 0, fmt.Errorf("GetCurrentHeight, %v", err)
+	}
+	return handler.GetCurrentHeight(native, chainID)
+}
+
+//GetHeaderByHeight returns the header synced from the side chain of chainID at height
+func GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	handler, err := getSideChainHandler(native, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("GetHeaderByHeight, %v", err)
+	}
+	return handler.GetHeaderByHeight(native, chainID, height)
+}
+
+func getSideChainHandler(native *native.NativeService, chainID uint64) (hscommon.HeaderSyncHandler, error) {
+	sideChain, err := side_chain_manager.GetSideChain(native, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("side_chain_manager.GetSideChain error: %v", err)
+	}
+	if sideChain == nil {
+		return nil, fmt.Errorf("side chain %d is not registered", chainID)
+	}
+	return GetChainHandler(sideChain.Router)
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package header_sync
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	cstates "github.com/polynetwork/poly/core/states"
+	"github.com/polynetwork/poly/core/store/leveldbstore"
+	"github.com/polynetwork/poly/core/store/overlaydb"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/native"
+	"github.com/polynetwork/poly/native/service/governance/side_chain_manager"
+	hscommon "github.com/polynetwork/poly/native/service/header_sync/common"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func newNative(t *testing.T) *native.NativeService {
+	store, _ := leveldbstore.NewMemLevelDBStore()
+	db := storage.NewCacheDB(overlaydb.NewOverlayDB(store))
+	ns, err := native.NewNativeService(db, new(types.Transaction), 0, 0, common.Uint256{0}, 0, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ns
+}
+
+func TestQueryDispatch(t *testing.T) {
+	ns := newNative(t)
+	_, err := GetCurrentHeight(ns, 2)
+	assert.Error(t, err, "side chain is not registered")
+
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{ChainId: 2, Router: utils.ETH_ROUTER, Name: "eth"}))
+	_, err = GetCurrentHeight(ns, 2)
+	assert.Error(t, err, "no header synced yet")
+
+	ns.GetCacheDB().Put(utils.ConcatKey(utils.HeaderSyncContractAddress, []byte(hscommon.CURRENT_HEADER_HEIGHT), utils.GetUint64Bytes(2)),
+		cstates.GenRawStorageItem(utils.GetUint64Bytes(100)))
+	height, err := GetCurrentHeight(ns, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), height)
+	_, err = GetHeaderByHeight(ns, 2, 101)
+	assert.Error(t, err, "above the tip")
+
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{ChainId: 4, Router: utils.NEO_ROUTER, Name: "neo"}))
+	_, err = GetHeaderByHeight(ns, 4, 1)
+	assert.Error(t, err, "neo headers are not stored")
+
+	assert.Nil(t, side_chain_manager.PutSideChain(ns, &side_chain_manager.SideChain{ChainId: 9, Router: 1000, Name: "unknown"}))
+	_, err = GetCurrentHeight(ns, 9)
+	assert.Error(t, err, "unknown router")
+}
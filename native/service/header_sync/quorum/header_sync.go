@@ -106,3 +106,12 @@ func (h *QuorumHandler) SyncBlockHeader(ns *native.NativeService) error {
 func (h *QuorumHandler) SyncCrossChainMsg(ns *native.NativeService) error {
 	return nil
 }
+
+func (h *QuorumHandler) GetCurrentHeight(ns *native.NativeService, chainID uint64) (uint64, error) {
+	return GetCurrentValHeight(ns, chainID)
+}
+
+//GetHeaderByHeight always fails, quorum headers are verified on sync but never stored
+func (h *QuorumHandler) GetHeaderByHeight(ns *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	return nil, fmt.Errorf("QuorumHandler GetHeaderByHeight, quorum headers are not stored")
+}
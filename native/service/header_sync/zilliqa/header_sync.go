@@ -205,6 +205,18 @@ func (h *Handler) SyncCrossChainMsg(native *native.NativeService) error {
 	return nil
 }
 
+func (h *Handler) GetCurrentHeight(native *native.NativeService, chainID uint64) (uint64, error) {
+	return GetCurrentTxHeaderHeight(native, chainID)
+}
+
+func (h *Handler) GetHeaderByHeight(native *native.NativeService, chainID uint64, height uint64) (interface{}, error) {
+	txBlock, err := GetTxHeaderByHeight(native, height, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return txBlock, nil
+}
+
 type TxBlockAndDsComm struct {
 	TxBlock *core.TxBlock
 	DsBlock *core.DsBlock
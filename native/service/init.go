@@ -35,5 +35,8 @@ func init() {
 	native.Contracts[utils.NodeManagerContractAddress] = node_manager.RegisterNodeManagerContract
 	native.Contracts[utils.RelayerManagerContractAddress] = relayer_manager.RegisterRelayerManagerContract
 
+	node_manager.ProposalClosers[utils.SideChainManagerContractAddress] = side_chain_manager.CloseProposal
+	node_manager.ProposalClosers[utils.RelayerManagerContractAddress] = relayer_manager.CloseProposal
+
 	config.EXTRA_INFO_HEIGHT_FORK_CHECK = true
 }
@@ -38,6 +38,15 @@ var (
 	NodeManagerContractAddress, _       = common.AddressParseFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05})
 	RelayerManagerContractAddress, _    = common.AddressParseFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06})
 
+	//NativeContracts maps the names of the native contracts to their addresses
+	NativeContracts = map[string]common.Address{
+		"header_sync":         HeaderSyncContractAddress,
+		"cross_chain_manager": CrossChainManagerContractAddress,
+		"side_chain_manager":  SideChainManagerContractAddress,
+		"node_manager":        NodeManagerContractAddress,
+		"relayer_manager":     RelayerManagerContractAddress,
+	}
+
 	BTC_ROUTER     = uint64(1)
 	ETH_ROUTER     = uint64(2)
 	ONT_ROUTER     = uint64(3)
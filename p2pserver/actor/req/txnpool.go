@@ -19,9 +19,11 @@
 package req
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/types"
 	p2pcommon "github.com/polynetwork/poly/p2pserver/common"
@@ -31,11 +33,21 @@ import (
 const txnPoolReqTimeout = p2pcommon.ACTOR_TIMEOUT * time.Second
 
 var txnPoolPid *actor.PID
+var txnPoolLookup func(common.Uint256) bool
+var txnPoolGetter func(common.Uint256) *types.Transaction
 
 func SetTxnPoolPid(txnPid *actor.PID) {
 	txnPoolPid = txnPid
 }
 
+func SetTxnPoolLookup(lookup func(common.Uint256) bool) {
+	txnPoolLookup = lookup
+}
+
+func SetTxnPoolGetter(getter func(common.Uint256) *types.Transaction) {
+	txnPoolGetter = getter
+}
+
 //add txn to txnpool
 func AddTransaction(transaction *types.Transaction) {
 	if txnPoolPid == nil {
@@ -49,3 +61,21 @@ func AddTransaction(transaction *types.Transaction) {
 	}
 	txnPoolPid.Tell(txReq)
 }
+
+//get txn from txnpool by hash, the getter reads the pool directly so that
+//data requests are not blocked by the txnpool actor
+func GetTransaction(hash common.Uint256) (*types.Transaction, error) {
+	if txnPoolGetter == nil {
+		return nil, errors.New("[p2p]net_server GetTransaction(): txnpool getter is nil")
+	}
+	return txnPoolGetter(hash), nil
+}
+
+//check whether txn is pending or verified in txnpool, the lookup reads the
+//pool directly so that inv handling is not blocked by the txnpool actor
+func CheckTransaction(hash common.Uint256) (bool, error) {
+	if txnPoolLookup == nil {
+		return false, errors.New("[p2p]net_server CheckTransaction(): txnpool lookup is nil")
+	}
+	return txnPoolLookup(hash), nil
+}
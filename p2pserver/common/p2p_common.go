@@ -58,8 +58,10 @@ const (
 
 //msg type const
 const (
-	MAX_ADDR_NODE_CNT = 64 //the maximum peer address from msg
-	MAX_INV_BLK_CNT   = 64 //the maximum blk hash cnt of inv msg
+	MAX_ADDR_NODE_CNT = 64    //the maximum peer address from msg
+	MAX_INV_BLK_CNT   = 64    //the maximum blk hash cnt of inv msg
+	MAX_KNOWN_TX_CNT  = 32768 //the maximum tx hash cnt remembered per peer
+	MAX_TX_REQ_CNT    = 8192  //the maximum in-flight tx data req record
 )
 
 //info update const
@@ -67,6 +67,11 @@ func (this *Inv) Deserialization(source *common.ZeroCopySource) error {
 		return io.ErrUnexpectedEOF
 	}
 
+	//only the first MAX_INV_BLK_CNT hashes are kept, the rest is not read
+	if blkCnt > p2pCommon.MAX_INV_BLK_CNT {
+		blkCnt = p2pCommon.MAX_INV_BLK_CNT
+	}
+	this.P.Blk = make([]common.Uint256, 0, blkCnt)
 	for i := 0; i < int(blkCnt); i++ {
 		hash, eof := source.NextHash()
 		if eof {
@@ -76,9 +81,5 @@ func (this *Inv) Deserialization(source *common.ZeroCopySource) error {
 		this.P.Blk = append(this.P.Blk, hash)
 	}
 
-	if blkCnt > p2pCommon.MAX_INV_BLK_CNT {
-		blkCnt = p2pCommon.MAX_INV_BLK_CNT
-	}
-	this.P.Blk = this.P.Blk[:blkCnt]
 	return nil
 }
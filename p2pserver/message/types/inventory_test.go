@@ -0,0 +1,55 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	comm "github.com/polynetwork/poly/p2pserver/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvSerializationDeserialization(t *testing.T) {
+	var msg Inv
+	msg.P.InvType = common.TRANSACTION
+	msg.P.Blk = []common.Uint256{{1}, {2}}
+
+	MessageTest(t, &msg)
+}
+
+func TestInvDeserializationCap(t *testing.T) {
+	var msg Inv
+	msg.P.InvType = common.TRANSACTION
+	for i := 0; i < comm.MAX_INV_BLK_CNT+10; i++ {
+		msg.P.Blk = append(msg.P.Blk, common.Uint256{byte(i)})
+	}
+	sink := common.NewZeroCopySink(nil)
+	assert.Nil(t, msg.Serialization(sink))
+
+	var inv Inv
+	assert.Nil(t, inv.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, msg.P.Blk[:comm.MAX_INV_BLK_CNT], inv.P.Blk)
+
+	//a count beyond the cap must not be trusted for allocation
+	sink = common.NewZeroCopySink(nil)
+	sink.WriteUint8(uint8(common.TRANSACTION))
+	sink.WriteUint32(0xffffffff)
+	inv = Inv{}
+	assert.NotNil(t, inv.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+}
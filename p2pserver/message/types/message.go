@@ -134,7 +134,7 @@ func ReadMessage(reader io.Reader) (Message, uint32, error) {
 		return nil, 0, fmt.Errorf("message checksum mismatch: %x != %x ", hdr.Checksum, checksum)
 	}
 
-	cmdType := string(bytes.TrimRight(hdr.CMD[:], string(0)))
+	cmdType := string(bytes.TrimRight(hdr.CMD[:], "\x00"))
 	msg, err := MakeEmptyMessage(cmdType)
 	if err != nil {
 		return nil, 0, err
@@ -43,6 +43,10 @@ import (
 //respCache cache for some response data
 var respCache *lru.ARCCache
 
+//txReqCache records the time of in-flight tx data req, avoid requesting
+//the same tx from every peer announcing it
+var txReqCache, _ = lru.New(msgCommon.MAX_TX_REQ_CNT)
+
 // AddrReqHandle handles the neighbor address request from peer
 func AddrReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
 	log.Trace("[p2p]receive addr request message", data.Addr, data.Id)
@@ -197,8 +201,13 @@ func TransactionHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID
 	log.Trace("[p2p]receive transaction message", data.Addr, data.Id)
 
 	var trn = data.Payload.(*msgTypes.Trn)
+	hash := trn.Txn.Hash()
+	if remotePeer := p2p.GetPeer(data.Id); remotePeer != nil {
+		remotePeer.MarkTxKnown(hash)
+	}
+	txReqCache.Remove(hash)
 	actor.AddTransaction(trn.Txn)
-	log.Trace("[p2p]receive Transaction message hash", trn.Txn.Hash())
+	log.Trace("[p2p]receive Transaction message hash", hash)
 
 }
 
@@ -549,17 +558,22 @@ func DataReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 		}
 
 	case common.TRANSACTION:
-		txn, err := ledger.DefLedger.GetTransaction(hash)
-		if err != nil {
+		// announced txs are usually still in the pool, fall back to ledger
+		txn, err := actor.GetTransaction(hash)
+		if txn == nil || err != nil {
+			txn, err = ledger.DefLedger.GetTransaction(hash)
+		}
+		if txn == nil || err != nil {
 			log.Debug("[p2p]Can't get transaction by hash: ",
 				hash, " ,send not found message")
 			msg := msgpack.NewNotFound(hash)
 			err = p2p.Send(remotePeer, msg, false)
 			if err != nil {
 				log.Warn(err)
-				return
 			}
+			return
 		}
+		remotePeer.MarkTxKnown(hash)
 		msg := msgpack.NewTxn(txn)
 		err = p2p.Send(remotePeer, msg, false)
 		if err != nil {
@@ -592,14 +606,17 @@ func InvHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args .
 	invType := common.InventoryType(inv.P.InvType)
 	switch invType {
 	case common.TRANSACTION:
-		log.Debug("[p2p]receive transaction message", id)
-		// TODO check the ID queue
-		id = inv.P.Blk[0]
-		trn, err := ledger.DefLedger.GetTransaction(id)
-		if trn == nil || err != nil {
+		log.Debug("[p2p]receive inv-transaction message")
+		for _, id = range inv.P.Blk {
+			remotePeer.MarkTxKnown(id)
+			if !needTxData(id) {
+				continue
+			}
+			log.Debugf("[p2p]inv request transaction hash: %s", id.ToHexString())
 			msg := msgpack.NewTxnDataReq(id)
-			err = p2p.Send(remotePeer, msg, false)
+			err := p2p.Send(remotePeer, msg, false)
 			if err != nil {
+				txReqCache.Remove(id)
 				log.Warn(err)
 				return
 			}
@@ -665,6 +682,30 @@ func DisconnectHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID,
 	}
 }
 
+//needTxData return whether an announced tx is unknown locally and not
+//requested recently, recording the request if so. A failed lookup counts
+//as unknown, requesting a tx twice is cheaper than missing it
+func needTxData(hash common.Uint256) bool {
+	if reqTime, ok := txReqCache.Get(hash); ok &&
+		time.Since(reqTime.(time.Time)) < msgCommon.REQ_INTERVAL*time.Second {
+		return false
+	}
+	isContain, err := ledger.DefLedger.IsContainTransaction(hash)
+	if err != nil {
+		log.Debugf("[p2p]check ledger for tx %s: %s", hash.ToHexString(), err)
+	} else if isContain {
+		return false
+	}
+	inPool, err := actor.CheckTransaction(hash)
+	if err != nil {
+		log.Debugf("[p2p]check txnpool for tx %s: %s", hash.ToHexString(), err)
+	} else if inPool {
+		return false
+	}
+	txReqCache.Add(hash, time.Now())
+	return true
+}
+
 //get blk hdrs from starthash to stophash
 func GetHeadersFromHash(startHash common.Uint256, stopHash common.Uint256) ([]*types.Header, error) {
 	var count uint32 = 0
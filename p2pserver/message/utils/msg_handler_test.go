@@ -34,6 +34,7 @@ import (
 	"github.com/polynetwork/poly/core/payload"
 	ct "github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/events"
+	actor "github.com/polynetwork/poly/p2pserver/actor/req"
 	msgCommon "github.com/polynetwork/poly/p2pserver/common"
 	"github.com/polynetwork/poly/p2pserver/message/msg_pack"
 	"github.com/polynetwork/poly/p2pserver/message/types"
@@ -55,7 +56,7 @@ func init() {
 	events.Init()
 	// Initial a ledger
 	var err error
-	ledger.DefLedger, err = ledger.NewLedger(config.DEFAULT_DATA_DIR, 0)
+	ledger.DefLedger, err = ledger.NewLedger(config.DEFAULT_DATA_DIR)
 	if err != nil {
 		log.Fatalf("NewLedger error %s", err)
 	}
@@ -539,6 +540,118 @@ func TestInvHandle(t *testing.T) {
 	network.DelNbrNode(testID)
 }
 
+// sendRecorder records the messages sent by handlers instead of writing them
+type sendRecorder struct {
+	p2p.P2P
+	sent []types.Message
+}
+
+func (this *sendRecorder) Send(p *peer.Peer, msg types.Message, isConsensus bool) error {
+	this.sent = append(this.sent, msg)
+	return nil
+}
+
+// TestNeedTxData tests Function needTxData deciding whether to request a tx
+func TestNeedTxData(t *testing.T) {
+	defer actor.SetTxnPoolLookup(nil)
+	hash := common.Uint256{1, 2, 3}
+
+	txReqCache.Purge()
+	actor.SetTxnPoolLookup(func(common.Uint256) bool { return true })
+	assert.False(t, needTxData(hash))
+
+	actor.SetTxnPoolLookup(func(common.Uint256) bool { return false })
+	assert.True(t, needTxData(hash))
+	//requested recently
+	assert.False(t, needTxData(hash))
+
+	//a failed lookup still requests the tx
+	txReqCache.Purge()
+	actor.SetTxnPoolLookup(nil)
+	assert.True(t, needTxData(hash))
+}
+
+// TestInvHandleTransaction tests Function InvHandle requesting announced txs
+func TestInvHandleTransaction(t *testing.T) {
+	defer actor.SetTxnPoolLookup(nil)
+	var testID uint64
+	_, testPub, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	key := keypair.SerializePublicKey(testPub)
+	err := binary.Read(bytes.NewBuffer(key[:8]), binary.LittleEndian, &(testID))
+	assert.Nil(t, err)
+
+	remotePeer := peer.NewPeer()
+	remotePeer.UpdateInfo(time.Now(), 1, 12345678, 20336,
+		20337, testID, 0, 12345, "1.5.2")
+	network.AddNbrNode(remotePeer)
+	defer network.DelNbrNode(testID)
+
+	inPool := common.Uint256{4, 5, 6}
+	unknown := common.Uint256{7, 8, 9}
+	txReqCache.Purge()
+	actor.SetTxnPoolLookup(func(hash common.Uint256) bool { return hash == inPool })
+
+	recorder := &sendRecorder{P2P: network}
+	invPayload := msgpack.NewInvPayload(common.TRANSACTION, []common.Uint256{inPool, unknown})
+	msg := &types.MsgPayload{
+		Id:      testID,
+		Addr:    "127.0.0.1:50010",
+		Payload: msgpack.NewInv(invPayload),
+	}
+	InvHandle(msg, recorder, nil)
+
+	assert.True(t, remotePeer.IsTxKnown(inPool))
+	assert.True(t, remotePeer.IsTxKnown(unknown))
+	assert.Equal(t, []types.Message{msgpack.NewTxnDataReq(unknown)}, recorder.sent)
+
+	//announced again by another peer within REQ_INTERVAL, not requested twice
+	recorder.sent = nil
+	InvHandle(msg, recorder, nil)
+	assert.Empty(t, recorder.sent)
+}
+
+// TestDataReqHandleTransaction tests Function DataReqHandle serving txs from the pool getter
+func TestDataReqHandleTransaction(t *testing.T) {
+	defer actor.SetTxnPoolGetter(nil)
+	var testID uint64
+	_, testPub, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	key := keypair.SerializePublicKey(testPub)
+	err := binary.Read(bytes.NewBuffer(key[:8]), binary.LittleEndian, &(testID))
+	assert.Nil(t, err)
+
+	remotePeer := peer.NewPeer()
+	remotePeer.UpdateInfo(time.Now(), 1, 12345678, 20336,
+		20337, testID, 0, 12345, "1.5.2")
+	network.AddNbrNode(remotePeer)
+	defer network.DelNbrNode(testID)
+
+	tx := &ct.Transaction{
+		Version: 0,
+		TxType:  ct.Invoke,
+		Payload: &payload.InvokeCode{Code: []byte("ont")},
+	}
+	inPool := common.Uint256{4, 5, 6}
+	unknown := common.Uint256{7, 8, 9}
+	actor.SetTxnPoolGetter(func(hash common.Uint256) *ct.Transaction {
+		if hash == inPool {
+			return tx
+		}
+		return nil
+	})
+
+	recorder := &sendRecorder{P2P: network}
+	for _, hash := range []common.Uint256{inPool, unknown} {
+		DataReqHandle(&types.MsgPayload{
+			Id:      testID,
+			Addr:    "127.0.0.1:50010",
+			Payload: msgpack.NewTxnDataReq(hash),
+		}, recorder, nil)
+	}
+	assert.Equal(t, []types.Message{msgpack.NewTxn(tx), msgpack.NewNotFound(unknown)}, recorder.sent)
+	assert.True(t, remotePeer.IsTxKnown(inPool))
+	assert.False(t, remotePeer.IsTxKnown(unknown))
+}
+
 // TestDisconnectHandle tests Function DisconnectHandle handling a disconnect event
 func TestDisconnectHandle(t *testing.T) {
 	var testID uint64
@@ -68,11 +68,14 @@ type ReconnectAddrs struct {
 
 //NewServer return a new p2pserver according to the pubkey
 func NewServer() *P2PServer {
-	n := netserver.NewNetServer()
+	return NewServerWithNetwork(netserver.NewNetServer(), ledger.DefLedger)
+}
 
+//NewServerWithNetwork return a new p2pserver working on the given network and ledger
+func NewServerWithNetwork(network p2pnet.P2P, db *ledger.Ledger) *P2PServer {
 	p := &P2PServer{
-		network: n,
-		ledger:  ledger.DefLedger,
+		network: network,
+		ledger:  db,
 	}
 
 	p.msgRouter = utils.NewMsgRouter(p.network)
@@ -149,7 +152,9 @@ func (this *P2PServer) Xmit(message interface{}) error {
 	case *types.Transaction:
 		log.Debug("[p2p]TX transaction message")
 		txn := message.(*types.Transaction)
-		msg = msgpack.NewTxn(txn)
+		// announce the hash only, peers lacking the tx request it by getdata
+		this.network.GetNp().BroadcastTxInv(txn.Hash())
+		return nil
 	case *msgtypes.ConsensusPayload:
 		log.Debug("[p2p]TX consensus message")
 		consensusPayload := message.(*msgtypes.ConsensusPayload)
@@ -359,7 +364,7 @@ func (this *P2PServer) reachMinConnection() bool {
 	case "dbft":
 	case "solo":
 		minCount = config.SOLO_MIN_NODE_NUM
-	case "vbft":
+	case "vbft", "hotstuff":
 		minCount = config.VBFT_MIN_NODE_NUM
 
 	}
@@ -52,6 +52,32 @@ func (this *NbrPeers) Broadcast(msg types.Message, isConsensus bool) {
 	}
 }
 
+//BroadcastTxInv announce the tx hash to all establish peers not yet known to have it
+func (this *NbrPeers) BroadcastTxInv(hash comm.Uint256) {
+	inv := &types.Inv{
+		P: types.InvPayload{
+			InvType: comm.TRANSACTION,
+			Blk:     []comm.Uint256{hash},
+		},
+	}
+	sink := comm.NewZeroCopySink(nil)
+	err := types.WriteMessage(sink, inv)
+	if err != nil {
+		log.Errorf("[p2p]error serialize message ", err.Error())
+		return
+	}
+
+	this.RLock()
+	defer this.RUnlock()
+	for _, node := range this.List {
+		if node.syncState == common.ESTABLISH && node.GetRelay() == true &&
+			!node.IsTxKnown(hash) {
+			node.MarkTxKnown(hash)
+			node.SendRaw(inv.CmdType(), sink.Bytes(), false)
+		}
+	}
+}
+
 //NodeExisted return when peer in nbr list
 func (this *NbrPeers) NodeExisted(uid uint64) bool {
 	_, ok := this.List[uid]
@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	comm "github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/p2pserver/common"
@@ -147,6 +148,7 @@ type Peer struct {
 	txnCnt    uint64
 	rxTxnCnt  uint64
 	connLock  sync.RWMutex
+	knownTxs  *lru.Cache //hashes of txs the peer is known to have
 }
 
 //NewPeer return new peer without publickey initial
@@ -157,6 +159,7 @@ func NewPeer() *Peer {
 	}
 	p.SyncLink = conn.NewLink()
 	p.ConsLink = conn.NewLink()
+	p.knownTxs, _ = lru.New(common.MAX_KNOWN_TX_CNT)
 	runtime.SetFinalizer(p, rmPeer)
 	return p
 }
@@ -406,3 +409,13 @@ func (this *Peer) UpdateInfo(t time.Time, version uint32, services uint64,
 	}
 	this.SetHeight(uint64(height))
 }
+
+//MarkTxKnown records that the peer has the tx, so it is not announced again
+func (this *Peer) MarkTxKnown(hash comm.Uint256) {
+	this.knownTxs.Add(hash, struct{}{})
+}
+
+//IsTxKnown return whether the peer is known to have the tx
+func (this *Peer) IsTxKnown(hash comm.Uint256) bool {
+	return this.knownTxs.Contains(hash)
+}
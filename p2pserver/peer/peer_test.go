@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	comm "github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
 )
 
@@ -113,3 +114,14 @@ func TestUpdatePeer(t *testing.T) {
 	p.DumpInfo()
 
 }
+
+func TestKnownTxs(t *testing.T) {
+	hash := comm.Uint256{1, 2, 3}
+	if p.IsTxKnown(hash) {
+		t.Errorf("IsTxKnown error")
+	}
+	p.MarkTxKnown(hash)
+	if !p.IsTxKnown(hash) {
+		t.Errorf("MarkTxKnown error")
+	}
+}
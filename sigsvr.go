@@ -22,6 +22,7 @@ import (
 	"github.com/polynetwork/poly/cmd/abi"
 	cmdsvr "github.com/polynetwork/poly/cmd/sigsvr"
 	clisvrcom "github.com/polynetwork/poly/cmd/sigsvr/common"
+	"github.com/polynetwork/poly/cmd/sigsvr/policy"
 	"github.com/polynetwork/poly/cmd/sigsvr/store"
 	"github.com/polynetwork/poly/cmd/utils"
 	"github.com/polynetwork/poly/common/config"
@@ -42,6 +43,7 @@ func setupSigSvr() *cli.App {
 	app.Flags = []cli.Flag{
 		utils.LogLevelFlag,
 		utils.CliWalletDirFlag,
+		utils.CliPolicyFileFlag,
 		//cli setting
 		utils.CliAddressFlag,
 		utils.CliRpcPortFlag,
@@ -81,6 +83,19 @@ func startSigSvr(ctx *cli.Context) {
 	}
 	log.Infof("Load wallet data success. Account number:%d", accountNum)
 
+	policyFile := ctx.String(utils.GetFlagName(utils.CliPolicyFileFlag))
+	if policyFile != "" {
+		engine, err := policy.LoadEngine(policyFile)
+		if err != nil {
+			log.Errorf("LoadEngine error:%s", err)
+			return
+		}
+		policy.DefEngine = engine
+		log.Infof("Load signing policy success")
+	} else {
+		log.Warnf("No signing policy, using --%s flag to restrict what keys sign", utils.GetFlagName(utils.CliPolicyFileFlag))
+	}
+
 	rpcAddress := ctx.String(utils.GetFlagName(utils.CliAddressFlag))
 	rpcPort := ctx.Uint(utils.GetFlagName(utils.CliRpcPortFlag))
 	if rpcPort == 0 {
@@ -106,6 +121,7 @@ func startSigSvr(ctx *cli.Context) {
 		}
 	}()
 	<-exit
+	policy.DefEngine.Close()
 }
 
 func main() {
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"bytes"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+)
+
+// NonceKey identifies the versions of a transaction, the transactions of
+// a signer with the same nonce replace each other in the pool. The signer is
+// derived from the signatures, which are verified before the transactions
+// enter the pool, the transactions without a signer are never replaced.
+type NonceKey struct {
+	Signer common.Address
+	Nonce  uint32
+}
+
+// TxNonceKey returns the nonce key of the transaction.
+func TxNonceKey(tx *types.Transaction) NonceKey {
+	return NonceKey{Signer: TxSigner(tx), Nonce: tx.Nonce}
+}
+
+// Replaces reports whether tx wins over old of the same nonce key. The one
+// with the higher gas price wins, then the one with the higher gas limit,
+// then the one with the lower hash, so that every node keeps the same
+// version whatever order they receive them in.
+func Replaces(tx, old *types.Transaction) bool {
+	if tx.GasPrice != old.GasPrice {
+		return tx.GasPrice > old.GasPrice
+	}
+	if tx.GasLimit != old.GasLimit {
+		return tx.GasLimit > old.GasLimit
+	}
+	hash, oldHash := tx.Hash(), old.Hash()
+	return bytes.Compare(hash[:], oldHash[:]) < 0
+}
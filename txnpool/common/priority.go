@@ -0,0 +1,127 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
+	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/types"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+)
+
+type priorityKey struct {
+	contract common.Address
+	method   string
+}
+
+// Prioritizer assigns the configured priority to the transactions by the
+// native contract and method they invoke.
+type Prioritizer struct {
+	rules map[priorityKey]uint8
+}
+
+// NewPrioritizer builds a prioritizer from the configured rules, the
+// contract of a rule is a native contract name or an address in hex.
+func NewPrioritizer(priorities []*config.TxPoolPriority) (*Prioritizer, error) {
+	p := &Prioritizer{rules: make(map[priorityKey]uint8)}
+	for _, rule := range priorities {
+		addr, err := ParseContract(rule.Contract)
+		if err != nil {
+			return nil, err
+		}
+		p.rules[priorityKey{contract: addr, method: rule.Method}] = rule.Priority
+	}
+	return p, nil
+}
+
+// ParseContract resolves a native contract name or a contract address in hex.
+func ParseContract(contract string) (common.Address, error) {
+	if addr, ok := nutils.NativeContracts[strings.ToLower(contract)]; ok {
+		return addr, nil
+	}
+	addr, err := common.AddressFromHexString(contract)
+	if err != nil {
+		return common.ADDRESS_EMPTY, fmt.Errorf("unknown contract %s", contract)
+	}
+	return addr, nil
+}
+
+// Priority returns the priority of invoking the method of the contract, a
+// rule of the method takes precedence over a rule of the whole contract.
+func (p *Prioritizer) Priority(contract common.Address, method string) uint8 {
+	if p == nil {
+		return 0
+	}
+	if prio, ok := p.rules[priorityKey{contract: contract, method: method}]; ok {
+		return prio
+	}
+	return p.rules[priorityKey{contract: contract}]
+}
+
+// DecodeInvoke returns the contract and the method the transaction invokes.
+func DecodeInvoke(tx *types.Transaction) (common.Address, string, bool) {
+	if tx.TxType != types.Invoke {
+		return common.ADDRESS_EMPTY, "", false
+	}
+	invokeCode, ok := tx.Payload.(*payload.InvokeCode)
+	if !ok {
+		return common.ADDRESS_EMPTY, "", false
+	}
+	param := &states.ContractInvokeParam{}
+	if err := param.Deserialization(common.NewZeroCopySource(invokeCode.Code)); err != nil {
+		return common.ADDRESS_EMPTY, "", false
+	}
+	return param.Address, param.Method, true
+}
+
+// TxSigner returns the account the transaction is accounted to in the pool,
+// the payer if it is among the signers, or the signer of the first signature,
+// so that it can't be spoofed and doesn't depend on the order of SignedAddr.
+// The signatures are verified before the transaction enters the pool.
+func TxSigner(tx *types.Transaction) common.Address {
+	signer := common.ADDRESS_EMPTY
+	for i := range tx.Sigs {
+		addr, err := sigAddress(&tx.Sigs[i])
+		if err != nil {
+			return common.ADDRESS_EMPTY
+		}
+		if i == 0 {
+			signer = addr
+		}
+		if tx.Payer == common.ADDRESS_EMPTY || addr == tx.Payer {
+			return addr
+		}
+	}
+	return signer
+}
+
+func sigAddress(sig *types.Sig) (common.Address, error) {
+	switch len(sig.PubKeys) {
+	case 0:
+		return common.ADDRESS_EMPTY, fmt.Errorf("no public key")
+	case 1:
+		return types.AddressFromPubKey(sig.PubKeys[0]), nil
+	default:
+		return types.AddressFromMultiPubKeys(sig.PubKeys, int(sig.M))
+	}
+}
@@ -20,7 +20,11 @@
 package common
 
 import (
+	"container/list"
+	"math"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/config"
@@ -41,20 +45,74 @@ type TXEntry struct {
 	Attrs []*TXAttr          // the result from each validator
 }
 
+// poolEntry is a verified transaction with its bookkeeping in the pool
+type poolEntry struct {
+	*TXEntry
+	priority uint8
+	signer   common.Address
+	nonceKey NonceKey
+	contract common.Address // the contract invoked, empty if not an invocation
+	method   string
+	size     uint64
+	arrival  time.Time
+	elem     *list.Element
+}
+
 // TXPool contains all currently valid transactions. Transactions
 // enter the pool when they are valid from the network,
 // consensus or submitted. They exit the pool when they are included
 // in the ledger.
 type TXPool struct {
 	sync.RWMutex
-	txList map[common.Uint256]*TXEntry // Transactions which have been verified
+	txList      map[common.Uint256]*poolEntry // Transactions which have been verified
+	queues      [math.MaxUint8 + 1]*list.List // FIFO queues of the transactions by priority
+	queueSize   [math.MaxUint8 + 1]uint64     // total size of the transactions by priority
+	signers     map[common.Address]uint       // transaction count by signer
+	nonces      map[NonceKey]common.Uint256   // transaction by signer and nonce
+	remained    map[common.Uint256]time.Time  // arrival of the transactions to be re-verified
+	size        uint64                        // total size of the transactions
+	prioritizer *Prioritizer
+	maxSize     uint64
+	maxBySigner uint
+
+	evicted        uint64 // transactions evicted for the higher priority ones
+	signerRejected uint64 // transactions rejected by the per signer limit
+	fullRejected   uint64 // transactions rejected by the size limit
+	replaced       uint64 // transactions replaced by the ones of the same signer and nonce
 }
 
-// Init creates a new transaction pool to gather.
+// Init creates a new transaction pool to gather with the configured limits.
 func (tp *TXPool) Init() {
+	if err := tp.InitWithConfig(config.DefConfig.TxPool); err != nil {
+		log.Errorf("Init: invalid tx pool config, %s", err)
+		tp.InitWithConfig(nil)
+	}
+}
+
+// InitWithConfig creates a new transaction pool, a nil config means no
+// priorities and no limits besides the capacity.
+func (tp *TXPool) InitWithConfig(cfg *config.TxPoolConfig) error {
 	tp.Lock()
 	defer tp.Unlock()
-	tp.txList = make(map[common.Uint256]*TXEntry)
+	tp.txList = make(map[common.Uint256]*poolEntry)
+	tp.signers = make(map[common.Address]uint)
+	tp.nonces = make(map[NonceKey]common.Uint256)
+	tp.remained = make(map[common.Uint256]time.Time)
+	for i := range tp.queues {
+		tp.queues[i] = nil
+		tp.queueSize[i] = 0
+	}
+	tp.size = 0
+	tp.prioritizer, tp.maxSize, tp.maxBySigner = nil, 0, 0
+	if cfg == nil {
+		return nil
+	}
+	prioritizer, err := NewPrioritizer(cfg.Priorities)
+	if err != nil {
+		return err
+	}
+	tp.prioritizer, tp.maxSize, tp.maxBySigner = prioritizer, cfg.MaxSize, cfg.MaxTxsPerSigner
+	return nil
 }
 
 // AddTxList adds a valid transaction to the transaction pool. If the
@@ -62,19 +120,168 @@ func (tp *TXPool) Init() {
 // txEntry includes transaction, fee, and verified information(height,
 // validator, error code).
 func (tp *TXPool) AddTxList(txEntry *TXEntry) bool {
+	return tp.AddTx(txEntry, false) == errors.ErrNoError
+}
+
+// AddTx adds a valid transaction to the transaction pool. If limited, the
+// transaction replaces the one of the same signer and nonce it wins over or
+// is rejected if it loses, it is rejected when its signer has too many
+// transactions in the pool, and when the pool is full it evicts the oldest
+// transactions of lower priorities or is rejected if there are not enough
+// of them.
+func (tp *TXPool) AddTx(txEntry *TXEntry, limited bool) errors.ErrCode {
 	tp.Lock()
 	defer tp.Unlock()
 	txHash := txEntry.Tx.Hash()
 	if _, ok := tp.txList[txHash]; ok {
 		log.Infof("AddTxList: transaction %x is already in the pool",
 			txHash)
-		return false
+		return errors.ErrDuplicateInput
 	}
 
-	tp.txList[txHash] = txEntry
+	entry := &poolEntry{
+		TXEntry:  txEntry,
+		signer:   TxSigner(txEntry.Tx),
+		nonceKey: TxNonceKey(txEntry.Tx),
+		size:     uint64(len(txEntry.Tx.Raw)),
+		arrival:  time.Now(),
+	}
+	if contract, method, ok := DecodeInvoke(txEntry.Tx); ok {
+		entry.contract, entry.method = contract, method
+		entry.priority = tp.prioritizer.Priority(contract, method)
+	}
+	if arrival, ok := tp.remained[txHash]; ok {
+		entry.arrival = arrival
+		delete(tp.remained, txHash)
+	}
+	if limited {
+		var old *poolEntry
+		if oldHash, ok := tp.nonces[entry.nonceKey]; ok {
+			old = tp.txList[oldHash]
+			if !Replaces(txEntry.Tx, old.Tx) {
+				log.Infof("AddTxList: transaction %x loses to %x of the same nonce", txHash, oldHash)
+				return errors.ErrDuplicateInput
+			}
+			tp.remove(oldHash)
+		}
+		if ret := tp.checkLimits(txHash, entry); ret != errors.ErrNoError {
+			if old != nil {
+				tp.insert(old.Tx.Hash(), old)
+			}
+			return ret
+		}
+		if old != nil {
+			tp.replaced++
+			log.Infof("AddTxList: transaction %x replaced by %x", old.Tx.Hash(), txHash)
+		}
+	}
+	tp.insert(txHash, entry)
+	return errors.ErrNoError
+}
+
+// checkLimits checks the per signer limit and makes room for the entry.
+func (tp *TXPool) checkLimits(txHash common.Uint256, entry *poolEntry) errors.ErrCode {
+	if tp.maxBySigner > 0 && tp.signers[entry.signer] >= tp.maxBySigner {
+		tp.signerRejected++
+		log.Infof("AddTxList: transaction %x rejected, signer %s has %d transactions in the pool",
+			txHash, entry.signer.ToBase58(), tp.signers[entry.signer])
+		return errors.ErrTxPoolFull
+	}
+	if !tp.makeRoom(entry) {
+		tp.fullRejected++
+		log.Infof("AddTxList: transaction %x rejected, the pool is full", txHash)
+		return errors.ErrTxPoolFull
+	}
+	return errors.ErrNoError
+}
+
+// isFull checks whether the pool can't hold more transactions of the size
+// after removing count transactions of the freed size.
+func (tp *TXPool) isFull(size, count, freed uint64) bool {
+	if uint64(len(tp.txList))+1-count > MAX_CAPACITY {
+		return true
+	}
+	return tp.maxSize > 0 && tp.size+size-freed > tp.maxSize
+}
+
+// makeRoom evicts the oldest transactions of priorities lower than the entry
+// until it fits in the pool, nothing is evicted if it can't fit anyway.
+func (tp *TXPool) makeRoom(entry *poolEntry) bool {
+	var count, freed uint64
+	var victims []*poolEntry
+	for prio := 0; tp.isFull(entry.size, count, freed); prio++ {
+		if prio >= int(entry.priority) {
+			return false
+		}
+		if tp.queues[prio] == nil {
+			continue
+		}
+		for elem := tp.queues[prio].Front(); elem != nil && tp.isFull(entry.size, count, freed); elem = elem.Next() {
+			victim := elem.Value.(*poolEntry)
+			victims = append(victims, victim)
+			count++
+			freed += victim.size
+		}
+	}
+	for _, victim := range victims {
+		log.Infof("AddTxList: transaction %x evicted for %x", victim.Tx.Hash(), entry.Tx.Hash())
+		tp.remove(victim.Tx.Hash())
+	}
+	tp.evicted += count
+	return true
+}
+
+func (tp *TXPool) insert(txHash common.Uint256, entry *poolEntry) {
+	queue := tp.queues[entry.priority]
+	if queue == nil {
+		queue = list.New()
+		tp.queues[entry.priority] = queue
+	}
+	entry.elem = queue.PushBack(entry)
+	tp.queueSize[entry.priority] += entry.size
+	tp.signers[entry.signer]++
+	if _, ok := tp.nonces[entry.nonceKey]; !ok && entry.signer != common.ADDRESS_EMPTY {
+		tp.nonces[entry.nonceKey] = txHash
+	}
+	tp.size += entry.size
+	tp.txList[txHash] = entry
+}
+
+func (tp *TXPool) remove(txHash common.Uint256) bool {
+	entry, ok := tp.txList[txHash]
+	if !ok {
+		return false
+	}
+	tp.queues[entry.priority].Remove(entry.elem)
+	tp.queueSize[entry.priority] -= entry.size
+	if tp.signers[entry.signer] <= 1 {
+		delete(tp.signers, entry.signer)
+	} else {
+		tp.signers[entry.signer]--
+	}
+	if tp.nonces[entry.nonceKey] == txHash {
+		delete(tp.nonces, entry.nonceKey)
+	}
+	tp.size -= entry.size
+	delete(tp.txList, txHash)
 	return true
 }
 
+// ordered returns the transactions from the highest priority to the lowest,
+// and by arrival in the same priority.
+func (tp *TXPool) ordered() []*poolEntry {
+	entries := make([]*poolEntry, 0, len(tp.txList))
+	for prio := len(tp.queues) - 1; prio >= 0; prio-- {
+		if tp.queues[prio] == nil {
+			continue
+		}
+		for elem := tp.queues[prio].Front(); elem != nil; elem = elem.Next() {
+			entries = append(entries, elem.Value.(*poolEntry))
+		}
+	}
+	return entries
+}
+
 // CleanTransactionList cleans the transaction list included in the ledger.
 func (tp *TXPool) CleanTransactionList(txs []*types.Transaction) error {
 	cleaned := 0
@@ -82,8 +289,7 @@ func (tp *TXPool) CleanTransactionList(txs []*types.Transaction) error {
 	tp.Lock()
 	defer tp.Unlock()
 	for _, tx := range txs {
-		if _, ok := tp.txList[tx.Hash()]; ok {
-			delete(tp.txList, tx.Hash())
+		if tp.remove(tx.Hash()) {
 			cleaned++
 		}
 	}
@@ -97,12 +303,7 @@ func (tp *TXPool) CleanTransactionList(txs []*types.Transaction) error {
 func (tp *TXPool) DelTxList(tx *types.Transaction) bool {
 	tp.Lock()
 	defer tp.Unlock()
-	txHash := tx.Hash()
-	if _, ok := tp.txList[txHash]; !ok {
-		return false
-	}
-	delete(tp.txList, txHash)
-	return true
+	return tp.remove(tx.Hash())
 }
 
 // compareTxHeight compares a verifed transaction's height with the next
@@ -118,18 +319,16 @@ func (tp *TXPool) compareTxHeight(txEntry *TXEntry, height uint32) bool {
 	return true
 }
 
-// GetTxPool gets the transaction lists from the pool for the consensus,
-// if the byCount is marked, return the configured number at most; if the
-// the byCount is not marked, return all of the current transaction pool.
+// GetTxPool gets the transaction lists from the pool for the consensus
+// ordered by priority and arrival, if the byCount is marked, return the
+// configured number at most; if the byCount is not marked, return all of
+// the current transaction pool.
 func (tp *TXPool) GetTxPool(byCount bool, height uint32) ([]*TXEntry,
 	[]*types.Transaction) {
 	tp.RLock()
 	defer tp.RUnlock()
 
-	orderByFee := make([]*TXEntry, 0, len(tp.txList))
-	for _, txEntry := range tp.txList {
-		orderByFee = append(orderByFee, txEntry)
-	}
+	ordered := tp.ordered()
 
 	count := int(config.DefConfig.Consensus.MaxTxInBlock)
 	if count <= 0 {
@@ -142,12 +341,12 @@ func (tp *TXPool) GetTxPool(byCount bool, height uint32) ([]*TXEntry,
 	var num int
 	txList := make([]*TXEntry, 0, count)
 	oldTxList := make([]*types.Transaction, 0)
-	for _, txEntry := range orderByFee {
-		if !tp.compareTxHeight(txEntry, height) {
-			oldTxList = append(oldTxList, txEntry.Tx)
+	for _, entry := range ordered {
+		if !tp.compareTxHeight(entry.TXEntry, height) {
+			oldTxList = append(oldTxList, entry.Tx)
 			continue
 		}
-		txList = append(txList, txEntry)
+		txList = append(txList, entry.TXEntry)
 		num++
 		if num >= count {
 			break
@@ -184,6 +383,22 @@ func (tp *TXPool) GetTxStatus(hash common.Uint256) *TxStatus {
 	return ret
 }
 
+// GetNonceConflict returns the transaction in the pool of the same signer
+// and nonce as tx but of a different hash, or nil if there is none.
+func (tp *TXPool) GetNonceConflict(tx *types.Transaction) *types.Transaction {
+	tp.RLock()
+	defer tp.RUnlock()
+	key := TxNonceKey(tx)
+	if key.Signer == common.ADDRESS_EMPTY {
+		return nil
+	}
+	hash, ok := tp.nonces[key]
+	if !ok || hash == tx.Hash() {
+		return nil
+	}
+	return tp.txList[hash].Tx
+}
+
 // GetTransactionCount returns the tx number of the pool.
 func (tp *TXPool) GetTransactionCount() int {
 	tp.RLock()
@@ -191,6 +406,13 @@ func (tp *TXPool) GetTransactionCount() int {
 	return len(tp.txList)
 }
 
+// GetTransactionSize returns the total size of the transactions in the pool.
+func (tp *TXPool) GetTransactionSize() uint64 {
+	tp.RLock()
+	defer tp.RUnlock()
+	return tp.size
+}
+
 // GetUnverifiedTxs checks the tx list in the block from consensus,
 // and returns verified tx list, unverified tx list, and
 // the tx list to be re-verified
@@ -211,8 +433,8 @@ func (tp *TXPool) GetUnverifiedTxs(txs []*types.Transaction,
 			continue
 		}
 
-		if !tp.compareTxHeight(txEntry, height) {
-			delete(tp.txList, tx.Hash())
+		if !tp.compareTxHeight(txEntry.TXEntry, height) {
+			tp.remove(tx.Hash())
 			res.OldTxs = append(res.OldTxs, txEntry.Tx)
 			continue
 		}
@@ -234,16 +456,97 @@ func (tp *TXPool) GetUnverifiedTxs(txs []*types.Transaction,
 	return res
 }
 
+// GetPoolTxs returns the transactions matching the filter from the offset,
+// limit at most, ordered by priority and arrival, and the number of the
+// matching transactions.
+func (tp *TXPool) GetPoolTxs(filter *TxFilter, offset, limit int) (int, []*PoolTx) {
+	tp.RLock()
+	defer tp.RUnlock()
+
+	total := 0
+	txs := make([]*PoolTx, 0)
+	for _, entry := range tp.ordered() {
+		if !filter.match(entry) {
+			continue
+		}
+		total++
+		if total <= offset || len(txs) >= limit {
+			continue
+		}
+		txs = append(txs, &PoolTx{
+			Tx:       entry.Tx,
+			Attrs:    entry.Attrs,
+			Signer:   entry.signer,
+			Contract: entry.contract,
+			Method:   entry.method,
+			Priority: entry.priority,
+			Arrival:  entry.arrival,
+		})
+	}
+	return total, txs
+}
+
+// DropTx removes a transaction from the pool, and returns false if it is
+// not in the pool.
+func (tp *TXPool) DropTx(hash common.Uint256) bool {
+	tp.Lock()
+	defer tp.Unlock()
+	return tp.remove(hash)
+}
+
 // Remain returns the remaining tx list to cleanup
 func (tp *TXPool) Remain() []*types.Transaction {
 	tp.Lock()
 	defer tp.Unlock()
 
 	txList := make([]*types.Transaction, 0, len(tp.txList))
-	for _, txEntry := range tp.txList {
-		txList = append(txList, txEntry.Tx)
-		delete(tp.txList, txEntry.Tx.Hash())
+	tp.remained = make(map[common.Uint256]time.Time, len(tp.txList))
+	for _, entry := range tp.ordered() {
+		txList = append(txList, entry.Tx)
+		tp.remained[entry.Tx.Hash()] = entry.arrival
+		tp.remove(entry.Tx.Hash())
 	}
 
 	return txList
 }
+
+// GetStats returns the statistics of the verified transactions in the pool.
+func (tp *TXPool) GetStats(topSigners int) *PoolStats {
+	tp.RLock()
+	defer tp.RUnlock()
+
+	stats := &PoolStats{
+		Count:           len(tp.txList),
+		Size:            tp.size,
+		MaxSize:         tp.maxSize,
+		MaxTxsPerSigner: tp.maxBySigner,
+		Signers:         len(tp.signers),
+		Evicted:         tp.evicted,
+		SignerRejected:  tp.signerRejected,
+		FullRejected:    tp.fullRejected,
+		Replaced:        tp.replaced,
+	}
+	for prio := len(tp.queues) - 1; prio >= 0; prio-- {
+		if tp.queues[prio] == nil || tp.queues[prio].Len() == 0 {
+			continue
+		}
+		stats.Priorities = append(stats.Priorities, &PriorityStats{
+			Priority: uint8(prio),
+			Count:    tp.queues[prio].Len(),
+			Size:     tp.queueSize[prio],
+		})
+	}
+	for signer, count := range tp.signers {
+		stats.TopSigners = append(stats.TopSigners, &SignerStats{Signer: signer, Count: count})
+	}
+	sort.Slice(stats.TopSigners, func(i, j int) bool {
+		if stats.TopSigners[i].Count != stats.TopSigners[j].Count {
+			return stats.TopSigners[i].Count > stats.TopSigners[j].Count
+		}
+		return stats.TopSigners[i].Signer.ToHexString() < stats.TopSigners[j].Signer.ToHexString()
+	})
+	if len(stats.TopSigners) > topSigners {
+		stats.TopSigners = stats.TopSigners[:topSigners]
+	}
+	return stats
+}
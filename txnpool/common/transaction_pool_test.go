@@ -19,12 +19,20 @@
 package common
 
 import (
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/config"
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/signature"
 	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/errors"
+	nutils "github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
 	"github.com/stretchr/testify/assert"
-	"testing"
-	"time"
 )
 
 var (
@@ -34,13 +42,48 @@ var (
 func init() {
 	log.Init(log.PATH, log.Stdout)
 
-	mutable := &types.MutableTransaction{
+	txn = newTestTx(0, common.ADDRESS_EMPTY, common.ADDRESS_EMPTY, "")
+}
+
+// newTestTx builds a transaction invoking the method of the contract, signed
+// by each of the signers
+func newTestTx(nonce uint32, payer, contract common.Address, method string, signers ...*account.Account) *types.Transaction {
+	param := &states.ContractInvokeParam{Address: contract, Method: method}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+	tx := &types.Transaction{
 		TxType:  types.Invoke,
-		Nonce:   uint32(time.Now().Unix()),
-		Payload: &payload.InvokeCode{Code: []byte{}},
+		Nonce:   nonce,
+		Payer:   payer,
+		Payload: &payload.InvokeCode{Code: sink.Bytes()},
 	}
+	for i := 0; i < 2; i++ {
+		sink = common.NewZeroCopySink(nil)
+		if err := tx.Serialization(sink); err != nil {
+			panic(err)
+		}
+		tx, _ = types.TransactionFromRawBytes(sink.Bytes())
+		if i > 0 {
+			break
+		}
+		hash := tx.Hash()
+		for _, signer := range signers {
+			sig, err := signature.Sign(signer, hash[:])
+			if err != nil {
+				panic(err)
+			}
+			tx.Sigs = append(tx.Sigs, types.Sig{
+				PubKeys: []keypair.PublicKey{signer.PublicKey},
+				M:       1,
+				SigData: [][]byte{sig},
+			})
+		}
+	}
+	return tx
+}
 
-	txn, _ = mutable.IntoImmutable()
+func newTestTxEntry(tx *types.Transaction) *TXEntry {
+	return &TXEntry{Tx: tx, Attrs: []*TXAttr{}}
 }
 
 func TestTxPool(t *testing.T) {
@@ -98,3 +141,158 @@ func TestTxPool(t *testing.T) {
 		return
 	}
 }
+
+func TestTxSigner(t *testing.T) {
+	acc1, acc2 := account.NewAccount(""), account.NewAccount("")
+	assert.Equal(t, common.ADDRESS_EMPTY, TxSigner(newTestTx(1, common.ADDRESS_EMPTY, common.ADDRESS_EMPTY, "")))
+	assert.Equal(t, acc1.Address, TxSigner(newTestTx(1, common.ADDRESS_EMPTY, common.ADDRESS_EMPTY, "", acc1, acc2)))
+
+	// the payer is the signer only if it signed the transaction
+	assert.Equal(t, acc2.Address, TxSigner(newTestTx(1, acc2.Address, common.ADDRESS_EMPTY, "", acc1, acc2)))
+	assert.Equal(t, acc1.Address, TxSigner(newTestTx(1, acc2.Address, common.ADDRESS_EMPTY, "", acc1)))
+
+	// the verified signer list is not ordered
+	tx := newTestTx(1, common.ADDRESS_EMPTY, common.ADDRESS_EMPTY, "", acc1, acc2)
+	tx.SignedAddr = []common.Address{acc2.Address, acc1.Address}
+	assert.Equal(t, acc1.Address, TxSigner(tx))
+}
+
+func TestTxPoolPriority(t *testing.T) {
+	txPool := &TXPool{}
+	assert.Nil(t, txPool.InitWithConfig(&config.TxPoolConfig{Priorities: []*config.TxPoolPriority{
+		{Contract: "node_manager", Priority: 2},
+		{Contract: "header_sync", Method: "syncHeader", Priority: 1},
+	}}))
+	acc := account.NewAccount("")
+	low := newTestTx(1, common.ADDRESS_EMPTY, nutils.HeaderSyncContractAddress, "syncGenesisHeader", acc)
+	mid := newTestTx(2, common.ADDRESS_EMPTY, nutils.HeaderSyncContractAddress, "syncHeader", acc)
+	high := newTestTx(3, common.ADDRESS_EMPTY, nutils.NodeManagerContractAddress, "approveCandidate", acc)
+	for _, tx := range []*types.Transaction{low, mid, high} {
+		assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(tx), true))
+	}
+
+	txList, _ := txPool.GetTxPool(false, 0)
+	assert.Equal(t, 3, len(txList))
+	for i, tx := range []*types.Transaction{high, mid, low} {
+		assert.Equal(t, tx.Hash(), txList[i].Tx.Hash())
+	}
+	_, txs := txPool.GetPoolTxs(&TxFilter{}, 0, 3)
+	assert.Equal(t, uint8(2), txs[0].Priority)
+	assert.Equal(t, nutils.NodeManagerContractAddress, txs[0].Contract)
+	assert.Equal(t, "approveCandidate", txs[0].Method)
+	assert.Equal(t, acc.Address, txs[0].Signer)
+}
+
+func TestTxPoolEviction(t *testing.T) {
+	acc := account.NewAccount("")
+	low1 := newTestTx(1, common.ADDRESS_EMPTY, nutils.HeaderSyncContractAddress, "syncHeader", acc)
+	low2 := newTestTx(2, common.ADDRESS_EMPTY, nutils.HeaderSyncContractAddress, "syncHeader", acc)
+	low3 := newTestTx(3, common.ADDRESS_EMPTY, nutils.HeaderSyncContractAddress, "syncHeader", acc)
+	high1 := newTestTx(4, common.ADDRESS_EMPTY, nutils.NodeManagerContractAddress, "syncHeader", acc)
+	high2 := newTestTx(5, common.ADDRESS_EMPTY, nutils.NodeManagerContractAddress, "syncHeader", acc)
+	high3 := newTestTx(6, common.ADDRESS_EMPTY, nutils.NodeManagerContractAddress, "syncHeader", acc)
+	size := uint64(len(low1.Raw))
+	assert.Equal(t, size, uint64(len(high1.Raw)))
+
+	txPool := &TXPool{}
+	assert.Nil(t, txPool.InitWithConfig(&config.TxPoolConfig{
+		MaxSize:    2 * size,
+		Priorities: []*config.TxPoolPriority{{Contract: "node_manager", Priority: 1}},
+	}))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(low1), true))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(low2), true))
+
+	// a full pool rejects the transactions of the same priority
+	assert.Equal(t, errors.ErrTxPoolFull, txPool.AddTx(newTestTxEntry(low3), true))
+	// the oldest ones of lower priorities are evicted for the higher priority ones
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(high1), true))
+	assert.Nil(t, txPool.GetTransaction(low1.Hash()))
+	assert.NotNil(t, txPool.GetTransaction(low2.Hash()))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(high2), true))
+	assert.Nil(t, txPool.GetTransaction(low2.Hash()))
+	assert.Equal(t, errors.ErrTxPoolFull, txPool.AddTx(newTestTxEntry(high3), true))
+
+	// transactions of blocks under verification are not limited
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(low3), false))
+	assert.Equal(t, 3*size, txPool.GetTransactionSize())
+
+	stats := txPool.GetStats(10)
+	assert.Equal(t, 3, stats.Count)
+	assert.Equal(t, 3*size, stats.Size)
+	assert.Equal(t, 2*size, stats.MaxSize)
+	assert.Equal(t, uint64(2), stats.Evicted)
+	assert.Equal(t, uint64(2), stats.FullRejected)
+	assert.Equal(t, []*PriorityStats{{Priority: 1, Count: 2, Size: 2 * size}, {Priority: 0, Count: 1, Size: size}}, stats.Priorities)
+}
+
+func TestTxPoolSignerLimit(t *testing.T) {
+	acc1, acc2, acc3 := account.NewAccount(""), account.NewAccount(""), account.NewAccount("")
+	txPool := &TXPool{}
+	assert.Nil(t, txPool.InitWithConfig(&config.TxPoolConfig{MaxTxsPerSigner: 2}))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(newTestTx(1, common.ADDRESS_EMPTY, common.ADDRESS_EMPTY, "", acc1)), true))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(newTestTx(2, common.ADDRESS_EMPTY, common.ADDRESS_EMPTY, "", acc1)), true))
+	assert.Equal(t, errors.ErrTxPoolFull, txPool.AddTx(newTestTxEntry(newTestTx(3, common.ADDRESS_EMPTY, common.ADDRESS_EMPTY, "", acc1)), true))
+
+	// naming another account as the payer doesn't use up its quota
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(newTestTx(1, acc3.Address, common.ADDRESS_EMPTY, "", acc2)), true))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(newTestTx(4, common.ADDRESS_EMPTY, common.ADDRESS_EMPTY, "", acc3)), true))
+
+	stats := txPool.GetStats(2)
+	assert.Equal(t, 4, stats.Count)
+	assert.Equal(t, 3, stats.Signers)
+	assert.Equal(t, uint(2), stats.MaxTxsPerSigner)
+	assert.Equal(t, uint64(1), stats.SignerRejected)
+	assert.Equal(t, 2, len(stats.TopSigners))
+	assert.Equal(t, &SignerStats{Signer: acc1.Address, Count: 2}, stats.TopSigners[0])
+	assert.Equal(t, uint(1), stats.TopSigners[1].Count)
+
+	_, txs := txPool.GetPoolTxs(&TxFilter{Signer: acc3.Address}, 0, 10)
+	assert.Equal(t, 1, len(txs))
+	_, txs = txPool.GetPoolTxs(&TxFilter{Signer: acc2.Address}, 0, 10)
+	assert.Equal(t, 1, len(txs))
+}
+
+func TestTxPoolNonceReplacement(t *testing.T) {
+	acc1, acc2 := account.NewAccount(""), account.NewAccount("")
+	newTx := func(gasPrice uint64, payer common.Address, signers ...*account.Account) *types.Transaction {
+		tx := newTestTx(1, payer, common.ADDRESS_EMPTY, "", signers...)
+		tx.GasPrice = gasPrice
+		sink := common.NewZeroCopySink(nil)
+		assert.Nil(t, tx.Serialization(sink))
+		tx, err := types.TransactionFromRawBytes(sink.Bytes())
+		assert.Nil(t, err)
+		return tx
+	}
+	txPool := &TXPool{}
+	assert.Nil(t, txPool.InitWithConfig(&config.TxPoolConfig{}))
+	v1 := newTx(1, common.ADDRESS_EMPTY, acc1)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(v1), true))
+
+	// the version of higher gas price replaces the one in the pool
+	v2 := newTx(2, common.ADDRESS_EMPTY, acc1)
+	assert.Equal(t, v1.Hash(), txPool.GetNonceConflict(v2).Hash())
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(v2), true))
+	assert.Nil(t, txPool.GetTransaction(v1.Hash()))
+	assert.Nil(t, txPool.GetNonceConflict(v2))
+
+	// the losing versions are rejected
+	assert.True(t, Replaces(v2, v1))
+	assert.Equal(t, errors.ErrDuplicateInput, txPool.AddTx(newTestTxEntry(v1), true))
+	v0 := newTx(0, common.ADDRESS_EMPTY, acc1)
+	assert.Equal(t, errors.ErrDuplicateInput, txPool.AddTx(newTestTxEntry(v0), true))
+	assert.Equal(t, 1, txPool.GetTransactionCount())
+
+	// the same nonce of another signer naming the signer as the payer is not a version
+	spoofed := newTx(3, acc1.Address, acc2)
+	assert.Nil(t, txPool.GetNonceConflict(spoofed))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(spoofed), true))
+	assert.NotNil(t, txPool.GetTransaction(v2.Hash()))
+
+	// transactions without a signer never replace each other
+	unsigned1, unsigned2 := newTx(4, common.ADDRESS_EMPTY), newTx(5, common.ADDRESS_EMPTY)
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(unsigned1), true))
+	assert.Nil(t, txPool.GetNonceConflict(unsigned2))
+	assert.Equal(t, errors.ErrNoError, txPool.AddTx(newTestTxEntry(unsigned2), true))
+	assert.Equal(t, 4, txPool.GetTransactionCount())
+	assert.Equal(t, uint64(1), txPool.GetStats(0).Replaced)
+}
@@ -19,6 +19,8 @@
 package common
 
 import (
+	"time"
+
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/errors"
@@ -33,6 +35,7 @@ const (
 	EXPIRE_INTERVAL  = 9                                // The timeout that verify tx
 	STATELESS_MASK   = 0x1                              // The mask of stateless validator
 	STATEFUL_MASK    = 0x2                              // The mask of stateful validator
+	PERMISSION_MASK  = 0x4                              // The mask of permission validator
 	VERIFY_MASK      = STATELESS_MASK | STATEFUL_MASK   // The mask that indicates tx valid
 	MAX_LIMITATION   = 10000                            // The length of pending tx from net and http
 	UPDATE_FREQUENCY = 100                              // The frequency to update gas price from global params
@@ -55,9 +58,10 @@ const (
 type SenderType uint8
 
 const (
-	NilSender  SenderType = iota
-	NetSender             // Net sends tx req
-	HttpSender            // Http sends tx req
+	NilSender     SenderType = iota
+	NetSender                // Net sends tx req
+	HttpSender               // Http sends tx req
+	JournalSender            // Journal reloads tx req at startup
 )
 
 func (sender SenderType) Sender() string {
@@ -68,11 +72,19 @@ func (sender SenderType) Sender() string {
 		return "net sender"
 	case HttpSender:
 		return "http sender"
+	case JournalSender:
+		return "journal sender"
 	default:
 		return "unknown sender"
 	}
 }
 
+// Submitted checks whether the tx is submitted to the pool rather than
+// from the blocks or the pool itself, the pool limits only apply to them.
+func (sender SenderType) Submitted() bool {
+	return sender == NetSender || sender == HttpSender || sender == JournalSender
+}
+
 // TxnStatsType enumerates the kind of tx statistics
 type TxnStatsType uint8
 
@@ -172,6 +184,98 @@ type GetTxnStatsRsp struct {
 	Count []uint64
 }
 
+// GetTxnPoolStatsReq specifies the api that how to get the statistics of
+// the verified transactions in the pool.
+type GetTxnPoolStatsReq struct {
+	TopSigners int
+}
+
+// GetTxnPoolStatsRsp returns the statistics of the verified transactions.
+type GetTxnPoolStatsRsp struct {
+	Stats *PoolStats
+}
+
+// PriorityStats contains the transactions of a priority in the pool
+type PriorityStats struct {
+	Priority uint8
+	Count    int
+	Size     uint64
+}
+
+// SignerStats contains the transaction count of a signer in the pool
+type SignerStats struct {
+	Signer common.Address
+	Count  uint
+}
+
+// PoolStats contains the statistics of the verified transactions in the pool
+type PoolStats struct {
+	Count           int              // transactions in the pool
+	Size            uint64           // total size of the transactions
+	MaxSize         uint64           // max total size, 0 for no limit
+	MaxTxsPerSigner uint             // max transactions of a signer, 0 for no limit
+	Signers         int              // signers with transactions in the pool
+	Priorities      []*PriorityStats // from the highest priority to the lowest
+	TopSigners      []*SignerStats   // signers with the most transactions
+	Evicted         uint64           // transactions evicted for the higher priority ones
+	SignerRejected  uint64           // transactions rejected by the per signer limit
+	FullRejected    uint64           // transactions rejected by the size limit
+	Replaced        uint64           // transactions replaced by the ones of the same signer and nonce
+}
+
+// TxFilter selects the transactions in the pool, the empty fields match
+// every transaction.
+type TxFilter struct {
+	Signer   common.Address
+	Contract common.Address
+	Method   string
+}
+
+func (filter *TxFilter) match(entry *poolEntry) bool {
+	if filter == nil {
+		return true
+	}
+	return (filter.Signer == common.ADDRESS_EMPTY || filter.Signer == entry.signer) &&
+		(filter.Contract == common.ADDRESS_EMPTY || filter.Contract == entry.contract) &&
+		(filter.Method == "" || filter.Method == entry.method)
+}
+
+// PoolTx is a verified transaction in the pool
+type PoolTx struct {
+	Tx       *types.Transaction
+	Attrs    []*TXAttr      // the result from each validator
+	Signer   common.Address // the account the transaction is accounted to
+	Contract common.Address // the contract invoked, empty if not an invocation
+	Method   string         // the method invoked
+	Priority uint8
+	Arrival  time.Time // when the transaction entered the pool
+}
+
+// GetPoolTxsReq specifies the api that how to get the verified
+// transactions in the pool matching the filter, page by page.
+type GetPoolTxsReq struct {
+	Filter *TxFilter
+	Offset int
+	Limit  int
+}
+
+// GetPoolTxsRsp returns a page of the matching transactions, and the
+// number of them.
+type GetPoolTxsRsp struct {
+	Total int
+	Txs   []*PoolTx
+}
+
+// DropTxnReq specifies the api that how to remove a transaction from the pool.
+type DropTxnReq struct {
+	Hash common.Uint256
+}
+
+// DropTxnRsp returns whether the transaction was in the pool.
+type DropTxnRsp struct {
+	Ok bool
+}
+
 // GetTxnCountReq specifies the api that how to get the tx count
 type GetTxnCountReq struct {
 }
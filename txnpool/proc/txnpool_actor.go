@@ -189,6 +189,39 @@ func (ta *TxActor) Receive(context actor.Context) {
 				context.Self())
 		}
 
+	case *tc.GetPoolTxsReq:
+		sender := context.Sender()
+
+		log.Debugf("txpool-tx actor receives getting pool txs req from %v", sender)
+
+		total, txs := ta.server.getPoolTxs(msg.Filter, msg.Offset, msg.Limit)
+		if sender != nil {
+			sender.Request(&tc.GetPoolTxsRsp{Total: total, Txs: txs},
+				context.Self())
+		}
+
+	case *tc.DropTxnReq:
+		sender := context.Sender()
+
+		log.Infof("txpool-tx actor receives dropping tx %x req from %v", msg.Hash, sender)
+
+		res := ta.server.dropTx(msg.Hash)
+		if sender != nil {
+			sender.Request(&tc.DropTxnRsp{Ok: res},
+				context.Self())
+		}
+
+	case *tc.GetTxnPoolStatsReq:
+		sender := context.Sender()
+
+		log.Debugf("txpool-tx actor receives getting tx pool stats req from %v", sender)
+
+		res := ta.server.getTxPoolStats(msg.TopSigners)
+		if sender != nil {
+			sender.Request(&tc.GetTxnPoolStatsRsp{Stats: res},
+				context.Self())
+		}
+
 	default:
 		log.Debugf("txpool-tx actor: unknown msg %v type %v", msg, reflect.TypeOf(msg))
 	}
@@ -235,6 +268,11 @@ func (tpa *TxPoolActor) Receive(context actor.Context) {
 			sender.Request(&tc.GetPendingTxnRsp{Txs: res}, context.Self())
 		}
 
+	case *tc.TxReq:
+		log.Debugf("txpool actor receives tx from %v", msg.Sender.Sender())
+
+		tpa.server.GetPID(tc.TxActor).Tell(msg)
+
 	case *tc.VerifyBlockReq:
 		sender := context.Sender()
 
@@ -0,0 +1,163 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package proc
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/polynetwork/poly/common"
+	tx "github.com/polynetwork/poly/core/types"
+)
+
+// txJournal keeps the submitted transactions accepted into the pool on
+// disk, so that they can be reloaded at startup. Each record is the raw
+// transaction as var bytes, the records of the transactions which have
+// left the pool are dropped when the journal is rotated.
+type txJournal struct {
+	mu      sync.Mutex
+	path    string   // The journal file
+	maxSize uint64   // The max size of the journal, records over it are dropped
+	file    *os.File // The journal opened for appending
+	size    uint64   // The current size of the journal
+}
+
+func newTxJournal(path string, maxSize uint64) *txJournal {
+	return &txJournal{path: path, maxSize: maxSize}
+}
+
+// load reads the transactions in the journal and opens it for appending,
+// a truncated or corrupted tail left by a crash is ignored.
+func (j *txJournal) load() ([]*tx.Transaction, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	txs := make([]*tx.Transaction, 0)
+	data, err := ioutil.ReadFile(j.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	source := common.NewZeroCopySource(data)
+	for source.Len() > 0 {
+		raw, eof := source.NextVarBytes()
+		if eof {
+			break
+		}
+		t, err := tx.TransactionFromRawBytes(raw)
+		if err != nil {
+			break
+		}
+		txs = append(txs, t)
+	}
+	// drop the corrupted tail, or the appended records can't be read
+	if err := j.write(txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// insert appends a transaction to the journal.
+func (j *txJournal) insert(t *tx.Transaction) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return fmt.Errorf("journal %s is not opened", j.path)
+	}
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes(t.Raw)
+	if j.size+sink.Size() > j.maxSize {
+		return fmt.Errorf("journal %s is full", j.path)
+	}
+	n, err := j.file.Write(sink.Bytes())
+	j.size += uint64(n)
+	return err
+}
+
+// needRotate checks whether the journal holds more than twice the size
+// of the transactions in the pool.
+func (j *txJournal) needRotate(poolSize uint64) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file != nil && j.size > 2*poolSize
+}
+
+// rotate rewrites the journal with the transactions in the pool.
+func (j *txJournal) rotate(txs []*tx.Transaction) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.write(txs)
+}
+
+// write replaces the journal with the transactions, as many as fit in the
+// size limit, and reopens it for appending.
+func (j *txJournal) write(txs []*tx.Transaction) error {
+	tmp := j.path + ".new"
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	var size uint64
+	for _, t := range txs {
+		sink := common.NewZeroCopySink(nil)
+		sink.WriteVarBytes(t.Raw)
+		if size+sink.Size() > j.maxSize {
+			break
+		}
+		if _, err := writer.Write(sink.Bytes()); err != nil {
+			file.Close()
+			return err
+		}
+		size += sink.Size()
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if j.file != nil {
+		j.file.Close()
+		j.file = nil
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return err
+	}
+	j.file, err = os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	j.size = size
+	return nil
+}
+
+// close closes the journal.
+func (j *txJournal) close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
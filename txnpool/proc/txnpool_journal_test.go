@@ -0,0 +1,143 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package proc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/errors"
+	tc "github.com/polynetwork/poly/txnpool/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestJournal opens an empty journal in a temp dir
+func newTestJournal(t *testing.T, maxSize uint64) (*txJournal, func()) {
+	dir, err := ioutil.TempDir("", "txjournal")
+	assert.Nil(t, err)
+	journal := newTxJournal(filepath.Join(dir, "txs.journal"), maxSize)
+	txs, err := journal.load()
+	assert.Nil(t, err)
+	assert.Empty(t, txs)
+	return journal, func() {
+		journal.close()
+		os.RemoveAll(dir)
+	}
+}
+
+func recordSize(t *types.Transaction) uint64 {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes(t.Raw)
+	return sink.Size()
+}
+
+func TestJournalCorruptedTail(t *testing.T) {
+	journal, clean := newTestJournal(t, 1<<20)
+	defer clean()
+
+	tx1, tx2, tx3 := newTestTx(1, 0), newTestTx(2, 0), newTestTx(3, 0)
+	assert.Nil(t, journal.insert(tx1))
+	assert.Nil(t, journal.insert(tx2))
+	//a record cut short by a crash
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes(tx3.Raw)
+	_, err := journal.file.Write(sink.Bytes()[:sink.Size()-1])
+	assert.Nil(t, err)
+	assert.Nil(t, journal.close())
+
+	txs, err := journal.load()
+	assert.Nil(t, err)
+	assert.Equal(t, []common.Uint256{tx1.Hash(), tx2.Hash()}, txHashes(txs))
+	assert.Equal(t, recordSize(tx1)+recordSize(tx2), journal.size)
+
+	//the records appended after the tail is dropped are readable
+	assert.Nil(t, journal.insert(tx3))
+	assert.Nil(t, journal.close())
+	txs, err = journal.load()
+	assert.Nil(t, err)
+	assert.Equal(t, []common.Uint256{tx1.Hash(), tx2.Hash(), tx3.Hash()}, txHashes(txs))
+}
+
+func TestJournalRotate(t *testing.T) {
+	journal, clean := newTestJournal(t, 1<<20)
+	defer clean()
+
+	tx1, tx2, tx3 := newTestTx(1, 0), newTestTx(2, 0), newTestTx(3, 0)
+	for _, tx := range []*types.Transaction{tx1, tx2, tx3} {
+		assert.Nil(t, journal.insert(tx))
+	}
+	assert.False(t, journal.needRotate(journal.size))
+	assert.True(t, journal.needRotate(recordSize(tx2)))
+
+	assert.Nil(t, journal.rotate([]*types.Transaction{tx2}))
+	assert.Equal(t, recordSize(tx2), journal.size)
+	assert.Nil(t, journal.insert(tx1))
+	assert.Nil(t, journal.close())
+	assert.False(t, journal.needRotate(0))
+
+	txs, err := journal.load()
+	assert.Nil(t, err)
+	assert.Equal(t, []common.Uint256{tx2.Hash(), tx1.Hash()}, txHashes(txs))
+}
+
+func TestJournalSizeCap(t *testing.T) {
+	tx1, tx2, tx3 := newTestTx(1, 0), newTestTx(2, 0), newTestTx(3, 0)
+	journal, clean := newTestJournal(t, recordSize(tx1)+recordSize(tx2))
+	defer clean()
+
+	assert.Nil(t, journal.insert(tx1))
+	assert.Nil(t, journal.insert(tx2))
+	assert.NotNil(t, journal.insert(tx3))
+	assert.Equal(t, journal.maxSize, journal.size)
+
+	//rotation keeps as many as fit
+	assert.Nil(t, journal.rotate([]*types.Transaction{tx3, tx2, tx1}))
+	assert.Nil(t, journal.close())
+	txs, err := journal.load()
+	assert.Nil(t, err)
+	assert.Equal(t, []common.Uint256{tx3.Hash(), tx2.Hash()}, txHashes(txs))
+}
+
+func TestJournalReloadedTx(t *testing.T) {
+	journal, clean := newTestJournal(t, 1<<20)
+	defer clean()
+	s := NewTxPoolServer(1, true, false)
+	defer s.Stop()
+	s.journal = journal
+
+	reloaded, submitted := newTestTx(1, 0), newTestTx(2, 0)
+	assert.True(t, s.setPendingTx(reloaded, tc.JournalSender, nil))
+	assert.Equal(t, errors.ErrNoError, s.addTxList(&tc.TXEntry{Tx: reloaded}))
+	assert.Equal(t, uint64(0), journal.size)
+
+	assert.True(t, s.setPendingTx(submitted, tc.HttpSender, nil))
+	assert.Equal(t, errors.ErrNoError, s.addTxList(&tc.TXEntry{Tx: submitted}))
+	assert.Equal(t, recordSize(submitted), journal.size)
+}
+
+func txHashes(txs []*types.Transaction) []common.Uint256 {
+	hashes := make([]common.Uint256, 0, len(txs))
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash())
+	}
+	return hashes
+}
@@ -21,9 +21,12 @@
 package proc
 
 import (
+	"fmt"
+
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/ledger"
 	tx "github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/errors"
 	tc "github.com/polynetwork/poly/txnpool/common"
@@ -77,6 +80,7 @@ type TXPoolServer struct {
 	gasPrice              uint64                              // Gas price to enforce for acceptance into the pool
 	disablePreExec        bool                                // Disbale PreExecute a transaction
 	disableBroadcastNetTx bool                                // Disable broadcast tx from network
+	journal               *txJournal                          // The journal of the submitted txs, nil if disabled
 }
 
 // NewTxPoolServer creates a new tx pool server to schedule workers to
@@ -231,8 +235,23 @@ func (s *TXPoolServer) removePendingTx(hash common.Uint256,
 	s.checkPendingBlockOk(hash, err)
 }
 
+// checkNonce checks whether a submitted transaction wins over the one of
+// the same signer and nonce in the pool. The signatures of the transaction
+// are not verified yet, so it only fails the ones losing to a verified
+// transaction, the pending versions of a nonce are left to replace each other
+// in the pool once verified. It must be called with the server locked.
+func (s *TXPoolServer) checkNonce(tx *tx.Transaction) bool {
+	if old := s.txPool.GetNonceConflict(tx); old != nil && !tc.Replaces(tx, old) {
+		log.Debugf("checkNonce: transaction %x loses to %x of the same nonce in the pool",
+			tx.Hash(), old.Hash())
+		return false
+	}
+	return true
+}
+
 // setPendingTx adds a transaction to the pending list, if the
-// transaction is already in the pending list, just return false.
+// transaction is already in the pending list, or a submitted one loses to
+// another of the same signer and nonce, just return false.
 func (s *TXPoolServer) setPendingTx(tx *tx.Transaction,
 	sender tc.SenderType, txResultCh chan *tc.TxResult) bool {
 
@@ -243,6 +262,9 @@ func (s *TXPoolServer) setPendingTx(tx *tx.Transaction,
 			tx.Hash())
 		return false
 	}
+	if sender.Submitted() && !s.checkNonce(tx) {
+		return false
+	}
 
 	pt := &serverPendingTx{
 		tx:     tx,
@@ -371,8 +393,8 @@ func (s *TXPoolServer) unRegisterValidator(checkType types.VerifyType,
 }
 
 // getNextValidatorPIDs returns the next pids to verify the transaction using
-// roundRobin LB.
-func (s *TXPoolServer) getNextValidatorPIDs() []*actor.PID {
+// roundRobin LB, the permission validator is left out unless permission is set.
+func (s *TXPoolServer) getNextValidatorPIDs(permission bool) []*actor.PID {
 	s.validators.Lock()
 	defer s.validators.Unlock()
 
@@ -382,6 +404,9 @@ func (s *TXPoolServer) getNextValidatorPIDs() []*actor.PID {
 
 	ret := make([]*actor.PID, 0, len(s.validators.entries))
 	for k, v := range s.validators.entries {
+		if k == types.Permission && !permission {
+			continue
+		}
 		lastIdx := s.validators.state.state[k]
 		next := (lastIdx + 1) % len(v)
 		s.validators.state.state[k] = next
@@ -407,6 +432,21 @@ func (s *TXPoolServer) getNextValidatorPID(key types.VerifyType) *actor.PID {
 	return entries[next].Sender
 }
 
+// verifyMask returns the mask of the validators a transaction must pass,
+// the stateless and stateful ones, and the permission one if registered and
+// the transaction is submitted to the pool. The permission is a local policy
+// of the pool ingress, not a rule of block validity.
+func (s *TXPoolServer) verifyMask(submitted bool) uint8 {
+	s.validators.RLock()
+	defer s.validators.RUnlock()
+
+	mask := uint8(tc.VERIFY_MASK)
+	if submitted && len(s.validators.entries[types.Permission]) > 0 {
+		mask |= tc.PERMISSION_MASK
+	}
+	return mask
+}
+
 // Stop stops server and workers.
 func (s *TXPoolServer) Stop() {
 	for _, v := range s.actors {
@@ -421,6 +461,54 @@ func (s *TXPoolServer) Stop() {
 	if s.slots != nil {
 		close(s.slots)
 	}
+	if s.journal != nil {
+		if err := s.journal.close(); err != nil {
+			log.Warnf("Stop: close tx journal error: %s", err)
+		}
+	}
+}
+
+// LoadJournal reloads the transactions in the journal, which go through the
+// validators again except those already in the ledger, and journals the
+// submitted transactions accepted from then on. It must be called after the
+// validators are registered.
+func (s *TXPoolServer) LoadJournal(path string, maxSize uint64) error {
+	journal := newTxJournal(path, maxSize)
+	txs, err := journal.load()
+	if err != nil {
+		return fmt.Errorf("load tx journal %s error: %s", path, err)
+	}
+	s.journal = journal
+
+	pid := s.GetPID(tc.TxActor)
+	if pid == nil {
+		return fmt.Errorf("tx actor is not started")
+	}
+	reloaded := 0
+	for _, t := range txs {
+		if ok, err := ledger.DefLedger.IsContainTransaction(t.Hash()); err != nil || ok {
+			continue
+		}
+		pid.Tell(&tc.TxReq{Tx: t, Sender: tc.JournalSender})
+		reloaded++
+	}
+	log.Infof("LoadJournal: %d transactions in journal %s, %d reloaded", len(txs), path, reloaded)
+	return nil
+}
+
+// rotateJournal drops the transactions which have left the pool from the journal.
+func (s *TXPoolServer) rotateJournal() {
+	if s.journal == nil || !s.journal.needRotate(s.txPool.GetTransactionSize()) {
+		return
+	}
+	entries, _ := s.txPool.GetTxPool(false, 0)
+	txs := make([]*tx.Transaction, 0, len(entries))
+	for _, entry := range entries {
+		txs = append(txs, entry.Tx)
+	}
+	if err := s.journal.rotate(txs); err != nil {
+		log.Warnf("rotateJournal: rotate tx journal error: %s", err)
+	}
 }
 
 // getTransaction returns a transaction with the transaction hash.
@@ -464,6 +552,7 @@ func (s *TXPoolServer) getPendingTxs(byCount bool) []*tx.Transaction {
 // cleanTransactionList cleans the txs in the block from the ledger
 func (s *TXPoolServer) cleanTransactionList(txs []*tx.Transaction, height uint32) {
 	s.txPool.CleanTransactionList(txs)
+	s.rotateJournal()
 
 	// Cleanup tx pool
 	if !s.disablePreExec {
@@ -479,15 +568,65 @@ func (s *TXPoolServer) delTransaction(t *tx.Transaction) {
 	s.txPool.DelTxList(t)
 }
 
-// addTxList adds a valid transaction to the tx pool.
-func (s *TXPoolServer) addTxList(txEntry *tc.TXEntry) bool {
-	ret := s.txPool.AddTxList(txEntry)
-	if !ret {
+// addTxList adds a valid transaction to the tx pool, the pool limits only
+// apply to the transactions submitted which are not in the pending block.
+// The transactions reloaded from the journal are already in it.
+func (s *TXPoolServer) addTxList(txEntry *tc.TXEntry) errors.ErrCode {
+	limited, sender := s.isLimited(txEntry.Tx.Hash())
+	ret := s.txPool.AddTx(txEntry, limited)
+	if ret == errors.ErrDuplicateInput {
 		s.increaseStats(tc.DuplicateStats)
 	}
+	if ret == errors.ErrNoError && limited && sender != tc.JournalSender && s.journal != nil {
+		if err := s.journal.insert(txEntry.Tx); err != nil {
+			log.Debugf("addTxList: journal transaction %x error: %s", txEntry.Tx.Hash(), err)
+		}
+	}
 	return ret
 }
 
+// isSubmitted checks whether the pending transaction is submitted to the pool
+func (s *TXPoolServer) isSubmitted(hash common.Uint256) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pt, ok := s.allPendingTxs[hash]
+	return ok && pt.sender.Submitted()
+}
+
+// isLimited checks whether the pending transaction is subject to the pool
+// limits, and returns its sender.
+func (s *TXPoolServer) isLimited(hash common.Uint256) (bool, tc.SenderType) {
+	s.mu.RLock()
+	pt, ok := s.allPendingTxs[hash]
+	s.mu.RUnlock()
+	if !ok {
+		return false, tc.NilSender
+	}
+	if !pt.sender.Submitted() {
+		return false, pt.sender
+	}
+
+	s.pendingBlock.mu.RLock()
+	defer s.pendingBlock.mu.RUnlock()
+	_, ok = s.pendingBlock.unProcessedTxs[hash]
+	return !ok, pt.sender
+}
+
+// getPoolTxs returns a page of the verified transactions matching the filter
+func (s *TXPoolServer) getPoolTxs(filter *tc.TxFilter, offset, limit int) (int, []*tc.PoolTx) {
+	return s.txPool.GetPoolTxs(filter, offset, limit)
+}
+
+// dropTx removes a verified transaction from the tx pool
+func (s *TXPoolServer) dropTx(hash common.Uint256) bool {
+	return s.txPool.DropTx(hash)
+}
+
+// getTxPoolStats returns the statistics of the verified transactions
+func (s *TXPoolServer) getTxPoolStats(topSigners int) *tc.PoolStats {
+	return s.txPool.GetStats(topSigners)
+}
+
 // increaseStats increases the count with the stats type
 func (s *TXPoolServer) increaseStats(v tc.TxnStatsType) {
 	s.stats.Lock()
@@ -525,6 +664,18 @@ func (s *TXPoolServer) checkTx(hash common.Uint256) bool {
 	return false
 }
 
+// HasTransaction checks whether the tx is pending or in the pool without
+// going through the actor, it is safe for concurrent use.
+func (s *TXPoolServer) HasTransaction(hash common.Uint256) bool {
+	return s.checkTx(hash)
+}
+
+// GetTransaction returns the verified tx of the hash in the pool, nil if not
+// present, without going through the actor. It is safe for concurrent use.
+func (s *TXPoolServer) GetTransaction(hash common.Uint256) *tx.Transaction {
+	return s.getTransaction(hash)
+}
+
 // getTxStatusReq returns a transaction's status with the transaction hash.
 func (s *TXPoolServer) getTxStatusReq(hash common.Uint256) *tc.TxStatus {
 	for i := 0; i < len(s.workers); i++ {
@@ -22,9 +22,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
 	"github.com/polynetwork/poly/core/payload"
+	"github.com/polynetwork/poly/core/signature"
 	"github.com/polynetwork/poly/core/types"
 	"github.com/polynetwork/poly/errors"
 	tc "github.com/polynetwork/poly/txnpool/common"
@@ -43,21 +47,43 @@ func init() {
 	log.Init(log.PATH, log.Stdout)
 	topic = "TXN"
 
-	code := []byte("ont")
+	txn = newTestTx(uint32(time.Now().Unix()), 0)
 
-	invokeCodePayload := &payload.InvokeCode{
-		Code: code,
-	}
+	sender = tc.NilSender
+}
 
-	mutable := &types.MutableTransaction{
-		TxType:  types.Invoke,
-		Nonce:   uint32(time.Now().Unix()),
-		Payload: invokeCodePayload,
+// newTestTx builds a transaction of the nonce and gas price, signed by each
+// of the signers
+func newTestTx(nonce uint32, gasPrice uint64, signers ...*account.Account) *types.Transaction {
+	tx := &types.Transaction{
+		TxType:   types.Invoke,
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Payload:  &payload.InvokeCode{Code: []byte("ont")},
 	}
-
-	txn, _ = mutable.IntoImmutable()
-
-	sender = tc.NilSender
+	for i := 0; i < 2; i++ {
+		sink := common.NewZeroCopySink(nil)
+		if err := tx.Serialization(sink); err != nil {
+			panic(err)
+		}
+		tx, _ = types.TransactionFromRawBytes(sink.Bytes())
+		if i > 0 {
+			break
+		}
+		hash := tx.Hash()
+		for _, signer := range signers {
+			sig, err := signature.Sign(signer, hash[:])
+			if err != nil {
+				panic(err)
+			}
+			tx.Sigs = append(tx.Sigs, types.Sig{
+				PubKeys: []keypair.PublicKey{signer.PublicKey},
+				M:       1,
+				SigData: [][]byte{sig},
+			})
+		}
+	}
+	return tx
 }
 
 func startActor(obj interface{}) *actor.PID {
@@ -265,12 +291,12 @@ func TestValidator(t *testing.T) {
 
 	time.Sleep(1 * time.Second)
 
-	ret := s.getNextValidatorPIDs()
+	ret := s.getNextValidatorPIDs(true)
 	for _, v := range ret {
 		assert.NotNil(t, v)
 	}
 
-	ret = s.getNextValidatorPIDs()
+	ret = s.getNextValidatorPIDs(true)
 	for _, v := range ret {
 		assert.NotNil(t, v)
 	}
@@ -280,10 +306,57 @@ func TestValidator(t *testing.T) {
 
 	time.Sleep(1 * time.Second)
 
-	ret = s.getNextValidatorPIDs()
+	ret = s.getNextValidatorPIDs(true)
 	for _, v := range ret {
 		assert.NotNil(t, v)
 	}
 
 	t.Log("Ending validator testing")
 }
+
+func TestVerifyMask(t *testing.T) {
+	s := NewTxPoolServer(tc.MAX_WORKER_NUM, true, false)
+	defer s.Stop()
+
+	assert.Equal(t, uint8(tc.VERIFY_MASK), s.verifyMask(true))
+	s.registerValidator(&vt.RegisterValidator{Type: vt.Stateless, Id: "stateless"})
+	assert.Equal(t, uint8(tc.VERIFY_MASK), s.verifyMask(true))
+
+	s.registerValidator(&vt.RegisterValidator{Type: vt.Permission, Id: "permission"})
+	assert.Equal(t, uint8(tc.VERIFY_MASK|tc.PERMISSION_MASK), s.verifyMask(true))
+	//transactions of blocks are not checked by the permission validator
+	assert.Equal(t, uint8(tc.VERIFY_MASK), s.verifyMask(false))
+	assert.Equal(t, 2, len(s.getNextValidatorPIDs(true)))
+	assert.Equal(t, 1, len(s.getNextValidatorPIDs(false)))
+
+	s.unRegisterValidator(vt.Permission, "permission")
+	assert.Equal(t, uint8(tc.VERIFY_MASK), s.verifyMask(true))
+}
+
+func TestNonceReplacement(t *testing.T) {
+	s := NewTxPoolServer(tc.MAX_WORKER_NUM, true, false)
+	defer s.Stop()
+	acc := account.NewAccount("")
+	nonce := uint32(time.Now().Unix())
+	v1 := newTestTx(nonce, 1, acc)
+	assert.Equal(t, errors.ErrNoError, s.addTxList(&tc.TXEntry{Tx: v1, Attrs: []*tc.TXAttr{}}))
+
+	// the versions losing to the verified one in the pool are rejected
+	assert.False(t, s.setPendingTx(newTestTx(nonce, 0, acc), tc.HttpSender, nil))
+
+	// a version with a forged signature of the signer doesn't fail the pending ones
+	forged := newTestTx(nonce, 3, account.NewAccount(""))
+	forged.Sigs = v1.Sigs
+	assert.Equal(t, acc.Address, tc.TxSigner(forged))
+	assert.True(t, s.setPendingTx(forged, tc.HttpSender, nil))
+	v2 := newTestTx(nonce, 2, acc)
+	assert.True(t, s.setPendingTx(v2, tc.HttpSender, nil))
+	s.removePendingTx(forged.Hash(), errors.ErrVerifySignature)
+
+	// the verified version replaces the one in the pool
+	assert.Equal(t, errors.ErrNoError, s.addTxList(&tc.TXEntry{Tx: v2, Attrs: []*tc.TXAttr{}}))
+	s.removePendingTx(v2.Hash(), errors.ErrNoError)
+	assert.Nil(t, s.getTransaction(v1.Hash()))
+	assert.NotNil(t, s.getTransaction(v2.Hash()))
+	assert.Equal(t, uint64(1), s.getTxPoolStats(0).Replaced)
+}
@@ -34,12 +34,13 @@ import (
 // the cache of check request, the flag indicating the verified status,
 // the verified result and retry mechanism
 type pendingTx struct {
-	tx      *tx.Transaction // That is unverified or on the verifying process
-	valTime time.Time       // The start time
-	req     *types.CheckTx  // Req cache
-	flag    uint8           // For different types of verification
-	retries uint8           // For resend to validator when time out before verified
-	ret     []*tc.TXAttr    // verified results
+	tx        *tx.Transaction // That is unverified or on the verifying process
+	valTime   time.Time       // The start time
+	req       *types.CheckTx  // Req cache
+	flag      uint8           // For different types of verification
+	retries   uint8           // For resend to validator when time out before verified
+	ret       []*tc.TXAttr    // verified results
+	submitted bool            // Submitted to the pool, checked by the permission validator
 }
 
 // txPoolWorker handles the tasks scheduled by server
@@ -124,7 +125,7 @@ func (worker *txPoolWorker) handleRsp(rsp *types.CheckResponse) {
 		pt.ret = append(pt.ret, retAttr)
 	}
 
-	if pt.flag&0xf == tc.VERIFY_MASK {
+	if mask := worker.server.verifyMask(pt.submitted); pt.flag&mask == mask {
 		worker.putTxPool(pt)
 		delete(worker.pendingTxList, rsp.Hash)
 	}
@@ -144,7 +145,7 @@ func (worker *txPoolWorker) handleTimeoutEvent() {
 	 * resend them to the validators
 	 */
 	for k, v := range worker.pendingTxList {
-		if v.flag&0xf != tc.VERIFY_MASK && (time.Now().Sub(v.valTime)/time.Second) >=
+		if mask := worker.server.verifyMask(v.submitted); v.flag&mask != mask && (time.Now().Sub(v.valTime)/time.Second) >=
 			tc.EXPIRE_INTERVAL {
 			if v.retries < tc.MAX_RETRIES {
 				worker.reVerifyTx(k)
@@ -167,7 +168,10 @@ func (worker *txPoolWorker) putTxPool(pt *pendingTx) bool {
 		Tx:    pt.tx,
 		Attrs: pt.ret,
 	}
-	worker.server.addTxList(txEntry)
+	if err := worker.server.addTxList(txEntry); err == errors.ErrTxPoolFull {
+		worker.server.removePendingTx(pt.tx.Hash(), err)
+		return false
+	}
 	worker.server.removePendingTx(pt.tx.Hash(), errors.ErrNoError)
 	return true
 }
@@ -192,14 +196,16 @@ func (worker *txPoolWorker) verifyTx(tx *tx.Transaction) {
 		Tx:       tx,
 	}
 
-	worker.sendReq2Validator(req)
+	submitted := worker.server.isSubmitted(tx.Hash())
+	worker.sendReq2Validator(req, submitted)
 
 	// Construct the pending transaction
 	pt := &pendingTx{
-		tx:      tx,
-		req:     req,
-		flag:    0,
-		retries: 0,
+		tx:        tx,
+		req:       req,
+		flag:      0,
+		retries:   0,
+		submitted: submitted,
 	}
 	// Add it to the pending transaction list
 	worker.mu.Lock()
@@ -216,23 +222,24 @@ func (worker *txPoolWorker) reVerifyTx(txHash common.Uint256) {
 		return
 	}
 
-	if pt.flag&0xf != tc.VERIFY_MASK {
-		worker.sendReq2Validator(pt.req)
+	if mask := worker.server.verifyMask(pt.submitted); pt.flag&mask != mask {
+		worker.sendReq2Validator(pt.req, pt.submitted)
 	}
 
 	// Update the verifying time
 	pt.valTime = time.Now()
 }
 
-// sendReq2Validator sends a check request to the validators
-func (worker *txPoolWorker) sendReq2Validator(req *types.CheckTx) bool {
+// sendReq2Validator sends a check request to the validators, and to the
+// permission validator if the transaction is submitted to the pool
+func (worker *txPoolWorker) sendReq2Validator(req *types.CheckTx, submitted bool) bool {
 	rspPid := worker.server.GetPID(tc.VerifyRspActor)
 	if rspPid == nil {
 		log.Info("sendReq2Validator: VerifyRspActor not exist")
 		return false
 	}
 
-	pids := worker.server.getNextValidatorPIDs()
+	pids := worker.server.getNextValidatorPIDs(submitted)
 	if pids == nil {
 		return false
 	}
@@ -261,6 +268,23 @@ func (worker *txPoolWorker) sendReq2StatefulV(req *types.CheckTx) {
 
 }
 
+// sendReq2PermissionV sends a check request to the permission validator if
+// there is one registered
+func (worker *txPoolWorker) sendReq2PermissionV(req *types.CheckTx) {
+	rspPid := worker.server.GetPID(tc.VerifyRspActor)
+	if rspPid == nil {
+		log.Info("sendReq2PermissionV: VerifyRspActor not exist")
+		return
+	}
+
+	pid := worker.server.getNextValidatorPID(types.Permission)
+	if pid == nil {
+		return
+	}
+
+	pid.Request(req, rspPid)
+}
+
 // verifyStateful prepares a check request and sends it to the
 // stateful validator
 func (worker *txPoolWorker) verifyStateful(tx *tx.Transaction) {
@@ -269,12 +293,14 @@ func (worker *txPoolWorker) verifyStateful(tx *tx.Transaction) {
 		Tx:       tx,
 	}
 
-	// Construct the pending transaction
+	// Construct the pending transaction, the transactions in the pool are
+	// checked by the permission validator again like submitted ones
 	pt := &pendingTx{
-		tx:      tx,
-		req:     req,
-		retries: 0,
-		valTime: time.Now(),
+		tx:        tx,
+		req:       req,
+		retries:   0,
+		valTime:   time.Now(),
+		submitted: true,
 	}
 
 	retAttr := &tc.TXAttr{
@@ -293,6 +319,8 @@ func (worker *txPoolWorker) verifyStateful(tx *tx.Transaction) {
 	worker.mu.Unlock()
 
 	worker.sendReq2StatefulV(req)
+	// The permission may have been revoked since the transaction entered the pool
+	worker.sendReq2PermissionV(req)
 }
 
 // Start is the main event loop.
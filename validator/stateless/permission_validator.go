@@ -0,0 +1,304 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stateless
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/polynetwork/poly/common"
+	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/ledger"
+	"github.com/polynetwork/poly/core/payload"
+	scommon "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/errors"
+	"github.com/polynetwork/poly/events"
+	"github.com/polynetwork/poly/events/message"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	vatypes "github.com/polynetwork/poly/validator/types"
+)
+
+// MAX_PERMISSION_CACHE is the number of signers whose permission is cached
+const MAX_PERMISSION_CACHE = 10000
+
+// permissionStore is the part of the ledger the permissions are read from
+type permissionStore interface {
+	GetCurrentBlockHeight() uint32
+	GetStorageItem(codeHash common.Address, key []byte) ([]byte, error)
+}
+
+// permissionValidator checks that a transaction is signed by a registered
+// relayer or a consensus peer. The permissions are cached for the height
+// they are read at, and kept for the next blocks saved unless one of them
+// invokes the relayer manager or the node manager.
+type permissionValidator struct {
+	pid      *actor.PID
+	id       string
+	sub      *events.ActorSubscriber
+	store    permissionStore
+	height   uint32                  // The height the cache is valid at
+	relayers map[common.Address]bool // Whether the signer is a relayer
+	peers    map[common.Address]bool // The consensus peers, nil if not loaded
+}
+
+// NewPermissionValidator spawns a permission validator actor and return its
+// pid wraped in Validator
+func NewPermissionValidator(id string) (Validator, error) {
+	validator, err := newPermissionValidator(id, ledger.DefLedger)
+	if err != nil {
+		return nil, err
+	}
+	return validator, nil
+}
+
+func newPermissionValidator(id string, store permissionStore) (*permissionValidator, error) {
+	validator := &permissionValidator{id: id, store: store}
+	validator.reset(store.GetCurrentBlockHeight())
+	props := actor.FromProducer(func() actor.Actor {
+		return validator
+	})
+
+	pid, err := actor.SpawnNamed(props, id)
+	if err != nil {
+		return nil, err
+	}
+	validator.pid = pid
+	validator.sub = events.NewActorSubscriber(pid)
+	validator.sub.Subscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+	return validator, nil
+}
+
+func (self *permissionValidator) Receive(context actor.Context) {
+	switch msg := context.Message().(type) {
+	case *actor.Started:
+		log.Info("permission-validator: started and be ready to receive txn")
+	case *actor.Stopping:
+		log.Info("permission-validator: stopping")
+		self.sub.Unsubscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+	case *actor.Restarting:
+		log.Info("permission-validator: restarting")
+	case *actor.Stopped:
+		log.Info("permission-validator: stopped")
+	case *message.SaveBlockCompleteMsg:
+		self.blockSaved(msg.Block)
+	case *vatypes.CheckTx:
+		log.Debugf("permission-validator receive tx %x", msg.Tx.Hash())
+		sender := context.Sender()
+		errCode := self.verify(msg.Tx)
+
+		response := &vatypes.CheckResponse{
+			WorkerId: msg.WorkerId,
+			ErrCode:  errCode,
+			Hash:     msg.Tx.Hash(),
+			Type:     self.VerifyType(),
+			Height:   self.store.GetCurrentBlockHeight(),
+		}
+
+		sender.Tell(response)
+	case *vatypes.UnRegisterAck:
+		context.Self().Stop()
+	default:
+		log.Info("permission-validator: unknown msg ", msg, "type", reflect.TypeOf(msg))
+	}
+}
+
+// reset drops the cached permissions, the cache is valid at the height then.
+func (self *permissionValidator) reset(height uint32) {
+	self.height = height
+	self.relayers = make(map[common.Address]bool)
+	self.peers = nil
+}
+
+// blockSaved keeps the cache valid at the height of the saved block if the
+// block follows the height and doesn't change the permissions.
+func (self *permissionValidator) blockSaved(block *types.Block) {
+	height := block.Header.Height
+	if height <= self.height {
+		return
+	}
+	if height == self.height+1 && !changesPermission(block) {
+		self.height = height
+		return
+	}
+	log.Debugf("permission-validator: block %d may change permissions", height)
+	self.reset(height)
+}
+
+// verify checks whether any signer of the transaction is a relayer or a
+// consensus peer. The signatures are verified by the stateless validator,
+// the transaction must pass both to enter the pool.
+func (self *permissionValidator) verify(tx *types.Transaction) errors.ErrCode {
+	// the events of the blocks saved may not be received yet
+	if height := self.store.GetCurrentBlockHeight(); height != self.height {
+		self.reset(height)
+	}
+	addresses, err := signerAddresses(tx)
+	if err != nil {
+		log.Debugf("permission-validator: tx %x signature addresses error: %s", tx.Hash(), err)
+		return errors.ErrVerifySignature
+	}
+	for _, address := range addresses {
+		permitted, err := self.isRelayer(address)
+		if err == nil && !permitted {
+			permitted, err = self.isPeer(address)
+		}
+		if err != nil {
+			log.Errorf("permission-validator: check permission of %s error: %s", address.ToBase58(), err)
+			return errors.ErrUnknown
+		}
+		if permitted {
+			return errors.ErrNoError
+		}
+	}
+	return errors.ErrNotPermitted
+}
+
+func (self *permissionValidator) isRelayer(address common.Address) (bool, error) {
+	if permitted, ok := self.relayers[address]; ok {
+		return permitted, nil
+	}
+	key := append([]byte(relayer_manager.RELAYER), address[:]...)
+	value, err := self.store.GetStorageItem(utils.RelayerManagerContractAddress, key)
+	if err != nil && err != scommon.ErrNotFound {
+		return false, err
+	}
+	if len(self.relayers) >= MAX_PERMISSION_CACHE {
+		self.relayers = make(map[common.Address]bool)
+	}
+	self.relayers[address] = value != nil
+	return value != nil, nil
+}
+
+func (self *permissionValidator) isPeer(address common.Address) (bool, error) {
+	if self.peers == nil {
+		peers, err := getConsensusPeers(self.store)
+		if err != nil {
+			return false, err
+		}
+		self.peers = peers
+	}
+	return self.peers[address], nil
+}
+
+// getConsensusPeers returns the addresses of the peers of the current governance view
+func getConsensusPeers(store permissionStore) (map[common.Address]bool, error) {
+	governanceViewBytes, err := store.GetStorageItem(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW))
+	if err != nil {
+		return nil, fmt.Errorf("get governance view error: %v", err)
+	}
+	governanceView := new(node_manager.GovernanceView)
+	if err := governanceView.Deserialization(common.NewZeroCopySource(governanceViewBytes)); err != nil {
+		return nil, fmt.Errorf("deserialize governance view error: %v", err)
+	}
+	key := append([]byte(node_manager.PEER_POOL), utils.GetUint32Bytes(governanceView.View)...)
+	peerPoolMapBytes, err := store.GetStorageItem(utils.NodeManagerContractAddress, key)
+	if err != nil {
+		return nil, fmt.Errorf("get peer pool error: %v", err)
+	}
+	peerMap := &node_manager.PeerPoolMap{
+		PeerPoolMap: make(map[string]*node_manager.PeerPoolItem),
+	}
+	if err := peerMap.Deserialization(common.NewZeroCopySource(peerPoolMapBytes)); err != nil {
+		return nil, fmt.Errorf("deserialize peer pool error: %v", err)
+	}
+	peers := make(map[common.Address]bool, len(peerMap.PeerPoolMap))
+	for k := range peerMap.PeerPoolMap {
+		kb, err := hex.DecodeString(k)
+		if err != nil {
+			return nil, fmt.Errorf("decode peer public key error: %v", err)
+		}
+		pk, err := keypair.DeserializePublicKey(kb)
+		if err != nil {
+			return nil, fmt.Errorf("deserialize peer public key error: %v", err)
+		}
+		peers[types.AddressFromPubKey(pk)] = true
+	}
+	return peers, nil
+}
+
+// signerAddresses returns the addresses of the signatures of the transaction,
+// unlike tx.GetSignatureAddresses it doesn't fill tx.SignedAddr, which is
+// only assigned once the signatures are verified.
+func signerAddresses(tx *types.Transaction) ([]common.Address, error) {
+	addrs := make([]common.Address, 0, len(tx.Sigs))
+	for _, sig := range tx.Sigs {
+		switch len(sig.PubKeys) {
+		case 0:
+			return nil, fmt.Errorf("no public key")
+		case 1:
+			addrs = append(addrs, types.AddressFromPubKey(sig.PubKeys[0]))
+		default:
+			sink := common.NewZeroCopySink(nil)
+			if err := types.EncodeMultiPubKeyProgramInto(sink, sig.PubKeys, sig.M); err != nil {
+				return nil, err
+			}
+			addrs = append(addrs, common.AddressFromVmCode(sink.Bytes()))
+		}
+	}
+	return addrs, nil
+}
+
+// changesPermission checks whether a transaction of the block invokes the
+// relayer manager or the node manager, which may change the permissions.
+func changesPermission(block *types.Block) bool {
+	for _, tx := range block.Transactions {
+		if tx.TxType != types.Invoke {
+			continue
+		}
+		invokeCode, ok := tx.Payload.(*payload.InvokeCode)
+		if !ok {
+			continue
+		}
+		param := &states.ContractInvokeParam{}
+		if err := param.Deserialization(common.NewZeroCopySource(invokeCode.Code)); err != nil {
+			continue
+		}
+		if param.Address == utils.RelayerManagerContractAddress || param.Address == utils.NodeManagerContractAddress {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *permissionValidator) VerifyType() vatypes.VerifyType {
+	return vatypes.Permission
+}
+
+// Register send RegisterValidator message to txpool
+func (self *permissionValidator) Register(poolId *actor.PID) {
+	poolId.Tell(&vatypes.RegisterValidator{
+		Sender: self.pid,
+		Type:   self.VerifyType(),
+		Id:     self.id,
+	})
+}
+
+// UnRegister send UnRegisterValidator message to txpool
+func (self *permissionValidator) UnRegister(poolId *actor.PID) {
+	poolId.Tell(&vatypes.UnRegisterValidator{
+		Id:   self.id,
+		Type: self.VerifyType(),
+	})
+}
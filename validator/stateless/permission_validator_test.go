@@ -0,0 +1,208 @@
+/*
+ * Copyright (C) 2021 The poly network Authors
+ * This file is part of The poly network library.
+ *
+ * The  poly network  is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The  poly network  is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The poly network .  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package stateless
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
+	scommon "github.com/polynetwork/poly/core/store/common"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/errors"
+	"github.com/polynetwork/poly/events"
+	"github.com/polynetwork/poly/events/message"
+	"github.com/polynetwork/poly/native/service/governance/node_manager"
+	"github.com/polynetwork/poly/native/service/governance/relayer_manager"
+	"github.com/polynetwork/poly/native/service/utils"
+	"github.com/polynetwork/poly/native/states"
+	vatypes "github.com/polynetwork/poly/validator/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testStore is a permission store of the relayers and the peers of view 1
+type testStore struct {
+	height uint32
+	items  map[string][]byte
+	reads  int
+}
+
+func newTestStore(relayers []*account.Account, peers []*account.Account) *testStore {
+	store := &testStore{items: make(map[string][]byte)}
+	for _, relayer := range relayers {
+		store.setRelayer(relayer.Address, true)
+	}
+	sink := common.NewZeroCopySink(nil)
+	(&node_manager.GovernanceView{View: 1}).Serialization(sink)
+	store.set(utils.NodeManagerContractAddress, []byte(node_manager.GOVERNANCE_VIEW), sink.Bytes())
+	peerMap := &node_manager.PeerPoolMap{PeerPoolMap: make(map[string]*node_manager.PeerPoolItem)}
+	for i, peer := range peers {
+		pubKey := hex.EncodeToString(keypair.SerializePublicKey(peer.PublicKey))
+		peerMap.PeerPoolMap[pubKey] = &node_manager.PeerPoolItem{
+			Index:      uint32(i),
+			PeerPubkey: pubKey,
+			Address:    peer.Address,
+			Status:     node_manager.ConsensusStatus,
+		}
+	}
+	sink = common.NewZeroCopySink(nil)
+	peerMap.Serialization(sink)
+	key := append([]byte(node_manager.PEER_POOL), utils.GetUint32Bytes(1)...)
+	store.set(utils.NodeManagerContractAddress, key, sink.Bytes())
+	return store
+}
+
+func (self *testStore) set(contract common.Address, key, value []byte) {
+	self.items[string(append(contract[:], key...))] = value
+}
+
+func (self *testStore) setRelayer(address common.Address, registered bool) {
+	key := append([]byte(relayer_manager.RELAYER), address[:]...)
+	if registered {
+		self.set(utils.RelayerManagerContractAddress, key, []byte{1})
+	} else {
+		delete(self.items, string(append(utils.RelayerManagerContractAddress[:], key...)))
+	}
+}
+
+func (self *testStore) GetCurrentBlockHeight() uint32 {
+	return self.height
+}
+
+func (self *testStore) GetStorageItem(contract common.Address, key []byte) ([]byte, error) {
+	self.reads++
+	value, ok := self.items[string(append(contract[:], key...))]
+	if !ok {
+		return nil, scommon.ErrNotFound
+	}
+	return value, nil
+}
+
+// newInvokeBlock builds a block at the height invoking each of the contracts
+func newInvokeBlock(t *testing.T, height uint32, contracts ...common.Address) *types.Block {
+	block := &types.Block{Header: &types.Header{Height: height}}
+	for _, contract := range contracts {
+		sink := common.NewZeroCopySink(nil)
+		(&states.ContractInvokeParam{Address: contract, Method: "test"}).Serialization(sink)
+		block.Transactions = append(block.Transactions, newSignedTx(t, sink.Bytes()))
+	}
+	return block
+}
+
+func TestChangesPermission(t *testing.T) {
+	assert.False(t, changesPermission(newInvokeBlock(t, 1)))
+	assert.False(t, changesPermission(newInvokeBlock(t, 1, utils.HeaderSyncContractAddress)))
+	assert.True(t, changesPermission(newInvokeBlock(t, 1, utils.RelayerManagerContractAddress)))
+	assert.True(t, changesPermission(newInvokeBlock(t, 1,
+		utils.HeaderSyncContractAddress, utils.NodeManagerContractAddress)))
+
+	//an invoke code which is not a contract invoke
+	block := &types.Block{Header: &types.Header{Height: 1},
+		Transactions: []*types.Transaction{newSignedTx(t, []byte("ont"))}}
+	assert.False(t, changesPermission(block))
+}
+
+func TestPermissionVerify(t *testing.T) {
+	relayer, peer, other := account.NewAccount(""), account.NewAccount(""), account.NewAccount("")
+	validator := &permissionValidator{store: newTestStore([]*account.Account{relayer}, []*account.Account{peer})}
+	validator.reset(0)
+
+	assert.Equal(t, errors.ErrNoError, validator.verify(newSignedTx(t, nil, relayer)))
+	assert.Equal(t, errors.ErrNoError, validator.verify(newSignedTx(t, nil, peer)))
+	assert.Equal(t, errors.ErrNoError, validator.verify(newSignedTx(t, nil, other, peer)))
+	assert.Equal(t, errors.ErrNotPermitted, validator.verify(newSignedTx(t, nil, other)))
+	assert.Equal(t, errors.ErrNotPermitted, validator.verify(newSignedTx(t, nil)))
+
+	//the signers are not taken as verified
+	tx := newSignedTx(t, nil, relayer)
+	validator.verify(tx)
+	assert.Empty(t, tx.SignedAddr)
+
+	tx.Sigs = append(tx.Sigs, types.Sig{M: 1})
+	assert.Equal(t, errors.ErrVerifySignature, validator.verify(tx))
+}
+
+func TestPermissionCache(t *testing.T) {
+	relayer := account.NewAccount("")
+	store := newTestStore([]*account.Account{relayer}, nil)
+	validator := &permissionValidator{store: store}
+	validator.reset(0)
+
+	tx := newSignedTx(t, nil, relayer)
+	assert.Equal(t, errors.ErrNoError, validator.verify(tx))
+	reads := store.reads
+	store.setRelayer(relayer.Address, false)
+	assert.Equal(t, errors.ErrNoError, validator.verify(tx))
+	assert.Equal(t, reads, store.reads)
+
+	//a block not changing the permissions keeps the cache
+	store.height = 1
+	validator.blockSaved(newInvokeBlock(t, 1, utils.HeaderSyncContractAddress))
+	assert.Equal(t, errors.ErrNoError, validator.verify(tx))
+	assert.Equal(t, reads, store.reads)
+
+	//a block changing the permissions drops it
+	store.height = 2
+	validator.blockSaved(newInvokeBlock(t, 2, utils.RelayerManagerContractAddress))
+	assert.Equal(t, errors.ErrNotPermitted, validator.verify(tx))
+
+	//so does a block saved before its event is received
+	store.setRelayer(relayer.Address, true)
+	store.height = 3
+	assert.Equal(t, errors.ErrNoError, validator.verify(tx))
+	//and a block skipped by the events
+	store.setRelayer(relayer.Address, false)
+	validator.blockSaved(newInvokeBlock(t, 5, utils.HeaderSyncContractAddress))
+	assert.Equal(t, uint32(5), validator.height)
+	assert.Empty(t, validator.relayers)
+	//the events of the blocks the cache is valid at are ignored
+	validator.relayers[relayer.Address] = true
+	validator.blockSaved(newInvokeBlock(t, 4, utils.RelayerManagerContractAddress))
+	assert.True(t, validator.relayers[relayer.Address])
+}
+
+func TestPermissionValidator(t *testing.T) {
+	events.Init()
+	relayer, other := account.NewAccount(""), account.NewAccount("")
+	store := newTestStore([]*account.Account{relayer}, nil)
+	validator, err := newPermissionValidator("permission_test", store)
+	assert.Nil(t, err)
+	defer validator.pid.Stop()
+	assert.Equal(t, vatypes.Permission, validator.VerifyType())
+
+	check := func(tx *types.Transaction) *vatypes.CheckResponse {
+		fut := validator.pid.RequestFuture(&vatypes.CheckTx{WorkerId: 1, Tx: tx}, time.Second)
+		res, err := fut.Result()
+		assert.Nil(t, err)
+		return res.(*vatypes.CheckResponse)
+	}
+	tx := newSignedTx(t, nil, relayer)
+	rsp := check(tx)
+	assert.Equal(t, errors.ErrNoError, rsp.ErrCode)
+	assert.Equal(t, tx.Hash(), rsp.Hash)
+	assert.Equal(t, vatypes.Permission, rsp.Type)
+	assert.Equal(t, errors.ErrNotPermitted, check(newSignedTx(t, nil, other)).ErrCode)
+
+	store.setRelayer(relayer.Address, false)
+	store.height = 1
+	validator.pid.Tell(&message.SaveBlockCompleteMsg{Block: newInvokeBlock(t, 1, utils.RelayerManagerContractAddress)})
+	assert.Equal(t, errors.ErrNotPermitted, check(tx).ErrCode)
+}
@@ -18,49 +18,55 @@
 package stateless
 
 import (
-	"fmt"
-	"github.com/ontio/ontology-eventbus/actor"
-	"github.com/polynetwork/poly/core/payload"
-	"github.com/polynetwork/poly/core/types"
-	"github.com/polynetwork/poly/errors"
-	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
 
 	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/polynetwork/poly/account"
+	"github.com/polynetwork/poly/common"
 	"github.com/polynetwork/poly/common/log"
+	"github.com/polynetwork/poly/core/payload"
 	"github.com/polynetwork/poly/core/signature"
-	ctypes "github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/core/types"
+	"github.com/polynetwork/poly/errors"
 	types2 "github.com/polynetwork/poly/validator/types"
+	"github.com/stretchr/testify/assert"
 )
 
-func signTransaction(signer *account.Account, tx *ctypes.MutableTransaction) error {
+// newSignedTx builds an invoke transaction of the code signed by each signer
+func newSignedTx(t *testing.T, code []byte, signers ...*account.Account) *types.Transaction {
+	tx := &types.Transaction{
+		TxType:  types.Invoke,
+		Payload: &payload.InvokeCode{Code: code},
+	}
+	tx = reparse(t, tx)
 	hash := tx.Hash()
-	sign, _ := signature.Sign(signer, hash[:])
-	tx.Sigs = append(tx.Sigs, ctypes.Sig{
-		PubKeys: []keypair.PublicKey{signer.PublicKey},
-		M:       1,
-		SigData: [][]byte{sign},
-	})
-	return nil
+	for _, signer := range signers {
+		sign, err := signature.Sign(signer, hash[:])
+		assert.Nil(t, err)
+		tx.Sigs = append(tx.Sigs, types.Sig{
+			PubKeys: []keypair.PublicKey{signer.PublicKey},
+			M:       1,
+			SigData: [][]byte{sign},
+		})
+	}
+	return reparse(t, tx)
+}
+
+// reparse serializes the transaction and reads it back, filling tx.Raw
+func reparse(t *testing.T, tx *types.Transaction) *types.Transaction {
+	sink := common.NewZeroCopySink(nil)
+	assert.Nil(t, tx.Serialization(sink))
+	tx, err := types.TransactionFromRawBytes(sink.Bytes())
+	assert.Nil(t, err)
+	return tx
 }
 
 func TestStatelessValidator(t *testing.T) {
 	log.Init(log.PATH, log.Stdout)
 	acc := account.NewAccount("")
-	invoke := &payload.InvokeCode{}
-	mutable := &types.MutableTransaction{
-		TxType:  types.Invoke,
-		Payload: invoke,
-	}
-	fmt.Print(mutable.Hash())
-
-	err := signTransaction(acc, mutable)
-	assert.Nil(t, err)
-
-	tx, err := mutable.IntoImmutable()
-	assert.Nil(t, err)
+	tx := newSignedTx(t, nil, acc)
 
 	validator := &validator{id: "test"}
 	props := actor.FromProducer(func() actor.Actor {
@@ -78,5 +84,5 @@ func TestStatelessValidator(t *testing.T) {
 
 	result := res.(*types2.CheckResponse)
 	assert.Equal(t, result.ErrCode, errors.ErrNoError)
-	assert.Equal(t, mutable.Hash(), result.Hash)
+	assert.Equal(t, tx.Hash(), result.Hash)
 }
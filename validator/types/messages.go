@@ -59,6 +59,7 @@ type CheckResponse struct {
 type VerifyType uint8
 
 const (
-	Stateless VerifyType = iota
-	Stateful  VerifyType = iota
+	Stateless  VerifyType = iota
+	Stateful   VerifyType = iota
+	Permission VerifyType = iota
 )